@@ -0,0 +1,95 @@
+package artifacts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "run-1/run.log", []byte("log")))
+	require.NoError(t, store.Put(ctx, "run-2/run.log", []byte("other")))
+
+	objects, err := store.List(ctx, "run-1/")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "run-1/run.log", objects[0].Key)
+	assert.Equal(t, int64(3), objects[0].Size)
+
+	require.NoError(t, store.Delete(ctx, "run-1/run.log"))
+	assert.NoDirExists(t, filepath.Join(dir, "run-1"))
+	assert.DirExists(t, dir)
+}
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	assert.Error(t, store.Put(context.Background(), "../outside", []byte("x")))
+}
+
+func TestLocalStoreListMissingDirectory(t *testing.T) {
+	objects, err := NewLocalStore(filepath.Join(t.TempDir(), "missing")).List(context.Background(), "")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, store.Put(ctx, "old/run.log", []byte("old")))
+	require.NoError(t, store.Put(ctx, "new/run.log", []byte("new")))
+	old := now.Add(-40 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "old", "run.log"), old, old))
+
+	deleted, err := Prune(ctx, store, 30*24*time.Hour, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	objects, err := store.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "new/run.log", objects[0].Key)
+
+	deleted, err = Prune(ctx, store, 0, now)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("GCS_HMAC_ACCESS_KEY", "gkey")
+	t.Setenv("GCS_HMAC_SECRET", "gsecret")
+
+	store, err := Open("file:///var/lib/monday")
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/monday", store.(*LocalStore).dir)
+
+	store, err = Open("s3://bucket/runs")
+	require.NoError(t, err)
+	s3 := store.(*S3Store)
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com", s3.config.Endpoint)
+	assert.Equal(t, "runs", s3.config.Prefix)
+
+	store, err = Open("gs://bucket")
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com", store.(*S3Store).config.Endpoint)
+
+	for _, bad := range []string{"ftp://host/x", "s3:///prefix", "file://"} {
+		_, err := Open(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,32 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Prune deletes the objects in store last modified before now minus maxAge and returns how
+// many were deleted. A zero maxAge keeps everything.
+func Prune(ctx context.Context, store Store, maxAge time.Duration, now time.Time) (int, error) {
+	if maxAge <= 0 {
+		return 0, nil
+	}
+
+	objects, err := store.List(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	cutoff := now.Add(-maxAge)
+	var deleted int
+	for _, object := range objects {
+		if !object.Modified.Before(cutoff) {
+			continue
+		}
+		if err := store.Delete(ctx, object.Key); err != nil {
+			return deleted, fmt.Errorf("failed to prune artifacts: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
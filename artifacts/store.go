@@ -0,0 +1,98 @@
+// Package artifacts stores the files a run produces (logs, transcripts, diffs, and summaries)
+// outside the machine that ran it, so ephemeral server deployments keep them after a restart.
+// Backends are selected by URL: file:// for a local directory, s3:// for Amazon S3 or an
+// S3-compatible service, and gs:// for Google Cloud Storage through its S3-interoperable
+// XML API.
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Object describes a stored artifact.
+type Object struct {
+	// Key is the object key, relative to the store's prefix
+	Key string
+	// Size is the object size in bytes
+	Size int64
+	// Modified is when the object was last written
+	Modified time.Time
+}
+
+// Store is an artifact storage backend. Keys use forward slashes.
+type Store interface {
+	// Put writes data under key, replacing any existing object
+	Put(ctx context.Context, key string, data []byte) error
+	// List returns the objects whose keys start with prefix
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+}
+
+// Open returns the store for rawURL:
+//
+//	file:///var/lib/monday/artifacts
+//	s3://bucket/optional/prefix
+//	gs://bucket/optional/prefix
+//
+// S3 credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN,
+// the region from AWS_REGION (default us-east-1), and AWS_ENDPOINT_URL selects an
+// S3-compatible service. GCS uses the HMAC key in GCS_HMAC_ACCESS_KEY and GCS_HMAC_SECRET.
+func Open(rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact store URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		dir := u.Path
+		if u.Host != "" {
+			dir = path.Join(u.Host, u.Path)
+		}
+		if dir == "" {
+			return nil, fmt.Errorf("invalid artifact store URL %q: missing directory", rawURL)
+		}
+		return NewLocalStore(dir), nil
+	case "s3", "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid artifact store URL %q: missing bucket", rawURL)
+		}
+		prefix := strings.Trim(u.Path, "/")
+		if u.Scheme == "gs" {
+			return NewS3Store(S3Config{
+				Endpoint:        "https://storage.googleapis.com",
+				Region:          "auto",
+				Bucket:          u.Host,
+				Prefix:          prefix,
+				AccessKeyID:     os.Getenv("GCS_HMAC_ACCESS_KEY"),
+				SecretAccessKey: os.Getenv("GCS_HMAC_SECRET"),
+			})
+		}
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		endpoint := os.Getenv("AWS_ENDPOINT_URL")
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+		}
+		return NewS3Store(S3Config{
+			Endpoint:        endpoint,
+			Region:          region,
+			Bucket:          u.Host,
+			Prefix:          prefix,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported artifact store %q: use file://, s3://, or gs://", rawURL)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// transcriptFile is the file in a run's directory the agent's output is written to.
+const transcriptFile = "transcript.log"
+
+// logDir is the directory the agent output of every run is also written to.
+var logDir string
+
+func init() {
+	rootCmd.Flags().StringVar(&logDir, "log-dir", "", "Directory the agent output of each run is also written to, as <run-id>.log (default: $MONDAY_LOG_DIR; it is always kept as transcript.log in the run directory)")
+}
+
+// resolveLogDir returns the directory selected with --log-dir or MONDAY_LOG_DIR, or "" if none.
+func resolveLogDir() string {
+	if logDir != "" {
+		return logDir
+	}
+	return os.Getenv("MONDAY_LOG_DIR")
+}
+
+// agentLog is the agent output of a run: its transcript, and its copy in the log directory.
+type agentLog struct {
+	io.Writer
+	files []*os.File
+}
+
+// Close closes the files of the log.
+func (l *agentLog) Close() error {
+	var err error
+	for _, f := range l.files {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// openAgentLog opens the transcript of the run with its files in runDir, which is named after
+// the run, for appending, along with <log-dir>/<run-id>.log when a log directory is set.
+func openAgentLog(runDir string) (*agentLog, error) {
+	paths := []string{filepath.Join(runDir, transcriptFile)}
+	if dir := resolveLogDir(); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		paths = append(paths, filepath.Join(dir, filepath.Base(runDir)+".log"))
+	}
+
+	l := &agentLog{}
+	writers := make([]io.Writer, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		l.files = append(l.files, f)
+		writers = append(writers, f)
+	}
+	l.Writer = io.MultiWriter(writers...)
+	return l, nil
+}
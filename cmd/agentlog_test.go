@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAgentLogCapturesStdoutAndStderr(t *testing.T) {
+	runDir := filepath.Join(t.TempDir(), "run-1")
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logs := t.TempDir()
+	orig := logDir
+	t.Cleanup(func() { logDir = orig })
+	logDir = logs
+
+	out, err := openAgentLog(runDir)
+	if err != nil {
+		t.Fatalf("openAgentLog() error = %v", err)
+	}
+	cmd := exec.Command("sh", "-c", "echo editing; echo rate limited >&2")
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := runWithRedactedOutput(cmd, false, false); err != nil {
+		t.Fatalf("runWithRedactedOutput() error = %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{filepath.Join(runDir, transcriptFile), filepath.Join(logs, "run-1.log")} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// stdout and stderr are copied concurrently, so their lines may come in either order.
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		sort.Strings(lines)
+		if !slices.Equal(lines, []string{"editing", "rate limited"}) {
+			t.Errorf("%s = %q, want the agent's stdout and stderr", path, data)
+		}
+	}
+}
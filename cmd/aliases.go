@@ -0,0 +1,20 @@
+package cmd
+
+import "github.com/spf13/pflag"
+
+// flagAliases maps other names of run flags to the flags they stand for.
+var flagAliases = map[string]string{
+	"base-branch":        "base",
+	"pr-draft":           "draft",
+	"verify-cmd":         "test-command",
+	"max-fix-iterations": "test-fix-attempts",
+}
+
+func init() {
+	rootCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if alias, ok := flagAliases[name]; ok {
+			name = alias
+		}
+		return pflag.NormalizedName(name)
+	})
+}
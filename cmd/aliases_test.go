@@ -0,0 +1,27 @@
+package cmd
+
+import "testing"
+
+func TestFlagAliases(t *testing.T) {
+	origBase, origTest, origAttempts := baseBranch, testCommand, testFixAttempts
+	t.Cleanup(func() {
+		baseBranch, testCommand, testFixAttempts = origBase, origTest, origAttempts
+		for _, name := range []string{"base", "test-command", "test-fix-attempts"} {
+			rootCmd.Flags().Lookup(name).Changed = false
+		}
+	})
+
+	args := []string{"--base-branch", "develop", "--verify-cmd", "make test", "--max-fix-iterations", "3"}
+	if err := rootCmd.Flags().Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if baseBranch != "develop" {
+		t.Errorf("--base-branch set baseBranch = %q, want develop", baseBranch)
+	}
+	if testCommand != "make test" {
+		t.Errorf("--verify-cmd set testCommand = %q, want make test", testCommand)
+	}
+	if testFixAttempts != 3 {
+		t.Errorf("--max-fix-iterations set testFixAttempts = %d, want 3", testFixAttempts)
+	}
+}
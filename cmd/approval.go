@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/notify"
+	"monday/redact"
+	"monday/summary"
+)
+
+const (
+	// approvalStage is the stage in which a run waits for its changes to be approved.
+	approvalStage = "approval"
+	// approvalFile is created in a run's directory to approve or reject its changes.
+	approvalFile = "approval"
+	// pendingDiffFile holds the patch of the changes a run waits for approval of.
+	pendingDiffFile = "pending.patch"
+)
+
+// errChangesRejected is returned by runs whose changes were rejected.
+var errChangesRejected = errors.New("changes were rejected before pushing")
+
+var (
+	// requireApproval makes runs wait for their changes to be approved before committing them.
+	requireApproval bool
+	// rejectChanges makes monday approve reject the changes instead.
+	rejectChanges bool
+	// approvalInput is where CLI runs in a terminal read the answer to the approval prompt; it
+	// is nil for runs that are only approved with monday approve or the server.
+	approvalInput io.Reader
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <run-id>",
+	Short: "Approve the changes of a run waiting for approval",
+	Long: `Approve the changes of a --require-approval run of the CLI or the server on this machine,
+so it commits and pushes them and opens the pull request. With --reject, the run fails
+instead without pushing anything. Run IDs are listed by monday status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApprove,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&requireApproval, "require-approval", false, "Show the agent's changes and wait for them to be approved, at the prompt, with monday approve, or with POST /runs/{id}/approve, before committing and pushing them (default: $MONDAY_REQUIRE_APPROVAL)")
+	approveCmd.Flags().BoolVar(&rejectChanges, "reject", false, "Reject the changes instead, failing the run")
+	rootCmd.AddCommand(approveCmd)
+}
+
+// approvalRequired reports whether runs wait for approval: with --require-approval or
+// MONDAY_REQUIRE_APPROVAL.
+func approvalRequired() (bool, error) {
+	if requireApproval {
+		return true, nil
+	}
+	value := os.Getenv("MONDAY_REQUIRE_APPROVAL")
+	if value == "" {
+		return false, nil
+	}
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid MONDAY_REQUIRE_APPROVAL %q: must be true or false", value)
+	}
+	return required, nil
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	dir, err := runDir(runID)
+	if err != nil {
+		return err
+	}
+	if err := requestApproval(dir, !rejectChanges, processAlive); err != nil {
+		return err
+	}
+	status := "approved"
+	if rejectChanges {
+		status = "rejected"
+	}
+	fmt.Printf("✅ Changes of %s %s\n", runID, status)
+	if jsonOutput() {
+		return writeJSON(map[string]string{"run_id": runID, "status": status})
+	}
+	return nil
+}
+
+// requestApproval approves, or rejects, the changes of the run recorded in dir. It fails when
+// the run is not waiting for approval; alive reports whether the run's process still exists.
+func requestApproval(dir string, approve bool, alive func(pid int) bool) error {
+	run, err := summary.Load(filepath.Join(dir, "summary.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("run %s not found", filepath.Base(dir))
+	}
+	if err != nil {
+		return err
+	}
+	if run.Status != summary.StatusRunning || run.PID == 0 || !alive(run.PID) {
+		return fmt.Errorf("run %s is not running", run.RunID)
+	}
+	if run.FailedStage() != approvalStage {
+		return fmt.Errorf("run %s is not waiting for approval", run.RunID)
+	}
+
+	answer := "approved"
+	if !approve {
+		answer = "rejected"
+	}
+	if err := os.WriteFile(filepath.Join(dir, approvalFile), []byte(answer+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to record approval: %w", err)
+	}
+	return nil
+}
+
+// awaitApproval shows the changes the agent left in workDir, in the terminal, the chat
+// channels, and a comment on the issue, and waits until they are approved. The patch is kept
+// in the run's directory dir. It returns errChangesRejected when they are rejected and
+// errRunCanceled when the run is cancelled while waiting.
+func awaitApproval(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir, workDir string, tracker issues.Provider, issue *issues.Issue) error {
+	stageLog, endStage := startStage(ctx, log, sum, dir, approvalStage)
+	err := func() error {
+		stat, patch, err := pendingChanges(workDir)
+		if err != nil {
+			return err
+		}
+		if stat == "" {
+			return errNothingToCommit
+		}
+		if err := os.WriteFile(filepath.Join(dir, pendingDiffFile), []byte(redact.String(patch)), 0o644); err != nil {
+			stageLog.Warn("Failed to save the pending diff", zap.Error(err))
+		}
+
+		progressf("%s\n%s\n", redact.String(stat), redact.String(patch))
+		shortStat := lastLines(stat, 1)
+		event := runEvent(notify.RunAwaitingApproval, sum)
+		event.Changes = shortStat
+		sendNotification(stageLog, event)
+		if tracker != nil {
+			body := fmt.Sprintf("**Monday is waiting for approval of its changes to this issue.**\n\n- **Branch:** `%s`\n- **Changes:** %s\n- **Run:** `%s`\n\nApprove them with `monday approve %s` or `POST /runs/%s/approve`.\n",
+				sum.Branch, shortStat, sum.RunID, sum.RunID, sum.RunID)
+			if err := tracker.CreateComment(ctx, issue, redact.String(body)); err != nil {
+				stageLog.Warn("Failed to post approval comment", zap.Error(err))
+			}
+		}
+
+		if approvalInput != nil {
+			progressf("❓ Commit and push these changes? [y/N] (or run monday approve %s)\n", sum.RunID)
+		} else {
+			progressf("⏸️  Waiting for approval: monday approve %s, or POST /runs/%s/approve\n", sum.RunID, sum.RunID)
+		}
+		stageLog.Info("Waiting for approval")
+		approved, err := waitForApproval(ctx, dir, approvalInput, time.Second)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			return withExitCode(exitChangesRejected, errChangesRejected)
+		}
+		stageLog.Info("Changes approved")
+		return nil
+	}()
+	endStage(err)
+	return err
+}
+
+// pendingChanges stages everything in the repository in dir and returns the stat and patch of
+// the staged changes.
+func pendingChanges(dir string) (stat, patch string, err error) {
+	if out, err := gitCommand(dir, "add", ".").CombinedOutput(); err != nil {
+		return "", "", fmt.Errorf("failed to stage changes: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	statOut, err := gitCommand(dir, "diff", "--cached", "--stat").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to diff changes: %w", err)
+	}
+	patchOut, err := gitCommand(dir, "diff", "--cached", "--patch").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to diff changes: %w", err)
+	}
+	return strings.TrimRight(string(statOut), "\n"), string(patchOut), nil
+}
+
+// waitForApproval waits for the changes of the run in dir to be approved or rejected, checking
+// its approval file every interval and reading answers from input, if set. It returns whether
+// they were approved, or errRunCanceled once ctx is done.
+func waitForApproval(ctx context.Context, dir string, input io.Reader, interval time.Duration) (bool, error) {
+	answers := make(chan bool, 1)
+	if input != nil {
+		go func() {
+			scanner := bufio.NewScanner(input)
+			for scanner.Scan() {
+				switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+				case "y", "yes":
+					answers <- true
+					return
+				case "n", "no":
+					answers <- false
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, errRunCanceled
+		case approved := <-answers:
+			return approved, nil
+		case <-ticker.C:
+			data, err := os.ReadFile(filepath.Join(dir, approvalFile))
+			if err != nil {
+				continue
+			}
+			return strings.TrimSpace(string(data)) == "approved", nil
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"monday/summary"
+)
+
+func TestRequestApproval(t *testing.T) {
+	waiting := summary.New("run-waiting", "DEL-1", "repo")
+	waiting.PID = 100
+	waiting.StartStage(approvalStage)
+	working := summary.New("run-working", "DEL-1", "repo")
+	working.PID = 100
+	working.StartStage("agent")
+	exited := summary.New("run-exited", "DEL-1", "repo")
+	exited.PID = 200
+	exited.StartStage(approvalStage)
+
+	tests := []struct {
+		name    string
+		run     *summary.Summary
+		approve bool
+		want    string
+		wantErr string
+	}{
+		{name: "approve", run: waiting, approve: true, want: "approved"},
+		{name: "reject", run: waiting, want: "rejected"},
+		{name: "not waiting", run: working, approve: true, wantErr: "not waiting for approval"},
+		{name: "process exited", run: exited, approve: true, wantErr: "not running"},
+		{name: "unknown run", approve: true, wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "run")
+			if tt.run != nil {
+				if _, err := tt.run.WriteFiles(dir); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := requestApproval(dir, tt.approve, func(pid int) bool { return pid == 100 })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("requestApproval() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requestApproval() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, approvalFile))
+			if err != nil {
+				t.Fatalf("approval not written: %v", err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("approval = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForApproval(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		input string
+		want  bool
+	}{
+		{name: "approved with monday approve", file: "approved\n", want: true},
+		{name: "rejected with monday approve", file: "rejected\n", want: false},
+		{name: "approved at the prompt", input: "maybe\nyes\n", want: true},
+		{name: "rejected at the prompt", input: "n\n", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.file != "" {
+				if err := os.WriteFile(filepath.Join(dir, approvalFile), []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var input io.Reader
+			interval := 10 * time.Millisecond
+			if tt.input != "" {
+				input = strings.NewReader(tt.input)
+				interval = time.Hour
+			}
+
+			got, err := waitForApproval(context.Background(), dir, input, interval)
+			if err != nil {
+				t.Fatalf("waitForApproval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("waitForApproval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForApprovalCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := waitForApproval(ctx, t.TempDir(), nil, time.Hour); !errors.Is(err, errRunCanceled) {
+		t.Errorf("waitForApproval() error = %v, want %v", err, errRunCanceled)
+	}
+}
+
+func TestPendingChanges(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := gitCommand(dir, args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	stat, patch, err := pendingChanges(dir)
+	if err != nil {
+		t.Fatalf("pendingChanges() error = %v", err)
+	}
+	if stat != "" || patch != "" {
+		t.Errorf("pendingChanges() = %q, %q, want no changes", stat, patch)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stat, patch, err = pendingChanges(dir)
+	if err != nil {
+		t.Fatalf("pendingChanges() error = %v", err)
+	}
+	if !strings.Contains(stat, "1 file changed") {
+		t.Errorf("stat = %q, want one changed file", stat)
+	}
+	if !strings.Contains(patch, "+package main") {
+		t.Errorf("patch = %q, want the new file", patch)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/artifacts"
+)
+
+// uploadArtifacts copies the files of the run directory dir to the artifact store configured
+// by MONDAY_ARTIFACT_STORE under <runID>/, then deletes stored artifacts older than
+// MONDAY_ARTIFACT_RETENTION. Without a configured store the artifacts stay in dir only.
+// Failures are logged and otherwise ignored.
+func uploadArtifacts(log *zap.Logger, runID, dir string) {
+	storeURL := os.Getenv("MONDAY_ARTIFACT_STORE")
+	if storeURL == "" {
+		return
+	}
+	store, err := artifacts.Open(storeURL)
+	if err != nil {
+		log.Warn("Failed to open artifact store", zap.Error(err))
+		return
+	}
+
+	log.Sync()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Warn("Failed to read run artifacts", zap.Error(err))
+		return
+	}
+	var uploaded int
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Warn("Failed to read run artifact", zap.String("file", entry.Name()), zap.Error(err))
+			continue
+		}
+		if err := store.Put(ctx, runID+"/"+entry.Name(), data); err != nil {
+			log.Warn("Failed to upload run artifact", zap.String("file", entry.Name()), zap.Error(err))
+			continue
+		}
+		uploaded++
+	}
+	log.Info("Uploaded run artifacts", zap.Int("count", uploaded))
+	fmt.Printf("☁️  Uploaded %d artifacts to %s\n", uploaded, storeURL)
+
+	retention := os.Getenv("MONDAY_ARTIFACT_RETENTION")
+	if retention == "" {
+		return
+	}
+	maxAge, err := parseSince(retention)
+	if err != nil {
+		log.Warn("Invalid MONDAY_ARTIFACT_RETENTION", zap.String("value", retention), zap.Error(err))
+		return
+	}
+	deleted, err := artifacts.Prune(ctx, store, maxAge, time.Now())
+	if err != nil {
+		log.Warn("Failed to apply artifact retention", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		log.Info("Deleted expired artifacts", zap.Int("count", deleted))
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestUploadArtifactsToLocalStore(t *testing.T) {
+	runDir := t.TempDir()
+	storeDir := t.TempDir()
+	for _, name := range []string{"run.log", "summary.json"} {
+		if err := os.WriteFile(filepath.Join(runDir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("MONDAY_ARTIFACT_STORE", "file://"+storeDir)
+	t.Setenv("MONDAY_ARTIFACT_RETENTION", "30d")
+
+	uploadArtifacts(zap.NewNop(), "run-1", runDir)
+
+	for _, name := range []string{"run.log", "summary.json"} {
+		data, err := os.ReadFile(filepath.Join(storeDir, "run-1", name))
+		if err != nil {
+			t.Fatalf("artifact %s was not uploaded: %v", name, err)
+		}
+		if string(data) != name {
+			t.Errorf("artifact %s = %q, want %q", name, data, name)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/summary"
+)
+
+// assignee is the user runs assign their issue to.
+var assignee string
+
+func init() {
+	rootCmd.Flags().StringVar(&assignee, "assignee", "", "Linear user ID to assign the issue to when the run starts (default: $MONDAY_ASSIGNEE, or the user who triggered a server run)")
+}
+
+// issueAssigner is an issue tracker that can assign issues to a user.
+type issueAssigner interface {
+	// AssignIssue assigns the issue with the given internal ID to the user with the given ID
+	AssignIssue(ctx context.Context, issueID, userID string) error
+}
+
+// reviewMarker is an issue tracker that can move issues to In Review.
+type reviewMarker interface {
+	// MarkIssueInReview moves the issue to In Review
+	MarkIssueInReview(ctx context.Context, issue *issues.Issue) error
+}
+
+// assigneeKey is the context key of the user who triggered a run.
+type assigneeKey struct{}
+
+// withAssignee returns a context whose run assigns its issue to the user with the given ID,
+// such as the user who triggered it, instead of --assignee.
+func withAssignee(ctx context.Context, userID string) context.Context {
+	if userID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, assigneeKey{}, userID)
+}
+
+// runAssignee returns the user the run of ctx assigns its issue to, or "" for none.
+func runAssignee(ctx context.Context) string {
+	if userID, ok := ctx.Value(assigneeKey{}).(string); ok {
+		return userID
+	}
+	if assignee != "" {
+		return assignee
+	}
+	return os.Getenv("MONDAY_ASSIGNEE")
+}
+
+// assignIssue assigns issue to the run's assignee, if it has one and tracker can. A failure is
+// logged and otherwise ignored.
+func assignIssue(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir string, tracker issues.Provider, issue *issues.Issue) {
+	userID := runAssignee(ctx)
+	a, ok := tracker.(issueAssigner)
+	if userID == "" || !ok {
+		return
+	}
+	stageLog, endStage := startStage(ctx, log, sum, dir, "assign_issue")
+	stageLog.Info("Assigning issue", zap.String("assignee", userID))
+	err := a.AssignIssue(ctx, issue.ID, userID)
+	endStage(err)
+	if err != nil {
+		stageLog.Warn("Failed to assign issue", zap.Error(err))
+	}
+}
+
+// markInReview moves issue to In Review once its pull request is open, if tracker can. A
+// failure is logged and otherwise ignored: the pull request already exists.
+func markInReview(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir string, tracker issues.Provider, issue *issues.Issue) {
+	m, ok := tracker.(reviewMarker)
+	if !ok {
+		return
+	}
+	stageLog, endStage := startStage(ctx, log, sum, dir, "mark_in_review")
+	stageLog.Info("Marking issue as In Review")
+	err := m.MarkIssueInReview(ctx, issue)
+	endStage(err)
+	if err != nil {
+		stageLog.Warn("Failed to mark issue as In Review", zap.Error(err))
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/summary"
+)
+
+// assigningTracker is a tracker that records assignments and In Review moves.
+type assigningTracker struct {
+	issues.Provider
+	assigned []string
+	reviewed []string
+	err      error
+}
+
+func (a *assigningTracker) AssignIssue(_ context.Context, issueID, userID string) error {
+	a.assigned = append(a.assigned, issueID+"="+userID)
+	return a.err
+}
+
+func (a *assigningTracker) MarkIssueInReview(_ context.Context, issue *issues.Issue) error {
+	a.reviewed = append(a.reviewed, issue.ID)
+	return a.err
+}
+
+func TestRunAssignee(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger string
+		flag    string
+		env     string
+		want    string
+	}{
+		{name: "none"},
+		{name: "environment", env: "user-env", want: "user-env"},
+		{name: "flag", flag: "user-flag", env: "user-env", want: "user-flag"},
+		{name: "triggering user", trigger: "user-trigger", flag: "user-flag", env: "user-env", want: "user-trigger"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := assignee
+			t.Cleanup(func() { assignee = orig })
+			assignee = tt.flag
+			t.Setenv("MONDAY_ASSIGNEE", tt.env)
+
+			if got := runAssignee(withAssignee(context.Background(), tt.trigger)); got != tt.want {
+				t.Errorf("runAssignee() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignIssue(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		err        error
+		want       string
+		wantStatus string
+	}{
+		{name: "no assignee"},
+		{name: "assigned", userID: "user-7", want: "issue-1=user-7", wantStatus: "succeeded"},
+		{name: "failure is ignored", userID: "user-7", err: errors.New("boom"), want: "issue-1=user-7", wantStatus: "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MONDAY_ASSIGNEE", "")
+			tracker := &assigningTracker{err: tt.err}
+			sum := summary.New("run-1", "DEL-1", "repo")
+			ctx := withAssignee(context.Background(), tt.userID)
+
+			assignIssue(ctx, zap.NewNop(), sum, t.TempDir(), tracker, &issues.Issue{ID: "issue-1"})
+
+			got := ""
+			if len(tracker.assigned) > 0 {
+				got = tracker.assigned[0]
+			}
+			if got != tt.want {
+				t.Errorf("assigned = %q, want %q", got, tt.want)
+			}
+			status := ""
+			if len(sum.Stages) > 0 {
+				status = sum.Stages[0].Status
+			}
+			if status != tt.wantStatus {
+				t.Errorf("assign_issue stage status = %q, want %q", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMarkInReview(t *testing.T) {
+	tracker := &assigningTracker{}
+	sum := summary.New("run-1", "DEL-1", "repo")
+
+	markInReview(context.Background(), zap.NewNop(), sum, t.TempDir(), tracker, &issues.Issue{ID: "issue-1"})
+
+	if len(tracker.reviewed) != 1 || tracker.reviewed[0] != "issue-1" {
+		t.Errorf("reviewed = %v, want [issue-1]", tracker.reviewed)
+	}
+	if len(sum.Stages) != 1 || sum.Stages[0].Name != "mark_in_review" {
+		t.Errorf("stages = %+v, want one mark_in_review stage", sum.Stages)
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+)
+
+// urlAttacher is an issue tracker that can attach URLs to issues.
+type urlAttacher interface {
+	// AttachURL attaches the URL with the given title to the issue with the given internal ID
+	AttachURL(ctx context.Context, issueID, title, url string) error
+}
+
+// attachPullRequest attaches the pull request at prURL to issue, if tracker can, so it shows in
+// the issue's attachments and the tracker can follow its status. A failure is logged and
+// otherwise ignored: the pull request already exists.
+func attachPullRequest(ctx context.Context, log *zap.Logger, tracker issues.Provider, issue *issues.Issue, prURL string) {
+	a, ok := tracker.(urlAttacher)
+	if !ok {
+		return
+	}
+	if err := a.AttachURL(ctx, issue.ID, pullRequestAttachmentTitle(issue), prURL); err != nil {
+		log.Warn("Failed to attach pull request to issue", zap.Error(err))
+		return
+	}
+	log.Info("Attached pull request to issue", zap.String("pr_url", prURL))
+}
+
+// pullRequestAttachmentTitle returns the title of the attachment linking issue to its pull request.
+func pullRequestAttachmentTitle(issue *issues.Issue) string {
+	return fmt.Sprintf("Pull request: %s", issue.Title)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+)
+
+// attachingTracker is a tracker that records attached URLs.
+type attachingTracker struct {
+	issues.Provider
+	attached []string
+}
+
+func (a *attachingTracker) AttachURL(_ context.Context, issueID, title, url string) error {
+	a.attached = append(a.attached, issueID+" "+title+" "+url)
+	return nil
+}
+
+func TestAttachPullRequest(t *testing.T) {
+	tracker := &attachingTracker{}
+	issue := &issues.Issue{ID: "issue-1", Title: "Fix login"}
+
+	attachPullRequest(context.Background(), zap.NewNop(), tracker, issue, "https://github.com/o/r/pull/7")
+
+	want := "issue-1 Pull request: Fix login https://github.com/o/r/pull/7"
+	if len(tracker.attached) != 1 || tracker.attached[0] != want {
+		t.Errorf("attached = %v, want [%s]", tracker.attached, want)
+	}
+}
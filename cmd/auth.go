@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/linear"
+)
+
+// linearTokenFile is the file in the state directory monday auth linear stores the OAuth
+// token of Linear in.
+const linearTokenFile = "linear-token.json"
+
+var (
+	// authClientID is the client ID of the Linear OAuth app.
+	authClientID string
+	// authClientSecret is the client secret of the Linear OAuth app.
+	authClientSecret string
+	// authScopes are the scopes requested from Linear.
+	authScopes []string
+	// authPort is the local port Linear redirects back to.
+	authPort int
+	// authAsApp makes monday act in Linear as the OAuth app instead of the authorizing user.
+	authAsApp bool
+	// authNoBrowser prints the authorization URL instead of opening it.
+	authNoBrowser bool
+	// authLogout removes the stored token instead.
+	authLogout bool
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Authenticate with issue trackers",
+}
+
+var authLinearCmd = &cobra.Command{
+	Use:   "linear",
+	Short: "Authorize monday as a Linear OAuth app",
+	Long: `Authorize monday as a Linear OAuth app in the browser and store the token, which is
+refreshed when it expires, in ~/.monday/linear-token.json, readable only by you. Runs use it
+when neither LINEAR_API_KEY nor LINEAR_ACCESS_TOKEN is set.
+
+Create the app in Linear under Settings > API > OAuth applications with the callback URL
+http://localhost:8976/callback (or the --port given), and pass its client ID with
+--client-id or LINEAR_CLIENT_ID. With --as-app, issues are updated and commented on by the
+app instead of by you, which needs a workspace admin to authorize it.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthLinear,
+}
+
+func init() {
+	authLinearCmd.Flags().StringVar(&authClientID, "client-id", "", "Client ID of the Linear OAuth app (default: $LINEAR_CLIENT_ID)")
+	authLinearCmd.Flags().StringVar(&authClientSecret, "client-secret", "", "Client secret of the Linear OAuth app, if it has one (default: $LINEAR_CLIENT_SECRET)")
+	authLinearCmd.Flags().StringSliceVar(&authScopes, "scopes", []string{"read", "write"}, "Scopes to request from Linear")
+	authLinearCmd.Flags().IntVar(&authPort, "port", 8976, "Local port of the callback URL Linear redirects back to")
+	authLinearCmd.Flags().BoolVar(&authAsApp, "as-app", false, "Act in Linear as the OAuth app instead of as you")
+	authLinearCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "Print the authorization URL instead of opening it in the browser")
+	authLinearCmd.Flags().BoolVar(&authLogout, "logout", false, "Remove the stored token instead")
+	authCmd.AddCommand(authLinearCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+// linearTokenPath returns the path of the stored OAuth token of Linear.
+func linearTokenPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, linearTokenFile), nil
+}
+
+// linearOAuthConfig returns the Linear OAuth app configured with the flags or the environment.
+func linearOAuthConfig() linear.OAuthConfig {
+	config := linear.OAuthConfig{
+		ClientID:     authClientID,
+		ClientSecret: authClientSecret,
+		Scopes:       authScopes,
+		RedirectURL:  fmt.Sprintf("http://localhost:%d/callback", authPort),
+	}
+	if config.ClientID == "" {
+		config.ClientID = os.Getenv("LINEAR_CLIENT_ID")
+	}
+	if config.ClientSecret == "" {
+		config.ClientSecret = os.Getenv("LINEAR_CLIENT_SECRET")
+	}
+	if authAsApp {
+		config.Actor = "app"
+	}
+	return config
+}
+
+// linearTokenSource returns the OAuth access tokens Linear clients are authenticated with:
+// LINEAR_ACCESS_TOKEN, or else the token stored by monday auth linear. It returns nil if
+// there is neither.
+func linearTokenSource() (linear.TokenSource, error) {
+	if token := os.Getenv("LINEAR_ACCESS_TOKEN"); token != "" {
+		return linear.StaticToken(token), nil
+	}
+	path, err := linearTokenPath()
+	if err != nil {
+		return nil, err
+	}
+	token, err := linear.LoadToken(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &linear.StoredTokenSource{Path: path, Config: linearOAuthConfig(), Token: token}, nil
+}
+
+// newLinearAPIClient returns a Linear client authenticated with LINEAR_API_KEY,
+// LINEAR_ACCESS_TOKEN, or the token stored by monday auth linear, in that order.
+func newLinearAPIClient() (*linear.Client, error) {
+	var client *linear.Client
+	if apiKey := os.Getenv("LINEAR_API_KEY"); apiKey != "" {
+		client = linear.NewClient(apiKey)
+	} else {
+		tokens, err := linearTokenSource()
+		if err != nil {
+			return nil, err
+		}
+		if tokens == nil {
+			return nil, fmt.Errorf("LINEAR_API_KEY environment variable is required, or LINEAR_ACCESS_TOKEN or a token from monday auth linear")
+		}
+		client = linear.NewOAuthClient(tokens)
+	}
+
+	policy, err := retryPolicy()
+	if err != nil {
+		return nil, err
+	}
+	limiter, err := linearRateLimiter()
+	if err != nil {
+		return nil, err
+	}
+	client.SetRetryPolicy(policy)
+	client.SetRateLimiter(limiter)
+	return client, nil
+}
+
+func runAuthLinear(cmd *cobra.Command, args []string) error {
+	path, err := linearTokenPath()
+	if err != nil {
+		return err
+	}
+	if authLogout {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove Linear token: %w", err)
+		}
+		fmt.Printf("✅ Removed the Linear token from %s\n", path)
+		if jsonOutput() {
+			return writeJSON(map[string]string{"status": "logged_out", "token_file": path})
+		}
+		return nil
+	}
+
+	config := linearOAuthConfig()
+	if config.ClientID == "" {
+		return withExitCode(exitConfig, fmt.Errorf("--client-id or LINEAR_CLIENT_ID is required"))
+	}
+	token, err := authorizeLinear(cmd.Context(), config, authNoBrowser)
+	if err != nil {
+		return err
+	}
+	if err := linear.SaveToken(path, token); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Authorized monday in Linear; the token is stored in %s\n", path)
+	if jsonOutput() {
+		return writeJSON(map[string]any{"status": "authorized", "token_file": path, "scope": token.Scope, "expires_at": token.ExpiresAt})
+	}
+	return nil
+}
+
+// authorizeLinear has the user authorize the app of config in the browser, receives the
+// authorization code on the callback URL, and redeems it for a token. With noBrowser, the
+// URL to open is only printed.
+func authorizeLinear(ctx context.Context, config linear.OAuthConfig, noBrowser bool) (*linear.Token, error) {
+	verifier, err := linear.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+	state, err := linear.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(authPort)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the Linear callback: %w", err)
+	}
+	codes := make(chan string, 1)
+	failures := make(chan error, 1)
+	server := &http.Server{ReadHeaderTimeout: 10 * time.Second, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			fmt.Fprintf(w, "Linear did not authorize monday: %s. You can close this window.", html.EscapeString(query.Get("error")))
+			select {
+			case failures <- fmt.Errorf("Linear did not authorize monday: %s", query.Get("error")):
+			default:
+			}
+		default:
+			fmt.Fprint(w, "monday is authorized in Linear. You can close this window.")
+			select {
+			case codes <- query.Get("code"):
+			default:
+			}
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := config.AuthCodeURL(state, verifier)
+	fmt.Printf("🔑 Authorize monday in Linear at:\n   %s\n", authURL)
+	if !noBrowser {
+		if err := openBrowser(authURL); err != nil {
+			fmt.Printf("   (could not open the browser: %v)\n", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	select {
+	case code := <-codes:
+		return config.Exchange(ctx, code, verifier)
+	case err := <-failures:
+		return nil, err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the Linear authorization")
+	}
+}
+
+// openBrowser opens url in the user's browser.
+func openBrowser(url string) error {
+	name := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		name = "open"
+	}
+	return exec.Command(name, url).Start()
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"monday/linear"
+)
+
+func TestLinearTokenSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		accessToken string
+		stored      *linear.Token
+		want        string
+	}{
+		{name: "none"},
+		{name: "access token", accessToken: "lin_oauth_env", stored: &linear.Token{AccessToken: "stored"}, want: "static"},
+		{name: "stored token", stored: &linear.Token{AccessToken: "stored", ExpiresAt: time.Now().Add(time.Hour)}, want: "stored"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("MONDAY_HOME", home)
+			t.Setenv("LINEAR_ACCESS_TOKEN", tt.accessToken)
+			if tt.stored != nil {
+				if err := linear.SaveToken(filepath.Join(home, linearTokenFile), tt.stored); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tokens, err := linearTokenSource()
+			if err != nil {
+				t.Fatalf("linearTokenSource() error = %v", err)
+			}
+			var got string
+			switch tokens.(type) {
+			case nil:
+			case linear.StaticToken:
+				got = "static"
+			case *linear.StoredTokenSource:
+				got = "stored"
+			}
+			if got != tt.want {
+				t.Errorf("linearTokenSource() = %T, want %s", tokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLinearAPIClientWithoutCredentials(t *testing.T) {
+	t.Setenv("MONDAY_HOME", t.TempDir())
+	t.Setenv("LINEAR_API_KEY", "")
+	t.Setenv("LINEAR_ACCESS_TOKEN", "")
+
+	_, err := newLinearAPIClient()
+	if err == nil || !strings.Contains(err.Error(), "monday auth linear") {
+		t.Errorf("newLinearAPIClient() error = %v, want one naming monday auth linear", err)
+	}
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import "os"
+
+// runBaseBranch returns the branch selected with --base or MONDAY_BASE_BRANCH that runs start
+// their branch from and open their pull request against, or "" for the repository's default
+// branch, which is looked up with git symbolic-ref or git ls-remote where needed.
+func runBaseBranch() string {
+	if baseBranch != "" {
+		return baseBranch
+	}
+	return os.Getenv("MONDAY_BASE_BRANCH")
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import "testing"
+
+func TestRunBaseBranch(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{name: "default branch"},
+		{name: "flag", flag: "release-2.4", env: "develop", want: "release-2.4"},
+		{name: "environment", env: "develop", want: "develop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := baseBranch
+			t.Cleanup(func() { baseBranch = orig })
+			baseBranch = tt.flag
+			t.Setenv("MONDAY_BASE_BRANCH", tt.env)
+
+			if got := runBaseBranch(); got != tt.want {
+				t.Errorf("runBaseBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"monday/linear"
+	"monday/redact"
+	"monday/summary"
+)
+
+var (
+	concurrency  int
+	issueTeam    string
+	issueProject string
+	issueLabel   string
+	maxIssues    int
+)
+
+// batchFlags are the flags that select and schedule the issues of a batch; they are not
+// passed on to the runs of the individual issues.
+var batchFlags = map[string]bool{
+	"concurrency": true,
+	"team":        true,
+	"project":     true,
+	"label":       true,
+	"max":         true,
+	"output":      true,
+}
+
+func init() {
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", 2, "Number of issues worked on at the same time when several are given")
+	rootCmd.Flags().StringVar(&issueTeam, "team", "", "Work on the issues of this Linear team key")
+	rootCmd.Flags().StringVar(&issueProject, "project", "", "Work on the issues of this Linear project")
+	rootCmd.Flags().StringVar(&issueLabel, "label", "", "Work on the issues with this Linear label")
+	rootCmd.Flags().IntVar(&maxIssues, "max", 0, "Work on at most this many of the selected issues, the first ones given or the newest matching the filters (default: no limit)")
+}
+
+// validateIssueArgs requires at least one issue ID or an issue filter, and a repository, unless
+// a run is continued with --continue. Without --repo-url or --local-repo, the repositories are
+// those of MONDAY_REPO_URL, which may come from a profile; cobra validates arguments before the
+// profiles are loaded for the command, so they are loaded here.
+func validateIssueArgs(cmd *cobra.Command, args []string) error {
+	if continueRunID != "" {
+		if len(args) > 0 || hasIssueFilter() {
+			return fmt.Errorf("--continue takes no issue IDs, --team, --project, or --label")
+		}
+		if dryRun {
+			return fmt.Errorf("--continue and --dry-run cannot be combined")
+		}
+		return nil
+	}
+	if len(args) == 0 && !hasIssueFilter() {
+		return fmt.Errorf("requires at least one Linear issue ID, or --team, --project, or --label")
+	}
+	if len(repoURLs) == 0 && localRepo == "" {
+		if err := loadConfigEnv(); err != nil {
+			return err
+		}
+		repoURLs = profileRepoURLs()
+	}
+	if len(repoURLs) == 0 && localRepo == "" {
+		return fmt.Errorf("one of --repo-url or --local-repo is required (or repo_url in a profile)")
+	}
+	return nil
+}
+
+// hasIssueFilter reports whether issues are selected with --team, --project, or --label.
+func hasIssueFilter() bool {
+	return issueTeam != "" || issueProject != "" || issueLabel != ""
+}
+
+// issueRunResult is the outcome of the run of one issue of a batch.
+type issueRunResult struct {
+	IssueID  string           `json:"issue_id"`
+	Repo     string           `json:"repo,omitempty"`
+	Status   string           `json:"status"`
+	Summary  *summary.Summary `json:"summary,omitempty"`
+	Error    string           `json:"error,omitempty"`
+	ExitCode int              `json:"exit_code"`
+}
+
+// runIssueBatch works on several issues: each issue runs in its own monday process, with its
+// own workspace, at most --concurrency at a time. The results are reported together once all
+// runs are done.
+func runIssueBatch(cmd *cobra.Command, args []string) error {
+	issueIDs := args
+	if hasIssueFilter() {
+		filtered, err := filteredIssueIDs(cmd.Context())
+		if err != nil {
+			return err
+		}
+		issueIDs = append(issueIDs[:len(issueIDs):len(issueIDs)], filtered...)
+	}
+	issueIDs = uniqueIssueIDs(issueIDs)
+	if len(issueIDs) == 0 {
+		fmt.Println("No matching issues")
+		return nil
+	}
+	if maxIssues > 0 && len(issueIDs) > maxIssues {
+		if showProgress() {
+			fmt.Printf("📋 %d issues selected; working on the first %d\n", len(issueIDs), maxIssues)
+		}
+		issueIDs = issueIDs[:maxIssues]
+	}
+
+	flags := forwardedFlags(cmd.Flags())
+	jobs := make([]batchJob, len(issueIDs))
+	for i, issueID := range issueIDs {
+		jobs[i] = batchJob{IssueID: issueID, Flags: flags}
+	}
+	return reportBatch(cmd.Context(), jobs, concurrency)
+}
+
+// reportBatch runs jobs, each in its own monday process, and reports their results together,
+// failing if any run failed.
+func reportBatch(ctx context.Context, jobs []batchJob, concurrency int) error {
+	if showProgress() {
+		fmt.Printf("🚀 Working on %d issues, %d at a time\n", len(jobs), max(concurrency, 1))
+	}
+	results, err := runBatchProcesses(ctx, jobs, concurrency)
+	if err != nil {
+		return err
+	}
+	return reportResults(results)
+}
+
+// runBatchProcesses runs jobs, each in a monday process of its own, at most concurrency at a
+// time, and returns their results.
+func runBatchProcesses(ctx context.Context, jobs []batchJob, concurrency int) ([]issueRunResult, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the monday executable: %w", err)
+	}
+	start := func(ctx context.Context, args []string) *exec.Cmd {
+		return interruptOnCancel(exec.CommandContext(ctx, executable, args...))
+	}
+	return runBatch(ctx, jobs, concurrency, start, os.Stdout), nil
+}
+
+// reportResults prints the results of the runs of a batch, failing if any run failed.
+func reportResults(results []issueRunResult) error {
+	failed := 0
+	code := 0
+	for _, result := range results {
+		if result.Status != summary.StatusSucceeded {
+			failed++
+			code = batchExitCode(code, result.ExitCode)
+		}
+	}
+	if jsonOutput() {
+		if err := writeJSON(results); err != nil {
+			return err
+		}
+	} else {
+		printBatchResults(resultOut, results)
+	}
+	if failed > 0 {
+		return withExitCode(code, fmt.Errorf("%d of %d issues failed", failed, len(results)))
+	}
+	return nil
+}
+
+// batchExitCode combines the exit code of the failed runs so far with that of another failed
+// run: a batch whose runs all failed the same way exits with their code, and with exitFailure
+// otherwise.
+func batchExitCode(code, runCode int) int {
+	if runCode == 0 {
+		runCode = exitFailure
+	}
+	if code == 0 || code == runCode {
+		return runCode
+	}
+	return exitFailure
+}
+
+// filteredIssueIDs returns the identifiers of the issues matching --team, --project, and --label.
+func filteredIssueIDs(ctx context.Context) ([]string, error) {
+	client, err := newLinearClient()
+	if err != nil {
+		return nil, err
+	}
+	issues, err := client.FetchIssuesByFilters(ctx, linear.IssueFilter{Team: issueTeam, Project: issueProject, Label: issueLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	var ids []string
+	for _, issue := range issues {
+		ids = append(ids, issue.Identifier)
+	}
+	return ids, nil
+}
+
+// uniqueIssueIDs drops repeated issues, comparing the IDs extracted from URLs, and keeps order.
+func uniqueIssueIDs(inputs []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, input := range inputs {
+		id := strings.ToUpper(extractIssueID(input))
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, input)
+	}
+	return unique
+}
+
+// forwardedFlags returns the flags set on the command line that the run of each issue of a
+// batch needs, such as --repo-url or --rollback, leaving out those named in skip. Flags taking
+// a list are forwarded once per value.
+func forwardedFlags(flags *pflag.FlagSet, skip ...string) []string {
+	var forwarded []string
+	flags.Visit(func(flag *pflag.Flag) {
+		if batchFlags[flag.Name] || slices.Contains(skip, flag.Name) {
+			return
+		}
+		if list, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range list.GetSlice() {
+				forwarded = append(forwarded, fmt.Sprintf("--%s=%s", flag.Name, value))
+			}
+			return
+		}
+		forwarded = append(forwarded, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	return forwarded
+}
+
+// batchJob is the run of one issue of a batch.
+type batchJob struct {
+	// IssueID is the issue ID or URL the run works on
+	IssueID string
+	// Repo is the repository the run works on when an issue spans several; empty otherwise
+	Repo string
+	// Flags are the command-line flags of the run
+	Flags []string
+}
+
+// runBatch runs each job with start, at most concurrency at a time, and returns the results
+// in the order of jobs. Each run's human-oriented output is copied to out, prefixed with its
+// issue ID.
+func runBatch(ctx context.Context, jobs []batchJob, concurrency int, start func(context.Context, []string) *exec.Cmd, out io.Writer) []issueRunResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]issueRunResult, len(jobs))
+	next := make(chan int)
+	var outMu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(jobs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = runIssueProcess(ctx, jobs[i], start, out, &outMu)
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// runIssueProcess runs the job in a child process and collects its JSON result.
+func runIssueProcess(ctx context.Context, job batchJob, start func(context.Context, []string) *exec.Cmd, out io.Writer, outMu *sync.Mutex) issueRunResult {
+	result := issueRunResult{IssueID: extractIssueID(job.IssueID), Repo: job.Repo, Status: summary.StatusFailed}
+
+	var stdout bytes.Buffer
+	prefix := fmt.Sprintf("[%s] ", result.IssueID)
+	if job.Repo != "" {
+		prefix = fmt.Sprintf("[%s %s] ", result.IssueID, extractRepoName(job.Repo))
+	}
+	logs := &prefixWriter{out: out, mu: outMu, prefix: prefix}
+	cmd := start(ctx, append(append([]string{job.IssueID}, job.Flags...), "--output", outputJSON))
+	cmd.Stdout = &stdout
+	cmd.Stderr = logs
+	runErr := cmd.Run()
+	logs.Flush()
+
+	// The run prints its summary and, when it failed, an error object.
+	dec := json.NewDecoder(&stdout)
+	for {
+		var doc struct {
+			RunID string `json:"run_id"`
+			Error string `json:"error"`
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			break
+		}
+		if json.Unmarshal(raw, &doc) != nil {
+			continue
+		}
+		if doc.RunID != "" {
+			var run summary.Summary
+			if json.Unmarshal(raw, &run) == nil {
+				result.Summary = &run
+				result.Status = run.Status
+			}
+		}
+		if doc.Error != "" {
+			result.Error = doc.Error
+		}
+	}
+	if runErr != nil {
+		result.ExitCode = exitFailure
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) && exitErr.ExitCode() > 0 {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		if result.Status == summary.StatusSucceeded {
+			result.Status = summary.StatusFailed
+		}
+		if result.Error == "" {
+			result.Error = redact.Error(runErr)
+		}
+	}
+	return result
+}
+
+// printBatchResults prints one line per run of a batch, with the repository of runs of an issue
+// that spans several.
+func printBatchResults(w io.Writer, results []issueRunResult) {
+	multiRepo := slices.ContainsFunc(results, func(result issueRunResult) bool { return result.Repo != "" })
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if multiRepo {
+		fmt.Fprintln(tw, "\nISSUE\tREPO\tSTATUS\tDURATION\tRESULT")
+	} else {
+		fmt.Fprintln(tw, "\nISSUE\tSTATUS\tDURATION\tRESULT")
+	}
+	for _, result := range results {
+		duration, outcome := "-", result.Error
+		if result.Summary != nil {
+			duration = summary.FormatSeconds(result.Summary.DurationSeconds)
+			if result.Status == summary.StatusSucceeded {
+				outcome = result.Summary.PRURL
+			}
+		}
+		if multiRepo {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.IssueID, extractRepoName(result.Repo), result.Status, duration, dashIfEmpty(outcome))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.IssueID, result.Status, duration, dashIfEmpty(outcome))
+	}
+	tw.Flush()
+}
+
+// prefixWriter writes whole lines to out, each starting with prefix. Writers sharing mu never
+// interleave within a line.
+type prefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+		p.writeLine(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// Flush writes a trailing partial line.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.out.Write(append([]byte(p.prefix), line...))
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"monday/summary"
+)
+
+// TestBatchHelperProcess stands in for the monday process of one issue in runBatch tests.
+func TestBatchHelperProcess(t *testing.T) {
+	if os.Getenv("MONDAY_BATCH_HELPER") != "1" {
+		return
+	}
+	args := os.Args[len(os.Args)-4:]
+	issueID, repoFlag := args[0], args[1]
+	fmt.Fprintf(os.Stderr, "working on %s with %s\npartial line", issueID, repoFlag)
+
+	s := summary.New("run-"+issueID, issueID, "repo")
+	if issueID == "DEL-2" {
+		s.Finish(fmt.Errorf("failed to run Codex"))
+		json.NewEncoder(os.Stdout).Encode(s)
+		json.NewEncoder(os.Stdout).Encode(map[string]string{"error": "failed to run Codex"})
+		os.Exit(exitAgentFailed)
+	}
+	if issueID == "DEL-3" {
+		os.Exit(2)
+	}
+	s.PRURL = "https://github.com/acme/app/pull/1"
+	s.Finish(nil)
+	json.NewEncoder(os.Stdout).Encode(s)
+	os.Exit(0)
+}
+
+func TestRunBatch(t *testing.T) {
+	start := func(ctx context.Context, args []string) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, os.Args[0], append([]string{"-test.run=TestBatchHelperProcess", "--"}, args...)...)
+		cmd.Env = append(os.Environ(), "MONDAY_BATCH_HELPER=1")
+		return cmd
+	}
+	var out bytes.Buffer
+
+	results := runBatch(context.Background(), []batchJob{
+		{IssueID: "DEL-1", Flags: []string{"--repo-url=x"}},
+		{IssueID: "DEL-2", Flags: []string{"--repo-url=x"}},
+		{IssueID: "DEL-3", Flags: []string{"--repo-url=x"}},
+	}, 2, start, &out)
+
+	if len(results) != 3 {
+		t.Fatalf("runBatch() returned %d results, want 3", len(results))
+	}
+	if results[0].Status != summary.StatusSucceeded || results[0].Summary == nil || results[0].Summary.PRURL == "" {
+		t.Errorf("results[0] = %+v, want a succeeded run with a pull request", results[0])
+	}
+	if results[0].ExitCode != 0 {
+		t.Errorf("results[0].ExitCode = %d, want 0", results[0].ExitCode)
+	}
+	if results[1].Status != summary.StatusFailed || results[1].Error != "failed to run Codex" || results[1].ExitCode != exitAgentFailed {
+		t.Errorf("results[1] = %+v, want the run's failure", results[1])
+	}
+	if results[2].Status != summary.StatusFailed || results[2].Summary != nil || results[2].Error == "" || results[2].ExitCode != 2 {
+		t.Errorf("results[2] = %+v, want a failure without a summary", results[2])
+	}
+	for _, want := range []string{"[DEL-1] working on DEL-1 with --repo-url=x\n", "[DEL-2] partial line\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestUniqueIssueIDs(t *testing.T) {
+	got := uniqueIssueIDs([]string{"DEL-1", "https://linear.app/acme/issue/DEL-1/title", "del-2", "DEL-2", "DEL-3"})
+	want := []string{"DEL-1", "del-2", "DEL-3"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("uniqueIssueIDs() = %v, want %v", got, want)
+	}
+}
+
+func TestForwardedFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("monday", pflag.ContinueOnError)
+	flags.String("repo-url", "", "")
+	flags.Bool("rollback", false, "")
+	flags.Bool("no-mirror", false, "")
+	flags.Int("concurrency", 2, "")
+	flags.String("team", "", "")
+	flags.Int("max", 0, "")
+	if err := flags.Parse([]string{"--repo-url", "https://github.com/acme/app", "--rollback", "--concurrency", "4", "--team", "DEL", "--max", "10"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := forwardedFlags(flags)
+	want := []string{"--repo-url=https://github.com/acme/app", "--rollback=true"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("forwardedFlags() = %v, want %v", got, want)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{out: &out, mu: &sync.Mutex{}, prefix: "[DEL-1] "}
+	w.Write([]byte("first\nsec"))
+	w.Write([]byte("ond\nthird"))
+	w.Flush()
+
+	if want := "[DEL-1] first\n[DEL-1] second\n[DEL-1] third\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/summary"
+)
+
+// cancelRequestFile is created in a run's directory to ask the run to stop.
+const cancelRequestFile = "cancel"
+
+// errRunCanceled is returned by runs that were cancelled.
+var errRunCanceled = fmt.Errorf("run canceled: %w", context.Canceled)
+
+var cancelWait time.Duration
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <run-id>",
+	Short: "Cancel an in-flight run",
+	Long: `Cancel an in-flight run of the CLI or the server on this machine. The run kills the
+agent, removes the worktree or clone and any branches it created, and moves the Linear issue
+back to the state it had before the run. Run IDs are listed by monday status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCancel,
+}
+
+func init() {
+	cancelCmd.Flags().DurationVar(&cancelWait, "wait", time.Minute, "How long to wait for the run to stop (0 to return immediately)")
+	rootCmd.AddCommand(cancelCmd)
+}
+
+func runCancel(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	dir, err := runDir(runID)
+	if err != nil {
+		return err
+	}
+	if err := requestCancel(dir, processAlive); err != nil {
+		return err
+	}
+	fmt.Printf("🛑 Cancellation of %s requested\n", runID)
+	if cancelWait <= 0 {
+		if jsonOutput() {
+			return writeJSON(map[string]string{"run_id": runID, "status": "cancel_requested"})
+		}
+		return nil
+	}
+
+	deadline := time.Now().Add(cancelWait)
+	for time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+		run, err := summary.Load(filepath.Join(dir, "summary.json"))
+		if err == nil && run.Status != summary.StatusRunning {
+			fmt.Printf("✅ Run %s stopped: %s\n", runID, run.Status)
+			if jsonOutput() {
+				return writeJSON(run)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("run %s did not stop within %s", runID, cancelWait)
+}
+
+// requestCancel asks the run recorded in dir to stop. It fails when the run is not in flight;
+// alive reports whether the run's process still exists.
+func requestCancel(dir string, alive func(pid int) bool) error {
+	run, err := summary.Load(filepath.Join(dir, "summary.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("run %s not found", filepath.Base(dir))
+	}
+	if err != nil {
+		return err
+	}
+	if run.Status != summary.StatusRunning {
+		return fmt.Errorf("run %s is not running: %s", run.RunID, run.Status)
+	}
+	if run.PID == 0 || !alive(run.PID) {
+		return fmt.Errorf("run %s is not running: its process has exited", run.RunID)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, cancelRequestFile), []byte(time.Now().Format(time.RFC3339)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to request cancellation: %w", err)
+	}
+	return nil
+}
+
+// watchCancelRequest calls cancel once a cancellation of the run in dir is requested with
+// monday cancel. It checks every interval and returns when ctx is done. A canceled run kills its
+// agent, rolls back its workspace, and moves its issue back to the workflow state it had before
+// the run.
+func watchCancelRequest(ctx context.Context, dir string, cancel context.CancelFunc, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := os.Stat(filepath.Join(dir, cancelRequestFile)); err == nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// checkCanceled returns errRunCanceled once ctx is done.
+func checkCanceled(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errRunCanceled
+	}
+	return nil
+}
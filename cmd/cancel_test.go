@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"monday/summary"
+)
+
+func TestRequestCancel(t *testing.T) {
+	running := summary.New("run-running", "DEL-1", "repo")
+	running.PID = 100
+	finished := summary.New("run-finished", "DEL-1", "repo")
+	finished.Finish(nil)
+	exited := summary.New("run-exited", "DEL-1", "repo")
+	exited.PID = 200
+
+	tests := []struct {
+		name    string
+		run     *summary.Summary
+		wantErr string
+	}{
+		{name: "running", run: running},
+		{name: "finished", run: finished, wantErr: "is not running: succeeded"},
+		{name: "process exited", run: exited, wantErr: "process has exited"},
+		{name: "unknown run", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "run")
+			if tt.run != nil {
+				if _, err := tt.run.WriteFiles(dir); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := requestCancel(dir, func(pid int) bool { return pid == 100 })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("requestCancel() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requestCancel() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, cancelRequestFile)); err != nil {
+				t.Errorf("cancel request not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestWatchCancelRequest(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		watchCancelRequest(ctx, dir, cancel, 10*time.Millisecond)
+		close(done)
+	}()
+
+	if err := os.WriteFile(filepath.Join(dir, cancelRequestFile), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchCancelRequest() did not notice the cancel request")
+	}
+	if !errors.Is(checkCanceled(ctx), context.Canceled) {
+		t.Errorf("checkCanceled() = %v, want a cancellation", checkCanceled(ctx))
+	}
+}
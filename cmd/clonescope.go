@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"monday/gitops"
+)
+
+var (
+	// cloneDepth truncates the history of the run's working copy to that many commits.
+	cloneDepth int
+	// singleBranch fetches only the base branch, not a previously pushed issue branch.
+	singleBranch bool
+	// sparsePaths restricts the run's working copy to the files matching these patterns.
+	sparsePaths []string
+)
+
+func init() {
+	rootCmd.PersistentFlags().IntVar(&cloneDepth, "clone-depth", 0, "Truncate the history of the run's clone or worktree to this many commits (default: full history)")
+	rootCmd.PersistentFlags().BoolVar(&singleBranch, "single-branch", false, "Fetch only the base branch, not the issue branch pushed by an earlier run")
+	rootCmd.PersistentFlags().StringSliceVar(&sparsePaths, "sparse-paths", nil, "Only check out files matching these gitignore-style patterns, e.g. services/foo/**; repeat it or separate patterns with commas")
+}
+
+// validateCloneScope checks that the clone scope flags can be used together.
+func validateCloneScope() error {
+	if cloneDepth < 0 {
+		return fmt.Errorf("--clone-depth must not be negative")
+	}
+	if singleBranch && fullFetch {
+		return fmt.Errorf("--single-branch cannot be used with --full-fetch")
+	}
+	return nil
+}
+
+// runFetchScope returns what the run fetches from origin: base and branch, or only base with
+// --single-branch, truncated to --clone-depth.
+func runFetchScope(base, branch string) gitops.FetchScope {
+	scope := gitops.FetchScope{Branches: []string{base, branch}, Filter: cloneFilter, Depth: cloneDepth}
+	if singleBranch {
+		scope.Branches = scope.Branches[:1]
+	}
+	return scope
+}
+
+// containerCloneArgs returns the flags of the clone made inside the run's container.
+func containerCloneArgs() []string {
+	var args []string
+	if cloneFilter != "" {
+		args = append(args, "--filter="+cloneFilter)
+	}
+	if cloneDepth > 0 {
+		args = append(args, "--depth", strconv.Itoa(cloneDepth))
+	}
+	if len(sparsePaths) > 0 {
+		args = append(args, "--sparse")
+	}
+	return args
+}
+
+// applySparsePaths restricts the working tree in dir to --sparse-paths, if set.
+func applySparsePaths(ctx context.Context, log *zap.Logger, dir string) error {
+	if len(sparsePaths) == 0 {
+		return nil
+	}
+	progressf("   limiting checkout to %d sparse paths\n", len(sparsePaths))
+	args := append([]string{"sparse-checkout", "set", "--no-cone"}, sparsePaths...)
+	if err := runGitCommand(ctx, log, dir, args...); err != nil {
+		return fmt.Errorf("failed to set sparse checkout: %w", err)
+	}
+	return nil
+}
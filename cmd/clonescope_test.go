@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestValidateCloneScope(t *testing.T) {
+	origDepth, origSingle, origFull := cloneDepth, singleBranch, fullFetch
+	t.Cleanup(func() { cloneDepth, singleBranch, fullFetch = origDepth, origSingle, origFull })
+
+	tests := []struct {
+		name    string
+		depth   int
+		single  bool
+		full    bool
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "depth and single branch", depth: 1, single: true},
+		{name: "negative depth", depth: -1, wantErr: true},
+		{name: "single branch with full fetch", single: true, full: true, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cloneDepth, singleBranch, fullFetch = test.depth, test.single, test.full
+			if err := validateCloneScope(); (err != nil) != test.wantErr {
+				t.Errorf("validateCloneScope() error = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunFetchScope(t *testing.T) {
+	origDepth, origSingle := cloneDepth, singleBranch
+	t.Cleanup(func() { cloneDepth, singleBranch = origDepth, origSingle })
+
+	cloneDepth, singleBranch = 5, false
+	scope := runFetchScope("main", "feature/del-1")
+	if !reflect.DeepEqual(scope.Branches, []string{"main", "feature/del-1"}) || scope.Depth != 5 {
+		t.Errorf("runFetchScope() = %+v, want main and feature/del-1 at depth 5", scope)
+	}
+
+	singleBranch = true
+	if scope := runFetchScope("main", "feature/del-1"); !reflect.DeepEqual(scope.Branches, []string{"main"}) {
+		t.Errorf("runFetchScope() with --single-branch fetches %v, want only main", scope.Branches)
+	}
+}
+
+func TestApplySparsePaths(t *testing.T) {
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+	for _, name := range []string{"README.md", "services/foo/main.go", "services/bar/main.go"} {
+		if err := os.MkdirAll(filepath.Join(repo, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, repo, "add", ".")
+	git(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init")
+
+	orig := sparsePaths
+	t.Cleanup(func() { sparsePaths = orig })
+	sparsePaths = []string{"services/foo/**"}
+
+	if err := applySparsePaths(context.Background(), zap.NewNop(), repo); err != nil {
+		t.Fatalf("applySparsePaths() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "services", "foo", "main.go")); err != nil {
+		t.Errorf("sparse path was not checked out: %v", err)
+	}
+	for _, name := range []string{"README.md", "services/bar/main.go"} {
+		if _, err := os.Stat(filepath.Join(repo, name)); !os.IsNotExist(err) {
+			t.Errorf("%s is checked out outside the sparse paths", name)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/redact"
+	"monday/retry"
+	"monday/vcs"
+)
+
+// codeHost is the service hosting the repository of a run, on which the run opens its pull
+// request (a merge request on GitLab).
+type codeHost interface {
+	// create opens the request cr and returns its URL
+	create(ctx context.Context, log *zap.Logger, cr vcs.ChangeRequest) (string, error)
+	// open returns the URL of the open request from branch, or "" if there is none
+	open(ctx context.Context, branch string) (string, error)
+	// close closes the request at url, commenting on it first
+	close(ctx context.Context, url, comment string) error
+	// appendToBody appends text to the description of the request at url
+	appendToBody(ctx context.Context, url, text string) error
+	// createCommand returns the command a dry run shows for creating cr
+	createCommand(cr vcs.ChangeRequest) []string
+	// refreshCredentials renews the credentials git pushes with if they are about to expire
+	refreshCredentials(ctx context.Context) error
+}
+
+// newCodeHost returns the host of repoURL or, for --local-repo, of the origin of localRepo.
+// Repositories on GitLab.com or on the instance at GITLAB_URL need GITLAB_TOKEN, which git is
+// also configured to authenticate with; all others are on GitHub and need GITHUB_TOKEN or a
+// GitHub App, whose installation token git is configured to authenticate with.
+func newCodeHost(ctx context.Context, repoURL, localRepo string) (codeHost, error) {
+	remote := repoURL
+	if localRepo != "" {
+		out, err := exec.CommandContext(ctx, "git", "-C", localRepo, "remote", "get-url", "origin").Output()
+		if err == nil {
+			remote = strings.TrimSpace(string(out))
+		}
+	}
+
+	if !vcs.IsGitLab(remote, os.Getenv("GITLAB_URL")) {
+		app, err := githubApp()
+		if err != nil {
+			return nil, err
+		}
+		token := os.Getenv("GITHUB_TOKEN")
+		if app == nil && token == "" {
+			return nil, fmt.Errorf("GITHUB_TOKEN environment variable is required, or a GitHub App with GITHUB_APP_ID")
+		}
+		retries, err := retryPolicy()
+		if err != nil {
+			return nil, err
+		}
+		host := &githubHost{token: token, repo: vcs.GitHubRepo(remote), retries: retries, app: app}
+		if app != nil {
+			if host.repo == "" {
+				return nil, fmt.Errorf("cannot tell the GitHub repository of %q to authenticate the GitHub App to", remote)
+			}
+			if _, err := host.credentials(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return host, nil
+	}
+
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITLAB_TOKEN environment variable is required for GitLab repositories")
+	}
+	baseURL, project, err := vcs.ParseGitLabRepo(remote)
+	if err != nil {
+		return nil, err
+	}
+	// A self-hosted instance may serve its API on another scheme or port than the clone URL.
+	if instance, err := url.Parse(os.Getenv("GITLAB_URL")); err == nil && instance.Host != "" &&
+		strings.EqualFold(instance.Hostname(), strings.TrimPrefix(baseURL, "https://")) {
+		baseURL = strings.TrimSuffix(instance.String(), "/")
+	}
+	key, value := vcs.GitAuthConfig(baseURL, token)
+	redact.AddSecrets(strings.TrimPrefix(value, "Authorization: Basic "))
+	addGitConfigEnv(key, value)
+	return &gitlabHost{client: vcs.NewGitLab(baseURL, project, token), project: project}, nil
+}
+
+// addGitConfigEnv sets key to value in the configuration of the git commands monday runs,
+// through the GIT_CONFIG_* environment variables, unless it is set already.
+func addGitConfigEnv(key, value string) {
+	n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	for i := 0; i < n; i++ {
+		if os.Getenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", i)) == key && os.Getenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", i)) == value {
+			return
+		}
+	}
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", n), key)
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", n), value)
+	os.Setenv("GIT_CONFIG_COUNT", strconv.Itoa(n+1))
+}
+
+// changeRequest returns the pull request of issue from branch, describing the changes with
+// the summary of the diff in changes, if not empty.
+func changeRequest(issue *issues.Issue, branch, changes string) vcs.ChangeRequest {
+	body := issue.Description
+	if changes != "" {
+		body += "\n\n" + changes
+	}
+	return vcs.ChangeRequest{
+		Title:        commitSubject(issue),
+		Body:         fmt.Sprintf("%s\n\n%s", body, issueReference(issue)),
+		SourceBranch: branch,
+		TargetBranch: runBaseBranch(),
+		Draft:        draftPR,
+	}
+}
+
+// githubHost opens pull requests on repo, in HOST/OWNER/REPO form, with the gh CLI, retrying
+// failed gh commands with retries. With app, gh authenticates as the GitHub App instead of with
+// token.
+type githubHost struct {
+	token   string
+	repo    string
+	retries retry.Policy
+	app     *vcs.GitHubApp
+}
+
+// gh returns the gh command with args, working on the repository of h rather than on that of
+// the working directory.
+func (h *githubHost) gh(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	token, err := h.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("GITHUB_TOKEN=%s", token))
+	if h.repo != "" {
+		cmd.Env = append(cmd.Env, "GH_REPO="+h.repo)
+	}
+	return cmd, nil
+}
+
+func (h *githubHost) create(ctx context.Context, log *zap.Logger, cr vcs.ChangeRequest) (string, error) {
+	log.Info("Creating PR", zap.String("title", cr.Title))
+	var stdout bytes.Buffer
+	err := h.retries.Do(ctx, func() error {
+		stdout.Reset()
+		cmd, err := h.gh(ctx, pullRequestArgs(cr)...)
+		if err != nil {
+			return err
+		}
+		cmd = interruptOnCancel(cmd)
+		cmd.Stdout = &stdout
+		return runWithRedactedOutput(cmd, showChildStdout(), showChildStderr())
+	}, logRetry(log, "gh pr create"))
+	if err != nil {
+		return "", err
+	}
+	return pullRequestURL(stdout.String()), nil
+}
+
+func (h *githubHost) open(ctx context.Context, branch string) (string, error) {
+	var out []byte
+	err := h.retries.Do(ctx, func() (err error) {
+		cmd, err := h.gh(ctx, "pr", "list", "--head", branch, "--state", "open", "--json", "url", "--jq", ".[0].url // empty")
+		if err != nil {
+			return err
+		}
+		out, err = cmd.Output()
+		return err
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (h *githubHost) close(ctx context.Context, prURL, comment string) error {
+	cmd, err := h.gh(ctx, "pr", "close", prURL, "--comment", comment)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, redact.String(strings.TrimSpace(string(out))))
+	}
+	return nil
+}
+
+func (h *githubHost) appendToBody(ctx context.Context, prURL, text string) error {
+	cmd, err := h.gh(ctx, "pr", "view", prURL, "--json", "body", "--jq", ".body")
+	if err != nil {
+		return err
+	}
+	body, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to view pull request: %w", err)
+	}
+	if cmd, err = h.gh(ctx, "pr", "edit", prURL, "--body", strings.TrimRight(string(body), "\n")+text); err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to edit pull request: %w: %s", err, redact.String(strings.TrimSpace(string(out))))
+	}
+	return nil
+}
+
+func (h *githubHost) createCommand(cr vcs.ChangeRequest) []string {
+	return append([]string{"gh"}, pullRequestArgs(cr)...)
+}
+
+func (h *githubHost) refreshCredentials(ctx context.Context) error {
+	_, err := h.credentials(ctx)
+	return err
+}
+
+// pullRequestArgs returns the gh arguments that create the pull request cr.
+func pullRequestArgs(cr vcs.ChangeRequest) []string {
+	args := []string{"pr", "create", "--title", cr.Title, "--body", cr.Body, "--head", cr.SourceBranch}
+	if cr.TargetBranch != "" {
+		args = append(args, "--base", cr.TargetBranch)
+	}
+	if cr.Draft {
+		args = append(args, "--draft")
+	}
+	for _, label := range cr.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, reviewer := range cr.Reviewers {
+		args = append(args, "--reviewer", reviewer)
+	}
+	for _, assignee := range cr.Assignees {
+		args = append(args, "--assignee", assignee)
+	}
+	if cr.Milestone != "" {
+		args = append(args, "--milestone", cr.Milestone)
+	}
+	return args
+}
+
+// pullRequestURL extracts the pull request URL that `gh pr create` prints as its last line.
+func pullRequestURL(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "https://") {
+			return line
+		}
+	}
+	return ""
+}
+
+// gitlabHost opens merge requests through the GitLab API.
+type gitlabHost struct {
+	client  *vcs.GitLab
+	project string
+}
+
+func (h *gitlabHost) create(ctx context.Context, log *zap.Logger, cr vcs.ChangeRequest) (string, error) {
+	log.Info("Creating MR", zap.String("title", cr.Title), zap.String("project", h.project))
+	return h.client.CreateMergeRequest(ctx, cr)
+}
+
+func (h *gitlabHost) open(ctx context.Context, branch string) (string, error) {
+	return h.client.OpenMergeRequest(ctx, branch)
+}
+
+func (h *gitlabHost) close(ctx context.Context, mrURL, comment string) error {
+	return h.client.CloseMergeRequest(ctx, mrURL, comment)
+}
+
+func (h *gitlabHost) appendToBody(ctx context.Context, mrURL, text string) error {
+	return h.client.AppendToMergeRequest(ctx, mrURL, text)
+}
+
+func (h *gitlabHost) createCommand(cr vcs.ChangeRequest) []string {
+	form := url.Values{"source_branch": {cr.SourceBranch}, "title": {cr.Title}, "description": {cr.Body}}
+	if cr.Draft {
+		form.Set("title", "Draft: "+cr.Title)
+	}
+	if cr.TargetBranch != "" {
+		form.Set("target_branch", cr.TargetBranch)
+	}
+	if len(cr.Labels) > 0 {
+		form.Set("labels", strings.Join(cr.Labels, ","))
+	}
+	return []string{"curl", "--request", "POST", "--header", "PRIVATE-TOKEN: $GITLAB_TOKEN", "--data", form.Encode(), h.client.MergeRequestsURL()}
+}
+
+// refreshCredentials does nothing: GITLAB_TOKEN does not expire during a run.
+func (h *gitlabHost) refreshCredentials(ctx context.Context) error {
+	return nil
+}
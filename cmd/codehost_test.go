@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"monday/vcs"
+)
+
+func TestNewCodeHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoURL   string
+		gitlabURL string
+		want      string
+		wantMR    string
+	}{
+		{name: "github", repoURL: "https://github.com/acme/app", want: "github"},
+		{name: "gitlab.com", repoURL: "https://gitlab.com/acme/app.git", want: "gitlab", wantMR: "https://gitlab.com/api/v4/projects/acme%2Fapp/merge_requests"},
+		{name: "self-hosted", repoURL: "git@gitlab.acme.dev:platform/app.git", gitlabURL: "http://gitlab.acme.dev:8080", want: "gitlab", wantMR: "http://gitlab.acme.dev:8080/api/v4/projects/platform%2Fapp/merge_requests"},
+		{name: "unknown self-hosted", repoURL: "https://gitlab.acme.dev/platform/app", want: "github"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", "ghp_token")
+			t.Setenv("GITLAB_TOKEN", "glpat-token")
+			t.Setenv("GITLAB_URL", tt.gitlabURL)
+			t.Setenv("GIT_CONFIG_COUNT", "")
+			t.Setenv("GIT_CONFIG_KEY_0", "")
+			t.Setenv("GIT_CONFIG_VALUE_0", "")
+
+			host, err := newCodeHost(context.Background(), tt.repoURL, "")
+			if err != nil {
+				t.Fatalf("newCodeHost() error = %v", err)
+			}
+			switch h := host.(type) {
+			case *githubHost:
+				if tt.want != "github" {
+					t.Errorf("newCodeHost(%q) = GitHub, want %s", tt.repoURL, tt.want)
+				}
+				cmd, err := h.gh(context.Background(), "pr", "list")
+				if err != nil {
+					t.Fatalf("gh() error = %v", err)
+				}
+				if !slices.Contains(cmd.Env, "GH_REPO="+vcs.GitHubRepo(tt.repoURL)) {
+					t.Errorf("gh command does not target %s", tt.repoURL)
+				}
+			case *gitlabHost:
+				if tt.want != "gitlab" {
+					t.Errorf("newCodeHost(%q) = GitLab, want %s", tt.repoURL, tt.want)
+				}
+				if got := h.client.MergeRequestsURL(); got != tt.wantMR {
+					t.Errorf("MergeRequestsURL() = %q, want %q", got, tt.wantMR)
+				}
+				if os.Getenv("GIT_CONFIG_COUNT") != "1" || !strings.HasSuffix(os.Getenv("GIT_CONFIG_KEY_0"), "/.extraHeader") {
+					t.Errorf("git is not configured to authenticate to GitLab: count %q, key %q", os.Getenv("GIT_CONFIG_COUNT"), os.Getenv("GIT_CONFIG_KEY_0"))
+				}
+			}
+		})
+	}
+}
+
+func TestNewCodeHostRequiresToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_token")
+	t.Setenv("GITLAB_TOKEN", "")
+
+	_, err := newCodeHost(context.Background(), "https://gitlab.com/acme/app", "")
+	if err == nil || !strings.Contains(err.Error(), "GITLAB_TOKEN") {
+		t.Errorf("newCodeHost() error = %v, want one about GITLAB_TOKEN", err)
+	}
+}
+
+func TestAddGitConfigEnvIsIdempotent(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "core.autocrlf")
+	t.Setenv("GIT_CONFIG_VALUE_0", "false")
+	t.Setenv("GIT_CONFIG_KEY_1", "")
+	t.Setenv("GIT_CONFIG_VALUE_1", "")
+
+	addGitConfigEnv("http.https://gitlab.com/.extraHeader", "Authorization: Basic abc")
+	addGitConfigEnv("http.https://gitlab.com/.extraHeader", "Authorization: Basic abc")
+
+	if got := os.Getenv("GIT_CONFIG_COUNT"); got != "2" {
+		t.Errorf("GIT_CONFIG_COUNT = %q, want %q", got, "2")
+	}
+	if got := os.Getenv("GIT_CONFIG_VALUE_1"); got != "Authorization: Basic abc" {
+		t.Errorf("GIT_CONFIG_VALUE_1 = %q, want %q", got, "Authorization: Basic abc")
+	}
+}
+
+func TestGitLabDryRunCommand(t *testing.T) {
+	host := &gitlabHost{client: vcs.NewGitLab("https://gitlab.com", "acme/app", "token")}
+	command := host.createCommand(vcs.ChangeRequest{Title: "feat: Fix login", SourceBranch: "feature/del_163", Draft: true})
+
+	got := strings.Join(command, " ")
+	for _, want := range []string{"curl --request POST", "PRIVATE-TOKEN: $GITLAB_TOKEN", "title=Draft%3A+feat%3A+Fix+login", "https://gitlab.com/api/v4/projects/acme%2Fapp/merge_requests"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("createCommand() = %q, missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/redact"
+	"monday/summary"
+)
+
+// maxCommentFiles caps the files listed in a completion comment; the rest are counted.
+const maxCommentFiles = 10
+
+// postCompletionComment posts the completion comment of the run described by sum on the issue.
+// Failures are logged and otherwise ignored: the pull request already exists.
+func postCompletionComment(ctx context.Context, log *zap.Logger, client issues.Provider, issue *issues.Issue, sum *summary.Summary) {
+	body := completionComment(sum, diffShortStat(sum.Workspace), time.Since(sum.StartedAt))
+	if err := client.CreateComment(ctx, issue, redact.String(body)); err != nil {
+		log.Warn("Failed to post completion comment", zap.Error(err))
+		return
+	}
+	log.Info("Posted completion comment")
+}
+
+// completionComment renders the Linear comment announcing the pull request of a finished run:
+// the pull request and branch, the changes, and what the run took.
+func completionComment(sum *summary.Summary, diffStat string, elapsed time.Duration) string {
+	var b strings.Builder
+	b.WriteString("**Monday opened a pull request for this issue.**\n\n")
+	fmt.Fprintf(&b, "- **Pull request:** %s\n", sum.PRURL)
+	fmt.Fprintf(&b, "- **Branch:** `%s`\n", sum.Branch)
+	if diffStat != "" {
+		fmt.Fprintf(&b, "- **Changes:** %s\n", diffStat)
+	}
+	fmt.Fprintf(&b, "- **Duration:** %s\n", summary.FormatSeconds(elapsed.Seconds()))
+	if sum.AgentCostUSD != nil {
+		fmt.Fprintf(&b, "- **Agent cost:** $%.2f\n", *sum.AgentCostUSD)
+	}
+	if sum.AgentInputTokens > 0 || sum.AgentOutputTokens > 0 {
+		fmt.Fprintf(&b, "- **Agent tokens:** %d in, %d out\n", sum.AgentInputTokens, sum.AgentOutputTokens)
+	}
+	fmt.Fprintf(&b, "- **Run:** `%s`\n", sum.RunID)
+
+	if len(sum.FilesChanged) > 0 {
+		b.WriteString("\n**Files changed**\n\n")
+		for i, file := range sum.FilesChanged {
+			if i == maxCommentFiles {
+				fmt.Fprintf(&b, "- …and %d more\n", len(sum.FilesChanged)-maxCommentFiles)
+				break
+			}
+			fmt.Fprintf(&b, "- `%s`\n", file)
+		}
+	}
+	return b.String()
+}
+
+// failedRunComment renders the Linear comment explaining why a run failed and what it left in
+// place outside this machine: the pushed branch, the pull request, and the moved issue.
+func failedRunComment(sum *summary.Summary, runErr error, pushed bool, movedFrom issues.State) string {
+	var b strings.Builder
+	b.WriteString("**Monday could not finish this issue.**\n\n")
+	if stage := sum.FailedStage(); stage != "" {
+		fmt.Fprintf(&b, "- **Failed stage:** %s\n", stage)
+	}
+	fmt.Fprintf(&b, "- **Error:** %s\n", runErr)
+	fmt.Fprintf(&b, "- **Run:** `%s`\n", sum.RunID)
+
+	var left []string
+	if pushed {
+		left = append(left, fmt.Sprintf("Branch `%s`, pushed to origin", sum.Branch))
+	}
+	if sum.PRURL != "" {
+		left = append(left, "Pull request "+sum.PRURL)
+	}
+	if movedFrom.ID != "" {
+		left = append(left, fmt.Sprintf("This issue, moved from %s to In Progress", movedFrom.Name))
+	}
+	if len(left) > 0 {
+		b.WriteString("\n**Left in place**\n\n")
+		for _, item := range left {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+// diffShortStat returns git's one-line summary of the commit at HEAD of the repository in dir,
+// such as "3 files changed, 40 insertions(+), 2 deletions(-)", or the empty string.
+func diffShortStat(dir string) string {
+	out, err := gitCommand(dir, "show", "--shortstat", "--format=", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"monday/linear"
+	"monday/summary"
+)
+
+func TestCompletionComment(t *testing.T) {
+	cost := 1.234
+	sum := summary.New("run-1", "DEL-1", "https://github.com/acme/app")
+	sum.Branch = "del-1-fix"
+	sum.PRURL = "https://github.com/acme/app/pull/7"
+	sum.AgentCostUSD = &cost
+	sum.AgentInputTokens = 100
+	sum.AgentOutputTokens = 20
+	sum.FilesChanged = []string{"main.go", "main_test.go"}
+
+	got := completionComment(sum, "2 files changed, 10 insertions(+)", 90*time.Second)
+	for _, want := range []string{
+		"- **Pull request:** https://github.com/acme/app/pull/7\n",
+		"- **Branch:** `del-1-fix`\n",
+		"- **Changes:** 2 files changed, 10 insertions(+)\n",
+		"- **Duration:** 1m30s\n",
+		"- **Agent cost:** $1.23\n",
+		"- **Agent tokens:** 100 in, 20 out\n",
+		"- `main_test.go`\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("completionComment() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestCompletionCommentOmitsUnknowns(t *testing.T) {
+	sum := summary.New("run-1", "DEL-1", "repo")
+	for i := 0; i < maxCommentFiles+3; i++ {
+		sum.FilesChanged = append(sum.FilesChanged, fmt.Sprintf("file%d.go", i))
+	}
+
+	got := completionComment(sum, "", time.Second)
+	for _, unwanted := range []string{"Changes:", "Agent cost:", "Agent tokens:", "file10.go"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("completionComment() contains %q:\n%s", unwanted, got)
+		}
+	}
+	if !strings.Contains(got, "- …and 3 more\n") {
+		t.Errorf("completionComment() does not count the omitted files:\n%s", got)
+	}
+}
+
+func TestFailedRunComment(t *testing.T) {
+	sum := summary.New("run-1", "DEL-1", "repo")
+	sum.Branch = "del-1-fix"
+	sum.PRURL = "https://github.com/acme/app/pull/7"
+	sum.StartStage("create_pr")(errors.New("gh failed"))
+
+	got := failedRunComment(sum, errors.New("gh failed"), true, linear.WorkflowState{ID: "s1", Name: "Todo"})
+	for _, want := range []string{
+		"- **Failed stage:** create_pr\n",
+		"- **Error:** gh failed\n",
+		"- **Run:** `run-1`\n",
+		"- Branch `del-1-fix`, pushed to origin\n",
+		"- Pull request https://github.com/acme/app/pull/7\n",
+		"- This issue, moved from Todo to In Progress\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("failedRunComment() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestFailedRunCommentNothingLeft(t *testing.T) {
+	sum := summary.New("run-1", "DEL-1", "repo")
+
+	got := failedRunComment(sum, errors.New("boom"), false, linear.WorkflowState{})
+	if strings.Contains(got, "Left in place") {
+		t.Errorf("failedRunComment() lists leftovers of a run that left none:\n%s", got)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"monday/issues"
+)
+
+// commitTypeFlag overrides the conventional commit type of the run's commit and pull request.
+var commitTypeFlag string
+
+func init() {
+	rootCmd.Flags().StringVar(&commitTypeFlag, "commit-type", "", "Conventional commit type of the commit and pull request title, e.g. fix or chore (default: $MONDAY_COMMIT_TYPE or inferred from the issue's labels and title)")
+}
+
+// commitTypes are the conventional commit types --commit-type accepts.
+var commitTypes = []string{"feat", "fix", "chore", "refactor", "docs", "test", "perf", "build", "ci", "style", "revert"}
+
+// labelCommitTypes maps issue labels, lowercased, to the commit type they imply. Types are
+// checked in the order of inferredTypes, so an issue labeled both Bug and Feature is a fix.
+var labelCommitTypes = map[string][]string{
+	"fix":      {"bug", "defect", "fix", "regression", "hotfix"},
+	"refactor": {"refactor", "refactoring", "cleanup", "clean up"},
+	"chore":    {"chore", "maintenance", "tech debt", "dependencies", "deps"},
+	"feat":     {"feature", "enhancement", "improvement", "new feature"},
+}
+
+// titleCommitTypes matches the words of issue titles that imply a commit type.
+var titleCommitTypes = map[string]*regexp.Regexp{
+	"fix":      regexp.MustCompile(`(?i)\b(fix(es|ed)?|bug|crash(es|ing)?|broken|regression|fails?|failing)\b`),
+	"refactor": regexp.MustCompile(`(?i)\b(refactor(ing)?|clean ?up|restructure|simplify|rename)\b`),
+	"chore":    regexp.MustCompile(`(?i)\b(bump|upgrade|update (the )?dependenc(y|ies)|chore|deprecat(e|ion))\b`),
+}
+
+// inferredTypes are the types inference picks from, in order of precedence.
+var inferredTypes = []string{"fix", "refactor", "chore", "feat"}
+
+// conventionalTitle matches titles that already are conventional commit subjects, such as
+// "fix(auth): Reject expired sessions".
+var conventionalTitle = regexp.MustCompile(`^[a-z]+(\([^)]*\))?!?: \S`)
+
+// resolveCommitType returns the commit type given with --commit-type or MONDAY_COMMIT_TYPE,
+// or "" to infer it from the issue.
+func resolveCommitType() (string, error) {
+	value := commitTypeFlag
+	if value == "" {
+		value = os.Getenv("MONDAY_COMMIT_TYPE")
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value != "" && !slices.Contains(commitTypes, value) {
+		return "", fmt.Errorf("invalid commit type %q: use one of %s", value, strings.Join(commitTypes, ", "))
+	}
+	return value, nil
+}
+
+// commitType returns the conventional commit type of the changes made for issue: the one
+// given with --commit-type, else the type its labels imply, else the type the words of its
+// title imply, else feat.
+func commitType(issue *issues.Issue) string {
+	if t, err := resolveCommitType(); err == nil && t != "" {
+		return t
+	}
+	labels := make(map[string]bool, len(issue.Labels.Nodes))
+	for _, label := range issue.Labels.Nodes {
+		labels[strings.ToLower(strings.TrimSpace(label.Name))] = true
+	}
+	for _, t := range inferredTypes {
+		for _, name := range labelCommitTypes[t] {
+			if labels[name] {
+				return t
+			}
+		}
+	}
+	for _, t := range inferredTypes {
+		if re := titleCommitTypes[t]; re != nil && re.MatchString(issue.Title) {
+			return t
+		}
+	}
+	return "feat"
+}
+
+// commitSubject returns the subject of the commit and the title of the pull request for
+// issue: its title prefixed with its commit type, or the title as is if it already is a
+// conventional commit subject and no type was given with --commit-type.
+func commitSubject(issue *issues.Issue) string {
+	if t, _ := resolveCommitType(); t == "" && conventionalTitle.MatchString(issue.Title) {
+		return issue.Title
+	}
+	return fmt.Sprintf("%s: %s", commitType(issue), issue.Title)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"monday/linear"
+)
+
+func TestCommitSubject(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		labels   []string
+		override string
+		want     string
+	}{
+		{name: "default", title: "Add CSV export", want: "feat: Add CSV export"},
+		{name: "bug label", title: "Add CSV export", labels: []string{"Bug"}, want: "fix: Add CSV export"},
+		{name: "bug label wins over feature", title: "Add CSV export", labels: []string{"Feature", "bug"}, want: "fix: Add CSV export"},
+		{name: "tech debt label", title: "Move handlers", labels: []string{"Tech Debt"}, want: "chore: Move handlers"},
+		{name: "feature label wins over title", title: "Fix up the export dialog", labels: []string{"Enhancement"}, want: "feat: Fix up the export dialog"},
+		{name: "fix title", title: "Login fails for SSO users", want: "fix: Login fails for SSO users"},
+		{name: "refactor title", title: "Refactor the session store", want: "refactor: Refactor the session store"},
+		{name: "chore title", title: "Bump zap to 1.27", want: "chore: Bump zap to 1.27"},
+		{name: "word parts do not count", title: "Add prefix search", want: "feat: Add prefix search"},
+		{name: "conventional title", title: "fix(auth): Reject expired sessions", want: "fix(auth): Reject expired sessions"},
+		{name: "override", title: "Login fails for SSO users", labels: []string{"Bug"}, override: "Chore", want: "chore: Login fails for SSO users"},
+		{name: "override of conventional title", title: "fix: Reject expired sessions", override: "docs", want: "docs: fix: Reject expired sessions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := commitTypeFlag
+			t.Cleanup(func() { commitTypeFlag = orig })
+			commitTypeFlag = tt.override
+			t.Setenv("MONDAY_COMMIT_TYPE", "")
+
+			issue := &linear.IssueDetails{Title: tt.title}
+			for _, name := range tt.labels {
+				issue.Labels.Nodes = append(issue.Labels.Nodes, linear.Label{Name: name})
+			}
+			if got := commitSubject(issue); got != tt.want {
+				t.Errorf("commitSubject() = %q, want %q", got, tt.want)
+			}
+			if cr := changeRequest(issue, "feature/del_163", ""); cr.Title != tt.want {
+				t.Errorf("pull request title = %q, want %q", cr.Title, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCommitType(t *testing.T) {
+	orig := commitTypeFlag
+	t.Cleanup(func() { commitTypeFlag = orig })
+	commitTypeFlag = ""
+
+	t.Setenv("MONDAY_COMMIT_TYPE", "refactor")
+	if got, err := resolveCommitType(); err != nil || got != "refactor" {
+		t.Errorf("resolveCommitType() = %q, %v, want refactor from MONDAY_COMMIT_TYPE", got, err)
+	}
+	t.Setenv("MONDAY_COMMIT_TYPE", "feature")
+	if _, err := resolveCommitType(); err == nil || !strings.Contains(err.Error(), "invalid commit type") {
+		t.Errorf("resolveCommitType() error = %v, want an invalid commit type", err)
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/config"
+	"monday/secrets"
+)
+
+// secretResolveTimeout bounds looking up the secrets settings refer to in secrets managers.
+const secretResolveTimeout = 30 * time.Second
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write the config file",
+	Long: `Read and write monday's config file, ~/.monday/config.yaml or $MONDAY_CONFIG. Each key
+provides an environment variable, e.g. linear_api_key provides LINEAR_API_KEY, as shown by
+monday config list; variables set in the environment take precedence over the file. Secret
+values are masked when read back.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a config key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config key; an empty value removes it",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the keys set in the config file",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configPath returns the path of the config file: $MONDAY_CONFIG or config.yaml in the state
+// directory.
+func configPath() (string, error) {
+	if path := os.Getenv("MONDAY_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// loadConfigEnv provides the environment variables set in the profiles or the config file that
+// the environment does not set. The repository's profile takes precedence over the user's,
+// which takes precedence over the config file. Secret settings that refer to a secret, such as
+// vault://secret/monday#linear, are then replaced with the secret.
+func loadConfigEnv() error {
+	for _, path := range profilePaths() {
+		values, err := config.LoadFile(path)
+		if err != nil {
+			return withExitCode(exitConfig, err)
+		}
+		if err := config.ApplyEnv(values); err != nil {
+			return err
+		}
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	values, err := config.Load(path)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if err := config.ApplyEnv(values); err != nil {
+		return err
+	}
+	return resolveSecretEnv()
+}
+
+// resolveSecretEnv replaces the environment variables of secret settings that refer to a
+// secret with the secret.
+func resolveSecretEnv() error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+	for _, setting := range config.Settings {
+		value := os.Getenv(setting.Env)
+		if !setting.Secret || !secrets.IsReference(value) {
+			continue
+		}
+		secret, err := secrets.Resolve(ctx, value)
+		if err != nil {
+			return withExitCode(exitConfig, fmt.Errorf("%s: %w", setting.Env, err))
+		}
+		if err := os.Setenv(setting.Env, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	setting, err := config.Lookup(args[0])
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	values, err := config.Load(path)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	value, ok := values[setting.Key]
+	if !ok {
+		return withExitCode(exitConfig, fmt.Errorf("%s is not set in %s", setting.Key, path))
+	}
+	if jsonOutput() {
+		return writeJSON(map[string]string{setting.Key: setting.Display(value)})
+	}
+	fmt.Fprintln(resultOut, setting.Display(value))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	values, err := config.Load(path)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	// Unknown keys can be removed, so typos in a hand-edited file can be cleaned up.
+	if _, ok := values[key]; !ok || value != "" {
+		if _, err := config.Lookup(key); err != nil {
+			return withExitCode(exitConfig, err)
+		}
+	}
+	if value == "" {
+		delete(values, key)
+	} else {
+		values[key] = value
+	}
+	if err := config.Save(path, values); err != nil {
+		return err
+	}
+	if value == "" {
+		fmt.Printf("✅ Removed %s from %s\n", key, path)
+	} else {
+		fmt.Printf("✅ Set %s in %s\n", key, path)
+	}
+	return nil
+}
+
+// configSetting returns the setting called key; keys that are not settings are treated as
+// secrets, since they may be misspelled credentials.
+func configSetting(key string) config.Setting {
+	setting, err := config.Lookup(key)
+	if err != nil {
+		return config.Setting{Key: key, Env: "-", Secret: true}
+	}
+	return setting
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	values, err := config.Load(path)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	shown := make(map[string]string, len(values))
+	for key, value := range values {
+		keys = append(keys, key)
+		shown[key] = configSetting(key).Display(value)
+	}
+	sort.Strings(keys)
+
+	if jsonOutput() {
+		return writeJSON(shown)
+	}
+	if len(keys) == 0 {
+		fmt.Printf("No keys set in %s\n", path)
+		return nil
+	}
+	w := tabwriter.NewWriter(resultOut, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tVARIABLE")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key, shown[key], configSetting(key).Env)
+	}
+	w.Flush()
+	return nil
+}
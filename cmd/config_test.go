@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("MONDAY_CONFIG", path)
+	var out bytes.Buffer
+	orig := resultOut
+	resultOut = &out
+	t.Cleanup(func() { resultOut = orig })
+
+	for _, args := range [][]string{
+		{"github_token", "ghp_abcdefghijklmnop"},
+		{"aws_region", "eu-west-1"},
+		{"slack_channel", "#dev"},
+		{"slack_channel", ""},
+	} {
+		if err := runConfigSet(configSetCmd, args); err != nil {
+			t.Fatalf("config set %v: %v", args, err)
+		}
+	}
+	if err := runConfigSet(configSetCmd, []string{"github_tokn", "x"}); exitCodeFor(err) != exitConfig {
+		t.Errorf("config set of an unknown key = %v, want a configuration error", err)
+	}
+
+	if err := runConfigGet(configGetCmd, []string{"github_token"}); err != nil {
+		t.Fatalf("config get: %v", err)
+	}
+	if got := out.String(); got != "****mnop\n" {
+		t.Errorf("config get github_token = %q, want the masked token", got)
+	}
+	if err := runConfigGet(configGetCmd, []string{"slack_channel"}); err == nil {
+		t.Error("config get of a removed key succeeded")
+	}
+
+	out.Reset()
+	if err := runConfigList(configListCmd, nil); err != nil {
+		t.Fatalf("config list: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "aws_region") || !strings.Contains(lines[2], "****mnop") || strings.Contains(out.String(), "ghp_") {
+		t.Errorf("config list = %q, want both keys with the token masked", out.String())
+	}
+}
+
+func TestConfigSetRemovesUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("MONDAY_CONFIG", path)
+	if err := os.WriteFile(path, []byte("github_tokn: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runConfigSet(configSetCmd, []string{"github_tokn", ""}); err != nil {
+		t.Fatalf("config set of an unknown key to empty: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "github_tokn") {
+		t.Errorf("config file = %q, want the unknown key removed", data)
+	}
+}
+
+func TestResolveSecretEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "linear_api_key")
+	if err := os.WriteFile(path, []byte("lin_api_mounted\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LINEAR_API_KEY", "file://"+path)
+	t.Setenv("MONDAY_REPO_URL", "file:///srv/git/app.git")
+	t.Setenv("GITHUB_TOKEN", "ghp_plain")
+
+	if err := resolveSecretEnv(); err != nil {
+		t.Fatalf("resolveSecretEnv() error = %v", err)
+	}
+	for env, want := range map[string]string{
+		"LINEAR_API_KEY":  "lin_api_mounted",
+		"MONDAY_REPO_URL": "file:///srv/git/app.git",
+		"GITHUB_TOKEN":    "ghp_plain",
+	} {
+		if got := os.Getenv(env); got != want {
+			t.Errorf("%s = %q, want %q", env, got, want)
+		}
+	}
+
+	t.Setenv("OPENAI_API_KEY", "env://MONDAY_TEST_UNSET_SECRET")
+	err := resolveSecretEnv()
+	if err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") || exitCodeFor(err) != exitConfig {
+		t.Errorf("resolveSecretEnv() error = %v, want a config error naming OPENAI_API_KEY", err)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"monday/sandbox"
+)
+
+// defaultContainerImage is the image containerized runs use unless told otherwise.
+const defaultContainerImage = "monday-agent:latest"
+
+// gitCredentialHelper makes git authenticate with $GITHUB_TOKEN, in the container or, for
+// GitHub Apps, on the host.
+const gitCredentialHelper = `!f() { echo username=x-access-token; echo "password=$GITHUB_TOKEN"; }; f`
+
+var (
+	// containerized runs the clone, agent, gates, commit, and push of a run in a container.
+	containerized bool
+	// containerImage is the image containerized runs use.
+	containerImage string
+	// containerMemory is the memory limit of the container, e.g. "4g".
+	containerMemory string
+	// containerCPUs is the number of CPUs the container may use.
+	containerCPUs string
+)
+
+func init() {
+	rootCmd.Flags().BoolVar(&containerized, "containerized", false, "Run the clone, agent, gates, commit, and push in a Docker container that only sees the run's workspace")
+	rootCmd.Flags().StringVar(&containerImage, "container-image", "", "Image of --containerized runs; it needs git and codex (default: $MONDAY_CONTAINER_IMAGE or "+defaultContainerImage+")")
+	rootCmd.Flags().StringVar(&containerMemory, "memory", "4g", "Memory limit of the container of --containerized runs")
+	rootCmd.Flags().StringVar(&containerCPUs, "cpus", "2", "Number of CPUs the container of --containerized runs may use")
+}
+
+// resolveContainerImage returns the image of containerized runs from the flag, the
+// environment, or the default.
+func resolveContainerImage() string {
+	if containerImage != "" {
+		return containerImage
+	}
+	if image := os.Getenv("MONDAY_CONTAINER_IMAGE"); image != "" {
+		return image
+	}
+	return defaultContainerImage
+}
+
+// containerKey is the context key of the container a run's commands run in.
+type containerKey struct{}
+
+// runContainer returns the container the commands of the run of ctx run in, or nil if they run
+// on the host.
+func runContainer(ctx context.Context) *sandbox.Container {
+	c, _ := ctx.Value(containerKey{}).(*sandbox.Container)
+	return c
+}
+
+// containerWorkspace returns the workspace the container of a run mounts: that of the continued
+// run, which must still exist, or the run's own, which is created.
+func containerWorkspace(runID, continued string) (string, error) {
+	if continued != "" {
+		if _, err := os.Stat(continued); err != nil {
+			return "", fmt.Errorf("failed to open the workspace: %w", err)
+		}
+		return continued, nil
+	}
+	dir, err := runWorkspace(runID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return dir, nil
+}
+
+// startContainer starts the container of the run called runID with the workspace dir mounted,
+// and returns a context whose commands run in it and a func that removes it.
+func startContainer(ctx context.Context, log *zap.Logger, runID, dir string) (context.Context, func(), error) {
+	opts := sandbox.Options{
+		Image:  resolveContainerImage(),
+		Memory: containerMemory,
+		CPUs:   containerCPUs,
+		User:   hostUser(),
+		Env:    gitIdentityEnv(),
+	}
+	log.Info("Starting container",
+		zap.String("image", opts.Image),
+		zap.String("memory", opts.Memory),
+		zap.String("cpus", opts.CPUs),
+		zap.String("work_dir", dir))
+	c, err := sandbox.Start(ctx, "monday-"+runID, dir, opts)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+	return context.WithValue(ctx, containerKey{}, c), func() {
+		stopCtx, cancel := cleanupContext()
+		defer cancel()
+		if err := c.Stop(stopCtx); err != nil {
+			log.Warn("Failed to remove container", zap.Error(err))
+		}
+	}, nil
+}
+
+// hostUser returns the "uid:gid" of this process, or "" where there is none.
+func hostUser() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+}
+
+// gitIdentityEnv returns the variables giving git in the container the name and email runs
+// commit as: those of --git-author-name and --git-author-email, or else those the host commits
+// with, as its configuration is not available there.
+func gitIdentityEnv() []string {
+	name, email := commitIdentity()
+	var env []string
+	for _, setting := range []struct{ key, value, author, committer string }{
+		{"user.name", name, "GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"},
+		{"user.email", email, "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"},
+	} {
+		value := setting.value
+		if value == "" {
+			out, err := exec.Command("git", "config", "--get", setting.key).Output()
+			if err != nil {
+				continue
+			}
+			value = strings.TrimSpace(string(out))
+		}
+		if value == "" {
+			continue
+		}
+		env = append(env, setting.author+"="+value, setting.committer+"="+value)
+	}
+	return env
+}
+
+// gitEnv returns the variables git commands of the run of ctx need beyond the environment: in
+// a container, the credentials to fetch and push with.
+func gitEnv(ctx context.Context) []string {
+	token := os.Getenv("GITHUB_TOKEN")
+	if runContainer(ctx) == nil || token == "" {
+		return nil
+	}
+	return []string{
+		"GITHUB_TOKEN=" + token,
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=" + gitCredentialHelper,
+	}
+}
+
+// newCommand returns the command running name with args in dir, with env added to the
+// environment: in the container of the run of ctx if it has one, and on the host otherwise.
+// The command is interrupted when ctx is cancelled.
+func newCommand(ctx context.Context, dir string, env []string, name string, args ...string) *exec.Cmd {
+	if c := runContainer(ctx); c != nil {
+		return interruptOnCancel(c.Command(ctx, dir, env, name, args...))
+	}
+	cmd := interruptOnCancel(exec.CommandContext(ctx, name, args...))
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
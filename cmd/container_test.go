@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"monday/sandbox"
+)
+
+func TestResolveContainerImage(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{name: "default", want: defaultContainerImage},
+		{name: "environment", env: "registry.example.com/agent:1", want: "registry.example.com/agent:1"},
+		{name: "flag", flag: "agent:dev", env: "registry.example.com/agent:1", want: "agent:dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := containerImage
+			t.Cleanup(func() { containerImage = orig })
+			containerImage = tt.flag
+			t.Setenv("MONDAY_CONTAINER_IMAGE", tt.env)
+
+			if got := resolveContainerImage(); got != tt.want {
+				t.Errorf("resolveContainerImage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCommand(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	ctx := context.Background()
+
+	host := newCommand(ctx, "/work", []string{"OPENAI_API_KEY=sk"}, "codex", "exec")
+	if host.Dir != "/work" || filepath.Base(host.Args[0]) != "codex" {
+		t.Errorf("host command = %v in %q, want codex in /work", host.Args, host.Dir)
+	}
+	if !slices.Contains(host.Env, "OPENAI_API_KEY=sk") {
+		t.Errorf("host command env lacks OPENAI_API_KEY")
+	}
+	if env := gitEnv(ctx); env != nil {
+		t.Errorf("gitEnv() on the host = %v, want none", env)
+	}
+
+	ctx = context.WithValue(ctx, containerKey{}, &sandbox.Container{})
+	contained := newCommand(ctx, "/work", nil, "git", "status")
+	if contained.Args[0] != "docker" || contained.Args[1] != "exec" {
+		t.Errorf("containerized command = %v, want docker exec", contained.Args)
+	}
+	if env := gitEnv(ctx); !slices.Contains(env, "GITHUB_TOKEN=ghp_secret") {
+		t.Errorf("gitEnv() in a container = %v, want GITHUB_TOKEN", env)
+	}
+}
+
+func TestContainerWorkspace(t *testing.T) {
+	orig := workspaceRoot
+	t.Cleanup(func() { workspaceRoot = orig })
+	workspaceRoot = t.TempDir()
+
+	dir, err := containerWorkspace("run-1", "")
+	if err != nil {
+		t.Fatalf("containerWorkspace() error = %v", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("workspace %s was not created", dir)
+	}
+	if got, err := containerWorkspace("run-2", dir); err != nil || got != dir {
+		t.Errorf("containerWorkspace() of a continued run = %q, %v, want %q", got, err, dir)
+	}
+	if _, err := containerWorkspace("run-3", filepath.Join(workspaceRoot, "gone")); err == nil {
+		t.Errorf("containerWorkspace() of a missing continued workspace succeeded")
+	}
+}
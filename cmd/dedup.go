@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+)
+
+// dedupWindow is how long after a server run ends duplicate requests for it still get its run
+// ID instead of a new run.
+var dedupWindow time.Duration
+
+func init() {
+	serverCmd.Flags().DurationVar(&dedupWindow, "dedup-window", 0, "How long after a run ends duplicate triggers for the same issue and repository still return it instead of starting another, e.g. 10m (default: $MONDAY_DEDUP_WINDOW or only while it is in flight)")
+}
+
+// resolveDedupWindow returns the window selected with --dedup-window or MONDAY_DEDUP_WINDOW.
+func resolveDedupWindow() (time.Duration, error) {
+	return timeoutSetting("dedup-window", dedupWindow, "MONDAY_DEDUP_WINDOW")
+}
+
+// triggerKey returns the key that identifies duplicate requests to work on issueID in the
+// repository at repoURL: the idempotency key the client sent, or else the issue and repository.
+func triggerKey(idempotencyKey, issueID, repoURL string) string {
+	if idempotencyKey != "" {
+		return "key:" + idempotencyKey
+	}
+	return "run:" + issueID + " " + repoURL
+}
+
+// dedupRun is the run a trigger key was claimed for.
+type dedupRun struct {
+	// runID identifies the run
+	runID string
+	// ended is when the run ended; zero while it is in flight
+	ended time.Time
+}
+
+// runDedup coalesces duplicate requests to start a run: while the run of a key is in flight,
+// and for window after it ends, requests with the same key get its run ID.
+type runDedup struct {
+	mu     sync.Mutex
+	window time.Duration
+	runs   map[string]dedupRun
+	now    func() time.Time
+}
+
+// newRunDedup returns a runDedup that remembers ended runs for window.
+func newRunDedup(window time.Duration) *runDedup {
+	return &runDedup{window: window, runs: map[string]dedupRun{}, now: time.Now}
+}
+
+// claim records runID as the run of key and returns "", true, unless key already has a run in
+// flight or ended within the window, whose ID it returns with false.
+func (d *runDedup) claim(key, runID string) (existing string, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for k, run := range d.runs {
+		if !run.ended.IsZero() && now.Sub(run.ended) >= d.window {
+			delete(d.runs, k)
+		}
+	}
+	if run, found := d.runs[key]; found {
+		return run.runID, false
+	}
+	d.runs[key] = dedupRun{runID: runID}
+	return "", true
+}
+
+// end records that the run of key ended; it is remembered for the window.
+func (d *runDedup) end(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.window <= 0 {
+		delete(d.runs, key)
+		return
+	}
+	if run, found := d.runs[key]; found {
+		run.ended = d.now()
+		d.runs[key] = run
+	}
+}
+
+// release forgets the run of key right away, for runs that never started.
+func (d *runDedup) release(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.runs, key)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestTriggerKey(t *testing.T) {
+	if got, want := triggerKey("", "DEL-1", "https://github.com/acme/app"), "run:DEL-1 https://github.com/acme/app"; got != want {
+		t.Errorf("triggerKey() = %q, want %q", got, want)
+	}
+	if got, want := triggerKey("abc", "DEL-1", "https://github.com/acme/app"), "key:abc"; got != want {
+		t.Errorf("triggerKey() with an idempotency key = %q, want %q", got, want)
+	}
+}
+
+func TestRunDedup(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		after  time.Duration
+		want   string
+	}{
+		{name: "no window", window: 0, after: 0, want: ""},
+		{name: "within the window", window: 10 * time.Minute, after: 5 * time.Minute, want: "run-1"},
+		{name: "after the window", window: 10 * time.Minute, after: 10 * time.Minute, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 6, 15, 18, 0, 0, 0, time.UTC)
+			d := newRunDedup(tt.window)
+			d.now = func() time.Time { return now }
+
+			if existing, ok := d.claim("k", "run-1"); !ok {
+				t.Fatalf("claim() = %q, false, want a new run", existing)
+			}
+			if existing, ok := d.claim("k", "run-2"); ok || existing != "run-1" {
+				t.Errorf("claim() while in flight = %q, %v, want run-1, false", existing, ok)
+			}
+			if _, ok := d.claim("other", "run-3"); !ok {
+				t.Error("claim() of another key was coalesced")
+			}
+
+			d.end("k")
+			now = now.Add(tt.after)
+			existing, ok := d.claim("k", "run-4")
+			if existing != tt.want || ok != (tt.want == "") {
+				t.Errorf("claim() after the run ended = %q, %v, want %q", existing, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunDedupRelease(t *testing.T) {
+	d := newRunDedup(time.Hour)
+	d.claim("k", "run-1")
+	d.release("k")
+	if existing, ok := d.claim("k", "run-2"); !ok {
+		t.Errorf("claim() after release = %q, false, want a new run", existing)
+	}
+}
+
+func TestTriggerHandler(t *testing.T) {
+	var runs sync.WaitGroup
+	pool := newRunPool(zap.NewNop(), 1, 0, &runs)
+	release := make(chan struct{})
+	defer func() {
+		close(release)
+		runs.Wait()
+	}()
+	// A run of another issue takes the only slot, so new runs cannot be accepted.
+	if _, err := pool.submit(context.Background(), "busy", func() { <-release }); err != nil {
+		t.Fatal(err)
+	}
+	dedup := newRunDedup(time.Hour)
+	repo := "https://github.com/acme/app"
+	dedup.claim(triggerKey("", "DEL-1", repo), "run-in-flight")
+	handler := makeTriggerHandler(context.Background(), zap.NewNop(), "secret", pool, dedup)
+	trigger := func(issueID string) *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"linear_id": "` + issueID + `", "github_url": "` + repo + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/trigger", body)
+		req.Header.Set("X-API-Key", "secret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := trigger("DEL-1")
+	var resp triggerResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || resp.Status != "duplicate" || resp.RunID != "run-in-flight" {
+		t.Errorf("duplicate trigger = %d %+v, want the run in flight", rec.Code, resp)
+	}
+
+	if rec := trigger("DEL-2"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("trigger with a full queue status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	// The rejected trigger does not keep a later one of the issue from starting.
+	if existing, ok := dedup.claim(triggerKey("", "DEL-2", repo), "run-2"); !ok {
+		t.Errorf("claim() after the rejected trigger = %q, false, want a new run", existing)
+	}
+}
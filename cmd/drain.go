@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultDrainTimeout is how long a stopping server waits for in-flight runs unless told
+// otherwise.
+const defaultDrainTimeout = 5 * time.Minute
+
+// drainTimeout is how long a stopping server waits for in-flight runs to finish before
+// canceling them.
+var drainTimeout time.Duration
+
+func init() {
+	serverCmd.Flags().DurationVar(&drainTimeout, "drain-timeout", 0, "How long to let in-flight runs finish on SIGTERM before canceling them (default: $MONDAY_DRAIN_TIMEOUT or 5m)")
+}
+
+// resolveDrainTimeout returns the drain timeout selected with --drain-timeout or
+// MONDAY_DRAIN_TIMEOUT, or defaultDrainTimeout.
+func resolveDrainTimeout() (time.Duration, error) {
+	d, err := timeoutSetting("drain-timeout", drainTimeout, "MONDAY_DRAIN_TIMEOUT")
+	if err != nil {
+		return 0, err
+	}
+	if d == 0 {
+		return defaultDrainTimeout, nil
+	}
+	return d, nil
+}
+
+// drainRuns waits up to timeout for the runs to finish. Runs still in flight then are canceled
+// with cancel and waited for while they clean up. It reports whether the runs finished in time.
+func drainRuns(runs *sync.WaitGroup, cancel context.CancelFunc, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		runs.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		cancel()
+		<-done
+		return false
+	}
+}
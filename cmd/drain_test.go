@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResolveDrainTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    time.Duration
+		env     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: defaultDrainTimeout},
+		{name: "flag", flag: time.Minute, env: "10m", want: time.Minute},
+		{name: "environment", env: "10m", want: 10 * time.Minute},
+		{name: "invalid environment", env: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := drainTimeout
+			t.Cleanup(func() { drainTimeout = orig })
+			drainTimeout = tt.flag
+			t.Setenv("MONDAY_DRAIN_TIMEOUT", tt.env)
+
+			got, err := resolveDrainTimeout()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveDrainTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveDrainTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrainRuns(t *testing.T) {
+	t.Run("finished in time", func(t *testing.T) {
+		var runs sync.WaitGroup
+		runs.Add(1)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			runs.Done()
+		}()
+		canceled := false
+
+		if !drainRuns(&runs, func() { canceled = true }, time.Minute) {
+			t.Error("drainRuns() = false, want true")
+		}
+		if canceled {
+			t.Error("drainRuns() canceled runs that finished in time")
+		}
+	})
+
+	t.Run("canceled after the timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var runs sync.WaitGroup
+		runs.Add(1)
+		go func() {
+			<-ctx.Done()
+			runs.Done()
+		}()
+
+		if drainRuns(&runs, cancel, 10*time.Millisecond) {
+			t.Error("drainRuns() = true, want false")
+		}
+		if ctx.Err() == nil {
+			t.Error("drainRuns() did not cancel the runs")
+		}
+	})
+}
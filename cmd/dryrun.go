@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"monday/issues"
+	"monday/vcs"
+)
+
+// dryRun makes runs stop once the issue is fetched and print what they would do.
+var dryRun bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Fetch the issue, print the branch, prompt, commit message, pull request, and commands the run would use, then exit without cloning or changing Linear, GitHub, or GitLab")
+}
+
+// dryRunCommands returns the agent, git, and pull request commands a run would execute for
+// issue, opening cr on host. The gates and hooks configured in the repository are not known
+// without a clone and left out.
+func dryRunCommands(issue *issues.Issue, prompt string, cr vcs.ChangeRequest, host codeHost) [][]string {
+	commit, err := commitArgs(commitMessage(issue))
+	if err != nil {
+		commit = []string{"commit", "-m", commitMessage(issue)}
+	}
+	return [][]string{
+		append([]string{"codex"}, codexArgs(prompt)...),
+		{"git", "add", "."},
+		append([]string{"git"}, commit...),
+		{"git", "push", "--set-upstream", "origin", cr.SourceBranch},
+		host.createCommand(cr),
+	}
+}
+
+// printDryRun prints the plan of a dry run for issue: its branch, commit message, pull request
+// cr, agent prompt, and commands.
+func printDryRun(out io.Writer, issue *issues.Issue, prompt string, cr vcs.ChangeRequest, host codeHost) {
+	fmt.Fprintf(out, "\n🌿 Branch: %s\n", cr.SourceBranch)
+	fmt.Fprintf(out, "\n💬 Commit message:\n%s\n", indent(commitMessage(issue), "   "))
+	fmt.Fprintf(out, "\n🔀 Pull request: %s\n%s\n", cr.Title, indent(cr.Body, "   "))
+	fmt.Fprintf(out, "\n📝 Prompt:\n%s\n\n⚙️  Commands:\n", indent(prompt, "   "))
+	for _, command := range dryRunCommands(issue, prompt, cr, host) {
+		quoted := make([]string, len(command))
+		for i, arg := range command {
+			quoted[i] = shellQuote(arg)
+		}
+		fmt.Fprintf(out, "   %s\n", strings.Join(quoted, " "))
+	}
+	fmt.Fprintln(out)
+}
+
+// shellQuote quotes s for a POSIX shell unless it only contains characters that need no quoting.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@%+,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// indent prefixes every line of s with prefix.
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
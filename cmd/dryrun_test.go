@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"monday/linear"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"git", "git"},
+		{"--set-upstream", "--set-upstream"},
+		{"feature/del_163", "feature/del_163"},
+		{"", "''"},
+		{"feat: Fix login", "'feat: Fix login'"},
+		{"it's", `'it'\''s'`},
+		{"a\nb", "'a\nb'"},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.in); got != tt.want {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPrintDryRun(t *testing.T) {
+	issue := &linear.IssueDetails{Title: "Fix login", Description: "Users cannot log in", URL: "https://linear.app/t/DEL-163"}
+	var out bytes.Buffer
+	printDryRun(&out, issue, "Fix the login form", changeRequest(issue, "feature/del_163", ""), &githubHost{})
+
+	got := out.String()
+	for _, want := range []string{
+		"Branch: feature/del_163\n",
+		"Commit message:\n   fix: Fix login\n",
+		"Pull request: fix: Fix login\n   Users cannot log in\n",
+		"   Fix the login form\n",
+		"   codex --approval-mode full-auto -q",
+		"   git add .\n",
+		"   git commit -m 'fix: Fix login\n",
+		"   git push --set-upstream origin feature/del_163\n",
+		"   gh pr create --title 'fix: Fix login' --body 'Users cannot log in\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("printDryRun output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPullRequestArgsBaseAndDraft(t *testing.T) {
+	origBase, origDraft := baseBranch, draftPR
+	t.Cleanup(func() { baseBranch, draftPR = origBase, origDraft })
+	baseBranch, draftPR = "release-2.4", true
+
+	args := pullRequestArgs(changeRequest(&linear.IssueDetails{Title: "Fix login"}, "feature/del_163", ""))
+	got := strings.Join(args[len(args)-3:], " ")
+	if got != "--base release-2.4 --draft" {
+		t.Errorf("pullRequestArgs() ends with %q, want %q", got, "--base release-2.4 --draft")
+	}
+}
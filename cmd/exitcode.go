@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"monday/issues"
+)
+
+// Exit codes of monday. They are part of its interface for scripts and CI and must not change;
+// new outcomes get new codes.
+const (
+	// exitFailure is any failure without a more specific code.
+	exitFailure = 1
+	// exitConfig is an invalid command line or missing configuration, such as an API key.
+	exitConfig = 2
+	// exitIssueNotFound is a Linear issue that does not exist or is not visible to the API key.
+	exitIssueNotFound = 3
+	// exitAgentFailed is a Codex CLI run that failed.
+	exitAgentFailed = 4
+	// exitNothingToCommit is an agent run that left no changes to commit.
+	exitNothingToCommit = 5
+	// exitPublishFailed is a failure to push the branch or to create the pull request.
+	exitPublishFailed = 6
+	// exitGateFailed is a run whose changes failed the repository's tests or another gate.
+	exitGateFailed = 7
+	// exitChangesRejected is a --require-approval run whose changes were rejected.
+	exitChangesRejected = 8
+	// exitTimedOut is a run that exceeded --step-timeout or --total-timeout.
+	exitTimedOut = 124
+	// exitCanceled is a run stopped by monday cancel or an interrupt.
+	exitCanceled = 130
+)
+
+// errNothingToCommit is returned when the agent left the workspace unchanged.
+var errNothingToCommit = errors.New("agent made no changes; nothing to commit")
+
+// exitCodeError carries the exit code of an error through wrapping.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// withExitCode returns err annotated with the exit code monday ends with when it fails with err.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: code, err: err}
+}
+
+// exitCodeFor returns the exit code for err. Cancellation takes precedence over the error the
+// cancelled run happened to fail with.
+func exitCodeFor(err error) int {
+	var coded *exitCodeError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, context.Canceled):
+		return exitCanceled
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, issues.ErrNotFound):
+		return exitIssueNotFound
+	case errors.Is(err, errNothingToCommit):
+		return exitNothingToCommit
+	default:
+		return exitFailure
+	}
+}
+
+// markUsageErrors makes errors cobra returns before a command runs, such as unknown flags,
+// invalid arguments, and missing required flags, exit with exitConfig.
+func markUsageErrors(cmd *cobra.Command) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			commandStarted = true
+			return run(cmd, args)
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// commandStarted is set once the selected command starts running.
+var commandStarted bool
+
+// executeExitCode returns the exit code for the error returned by executing the root command.
+func executeExitCode(err error) int {
+	if err != nil && !commandStarted {
+		return exitConfig
+	}
+	return exitCodeFor(err)
+}
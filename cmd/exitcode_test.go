@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"monday/linear"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", want: 0},
+		{name: "unclassified", err: errors.New("failed to clone repository"), want: exitFailure},
+		{name: "configuration", err: withExitCode(exitConfig, errors.New("GITHUB_TOKEN environment variable is required")), want: exitConfig},
+		{name: "issue not found", err: fmt.Errorf("failed to fetch issue details: %w", fmt.Errorf("%w: DEL-1", linear.ErrIssueNotFound)), want: exitIssueNotFound},
+		{name: "agent", err: withExitCode(exitAgentFailed, errors.New("failed to run Codex")), want: exitAgentFailed},
+		{name: "nothing to commit", err: fmt.Errorf("commit: %w", errNothingToCommit), want: exitNothingToCommit},
+		{name: "push", err: withExitCode(exitPublishFailed, errors.New("failed to push branch")), want: exitPublishFailed},
+		{name: "tests", err: withExitCode(exitGateFailed, errors.New("tests failed: go test ./...")), want: exitGateFailed},
+		{name: "rejected", err: withExitCode(exitChangesRejected, errChangesRejected), want: exitChangesRejected},
+		{name: "timed out", err: withExitCode(exitTimedOut, fmt.Errorf("stage agent exceeded the step timeout of 30m0s: %w", errTimedOut)), want: exitTimedOut},
+		{name: "canceled", err: errRunCanceled, want: exitCanceled},
+		{name: "canceled wins", err: withExitCode(exitAgentFailed, fmt.Errorf("failed to run Codex: %w", errRunCanceled)), want: exitCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithExitCodeKeepsMessage(t *testing.T) {
+	inner := errors.New("failed to push branch")
+	err := withExitCode(exitPublishFailed, inner)
+	if err.Error() != inner.Error() || !errors.Is(err, inner) {
+		t.Errorf("withExitCode() = %v, want it to wrap %v", err, inner)
+	}
+	if withExitCode(exitPublishFailed, nil) != nil {
+		t.Error("withExitCode(nil) != nil")
+	}
+}
+
+func TestExecuteExitCode(t *testing.T) {
+	defer func() { commandStarted = false }()
+
+	root := &cobra.Command{Use: "root", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error {
+		return withExitCode(exitAgentFailed, errors.New("failed to run Codex"))
+	}}
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	markUsageErrors(root)
+
+	root.SetArgs([]string{})
+	if got := executeExitCode(root.Execute()); got != exitConfig {
+		t.Errorf("exit code for invalid arguments = %d, want %d", got, exitConfig)
+	}
+
+	root.SetArgs([]string{"DEL-1"})
+	if got := executeExitCode(root.Execute()); got != exitAgentFailed {
+		t.Errorf("exit code for a failed run = %d, want %d", got, exitAgentFailed)
+	}
+}
+
+func TestBatchExitCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []int
+		want  int
+	}{
+		{name: "same failure", codes: []int{exitAgentFailed, exitAgentFailed}, want: exitAgentFailed},
+		{name: "different failures", codes: []int{exitAgentFailed, exitPublishFailed}, want: exitFailure},
+		{name: "unknown code", codes: []int{0}, want: exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := 0
+			for _, c := range tt.codes {
+				code = batchExitCode(code, c)
+			}
+			if code != tt.want {
+				t.Errorf("batchExitCode(%v) = %d, want %d", tt.codes, code, tt.want)
+			}
+		})
+	}
+}
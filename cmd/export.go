@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"monday/history"
+	"monday/summary"
+)
+
+var (
+	exportFormat string
+	exportFile   string
+	exportRepo   string
+	exportIssue  string
+	exportStatus string
+	exportSince  string
+	exportUntil  string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export run history as CSV or JSON",
+	Long: `Export the recorded runs for BI tools and engineering-metrics dashboards. CSV has one
+row per run; JSON contains the full run summaries. The server offers the same export at
+GET /export.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "Export format: csv or json")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "Write the export to this file instead of stdout")
+	exportCmd.Flags().StringVar(&exportRepo, "repo", "", "Only runs whose repository URL or path contains this value")
+	exportCmd.Flags().StringVar(&exportIssue, "issue", "", "Only runs for this Linear issue")
+	exportCmd.Flags().StringVar(&exportStatus, "status", "", "Only runs with this status: running, succeeded, failed, or canceled")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "Only runs started at or after this date, timestamp, or period")
+	exportCmd.Flags().StringVar(&exportUntil, "until", "", "Only runs started at or before this date, timestamp, or period")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	write, err := exportWriter(exportFormat)
+	if err != nil {
+		return err
+	}
+	filter, err := buildHistoryFilter(exportRepo, exportIssue, exportStatus, exportSince, exportUntil)
+	if err != nil {
+		return err
+	}
+	runs, err := queryRuns(filter)
+	if err != nil {
+		return err
+	}
+
+	if exportFile == "" {
+		return write(resultOut, runs)
+	}
+	f, err := os.Create(exportFile)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := write(f, runs); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d runs to %s\n", len(runs), exportFile)
+	return nil
+}
+
+// exportWriter returns the history writer for format.
+func exportWriter(format string) (func(io.Writer, []*summary.Summary) error, error) {
+	switch format {
+	case "csv":
+		return history.WriteCSV, nil
+	case "json":
+		return history.WriteJSON, nil
+	default:
+		return nil, fmt.Errorf("invalid format %q: must be csv or json", format)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+)
+
+func TestExportHandler(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	for _, id := range []string{"run-1", "run-2"} {
+		s := summary.New(id, "DEL-1", "https://github.com/owner/repo")
+		s.Finish(nil)
+		if _, err := s.WriteFiles(filepath.Join(home, "runs", id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := makeExportHandler(zap.NewNop(), "secret")
+
+	tests := []struct {
+		name        string
+		url         string
+		apiKey      string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{name: "csv by default", url: "/export", apiKey: "secret", status: http.StatusOK, contentType: "text/csv", contains: "run-2"},
+		{name: "json", url: "/export?format=json&status=succeeded", apiKey: "secret", status: http.StatusOK, contentType: "application/json", contains: `"run_id": "run-1"`},
+		{name: "unknown format", url: "/export?format=xml", apiKey: "secret", status: http.StatusBadRequest},
+		{name: "invalid filter", url: "/export?since=yesterday", apiKey: "secret", status: http.StatusBadRequest},
+		{name: "missing API key", url: "/export", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("X-API-Key", tt.apiKey)
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.contentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.contains) {
+				t.Errorf("body %q does not contain %q", rec.Body.String(), tt.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"monday/gitops"
+	"monday/issues"
+	"monday/redact"
+	"monday/summary"
+)
+
+// Failure policies, selected with --on-failure, for what a failed run does about the changes it
+// made outside this machine.
+const (
+	// failureLeave leaves the pushed branch, the pull request, and the issue state as they are
+	failureLeave = "leave"
+	// failureRevert closes the pull request, deletes the pushed branch, and moves the issue back
+	failureRevert = "revert"
+	// failureComment leaves everything as it is and explains what is left in a Linear comment
+	failureComment = "comment"
+)
+
+var onFailure string
+
+func init() {
+	rootCmd.Flags().StringVar(&onFailure, "on-failure", "", "What a failed run does about its pushed branch, pull request, and Linear issue state: leave, revert, or comment (default: $MONDAY_ON_FAILURE or leave)")
+}
+
+// failurePolicy returns the failure policy selected with --on-failure or MONDAY_ON_FAILURE.
+func failurePolicy() (string, error) {
+	policy := onFailure
+	if policy == "" {
+		policy = os.Getenv("MONDAY_ON_FAILURE")
+	}
+	switch policy {
+	case "":
+		return failureLeave, nil
+	case failureLeave, failureRevert, failureComment:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid failure policy %q: must be leave, revert, or comment", policy)
+	}
+}
+
+// remoteEffects records the changes a run made outside this machine, so that a failed run can
+// deal with them according to its failure policy.
+type remoteEffects struct {
+	// log receives progress and failures of the cleanup
+	log *zap.Logger
+	// policy is the failure policy of the run
+	policy string
+	// host closes the pull request of the run
+	host codeHost
+	// tracker updates the issue; nil for runs that leave the issue tracker alone
+	tracker issues.Provider
+	// issue is the issue the run works on
+	issue *issues.Issue
+	// previousState is the state the run moved the issue out of; empty if it did not move it
+	previousState issues.State
+	// branch is the issue branch
+	branch string
+	// pushed is set once branch was pushed to origin
+	pushed bool
+}
+
+// cleanUp applies the failure policy to the run described by sum, which failed with runErr, in
+// the repository repo. A canceled run always moves the issue back, whatever the policy, and
+// posts no comment.
+func (e *remoteEffects) cleanUp(ctx context.Context, repo string, sum *summary.Summary, runErr error, canceled bool) {
+	switch {
+	case e.policy == failureRevert:
+		e.revert(ctx, repo, sum.PRURL, sum.RunID, runErr)
+	case canceled:
+		e.restoreIssue(ctx)
+	case e.policy == failureComment && e.tracker != nil:
+		body := failedRunComment(sum, runErr, e.pushed, e.previousState)
+		if err := e.tracker.CreateComment(ctx, e.issue, redact.String(body)); err != nil {
+			e.log.Warn("Failed to post failure comment", zap.Error(err))
+		} else {
+			fmt.Printf("💬 Explained the failure on %s\n", e.issue.Identifier)
+		}
+	}
+}
+
+// revert closes the pull request at prURL, deletes the pushed branch, and moves the issue back,
+// best effort.
+func (e *remoteEffects) revert(ctx context.Context, repo, prURL, runID string, runErr error) {
+	fmt.Printf("↩️  Reverting changes outside this machine...\n")
+	if prURL != "" {
+		comment := fmt.Sprintf("Closed by monday: run %s failed: %s", runID, redact.Error(runErr))
+		if err := e.host.close(ctx, prURL, comment); err != nil {
+			e.log.Warn("Failed to close pull request", zap.String("pr_url", prURL), zap.Error(err))
+		} else {
+			fmt.Printf("   closed pull request %s\n", prURL)
+		}
+	}
+	if e.pushed && repo != "" {
+		if err := gitops.DeleteRemoteBranch(ctx, repo, e.branch); err != nil {
+			e.log.Warn("Failed to delete remote branch", zap.String("branch", e.branch), zap.Error(err))
+		} else {
+			e.pushed = false
+			fmt.Printf("   deleted remote branch %s\n", e.branch)
+		}
+	}
+	e.restoreIssue(ctx)
+}
+
+// restoreIssue moves the issue back to the state the run moved it out of.
+func (e *remoteEffects) restoreIssue(ctx context.Context) {
+	if e.tracker == nil || e.previousState.ID == "" {
+		return
+	}
+	if err := e.tracker.SetIssueState(ctx, e.issue, e.previousState.ID); err != nil {
+		e.log.Warn("Failed to restore issue state", zap.String("state", e.previousState.Name), zap.Error(err))
+		return
+	}
+	fmt.Printf("↩️  Moved %s back to %s\n", e.issue.Identifier, e.previousState.Name)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+func TestFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: failureLeave},
+		{name: "env", env: "comment", want: failureComment},
+		{name: "flag overrides env", flag: "revert", env: "comment", want: failureRevert},
+		{name: "invalid", flag: "delete", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := onFailure
+			t.Cleanup(func() { onFailure = orig })
+			onFailure = tt.flag
+			t.Setenv("MONDAY_ON_FAILURE", tt.env)
+
+			got, err := failurePolicy()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("failurePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("failurePolicy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
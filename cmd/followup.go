@@ -0,0 +1,519 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"monday/notify"
+	"monday/redact"
+	"monday/summary"
+	"monday/vcs"
+)
+
+// followupContextLines is how many lines above and below a commented line the prompt of a
+// follow-up shows.
+const followupContextLines = 8
+
+var followupCmd = &cobra.Command{
+	Use:   "followup <pr-url>",
+	Short: "Address the unresolved review comments of a pull request",
+	Long: `Address the unresolved review comments of a GitHub pull request: the review threads are
+fetched from the GitHub API, the pull request's branch is cloned, and the agent gets the
+comments with the code they are on. Once the gates pass, its changes are pushed to the branch
+as a fixup! commit of the branch's last commit, and the pull request gets a comment listing
+the threads the commit addresses. Outdated threads are included, without code context.
+
+Threads are not resolved, so reviewers can check the changes before resolving them. Pull
+requests from forks and on GitLab are not supported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFollowup,
+}
+
+func init() {
+	followupCmd.Flags().BoolVar(&keepWorkspace, "keep-workspace", false, "Keep the clone of a successful follow-up instead of removing it")
+	rootCmd.AddCommand(followupCmd)
+}
+
+// reviewComment is a comment in a review thread.
+type reviewComment struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+}
+
+// reviewThread is a thread of review comments on a line of a pull request.
+type reviewThread struct {
+	// Path is the file the thread is on
+	Path string `json:"path"`
+	// Line is the line of the file at the head of the pull request, or 0 if the thread is on
+	// the whole file or its line no longer exists
+	Line int `json:"line,omitempty"`
+	// Resolved is set once a reviewer resolved the thread
+	Resolved bool `json:"resolved"`
+	// Outdated is set when the code the thread is on changed since
+	Outdated bool            `json:"outdated"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// reviewedPullRequest is a pull request with its review threads.
+type reviewedPullRequest struct {
+	Title string
+	URL   string
+	// State is OPEN, CLOSED, or MERGED
+	State string
+	// HeadBranch is the branch the pull request merges
+	HeadBranch string
+	// CrossRepository is set for pull requests from forks
+	CrossRepository bool
+	Threads         []reviewThread
+}
+
+// reviewThreadsQuery fetches a pull request and its review threads from the GitHub GraphQL API.
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      title url state headRefName isCrossRepository
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved isOutdated path line
+          comments(first: 50) { nodes { author { login } body url } }
+        }
+      }
+    }
+  }
+}`
+
+// fetchReviewThreads looks up the pull request number of repo, in HOST/OWNER/REPO form, with
+// its review threads on GitHub.
+var fetchReviewThreads = func(ctx context.Context, host *githubHost, repo string, number int) (*reviewedPullRequest, error) {
+	parts := strings.Split(repo, "/")
+	args := []string{"api", "graphql", "-f", "query=" + reviewThreadsQuery,
+		"-F", "owner=" + parts[1], "-F", "name=" + parts[2], "-F", "number=" + strconv.Itoa(number)}
+	if parts[0] != "github.com" {
+		args = append(args, "--hostname", parts[0])
+	}
+	var out []byte
+	err := host.retries.Do(ctx, func() error {
+		cmd, err := host.gh(ctx, args...)
+		if err != nil {
+			return err
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if out, err = cmd.Output(); err != nil && stderr.Len() > 0 {
+			return fmt.Errorf("%w: %s", err, redact.String(strings.TrimSpace(stderr.String())))
+		}
+		return err
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+	return parseReviewThreads(out)
+}
+
+// parseReviewThreads parses the response to reviewThreadsQuery.
+func parseReviewThreads(data []byte) (*reviewedPullRequest, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					Title             string `json:"title"`
+					URL               string `json:"url"`
+					State             string `json:"state"`
+					HeadRefName       string `json:"headRefName"`
+					IsCrossRepository bool   `json:"isCrossRepository"`
+					ReviewThreads     struct {
+						Nodes []struct {
+							IsResolved bool   `json:"isResolved"`
+							IsOutdated bool   `json:"isOutdated"`
+							Path       string `json:"path"`
+							Line       int    `json:"line"`
+							Comments   struct {
+								Nodes []struct {
+									Author struct {
+										Login string `json:"login"`
+									} `json:"author"`
+									Body string `json:"body"`
+									URL  string `json:"url"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse review comments: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to fetch review comments: %s", resp.Errors[0].Message)
+	}
+	pr := resp.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request not found")
+	}
+	result := &reviewedPullRequest{Title: pr.Title, URL: pr.URL, State: pr.State, HeadBranch: pr.HeadRefName, CrossRepository: pr.IsCrossRepository}
+	for _, node := range pr.ReviewThreads.Nodes {
+		thread := reviewThread{Path: node.Path, Line: node.Line, Resolved: node.IsResolved, Outdated: node.IsOutdated}
+		for _, c := range node.Comments.Nodes {
+			thread.Comments = append(thread.Comments, reviewComment{Author: c.Author.Login, Body: c.Body, URL: c.URL})
+		}
+		result.Threads = append(result.Threads, thread)
+	}
+	return result, nil
+}
+
+// parsePullRequestURL returns the repository, in HOST/OWNER/REPO form, and the number of the
+// GitHub pull request at prURL, such as https://github.com/acme/app/pull/42.
+func parsePullRequestURL(prURL string) (string, int, error) {
+	u, err := url.Parse(prURL)
+	if err == nil && u.Host != "" {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) >= 4 && parts[2] == "pull" {
+			if number, err := strconv.Atoi(parts[3]); err == nil && number > 0 {
+				return u.Host + "/" + parts[0] + "/" + parts[1], number, nil
+			}
+		}
+	}
+	return "", 0, fmt.Errorf("invalid pull request URL %q: want https://HOST/OWNER/REPO/pull/NUMBER", prURL)
+}
+
+// unresolvedThreads returns the threads of pr no reviewer resolved.
+func unresolvedThreads(pr *reviewedPullRequest) []reviewThread {
+	var threads []reviewThread
+	for _, thread := range pr.Threads {
+		if !thread.Resolved && len(thread.Comments) > 0 {
+			threads = append(threads, thread)
+		}
+	}
+	return threads
+}
+
+func runFollowup(cmd *cobra.Command, args []string) error {
+	prURL := args[0]
+	repo, number, err := parsePullRequestURL(prURL)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if vcs.IsGitLab(prURL, os.Getenv("GITLAB_URL")) {
+		return withExitCode(exitConfig, fmt.Errorf("monday followup supports GitHub pull requests only"))
+	}
+	if outputLevel() == levelQuiet {
+		restore, err := silenceStdout()
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+	sum, err := runFollowupWorkflow(cmd.Context(), newLogger(), repo, number, prURL)
+	if jsonOutput() && sum != nil {
+		if writeErr := writeJSON(sum); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+// runFollowupWorkflow addresses the unresolved review comments of the pull request number of
+// repo at prURL in a run of its own, which is recorded, canceled, timed out, and reported like
+// the runs of issues and whose summary is returned along with its error.
+func runFollowupWorkflow(ctx context.Context, base *zap.Logger, repo string, number int, prURL string) (sum *summary.Summary, err error) {
+	registerSecrets()
+	repoURL := "https://" + repo + ".git"
+	runID := newRunID(fmt.Sprintf("pr-%d", number))
+	sum = summary.New(runID, "", repoURL)
+	sum.PRURL = prURL
+	ctx, scope, err := startRun(ctx, base, sum, zap.String("pr_url", prURL), zap.String("repo", repoURL))
+	if err != nil {
+		return nil, err
+	}
+	defer scope.end(&err)
+	log, logPath, summaryDir := scope.log, scope.logPath, scope.dir
+
+	fmt.Printf("🚀 Following up on %s (run %s)\n", prURL, runID)
+	fmt.Printf("📄 Run log: %s\n", logPath)
+
+	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
+	if openaiAPIKey == "" {
+		return sum, withExitCode(exitConfig, fmt.Errorf("OPENAI_API_KEY environment variable is required"))
+	}
+	if _, signErr := signingEnabled(); signErr != nil {
+		return sum, withExitCode(exitConfig, signErr)
+	}
+	retries, err := retryPolicy()
+	if err != nil {
+		return sum, withExitCode(exitConfig, err)
+	}
+	host, err := newCodeHost(ctx, repoURL, "")
+	if err != nil {
+		return sum, withExitCode(exitConfig, err)
+	}
+	github, ok := host.(*githubHost)
+	if !ok {
+		return sum, withExitCode(exitConfig, fmt.Errorf("monday followup supports GitHub pull requests only"))
+	}
+	if ctx, err = scope.limit(); err != nil {
+		return sum, err
+	}
+
+	stageLog, endStage := startStage(ctx, log, sum, summaryDir, "fetch_review_comments")
+	pr, err := fetchReviewThreads(ctx, github, repo, number)
+	if err == nil {
+		switch {
+		case pr.State != "OPEN":
+			err = fmt.Errorf("pull request %s is %s", prURL, strings.ToLower(pr.State))
+		case pr.CrossRepository:
+			err = fmt.Errorf("pull request %s is from a fork, whose branch monday cannot push to", prURL)
+		}
+	}
+	endStage(err)
+	if err != nil {
+		return sum, err
+	}
+	threads := unresolvedThreads(pr)
+	stageLog.Info("Fetched review comments", zap.Int("threads", len(pr.Threads)), zap.Int("unresolved", len(threads)))
+	sum.IssueTitle = pr.Title
+	sum.Branch = pr.HeadBranch
+	notifyRun(log, notify.RunStarted, sum)
+	if len(threads) == 0 {
+		fmt.Printf("✅ %s has no unresolved review comments\n", prURL)
+		return sum, nil
+	}
+	fmt.Printf("💬 %d unresolved review thread(s) on %s\n", len(threads), pr.Title)
+
+	if err := checkCanceled(ctx); err != nil {
+		return sum, err
+	}
+	stageLog, endStage = startStage(ctx, log, sum, summaryDir, "prepare_workspace")
+	workDir, err := prepareFollowupWorkspace(ctx, stageLog, repoURL, runID, pr.HeadBranch)
+	endStage(err)
+	if err != nil {
+		return sum, err
+	}
+	sum.Workspace = workDir
+
+	agentPrompt := followupPrompt(pr, threads, workDir)
+	repoCfg, err := loadRepoConfig(workDir)
+	if err != nil {
+		return sum, withExitCode(exitConfig, err)
+	}
+	gates := resolveGates(workDir, repoCfg)
+
+	if err := checkCanceled(ctx); err != nil {
+		return sum, err
+	}
+	stageLog, endStage = startStage(ctx, log, sum, summaryDir, "agent")
+	stageLog.Info("Running Codex CLI on the review comments", zap.Int("threads", len(threads)))
+	usage, err := runCodex(ctx, stageLog, workDir, agentPrompt, openaiAPIKey, summaryDir)
+	endStage(err)
+	sum.AgentInputTokens = usage.InputTokens
+	sum.AgentOutputTokens = usage.OutputTokens
+	if usage.HasCost {
+		sum.AgentCostUSD = &usage.CostUSD
+	}
+	if err != nil {
+		return sum, withExitCode(exitAgentFailed, fmt.Errorf("failed to run Codex: %w", err))
+	}
+	if err := runGates(ctx, log, sum, summaryDir, gates, agentPrompt, openaiAPIKey); err != nil {
+		return sum, err
+	}
+
+	if err := checkCanceled(ctx); err != nil {
+		return sum, err
+	}
+	stageLog, endStage = startStage(ctx, log, sum, summaryDir, "commit")
+	files, err := commitFollowup(ctx, stageLog, workDir, pr, threads)
+	endStage(err)
+	if err != nil {
+		return sum, err
+	}
+	sum.FilesChanged = files
+
+	stageLog, endStage = startStage(ctx, log, sum, summaryDir, "push")
+	stageLog.Info("Pushing branch to origin")
+	err = pushOnce(ctx, stageLog, retries, host, workDir, pr.HeadBranch)
+	endStage(err)
+	if err != nil {
+		return sum, withExitCode(exitPublishFailed, fmt.Errorf("failed to push branch: %w", err))
+	}
+
+	commit, err := gitCommand(workDir, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		log.Warn("Failed to look up the fixup commit", zap.Error(err))
+	}
+	if err := commentOnPullRequest(ctx, github, prURL, followupComment(strings.TrimSpace(string(commit)), threads)); err != nil {
+		log.Warn("Failed to comment on the pull request", zap.Error(err))
+	}
+
+	removeWorkspace(log, workDir)
+	fmt.Printf("✅ Pushed a fixup commit for %d review thread(s) to %s\n", len(threads), pr.HeadBranch)
+	log.Info("Follow-up completed successfully", zap.String("branch", pr.HeadBranch))
+	return sum, nil
+}
+
+// prepareFollowupWorkspace clones repoURL into the directory of the run called runID and
+// checks out branch, the head branch of the pull request, and returns the clone's path.
+func prepareFollowupWorkspace(ctx context.Context, log *zap.Logger, repoURL, runID, branch string) (string, error) {
+	workDir, err := runWorkspace(runID)
+	if err != nil {
+		return "", err
+	}
+	progressf("   cloning %s into %s\n", redact.String(repoURL), workDir)
+	log.Info("Cloning repository", zap.String("repo_url", repoURL), zap.String("work_dir", workDir))
+	if err := cloneRepository(ctx, log, repoURL, workDir, branch); err != nil {
+		return "", fmt.Errorf("failed to clone repository: %w", err)
+	}
+	if err := applySparsePaths(ctx, log, workDir); err != nil {
+		return "", err
+	}
+	// A clone limited to the run's branches is a single-branch clone of the base branch.
+	progressf("   checking out %s\n", branch)
+	fetchArgs := []string{"fetch", "origin", fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch)}
+	if cloneDepth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", strconv.Itoa(cloneDepth))
+	}
+	if err := runGitCommand(ctx, log, workDir, fetchArgs...); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", branch, err)
+	}
+	if err := runGitCommand(ctx, log, workDir, "checkout", "-B", branch, "origin/"+branch); err != nil {
+		return "", fmt.Errorf("failed to check out %s: %w", branch, err)
+	}
+	return workDir, nil
+}
+
+// followupPrompt returns the prompt asking the agent to address threads of pr, showing the
+// code each one is on as checked out in workDir.
+func followupPrompt(pr *reviewedPullRequest, threads []reviewThread, workDir string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address the review comments below on the pull request %q. Change the code as each\n", pr.Title)
+	b.WriteString("comment asks, or as needed to answer its concern; leave code no comment is about alone.\n")
+	for i, thread := range threads {
+		location := thread.Path
+		if thread.Line > 0 {
+			location += fmt.Sprintf(":%d", thread.Line)
+		}
+		if thread.Outdated {
+			location += " (outdated: the code changed since)"
+		}
+		fmt.Fprintf(&b, "\n## Comment %d on %s\n", i+1, location)
+		for _, c := range thread.Comments {
+			fmt.Fprintf(&b, "\n**%s:** %s\n", c.Author, strings.TrimSpace(c.Body))
+		}
+		if code := fileContext(filepath.Join(workDir, thread.Path), thread.Line); code != "" {
+			fmt.Fprintf(&b, "\n```\n%s```\n", code)
+		}
+	}
+	return b.String()
+}
+
+// fileContext returns the numbered lines of the file at path around line, or "" if line is 0
+// or the file cannot be read.
+func fileContext(path string, line int) string {
+	if line <= 0 {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if line > len(lines) {
+		return ""
+	}
+	first := max(line-followupContextLines, 1)
+	last := min(line+followupContextLines, len(lines))
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s%5d  %s\n", marker, n, lines[n-1])
+	}
+	return b.String()
+}
+
+// commitFollowup stages and commits everything the agent changed in the workspace dir as a
+// fixup! commit of the last commit of the branch, and returns the committed files.
+func commitFollowup(ctx context.Context, log *zap.Logger, dir string, pr *reviewedPullRequest, threads []reviewThread) ([]string, error) {
+	if err := runGitCommand(ctx, log, dir, "add", "."); err != nil {
+		return nil, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	files, err := stagedFiles(dir)
+	if err != nil {
+		log.Warn("Failed to check staged changes", zap.Error(err))
+	}
+	if err == nil && len(files) == 0 {
+		return nil, errNothingToCommit
+	}
+	subject, err := gitCommand(dir, "log", "-1", "--format=%s").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the last commit of the branch: %w", err)
+	}
+	args, err := commitArgs(followupCommitMessage(strings.TrimSpace(string(subject)), pr, threads))
+	if err != nil {
+		return nil, err
+	}
+	commitCtx, err := commitContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := runGitCommand(commitCtx, log, dir, args...); err != nil {
+		return nil, fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return files, nil
+}
+
+// followupCommitMessage returns the message of the fixup! commit of the commit with subject
+// that addresses threads of pr.
+func followupCommitMessage(subject string, pr *reviewedPullRequest, threads []reviewThread) string {
+	for strings.HasPrefix(subject, "fixup! ") {
+		subject = strings.TrimPrefix(subject, "fixup! ")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "fixup! %s\n\nAddress review comments on %s:\n", subject, pr.URL)
+	for _, thread := range threads {
+		fmt.Fprintf(&b, "\n- %s", thread.Comments[0].URL)
+	}
+	return b.String()
+}
+
+// followupComment returns the comment on the pull request saying commit addresses threads.
+func followupComment(commit string, threads []reviewThread) string {
+	var b strings.Builder
+	if commit == "" {
+		commit = "a new commit"
+	}
+	fmt.Fprintf(&b, "monday addressed these review comments in %s:\n", commit)
+	for _, thread := range threads {
+		first := thread.Comments[0]
+		fmt.Fprintf(&b, "\n- [%s](%s) by @%s", thread.Path, first.URL, first.Author)
+	}
+	return b.String()
+}
+
+// commentOnPullRequest posts body as a comment on the pull request at prURL.
+func commentOnPullRequest(ctx context.Context, host *githubHost, prURL, body string) error {
+	cmd, err := host.gh(ctx, "pr", "comment", prURL, "--body", body)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, redact.String(strings.TrimSpace(string(out))))
+	}
+	return nil
+}
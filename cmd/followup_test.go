@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+)
+
+func TestParsePullRequestURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		wantRepo   string
+		wantNumber int
+		wantError  bool
+	}{
+		{url: "https://github.com/acme/app/pull/42", wantRepo: "github.com/acme/app", wantNumber: 42},
+		{url: "https://github.com/acme/app/pull/42/files?w=1", wantRepo: "github.com/acme/app", wantNumber: 42},
+		{url: "https://github.example.com/acme/app/pull/7#discussion_r1", wantRepo: "github.example.com/acme/app", wantNumber: 7},
+		{url: "https://github.com/acme/app/issues/42", wantError: true},
+		{url: "https://github.com/acme/app/pull/latest", wantError: true},
+		{url: "acme/app#42", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			repo, number, err := parsePullRequestURL(tt.url)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("parsePullRequestURL() error = %v, wantError %v", err, tt.wantError)
+			}
+			if repo != tt.wantRepo || number != tt.wantNumber {
+				t.Errorf("parsePullRequestURL() = %q, %d, want %q, %d", repo, number, tt.wantRepo, tt.wantNumber)
+			}
+		})
+	}
+}
+
+const reviewThreadsResponse = `{"data": {"repository": {"pullRequest": {
+  "title": "feat: Add CSV export", "url": "https://github.com/acme/app/pull/42", "state": "OPEN",
+  "headRefName": "feature/del_163", "isCrossRepository": false,
+  "reviewThreads": {"nodes": [
+    {"isResolved": true, "isOutdated": false, "path": "export.go", "line": 3,
+     "comments": {"nodes": [{"author": {"login": "alice"}, "body": "Typo", "url": "https://github.com/acme/app/pull/42#discussion_r1"}]}},
+    {"isResolved": false, "isOutdated": false, "path": "export.go", "line": 12,
+     "comments": {"nodes": [
+       {"author": {"login": "alice"}, "body": "Close the file here.", "url": "https://github.com/acme/app/pull/42#discussion_r2"},
+       {"author": {"login": "bob"}, "body": "And check the error.", "url": "https://github.com/acme/app/pull/42#discussion_r3"}]}},
+    {"isResolved": false, "isOutdated": true, "path": "README.md", "line": null,
+     "comments": {"nodes": [{"author": {"login": "bob"}, "body": "Document the flag.", "url": "https://github.com/acme/app/pull/42#discussion_r4"}]}}
+  ]}
+}}}}`
+
+func TestParseReviewThreads(t *testing.T) {
+	pr, err := parseReviewThreads([]byte(reviewThreadsResponse))
+	if err != nil {
+		t.Fatalf("parseReviewThreads() error = %v", err)
+	}
+	if pr.HeadBranch != "feature/del_163" || pr.State != "OPEN" || pr.CrossRepository {
+		t.Errorf("parseReviewThreads() = %+v", pr)
+	}
+	threads := unresolvedThreads(pr)
+	if len(threads) != 2 {
+		t.Fatalf("unresolvedThreads() = %+v, want 2 threads", threads)
+	}
+	if threads[0].Line != 12 || len(threads[0].Comments) != 2 || threads[0].Comments[1].Author != "bob" {
+		t.Errorf("first unresolved thread = %+v", threads[0])
+	}
+	if threads[1].Line != 0 || !threads[1].Outdated {
+		t.Errorf("second unresolved thread = %+v, want an outdated thread without a line", threads[1])
+	}
+
+	if _, err := parseReviewThreads([]byte(`{"data": {"repository": {"pullRequest": null}}, "errors": [{"message": "Could not resolve to a PullRequest with the number of 9."}]}`)); err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("parseReviewThreads() error = %v, want the GraphQL error", err)
+	}
+}
+
+func TestFollowupPrompt(t *testing.T) {
+	dir := t.TempDir()
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, "line "+strings.Repeat("x", i%3))
+	}
+	lines[11] = "f, _ := os.Create(path)"
+	if err := os.WriteFile(filepath.Join(dir, "export.go"), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := parseReviewThreads([]byte(reviewThreadsResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := followupPrompt(pr, unresolvedThreads(pr), dir)
+	for _, want := range []string{
+		`pull request "feat: Add CSV export"`,
+		"## Comment 1 on export.go:12\n",
+		"**alice:** Close the file here.\n\n**bob:** And check the error.\n",
+		">   12  f, _ := os.Create(path)\n",
+		"     4  line x\n",
+		"    20  line xx\n```\n",
+		"## Comment 2 on README.md (outdated: the code changed since)\n\n**bob:** Document the flag.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("followupPrompt() lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Typo") || strings.Contains(got, "    21  ") || strings.Contains(got, "     3  ") {
+		t.Errorf("followupPrompt() has a resolved thread or too much context:\n%s", got)
+	}
+}
+
+func TestFollowupCommitMessage(t *testing.T) {
+	pr, err := parseReviewThreads([]byte(reviewThreadsResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := followupCommitMessage("fixup! feat: Add CSV export", pr, unresolvedThreads(pr))
+	want := "fixup! feat: Add CSV export\n\nAddress review comments on https://github.com/acme/app/pull/42:\n\n" +
+		"- https://github.com/acme/app/pull/42#discussion_r2\n- https://github.com/acme/app/pull/42#discussion_r4"
+	if got != want {
+		t.Errorf("followupCommitMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestPrepareFollowupWorkspaceAndCommit(t *testing.T) {
+	origin := t.TempDir()
+	git(t, origin, "init", "-q", "-b", "main")
+	git(t, origin, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+	git(t, origin, "checkout", "-q", "-b", "feature/del_163")
+	git(t, origin, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "feat: Add CSV export")
+	git(t, origin, "checkout", "-q", "main")
+
+	origCache := cacheDir
+	t.Cleanup(func() {
+		cacheDir = origCache
+		mirrorCacheOnce, mirrorCache = sync.Once{}, nil
+	})
+	cacheDir = t.TempDir()
+	mirrorCacheOnce, mirrorCache = sync.Once{}, nil
+	t.Setenv("MONDAY_WORKSPACE_ROOT", t.TempDir())
+	t.Setenv("MONDAY_GIT_AUTHOR_NAME", "monday")
+	t.Setenv("MONDAY_GIT_AUTHOR_EMAIL", "monday@example.com")
+
+	dir, err := prepareFollowupWorkspace(context.Background(), zap.NewNop(), origin, "run-1", "feature/del_163")
+	if err != nil {
+		t.Fatalf("prepareFollowupWorkspace() error = %v", err)
+	}
+	if branch, _ := gitCommand(dir, "branch", "--show-current").Output(); strings.TrimSpace(string(branch)) != "feature/del_163" {
+		t.Errorf("checked out %q, want feature/del_163", branch)
+	}
+
+	pr, err := parseReviewThreads([]byte(reviewThreadsResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	threads := unresolvedThreads(pr)
+	if _, err := commitFollowup(context.Background(), zap.NewNop(), dir, pr, threads); !errors.Is(err, errNothingToCommit) {
+		t.Errorf("commitFollowup() error = %v, want %v", err, errNothingToCommit)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "export.go"), []byte("package export\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := commitFollowup(context.Background(), zap.NewNop(), dir, pr, threads)
+	if err != nil || len(files) != 1 || files[0] != "export.go" {
+		t.Fatalf("commitFollowup() = %v, %v, want export.go committed", files, err)
+	}
+	if subject, _ := gitCommand(dir, "log", "-1", "--format=%s").Output(); strings.TrimSpace(string(subject)) != "fixup! feat: Add CSV export" {
+		t.Errorf("commit subject = %q, want the fixup! of the branch's last commit", subject)
+	}
+}
+
+func TestRunFollowupWorkflowEndsLikeRuns(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	orig := fetchReviewThreads
+	t.Cleanup(func() { fetchReviewThreads = orig })
+
+	tests := []struct {
+		name     string
+		timeout  string
+		fetch    func(ctx context.Context) (*reviewedPullRequest, error)
+		wantErr  string
+		wantCode int
+	}{
+		{
+			name:     "panic",
+			fetch:    func(context.Context) (*reviewedPullRequest, error) { panic("boom") },
+			wantErr:  "workflow panicked: boom",
+			wantCode: exitFailure,
+		},
+		{
+			name:    "timeout",
+			timeout: "10ms",
+			fetch: func(ctx context.Context) (*reviewedPullRequest, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			wantErr:  "total timeout",
+			wantCode: exitTimedOut,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("MONDAY_HOME", home)
+			t.Setenv("MONDAY_TOTAL_TIMEOUT", tt.timeout)
+			fetchReviewThreads = func(ctx context.Context, _ *githubHost, _ string, _ int) (*reviewedPullRequest, error) {
+				return tt.fetch(ctx)
+			}
+
+			sum, err := runFollowupWorkflow(context.Background(), zap.NewNop(), "github.com/acme/app", 42, "https://github.com/acme/app/pull/42")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("runFollowupWorkflow() error = %v, want %q", err, tt.wantErr)
+			}
+			if code := exitCodeFor(err); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			recorded, loadErr := summary.Load(filepath.Join(home, "runs", sum.RunID, "summary.json"))
+			if loadErr != nil || recorded.Status != summary.StatusFailed {
+				t.Errorf("recorded summary = %+v, %v, want status %s", recorded, loadErr, summary.StatusFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"monday/redact"
+	"monday/summary"
+)
+
+const (
+	// repoConfigFile is the monday configuration checked in at the root of a repository.
+	repoConfigFile = ".monday.yml"
+	// gatesLogFile is the name of the file in the run directory with the output of the gates.
+	gatesLogFile = "gates.log"
+	// testsGate is the name of the gate that runs the repository's tests.
+	testsGate = "tests"
+	// maxGateOutputInPrompt is how much of the end of a failed gate's output the agent is shown.
+	maxGateOutputInPrompt = 16 << 10
+	// maxGateOutputInSummary is how much of the end of a failed gate's output the summary keeps.
+	maxGateOutputInSummary = 4 << 10
+)
+
+var (
+	// testCommand is the command that runs the repository's tests after the agent.
+	testCommand string
+	// skipTests turns the tests gate off.
+	skipTests bool
+	// testFixAttempts is how many more times the agent runs to fix failing gates.
+	testFixAttempts int
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&testCommand, "test-command", "", "Shell command that runs the repository's tests before committing, also --verify-cmd (default: $MONDAY_TEST_COMMAND, test in .monday.yml, or detected from go.mod, package.json, or pytest configuration)")
+	rootCmd.Flags().BoolVar(&skipTests, "skip-tests", false, "Commit the agent's changes without running the repository's tests")
+	rootCmd.Flags().IntVar(&testFixAttempts, "test-fix-attempts", 0, "How many times the agent is asked to fix failing tests or gates before the run fails, also --max-fix-iterations")
+}
+
+// gate is a command the agent's changes must pass before they are committed.
+type gate struct {
+	// Name identifies the gate, e.g. "lint"
+	Name string `yaml:"name"`
+	// Run is the shell command of the gate
+	Run string `yaml:"run"`
+}
+
+// repoConfig is the content of a repository's .monday.yml.
+type repoConfig struct {
+	// Test is the command that runs the repository's tests
+	Test string `yaml:"test"`
+	// Gates are the commands, such as linters and builds, run in order before the tests
+	Gates []gate `yaml:"gates"`
+	// Hooks are the commands run at points of the run's lifecycle
+	Hooks lifecycleHooks `yaml:"hooks"`
+}
+
+// gateName is what gate names may look like; they become stage names.
+var gateName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// loadRepoConfig reads the .monday.yml of the repository in dir; a missing file is an empty
+// configuration.
+func loadRepoConfig(dir string) (*repoConfig, error) {
+	var cfg repoConfig
+	data, err := os.ReadFile(filepath.Join(dir, repoConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", repoConfigFile, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", repoConfigFile, err)
+	}
+
+	seen := map[string]bool{testsGate: true}
+	for _, g := range cfg.Gates {
+		switch {
+		case !gateName.MatchString(g.Name):
+			return nil, fmt.Errorf("invalid gate name %q in %s: use lowercase letters, digits, - and _", g.Name, repoConfigFile)
+		case seen[g.Name]:
+			return nil, fmt.Errorf("duplicate gate %q in %s", g.Name, repoConfigFile)
+		case strings.TrimSpace(g.Run) == "":
+			return nil, fmt.Errorf("gate %q in %s has no command to run", g.Name, repoConfigFile)
+		}
+		seen[g.Name] = true
+	}
+	return &cfg, nil
+}
+
+// resolveGates returns the gates of the repository in dir, whose .monday.yml is cfg, in the
+// order they run: those of cfg, then the tests unless they are skipped or there is no command
+// to run them.
+func resolveGates(dir string, cfg *repoConfig) []gate {
+	gates := cfg.Gates
+	if command := resolveTestCommand(dir, cfg); command != "" {
+		gates = append(gates[:len(gates):len(gates)], gate{Name: testsGate, Run: command})
+	}
+	return gates
+}
+
+// resolveTestCommand returns the command that runs the tests of the repository in dir, whose
+// .monday.yml is cfg, or "" if tests are skipped or there is no command to run.
+func resolveTestCommand(dir string, cfg *repoConfig) string {
+	switch {
+	case skipTests:
+		return ""
+	case testCommand != "":
+		return testCommand
+	case os.Getenv("MONDAY_TEST_COMMAND") != "":
+		return os.Getenv("MONDAY_TEST_COMMAND")
+	case cfg.Test != "":
+		return cfg.Test
+	default:
+		return detectTestCommand(dir)
+	}
+}
+
+// detectTestCommand returns the usual test command of the Go, Node.js, or Python repository
+// in dir, or "" if it does not recognize the repository.
+func detectTestCommand(dir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	contains := func(name, section string) bool {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		return err == nil && strings.Contains(string(data), section)
+	}
+
+	switch {
+	case exists("go.mod"):
+		return "go test ./..."
+	case hasNpmTestScript(filepath.Join(dir, "package.json")):
+		return "npm test"
+	case exists("pytest.ini"), exists("conftest.py"),
+		contains("pyproject.toml", "[tool.pytest"),
+		contains("setup.cfg", "[tool:pytest]"),
+		contains("tox.ini", "[pytest]"):
+		return "pytest"
+	default:
+		return ""
+	}
+}
+
+// hasNpmTestScript reports whether the package.json at path defines a test script other than
+// the placeholder npm init writes.
+func hasNpmTestScript(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+	script := pkg.Scripts["test"]
+	return script != "" && !strings.Contains(script, "no test specified")
+}
+
+// gateStage returns the name of the stage that runs the gate called name.
+func gateStage(name string) string {
+	if name == testsGate {
+		return testsGate
+	}
+	return "gate_" + name
+}
+
+// runGates runs gates in order in the workspace after the agent. When one fails, the agent
+// gets up to testFixAttempts more runs, with prompt and the gate's output, to fix it, after
+// each of which all gates run again; if a gate still fails, the run fails before committing.
+func runGates(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir string, gates []gate, prompt, apiKey string) error {
+	if len(gates) == 0 {
+		if !skipTests {
+			log.Info("No test command configured or detected; committing without running tests")
+		}
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		failed, output, err := runGatesOnce(ctx, log, sum, dir, gates, attempt)
+		if err == nil {
+			return nil
+		}
+		if err := checkCanceled(ctx); err != nil {
+			return err
+		}
+		if attempt >= testFixAttempts {
+			return withExitCode(exitGateFailed, fmt.Errorf("%s failed: %s: %w", failed.Name, failed.Run, err))
+		}
+
+		log.Warn("Gate failed; asking the agent to fix it", zap.String("gate", failed.Name), zap.Int("attempts_left", testFixAttempts-attempt))
+		stageLog, endStage := startStage(ctx, log, sum, dir, "fix_gates")
+		usage, err := runCodex(ctx, stageLog, sum.Workspace, gateFixPrompt(prompt, failed, output), apiKey, dir)
+		endStage(err)
+		sum.AgentInputTokens += usage.InputTokens
+		sum.AgentOutputTokens += usage.OutputTokens
+		if usage.HasCost {
+			cost := usage.CostUSD
+			if sum.AgentCostUSD != nil {
+				cost += *sum.AgentCostUSD
+			}
+			sum.AgentCostUSD = &cost
+		}
+		if err != nil {
+			return withExitCode(exitAgentFailed, fmt.Errorf("failed to run Codex: %w", err))
+		}
+	}
+}
+
+// runGatesOnce runs gates in order until one fails, and returns that gate with its output and
+// error.
+func runGatesOnce(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir string, gates []gate, attempt int) (gate, string, error) {
+	for _, g := range gates {
+		stageLog, endStage := startStage(ctx, log, sum, dir, gateStage(g.Name))
+		stageLog.Info("Running gate", zap.String("command", g.Run), zap.Int("attempt", attempt+1))
+		output, err := runShell(ctx, sum.Workspace, g.Run, nil, filepath.Join(dir, gatesLogFile))
+		endStage(err)
+		result := summary.Gate{Name: g.Name, Command: g.Run, Passed: err == nil}
+		if err != nil {
+			result.Output = tail(strings.TrimSpace(output), maxGateOutputInSummary)
+		}
+		sum.RecordGate(result)
+		if err != nil {
+			return g, output, err
+		}
+	}
+	return gate{}, "", nil
+}
+
+// runShell runs command with the shell in workDir, with env added to the environment, and
+// returns its combined output, with credentials redacted, which is also appended to logPath.
+func runShell(ctx context.Context, workDir, command string, env []string, logPath string) (string, error) {
+	shell := []string{"sh", "-c"}
+	if runtime.GOOS == "windows" && runContainer(ctx) == nil {
+		shell = []string{"cmd", "/C"}
+	}
+	cmd := newCommand(ctx, workDir, env, shell[0], append(shell[1:], command)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+
+	output := redact.String(out.String())
+	if f, openErr := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); openErr == nil {
+		fmt.Fprintf(f, "$ %s\n%s\n", command, output)
+		f.Close()
+	}
+	return output, err
+}
+
+// gateFixPrompt returns the prompt of an agent run that fixes the gate g the changes made for
+// prompt failed, showing the end of its output.
+func gateFixPrompt(prompt string, g gate, output string) string {
+	return fmt.Sprintf("%s\n\n## Failing %s\n\nThe changes made for this issue so far make `%s` fail. "+
+		"Fix the code so that it passes; do not delete or skip tests or checks.\n\n```\n%s\n```",
+		prompt, g.Name, g.Run, tail(strings.TrimSpace(output), maxGateOutputInPrompt))
+}
+
+// tail returns the last max bytes of s, marked as truncated if that is not all of s.
+func tail(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return "[truncated]\n" + s[len(s)-max:]
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDetectTestCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "go", files: map[string]string{"go.mod": "module x\n"}, want: "go test ./..."},
+		{name: "npm", files: map[string]string{"package.json": `{"scripts": {"test": "jest"}}`}, want: "npm test"},
+		{name: "npm placeholder", files: map[string]string{"package.json": `{"scripts": {"test": "echo \"Error: no test specified\" && exit 1"}}`}},
+		{name: "pytest ini", files: map[string]string{"pytest.ini": "[pytest]\n"}, want: "pytest"},
+		{name: "pyproject", files: map[string]string{"pyproject.toml": "[tool.pytest.ini_options]\n"}, want: "pytest"},
+		{name: "pyproject without pytest", files: map[string]string{"pyproject.toml": "[project]\n"}},
+		{name: "unknown", files: map[string]string{"Makefile": "all:\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := detectTestCommand(dir); got != tt.want {
+				t.Errorf("detectTestCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		skip bool
+		want string
+	}{
+		{name: "detected", want: "go test ./..."},
+		{name: "env", env: "make test", want: "make test"},
+		{name: "flag overrides env", flag: "go test -race ./...", env: "make test", want: "go test -race ./..."},
+		{name: "skipped", flag: "make test", skip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origCommand, origSkip := testCommand, skipTests
+			t.Cleanup(func() { testCommand, skipTests = origCommand, origSkip })
+			testCommand, skipTests = tt.flag, tt.skip
+			t.Setenv("MONDAY_TEST_COMMAND", tt.env)
+
+			if got := resolveTestCommand(dir, &repoConfig{}); got != tt.want {
+				t.Errorf("resolveTestCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveGates(t *testing.T) {
+	origCommand, origSkip := testCommand, skipTests
+	t.Cleanup(func() { testCommand, skipTests = origCommand, origSkip })
+	testCommand, skipTests = "", false
+	t.Setenv("MONDAY_TEST_COMMAND", "")
+
+	dir := t.TempDir()
+	config := "test: make test\ngates:\n  - name: lint\n    run: golangci-lint run\n  - name: build\n    run: go build ./...\n"
+	if err := os.WriteFile(filepath.Join(dir, repoConfigFile), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadRepoConfig(dir)
+	if err != nil {
+		t.Fatalf("loadRepoConfig() error = %v", err)
+	}
+	got := resolveGates(dir, cfg)
+	want := []gate{{Name: "lint", Run: "golangci-lint run"}, {Name: "build", Run: "go build ./..."}, {Name: "tests", Run: "make test"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveGates() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadRepoConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{name: "unknown key", config: "lint: golangci-lint run\n", want: "field lint not found"},
+		{name: "invalid name", config: "gates:\n  - name: Lint Check\n    run: x\n", want: "invalid gate name"},
+		{name: "duplicate", config: "gates:\n  - name: lint\n    run: x\n  - name: lint\n    run: y\n", want: "duplicate gate"},
+		{name: "reserved", config: "gates:\n  - name: tests\n    run: x\n", want: "duplicate gate"},
+		{name: "no command", config: "gates:\n  - name: lint\n", want: "no command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, repoConfigFile), []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadRepoConfig(dir); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadRepoConfig() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	logPath := filepath.Join(t.TempDir(), gatesLogFile)
+
+	output, err := runShell(context.Background(), "", "echo FAIL: TestLogin; exit 1", nil, logPath)
+	if err == nil {
+		t.Error("runShell() succeeded for a failing command")
+	}
+	if !strings.Contains(output, "FAIL: TestLogin") {
+		t.Errorf("runShell() output = %q", output)
+	}
+	if _, err := runShell(context.Background(), "", "echo $GREETING", []string{"GREETING=ok"}, logPath); err != nil {
+		t.Errorf("runShell() = %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"$ echo FAIL: TestLogin; exit 1\nFAIL: TestLogin\n", "$ echo $GREETING\nok\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("gates log missing %q in:\n%s", want, data)
+		}
+	}
+}
+
+func TestGateFixPrompt(t *testing.T) {
+	output := strings.Repeat("x", maxGateOutputInPrompt) + "FAIL: TestLogin"
+
+	got := gateFixPrompt("Fix the login form", gate{Name: "tests", Run: "go test ./..."}, output)
+	if !strings.HasPrefix(got, "Fix the login form\n\n## Failing tests\n") {
+		t.Errorf("gateFixPrompt() does not start with the issue prompt:\n%.200s", got)
+	}
+	for _, want := range []string{"`go test ./...`", "[truncated]\n", "FAIL: TestLogin\n```"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("gateFixPrompt() missing %q", want)
+		}
+	}
+	if len(got) > maxGateOutputInPrompt+500 {
+		t.Errorf("gateFixPrompt() is %d bytes long", len(got))
+	}
+}
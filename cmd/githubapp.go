@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"monday/redact"
+	"monday/vcs"
+)
+
+var (
+	githubAppOnce sync.Once
+	githubAppInst *vcs.GitHubApp
+	githubAppErr  error
+)
+
+// githubApp returns the GitHub App monday pushes and opens pull requests as, configured with
+// GITHUB_APP_ID, the private key in GITHUB_APP_PRIVATE_KEY or the file at
+// GITHUB_APP_PRIVATE_KEY_FILE, and optionally GITHUB_APP_INSTALLATION_ID. It returns nil if
+// GITHUB_APP_ID is not set.
+func githubApp() (*vcs.GitHubApp, error) {
+	githubAppOnce.Do(func() {
+		githubAppInst, githubAppErr = loadGitHubApp()
+	})
+	return githubAppInst, githubAppErr
+}
+
+// loadGitHubApp reads the GitHub App configuration for githubApp.
+func loadGitHubApp() (*vcs.GitHubApp, error) {
+	appID := os.Getenv("GITHUB_APP_ID")
+	if appID == "" {
+		return nil, nil
+	}
+	key := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"); len(key) == 0 && path != "" {
+		var err error
+		if key, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read GITHUB_APP_PRIVATE_KEY_FILE: %w", err)
+		}
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_FILE is required with GITHUB_APP_ID")
+	}
+	var installationID int64
+	if value := os.Getenv("GITHUB_APP_INSTALLATION_ID"); value != "" {
+		var err error
+		if installationID, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_APP_INSTALLATION_ID %q", value)
+		}
+	}
+	return vcs.NewGitHubApp(appID, key, installationID)
+}
+
+// credentials returns the token h runs gh with: GITHUB_TOKEN or, with a GitHub App, an
+// installation token of the app for h.repo, which git is then also configured to fetch and
+// push with. A new installation token is minted when the last one is about to expire.
+func (h *githubHost) credentials(ctx context.Context) (string, error) {
+	if h.app == nil {
+		return h.token, nil
+	}
+	token, err := h.app.Token(ctx, h.repo)
+	if err != nil {
+		return "", err
+	}
+	useGitHubToken(h.repo, token)
+	return token, nil
+}
+
+// useGitHubToken makes the git commands monday runs authenticate to the host of repo, in
+// HOST/OWNER/REPO form, with token instead of the credentials git is configured with, by
+// exporting it as GITHUB_TOKEN for gitCredentialHelper. Containerized runs pass GITHUB_TOKEN
+// on to the container.
+func useGitHubToken(repo, token string) {
+	redact.AddSecrets(token)
+	os.Setenv("GITHUB_TOKEN", token)
+	host, _, _ := strings.Cut(repo, "/")
+	key := "credential.https://" + host + ".helper"
+	// An empty helper drops the helpers configured before, such as a keychain holding the
+	// token of a person.
+	addGitConfigEnv(key, "")
+	addGitConfigEnv(key, gitCredentialHelper)
+}
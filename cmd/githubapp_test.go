@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadGitHubApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		appID          string
+		keyFile        string
+		installationID string
+		wantApp        bool
+		wantErr        string
+	}{
+		{name: "not configured"},
+		{name: "key file", appID: "12345", keyFile: keyFile, installationID: "42", wantApp: true},
+		{name: "no key", appID: "12345", wantErr: "GITHUB_APP_PRIVATE_KEY"},
+		{name: "missing key file", appID: "12345", keyFile: filepath.Join(t.TempDir(), "missing.pem"), wantErr: "GITHUB_APP_PRIVATE_KEY_FILE"},
+		{name: "invalid installation", appID: "12345", keyFile: keyFile, installationID: "acme", wantErr: "GITHUB_APP_INSTALLATION_ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_APP_ID", tt.appID)
+			t.Setenv("GITHUB_APP_PRIVATE_KEY", "")
+			t.Setenv("GITHUB_APP_PRIVATE_KEY_FILE", tt.keyFile)
+			t.Setenv("GITHUB_APP_INSTALLATION_ID", tt.installationID)
+
+			app, err := loadGitHubApp()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadGitHubApp() error = %v, want one about %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadGitHubApp() error = %v", err)
+			}
+			if (app != nil) != tt.wantApp {
+				t.Errorf("loadGitHubApp() = %v, want an app: %v", app, tt.wantApp)
+			}
+		})
+	}
+}
+
+func TestUseGitHubToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_person")
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	t.Setenv("GIT_CONFIG_KEY_0", "")
+	t.Setenv("GIT_CONFIG_VALUE_0", "")
+	t.Setenv("GIT_CONFIG_KEY_1", "")
+	t.Setenv("GIT_CONFIG_VALUE_1", "")
+
+	useGitHubToken("github.com/acme/app", "ghs_one")
+	useGitHubToken("github.com/acme/app", "ghs_two")
+
+	if got := os.Getenv("GITHUB_TOKEN"); got != "ghs_two" {
+		t.Errorf("GITHUB_TOKEN = %q, want the latest installation token", got)
+	}
+	if got := os.Getenv("GIT_CONFIG_COUNT"); got != "2" {
+		t.Fatalf("GIT_CONFIG_COUNT = %q, want 2", got)
+	}
+	if key := os.Getenv("GIT_CONFIG_KEY_0"); key != "credential.https://github.com.helper" || os.Getenv("GIT_CONFIG_VALUE_0") != "" {
+		t.Errorf("first git config entry = %q=%q, want the helpers reset", key, os.Getenv("GIT_CONFIG_VALUE_0"))
+	}
+	if value := os.Getenv("GIT_CONFIG_VALUE_1"); value != gitCredentialHelper {
+		t.Errorf("second git config entry = %q, want the credential helper", value)
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/history"
+	"monday/summary"
+)
+
+var (
+	historyRepo   string
+	historyIssue  string
+	historyStatus string
+	historySince  string
+	historyUntil  string
+	historyLimit  int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List past workflow runs",
+	Long: `List past workflow runs of both the CLI and the server, most recent last.
+--since and --until accept a date (2006-01-02), an RFC 3339 timestamp, or a
+look-back period such as 7d or 12h.`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyRepo, "repo", "", "Only runs whose repository URL or path contains this value")
+	historyCmd.Flags().StringVar(&historyIssue, "issue", "", "Only runs for this Linear issue")
+	historyCmd.Flags().StringVar(&historyStatus, "status", "", "Only runs with this status: running, succeeded, failed, or canceled")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only runs started at or after this time")
+	historyCmd.Flags().StringVar(&historyUntil, "until", "", "Only runs started at or before this time")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Show at most this many of the most recent runs (0 for all)")
+	addDeprecatedJSONFlag(historyCmd)
+	historyCmd.Flags().MarkDeprecated("json", "use --output json")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	filter, err := buildHistoryFilter(historyRepo, historyIssue, historyStatus, historySince, historyUntil)
+	if err != nil {
+		return err
+	}
+	runs, err := queryRuns(filter)
+	if err != nil {
+		return err
+	}
+	if historyLimit > 0 && len(runs) > historyLimit {
+		runs = runs[len(runs)-historyLimit:]
+	}
+
+	if jsonOutput() {
+		return history.WriteJSON(resultOut, runs)
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No matching runs")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tRUN ID\tISSUE\tREPO\tSTATUS\tDURATION\tPULL REQUEST")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", run.StartedAt.Local().Format(time.DateTime), run.RunID, run.IssueID,
+			run.Repo, run.Status, summary.FormatSeconds(run.DurationSeconds), run.PRURL)
+	}
+	return w.Flush()
+}
+
+// buildHistoryFilter builds a run filter from the string values of the history filters, as
+// given on the command line or in a query string.
+func buildHistoryFilter(repo, issue, status, since, until string) (history.Filter, error) {
+	filter := history.Filter{Repo: repo, Issue: issue, Status: status}
+	switch status {
+	case "", summary.StatusRunning, summary.StatusSucceeded, summary.StatusFailed, summary.StatusCanceled:
+	default:
+		return filter, fmt.Errorf("invalid status %q: must be running, succeeded, failed, or canceled", status)
+	}
+
+	now := time.Now()
+	var err error
+	if since != "" {
+		if filter.Since, err = parseTimeBound(since, now); err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if until != "" {
+		if filter.Until, err = parseTimeBound(until, now); err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// parseTimeBound parses an RFC 3339 timestamp, a local date, or a look-back period relative to now.
+func parseTimeBound(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	period, err := parseSince(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date, timestamp, or period", s)
+	}
+	return now.Add(-period), nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{input: "2025-06-01T10:00:00Z", expected: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{input: "2025-06-01", expected: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)},
+		{input: "7d", expected: now.Add(-7 * 24 * time.Hour)},
+		{input: "2h", expected: now.Add(-2 * time.Hour)},
+		{input: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseTimeBound(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseTimeBound(%q) expected an error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTimeBound(%q) returned error: %v", tt.input, err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("parseTimeBound(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"monday/summary"
+	"monday/workflow"
+)
+
+// Lifecycle points of a run at which hooks, and the steps registered with the workflow
+// package, run.
+const (
+	hookPreAgent  = string(workflow.PreAgent)
+	hookPostAgent = string(workflow.PostAgent)
+	hookPreCommit = string(workflow.PreCommit)
+	hookPostPR    = string(workflow.PostPR)
+)
+
+// hooksLogFile is the name of the file in the run directory with the output of the hooks.
+const hooksLogFile = "hooks.log"
+
+// hookCommands are the shell commands of a hook, given in .monday.yml as one command or a list.
+type hookCommands []string
+
+// UnmarshalYAML decodes a hook given either as one command or as a list of commands.
+func (h *hookCommands) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*h = hookCommands{node.Value}
+		return nil
+	}
+	var commands []string
+	if err := node.Decode(&commands); err != nil {
+		return err
+	}
+	*h = commands
+	return nil
+}
+
+// lifecycleHooks are the hooks of a repository's .monday.yml.
+type lifecycleHooks struct {
+	PreAgent  hookCommands `yaml:"pre_agent"`
+	PostAgent hookCommands `yaml:"post_agent"`
+	PreCommit hookCommands `yaml:"pre_commit"`
+	PostPR    hookCommands `yaml:"post_pr"`
+}
+
+// commands returns the commands of the hook called name: those of the repository, followed by
+// the one in MONDAY_HOOK_<NAME>, e.g. MONDAY_HOOK_POST_PR.
+func (h lifecycleHooks) commands(name string) []string {
+	var commands []string
+	switch name {
+	case hookPreAgent:
+		commands = h.PreAgent
+	case hookPostAgent:
+		commands = h.PostAgent
+	case hookPreCommit:
+		commands = h.PreCommit
+	case hookPostPR:
+		commands = h.PostPR
+	}
+	if command := os.Getenv("MONDAY_HOOK_" + strings.ToUpper(name)); command != "" {
+		commands = append(commands[:len(commands):len(commands)], command)
+	}
+	return commands
+}
+
+// runHook runs the commands of the hook called name in the workspace, in order, with the
+// metadata of the run in sum in their environment, and then the steps registered for name
+// with the workflow package, each as a stage of its own. The output of the commands goes to
+// hooks.log in dir. The first command or step that fails fails the hook.
+func runHook(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir string, hooks lifecycleHooks, name string) error {
+	if commands := hooks.commands(name); len(commands) > 0 {
+		stageLog, endStage := startStage(ctx, log, sum, dir, "hook_"+name)
+		env := hookEnv(sum, dir, name)
+		for _, command := range commands {
+			stageLog.Info("Running hook", zap.String("command", command))
+			if _, err := runShell(ctx, sum.Workspace, command, env, filepath.Join(dir, hooksLogFile)); err != nil {
+				err = fmt.Errorf("%s hook failed: %s: %w", name, command, err)
+				endStage(err)
+				return err
+			}
+		}
+		endStage(nil)
+	}
+
+	for _, step := range workflow.Steps(workflow.Point(name)) {
+		stageLog, endStage := startStage(ctx, log, sum, dir, "step_"+step.Name())
+		stageLog.Info("Running step", zap.String("hook", name))
+		err := step.Run(ctx, stepRun(sum, dir, stageLog))
+		endStage(err)
+		if err != nil {
+			return fmt.Errorf("%s step %s failed: %w", name, step.Name(), err)
+		}
+	}
+	return nil
+}
+
+// stepRun describes the run in sum, with its files in dir, to a registered step logging to log.
+func stepRun(sum *summary.Summary, dir string, log *zap.Logger) *workflow.Run {
+	return &workflow.Run{
+		ID:         sum.RunID,
+		Dir:        dir,
+		Workspace:  sum.Workspace,
+		IssueID:    sum.IssueID,
+		IssueTitle: sum.IssueTitle,
+		IssueURL:   sum.IssueURL,
+		Repo:       sum.Repo,
+		Branch:     sum.Branch,
+		PRURL:      sum.PRURL,
+		Log:        log,
+	}
+}
+
+// hookEnv returns the environment variables describing the run in sum, with its files in dir,
+// to the hook called name.
+func hookEnv(sum *summary.Summary, dir, name string) []string {
+	return []string{
+		"MONDAY_HOOK=" + name,
+		"MONDAY_RUN_ID=" + sum.RunID,
+		"MONDAY_RUN_DIR=" + dir,
+		"MONDAY_ISSUE_ID=" + sum.IssueID,
+		"MONDAY_ISSUE_TITLE=" + sum.IssueTitle,
+		"MONDAY_ISSUE_URL=" + sum.IssueURL,
+		"MONDAY_REPO=" + sum.Repo,
+		"MONDAY_BRANCH=" + sum.Branch,
+		"MONDAY_WORKSPACE=" + sum.Workspace,
+		"MONDAY_PR_URL=" + sum.PRURL,
+	}
+}
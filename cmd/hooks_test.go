@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+	"monday/workflow"
+)
+
+func TestLoadRepoConfigHooks(t *testing.T) {
+	dir := t.TempDir()
+	config := "hooks:\n  pre_agent: make generate\n  pre_commit:\n    - gofmt -w .\n    - ./scripts/license-headers.sh\n"
+	if err := os.WriteFile(filepath.Join(dir, repoConfigFile), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadRepoConfig(dir)
+	if err != nil {
+		t.Fatalf("loadRepoConfig() error = %v", err)
+	}
+	want := lifecycleHooks{
+		PreAgent:  hookCommands{"make generate"},
+		PreCommit: hookCommands{"gofmt -w .", "./scripts/license-headers.sh"},
+	}
+	if !reflect.DeepEqual(cfg.Hooks, want) {
+		t.Errorf("hooks = %+v, want %+v", cfg.Hooks, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, repoConfigFile), []byte("hooks:\n  post_merge: x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadRepoConfig(dir); err == nil {
+		t.Error("loadRepoConfig() accepted an unknown hook")
+	}
+}
+
+func TestHookCommandsAddsUserHook(t *testing.T) {
+	t.Setenv("MONDAY_HOOK_POST_PR", "./notify.sh")
+	hooks := lifecycleHooks{PostPR: hookCommands{"echo done"}}
+
+	if got, want := hooks.commands(hookPostPR), []string{"echo done", "./notify.sh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("commands(post_pr) = %v, want %v", got, want)
+	}
+	if got := hooks.commands(hookPreAgent); len(got) != 0 {
+		t.Errorf("commands(pre_agent) = %v, want none", got)
+	}
+}
+
+func TestRunHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("MONDAY_HOOK_PRE_COMMIT", "")
+	dir := t.TempDir()
+	out := filepath.Join(dir, "env.txt")
+	sum := summary.New("run-1", "DEL-1", "repo")
+	sum.Branch = "del-1-fix"
+	hooks := lifecycleHooks{PreCommit: hookCommands{`echo "$MONDAY_HOOK $MONDAY_ISSUE_ID $MONDAY_BRANCH" > ` + out, "exit 3", "touch never"}}
+
+	err := runHook(context.Background(), zap.NewNop(), sum, dir, hooks, hookPreCommit)
+	if err == nil || !strings.Contains(err.Error(), "pre_commit hook failed: exit 3") {
+		t.Errorf("runHook() error = %v", err)
+	}
+	data, readErr := os.ReadFile(out)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	if got := strings.TrimSpace(string(data)); got != "pre_commit DEL-1 del-1-fix" {
+		t.Errorf("hook environment = %q", got)
+	}
+	if _, statErr := os.Stat("never"); statErr == nil {
+		os.Remove("never")
+		t.Error("runHook() ran the commands after the failing one")
+	}
+	if stage := sum.FailedStage(); stage != "hook_pre_commit" {
+		t.Errorf("FailedStage() = %q, want hook_pre_commit", stage)
+	}
+}
+
+func TestRunHookRunsRegisteredSteps(t *testing.T) {
+	t.Cleanup(workflow.Reset)
+	t.Setenv("MONDAY_HOOK_POST_PR", "")
+	var got []string
+	workflow.Register(workflow.PostPR, workflow.StepFunc("announce", func(_ context.Context, run *workflow.Run) error {
+		got = append(got, run.PRURL)
+		return nil
+	}))
+	workflow.Register(workflow.PostPR, workflow.StepFunc("broken", func(context.Context, *workflow.Run) error {
+		return errors.New("boom")
+	}))
+	sum := summary.New("run-1", "DEL-1", "repo")
+	sum.PRURL = "https://github.com/acme/app/pull/7"
+
+	err := runHook(context.Background(), zap.NewNop(), sum, t.TempDir(), lifecycleHooks{}, hookPostPR)
+	if err == nil || err.Error() != "post_pr step broken failed: boom" {
+		t.Errorf("runHook() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{sum.PRURL}) {
+		t.Errorf("announce step saw %v", got)
+	}
+	if stage := sum.FailedStage(); stage != "step_broken" {
+		t.Errorf("FailedStage() = %q, want step_broken", stage)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// cleanupTimeout bounds the cleanup of an interrupted or failed run, such as the rollback and
+// restoring the Linear issue, which runs after the run's own context is canceled.
+const cleanupTimeout = 2 * time.Minute
+
+// childGracePeriod is how long a child process gets to exit after it was interrupted before it
+// is killed.
+const childGracePeriod = 10 * time.Second
+
+// forceExitAfter is how long after the first signal a second one exits without cleanup. A
+// terminal delivers Ctrl-C to monday and to the monday processes of a batch, which the batch
+// forwards the interrupt to as well; a signal right after the first is that echo.
+const forceExitAfter = time.Second
+
+// interruptContext returns a context canceled on SIGINT or SIGTERM, so the command stops and
+// cleans up after itself. Another signal once cleanup is under way exits right away. stop
+// releases the signal handler.
+func interruptContext(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		var first time.Time
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				switch {
+				case first.IsZero():
+					first = time.Now()
+					fmt.Fprintf(os.Stderr, "\n🛑 Received %s, stopping and cleaning up; send it again to exit immediately\n", sig)
+					cancel()
+				case time.Since(first) > forceExitAfter:
+					fmt.Fprintf(os.Stderr, "🛑 Exiting without cleaning up\n")
+					os.Exit(exitCanceled)
+				}
+			}
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
+
+// cleanupContext returns the context for cleaning up after a run whose own context may already
+// be canceled.
+func cleanupContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), cleanupTimeout)
+}
+
+// interruptOnCancel makes cmd, created with exec.CommandContext, get interrupted rather than
+// killed when its context is canceled, so it can clean up in turn. It is killed if it has not
+// exited childGracePeriod later. Windows cannot interrupt other processes, so there it is
+// killed right away.
+func interruptOnCancel(cmd *exec.Cmd) *exec.Cmd {
+	cmd.Cancel = func() error {
+		if runtime.GOOS == "windows" {
+			return cmd.Process.Kill()
+		}
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = childGracePeriod
+	return cmd
+}
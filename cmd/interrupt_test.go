@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestInterruptHelperProcess stands in for a child process that cleans up when interrupted.
+func TestInterruptHelperProcess(t *testing.T) {
+	if os.Getenv("MONDAY_INTERRUPT_HELPER") != "1" {
+		return
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	os.Stdout.WriteString("ready\n")
+	select {
+	case <-signals:
+		os.Stdout.WriteString("cleaned up\n")
+		os.Exit(0)
+	case <-time.After(30 * time.Second):
+		os.Exit(1)
+	}
+}
+
+func TestInterruptOnCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("processes cannot be interrupted on Windows")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := interruptOnCancel(exec.CommandContext(ctx, os.Args[0], "-test.run=TestInterruptHelperProcess"))
+	cmd.Env = append(os.Environ(), "MONDAY_INTERRUPT_HELPER=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, len("ready\n"))
+	if _, err := stdout.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	rest := make([]byte, 64)
+	n, _ := stdout.Read(rest)
+	cmd.Wait()
+	if !strings.Contains(string(rest[:n]), "cleaned up") {
+		t.Errorf("interrupted process printed %q, want it to clean up", rest[:n])
+	}
+}
+
+func TestInterruptContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("processes cannot signal themselves on Windows")
+	}
+	ctx, stop := interruptContext(context.Background())
+	defer stop()
+
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := self.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	// An echo of the same interrupt does not force an exit.
+	self.Signal(os.Interrupt)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not canceled by SIGINT")
+	}
+}
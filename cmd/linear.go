@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"monday/linear"
+)
+
+// maxTitleWidth is the width issue titles are shortened to in tables.
+const maxTitleWidth = 60
+
+var teamsCmd = &cobra.Command{
+	Use:   "teams",
+	Short: "List Linear teams and their projects",
+	Long: `List the Linear teams available to monday's Linear credentials with their projects, to find the
+keys accepted by --team and --project.`,
+	Args: cobra.NoArgs,
+	RunE: runTeams,
+}
+
+var (
+	// listState lists only the issues in this workflow state.
+	listState string
+	// listAssignee lists only the issues assigned to this user.
+	listAssignee string
+)
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"issues"},
+	Short:   "List Linear issues to pick what to work on",
+	Long: `List up to 50 Linear issues, newest first, with their identifiers, state, priority, and
+assignee, matching --team, --project, --label, --state, and --assignee. The --team,
+--project, and --label filters also select the issues monday works on when no issue ID is
+given.`,
+	Args: cobra.NoArgs,
+	RunE: runIssues,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&issueTeam, "team", "", "List the issues of this Linear team key")
+	listCmd.Flags().StringVar(&issueProject, "project", "", "List the issues of this Linear project")
+	listCmd.Flags().StringVar(&issueLabel, "label", "", "List the issues with this Linear label")
+	listCmd.Flags().StringVar(&listState, "state", "", "List the issues in this workflow state, e.g. Todo")
+	listCmd.Flags().StringVar(&listAssignee, "assignee", "", "List the issues assigned to this email or name, me, or none for unassigned issues")
+
+	rootCmd.AddCommand(teamsCmd)
+	rootCmd.AddCommand(listCmd)
+}
+
+// newLinearClient returns a Linear client authenticated as newLinearAPIClient does, failing
+// with the configuration exit code.
+func newLinearClient() (*linear.Client, error) {
+	client, err := newLinearAPIClient()
+	if err != nil {
+		return nil, withExitCode(exitConfig, err)
+	}
+	return client, nil
+}
+
+func runTeams(cmd *cobra.Command, args []string) error {
+	client, err := newLinearClient()
+	if err != nil {
+		return err
+	}
+	teams, err := client.FetchTeams(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	if jsonOutput() {
+		if teams == nil {
+			teams = []linear.Team{}
+		}
+		return writeJSON(teams)
+	}
+	printTeams(resultOut, teams)
+	return nil
+}
+
+func runIssues(cmd *cobra.Command, args []string) error {
+	client, err := newLinearClient()
+	if err != nil {
+		return err
+	}
+	issues, err := client.FetchIssuesByFilters(cmd.Context(), linear.IssueFilter{
+		Team:     issueTeam,
+		Project:  issueProject,
+		Label:    issueLabel,
+		State:    listState,
+		Assignee: listAssignee,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	if jsonOutput() {
+		if issues == nil {
+			issues = []linear.IssueDetails{}
+		}
+		return writeJSON(issues)
+	}
+	printIssues(resultOut, issues)
+	return nil
+}
+
+// printTeams writes a table of teams with the keys of their projects.
+func printTeams(out io.Writer, teams []linear.Team) {
+	if len(teams) == 0 {
+		fmt.Fprintln(out, "No teams found")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tNAME\tPROJECTS")
+	for _, team := range teams {
+		var projects []string
+		for _, project := range team.Projects.Nodes {
+			if project.Key != "" && project.Key != project.Name {
+				projects = append(projects, fmt.Sprintf("%s (%s)", project.Name, project.Key))
+			} else {
+				projects = append(projects, project.Name)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", team.Key, team.Name, dashIfEmpty(strings.Join(projects, ", ")))
+	}
+	w.Flush()
+}
+
+// printIssues writes a table of issues.
+func printIssues(out io.Writer, issues []linear.IssueDetails) {
+	if len(issues) == 0 {
+		fmt.Fprintln(out, "No issues found")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ISSUE\tSTATE\tPRIORITY\tASSIGNEE\tTITLE\tURL")
+	for _, issue := range issues {
+		assignee := ""
+		if issue.Assignee != nil {
+			assignee = issue.Assignee.Name
+		}
+		priority := issue.PriorityLabel
+		if issue.Priority == 0 {
+			priority = ""
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", issue.Identifier, dashIfEmpty(issue.State.Name), dashIfEmpty(priority), dashIfEmpty(assignee), shorten(issue.Title, maxTitleWidth), issue.URL)
+	}
+	w.Flush()
+}
+
+// shorten cuts s to at most width runes, ending it with an ellipsis when cut.
+func shorten(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"monday/linear"
+)
+
+func TestPrintTeams(t *testing.T) {
+	var out bytes.Buffer
+	printTeams(&out, []linear.Team{
+		{Key: "DEL", Name: "Delivery", Projects: linear.ProjectsConnection{Nodes: []linear.Project{
+			{Name: "Checkout", Key: "CHK"},
+			{Name: "Search"},
+		}}},
+		{Key: "OPS", Name: "Operations"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("output = %q, want a header and 2 rows", out.String())
+	}
+	if !strings.Contains(lines[1], "Checkout (CHK), Search") {
+		t.Errorf("row = %q, want the team's projects", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "-") {
+		t.Errorf("row = %q, want a dash for a team without projects", lines[2])
+	}
+}
+
+func TestPrintIssues(t *testing.T) {
+	tests := []struct {
+		name   string
+		issues []linear.IssueDetails
+		want   []string
+	}{
+		{
+			name: "issues",
+			issues: []linear.IssueDetails{{
+				Identifier: "DEL-163",
+				Title:      "Add login",
+				URL:        "https://linear.app/acme/issue/DEL-163",
+				State:      linear.WorkflowState{Name: "Todo"},
+			}},
+			want: []string{"ISSUE", "DEL-163", "Todo", "Add login", "https://linear.app/acme/issue/DEL-163"},
+		},
+		{
+			name: "priority and assignee",
+			issues: []linear.IssueDetails{{
+				Identifier:    "DEL-164",
+				Title:         "Fix logout",
+				Priority:      2,
+				PriorityLabel: "High",
+				Assignee:      &linear.User{Name: "Ada Lovelace"},
+			}},
+			want: []string{"PRIORITY", "ASSIGNEE", "DEL-164", "High", "Ada Lovelace"},
+		},
+		{
+			name: "no issues",
+			want: []string{"No issues found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printIssues(&out, tt.issues)
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output = %q, want it to contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestShorten(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{in: "short", width: 10, want: "short"},
+		{in: "exactly10!", width: 10, want: "exactly10!"},
+		{in: "much too long", width: 8, want: "much to…"},
+		{in: "héllo wörld", width: 6, want: "héllo…"},
+	}
+
+	for _, tt := range tests {
+		if got := shorten(tt.in, tt.width); got != tt.want {
+			t.Errorf("shorten(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
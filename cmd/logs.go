@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/summary"
+)
+
+var (
+	logsFollow bool
+	logsServer string
+)
+
+// logsPollInterval is how often --follow checks for new log entries.
+const logsPollInterval = time.Second
+
+// logContextFields are fields every entry of a run log carries; they are left out when
+// entries are pretty-printed.
+var logContextFields = map[string]bool{
+	"ts": true, "level": true, "msg": true, "caller": true, "logger": true, "stacktrace": true,
+	"run_id": true, "issue_id": true, "repo": true, "stage": true,
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <run-id>",
+	Short: "Show the log of a run",
+	Long: `Show the log of a past or in-flight run, one line per entry. With --follow, new entries
+are shown as they are written until the run ends. With --output json, the entries are printed
+as recorded, one JSON object per line.
+
+With --server or MONDAY_SERVER_URL, the log of a run of a monday server is shown; requests
+authenticate with SERVER_API_KEY. Run IDs are listed by monday status and monday history.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep showing new entries until the run ends")
+	logsCmd.Flags().StringVar(&logsServer, "server", "", "Base URL of the monday server that ran the run (default: $MONDAY_SERVER_URL)")
+	rootCmd.AddCommand(logsCmd)
+}
+
+// logReader returns the part of a run log from offset on, along with the run's status.
+type logReader func(offset int64) (data []byte, status string, err error)
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	if !validRunID(runID) {
+		return withExitCode(exitConfig, fmt.Errorf("invalid run ID %q", runID))
+	}
+
+	server := logsServer
+	if server == "" {
+		server = os.Getenv("MONDAY_SERVER_URL")
+	}
+	read := localLogReader(runID, processAlive)
+	if server != "" {
+		read = remoteLogReader(server, os.Getenv("SERVER_API_KEY"), runID)
+	}
+
+	printEntry := printLogEntry
+	if jsonOutput() {
+		printEntry = func(w io.Writer, line []byte) { fmt.Fprintf(w, "%s\n", line) }
+	}
+	return followLog(read, resultOut, printEntry, logsFollow, logsPollInterval)
+}
+
+// validRunID reports whether runID names a run directory rather than a path.
+func validRunID(runID string) bool {
+	return runID != "" && runID != "." && runID != ".." && filepath.Base(runID) == runID && !strings.ContainsAny(runID, `/\`)
+}
+
+// localLogReader reads the log of a run on this machine; alive reports whether a process
+// still exists, so the log of an interrupted run is not followed forever.
+func localLogReader(runID string, alive func(pid int) bool) logReader {
+	return localRunFileReader(runID, "run.log", alive)
+}
+
+// localRunFileReader reads the file called name in the directory of a run on this machine,
+// like localLogReader.
+func localRunFileReader(runID, name string, alive func(pid int) bool) logReader {
+	return func(offset int64) ([]byte, string, error) {
+		dir, err := runDir(runID)
+		if err != nil {
+			return nil, "", err
+		}
+		run, err := summary.Load(filepath.Join(dir, "summary.json"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", fmt.Errorf("run %s not found", runID)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		status := run.Status
+		if status == summary.StatusRunning && run.PID > 0 && !alive(run.PID) {
+			status = statusInterrupted
+		}
+
+		data, err := readFrom(filepath.Join(dir, name), offset)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, status, nil
+		}
+		return data, status, err
+	}
+}
+
+// readFrom returns the contents of the file at path from offset on.
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
+
+// remoteLogReader reads the log of a run of the monday server at baseURL.
+func remoteLogReader(baseURL, apiKey, runID string) logReader {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(offset int64) ([]byte, string, error) {
+		endpoint := fmt.Sprintf("%s/logs?run_id=%s&offset=%d", strings.TrimRight(baseURL, "/"), url.QueryEscape(runID), offset)
+		req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Set("X-API-Key", apiKey)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return nil, "", fmt.Errorf("server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		data, err := io.ReadAll(resp.Body)
+		return data, resp.Header.Get("X-Run-Status"), err
+	}
+}
+
+// followLog prints the complete entries of a run log with printEntry. With follow set, it keeps
+// reading new entries every interval until the run is no longer running.
+func followLog(read logReader, out io.Writer, printEntry func(io.Writer, []byte), follow bool, interval time.Duration) error {
+	var offset int64
+	var pending []byte
+	for {
+		data, status, err := read(offset)
+		if err != nil {
+			return err
+		}
+		offset += int64(len(data))
+		pending = append(pending, data...)
+		for {
+			i := bytes.IndexByte(pending, '\n')
+			if i < 0 {
+				break
+			}
+			if line := bytes.TrimSpace(pending[:i]); len(line) > 0 {
+				printEntry(out, line)
+			}
+			pending = pending[i+1:]
+		}
+
+		if !follow || status != summary.StatusRunning {
+			if line := bytes.TrimSpace(pending); len(line) > 0 {
+				printEntry(out, line)
+			}
+			if follow && status != "" {
+				fmt.Printf("Run ended: %s\n", status)
+			}
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// printLogEntry pretty-prints one JSON entry of a run log as time, level, stage, message, and
+// the remaining fields as key=value pairs. Lines that are not JSON are printed as they are.
+func printLogEntry(out io.Writer, line []byte) {
+	var entry map[string]any
+	if err := json.Unmarshal(line, &entry); err != nil {
+		fmt.Fprintf(out, "%s\n", line)
+		return
+	}
+
+	var b strings.Builder
+	if ts, ok := entry["ts"].(string); ok {
+		if t, err := time.Parse("2006-01-02T15:04:05.000Z0700", ts); err == nil {
+			ts = t.Local().Format(time.DateTime)
+		}
+		b.WriteString(ts)
+		b.WriteString(" ")
+	}
+	level, _ := entry["level"].(string)
+	fmt.Fprintf(&b, "%-5s ", strings.ToUpper(level))
+	if stage, ok := entry["stage"].(string); ok {
+		fmt.Fprintf(&b, "[%s] ", stage)
+	}
+	msg, _ := entry["msg"].(string)
+	b.WriteString(msg)
+
+	var keys []string
+	for key := range entry {
+		if !logContextFields[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%s", key, formatLogValue(entry[key]))
+	}
+	fmt.Fprintln(out, b.String())
+}
+
+// formatLogValue renders a field value of a log entry, quoting strings that contain spaces.
+func formatLogValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t\n\"") {
+			return strconv.Quote(v)
+		}
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+)
+
+func TestPrintLogEntry(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "entry",
+			line: `{"level":"info","msg":"Running git command","run_id":"r","stage":"push","args":["push","origin"],"dir":"/tmp/my repo"}`,
+			want: `INFO  [push] Running git command args=["push","origin"] dir="/tmp/my repo"` + "\n",
+		},
+		{
+			name: "without stage",
+			line: `{"level":"warn","msg":"Failed to write run summary","attempt":2}`,
+			want: "WARN  Failed to write run summary attempt=2\n",
+		},
+		{
+			name: "not JSON",
+			line: "plain text",
+			want: "plain text\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printLogEntry(&out, []byte(tt.line))
+			if out.String() != tt.want {
+				t.Errorf("printLogEntry() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowLog(t *testing.T) {
+	chunks := []struct {
+		data   string
+		status string
+	}{
+		{"one\ntw", summary.StatusRunning},
+		{"", summary.StatusRunning},
+		{"o\nthree", summary.StatusFailed},
+	}
+	var reads int
+	var offsets []int64
+	read := func(offset int64) ([]byte, string, error) {
+		offsets = append(offsets, offset)
+		chunk := chunks[reads]
+		reads++
+		return []byte(chunk.data), chunk.status, nil
+	}
+	var out bytes.Buffer
+	printLine := func(w io.Writer, line []byte) { w.Write(append(line, '|')) }
+
+	if err := followLog(read, &out, printLine, true, 0); err != nil {
+		t.Fatalf("followLog() error = %v", err)
+	}
+
+	if out.String() != "one|two|three|" {
+		t.Errorf("output = %q, want each entry once and whole", out.String())
+	}
+	if reads != 3 || offsets[2] != 6 {
+		t.Errorf("reads = %d at offsets %v, want 3 reads ending at offset 6", reads, offsets)
+	}
+}
+
+func TestFollowLogWithoutFollow(t *testing.T) {
+	var reads int
+	read := func(offset int64) ([]byte, string, error) {
+		reads++
+		return []byte("one\n"), summary.StatusRunning, nil
+	}
+	var out bytes.Buffer
+	if err := followLog(read, &out, printLogEntry, false, 0); err != nil {
+		t.Fatalf("followLog() error = %v", err)
+	}
+	if reads != 1 {
+		t.Errorf("reads = %d, want 1", reads)
+	}
+}
+
+func TestValidRunID(t *testing.T) {
+	for runID, want := range map[string]bool{
+		"20250615-180409-del-163-9f2c": true,
+		"":                             false,
+		"..":                           false,
+		"../secrets":                   false,
+		`a\b`:                          false,
+	} {
+		if got := validRunID(runID); got != want {
+			t.Errorf("validRunID(%q) = %v, want %v", runID, got, want)
+		}
+	}
+}
+
+func TestLogsHandler(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	dir := filepath.Join(home, "runs", "run-1")
+	run := summary.New("run-1", "DEL-1", "repo")
+	run.Finish(nil)
+	if _, err := run.WriteFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run.log"), []byte("first\nsecond\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(makeLogsHandler(zap.NewNop(), "secret"))
+	defer server.Close()
+
+	data, status, err := remoteLogReader(server.URL, "secret", "run-1")(6)
+	if err != nil {
+		t.Fatalf("remoteLogReader() error = %v", err)
+	}
+	if string(data) != "second\n" || status != summary.StatusSucceeded {
+		t.Errorf("remoteLogReader() = %q, %q, want the log from the offset and the run status", data, status)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		apiKey string
+		want   int
+	}{
+		{name: "unauthorized", query: "run_id=run-1", apiKey: "wrong", want: http.StatusUnauthorized},
+		{name: "path", query: "run_id=../run-1", apiKey: "secret", want: http.StatusBadRequest},
+		{name: "offset", query: "run_id=run-1&offset=-1", apiKey: "secret", want: http.StatusBadRequest},
+		{name: "unknown run", query: "run_id=run-2", apiKey: "secret", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/logs?"+tt.query, nil)
+			req.Header.Set("X-API-Key", tt.apiKey)
+			rec := httptest.NewRecorder()
+			makeLogsHandler(zap.NewNop(), "secret")(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, strings.TrimSpace(rec.Body.String()))
+			}
+		})
+	}
+}
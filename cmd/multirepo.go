@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"monday/redact"
+)
+
+// runMultiRepo works on issueID in each repository given with --repo-url: every repository gets
+// a monday process of its own, with its own branch, agent run, and pull request, at most
+// --concurrency at a time. Once all are done, each pull request links to the others.
+func runMultiRepo(cmd *cobra.Command, issueID string) error {
+	flags := forwardedFlags(cmd.Flags(), "repo-url")
+	jobs := make([]batchJob, len(repoURLs))
+	for i, repo := range repoURLs {
+		jobs[i] = batchJob{IssueID: issueID, Repo: repo, Flags: append(slices.Clip(flags), "--repo-url="+repo)}
+	}
+
+	if showProgress() {
+		fmt.Printf("🚀 Working on %s in %d repositories, %d at a time\n", extractIssueID(issueID), len(jobs), max(concurrency, 1))
+	}
+	results, err := runBatchProcesses(cmd.Context(), jobs, concurrency)
+	if err != nil {
+		return err
+	}
+	linkPullRequests(cmd.Context(), results)
+	return reportResults(results)
+}
+
+// linkPullRequests appends links to the other pull requests of the runs of an issue that spans
+// several repositories to the description of each. Failures are reported and otherwise ignored.
+func linkPullRequests(ctx context.Context, results []issueRunResult) {
+	var opened []issueRunResult
+	for _, result := range results {
+		if result.Summary != nil && result.Summary.PRURL != "" {
+			opened = append(opened, result)
+		}
+	}
+	if len(opened) < 2 {
+		return
+	}
+	for _, result := range opened {
+		var related []string
+		for _, other := range opened {
+			if other.Summary.PRURL != result.Summary.PRURL {
+				related = append(related, other.Summary.PRURL)
+			}
+		}
+		host, err := newCodeHost(ctx, result.Repo, "")
+		if err == nil {
+			err = host.appendToBody(ctx, result.Summary.PRURL, relatedPullRequests(related))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not link the other pull requests from %s: %s\n", result.Summary.PRURL, redact.Error(err))
+		}
+	}
+}
+
+// relatedPullRequests renders the section of a pull request description that links to the
+// pull requests for the same issue in other repositories.
+func relatedPullRequests(urls []string) string {
+	var b strings.Builder
+	b.WriteString("\n\n---\n**Related pull requests for this issue:**\n")
+	for _, url := range urls {
+		fmt.Fprintf(&b, "- %s\n", url)
+	}
+	return b.String()
+}
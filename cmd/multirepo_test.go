@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"monday/summary"
+)
+
+func TestForwardedFlagsRepeated(t *testing.T) {
+	flags := pflag.NewFlagSet("monday", pflag.ContinueOnError)
+	flags.StringSlice("repo-url", nil, "")
+	flags.Bool("rollback", false, "")
+	if err := flags.Parse([]string{"--repo-url", "https://github.com/acme/web,https://github.com/acme/api", "--repo-url", "https://github.com/acme/docs", "--rollback"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(forwardedFlags(flags), " ")
+	want := "--repo-url=https://github.com/acme/web --repo-url=https://github.com/acme/api --repo-url=https://github.com/acme/docs --rollback=true"
+	if got != want {
+		t.Errorf("forwardedFlags() = %s, want %s", got, want)
+	}
+	if got := strings.Join(forwardedFlags(flags, "repo-url"), " "); got != "--rollback=true" {
+		t.Errorf("forwardedFlags() skipping repo-url = %s, want --rollback=true", got)
+	}
+}
+
+func TestRelatedPullRequests(t *testing.T) {
+	got := relatedPullRequests([]string{"https://github.com/acme/web/pull/4", "https://github.com/acme/api/pull/9"})
+	want := "\n\n---\n**Related pull requests for this issue:**\n- https://github.com/acme/web/pull/4\n- https://github.com/acme/api/pull/9\n"
+	if got != want {
+		t.Errorf("relatedPullRequests() = %q, want %q", got, want)
+	}
+}
+
+func TestPrintBatchResultsRepos(t *testing.T) {
+	var out bytes.Buffer
+	printBatchResults(&out, []issueRunResult{
+		{IssueID: "DEL-1", Repo: "https://github.com/acme/web", Status: summary.StatusSucceeded, Summary: &summary.Summary{PRURL: "https://github.com/acme/web/pull/4"}},
+		{IssueID: "DEL-1", Repo: "https://github.com/acme/api.git", Status: summary.StatusFailed, Error: "agent failed"},
+	})
+
+	got := out.String()
+	for _, want := range []string{"ISSUE  REPO  STATUS", "DEL-1  web   succeeded", "https://github.com/acme/web/pull/4", "DEL-1  api   failed", "agent failed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("printBatchResults() output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/notify"
+	"monday/redact"
+	"monday/summary"
+)
+
+// notifierFactory builds the notifier of one channel from the environment, returning nil when
+// the channel is not configured.
+type notifierFactory struct {
+	name  string
+	build func() (notify.Notifier, error)
+}
+
+// notifierFactories lists the chat channels monday can notify. Adding a channel only takes a
+// new entry here:
+//   - Slack: SLACK_WEBHOOK_URL, or SLACK_BOT_TOKEN and SLACK_CHANNEL, with per-repository
+//     channels from SLACK_REPO_CHANNELS
+//   - Discord: DISCORD_WEBHOOK_URL
+//   - Microsoft Teams: TEAMS_WEBHOOK_URL
+var notifierFactories = []notifierFactory{
+	{name: "slack", build: func() (notify.Notifier, error) {
+		slack, err := newSlackNotifier()
+		if slack == nil {
+			return nil, err
+		}
+		return slack, nil
+	}},
+	{name: "discord", build: func() (notify.Notifier, error) {
+		if webhook := os.Getenv("DISCORD_WEBHOOK_URL"); webhook != "" {
+			return notify.NewDiscord(webhook)
+		}
+		return nil, nil
+	}},
+	{name: "teams", build: func() (notify.Notifier, error) {
+		if webhook := os.Getenv("TEAMS_WEBHOOK_URL"); webhook != "" {
+			return notify.NewTeams(webhook)
+		}
+		return nil, nil
+	}},
+}
+
+var (
+	notifiers     *notify.Fanout
+	notifierErrs  []error
+	notifiersOnce sync.Once
+)
+
+// getNotifiers returns the fan-out over the notification channels configured in the
+// environment, along with the configuration errors of channels that could not be set up.
+func getNotifiers() (*notify.Fanout, []error) {
+	notifiersOnce.Do(func() {
+		notifiers, notifierErrs = buildNotifiers(notifierFactories)
+	})
+	return notifiers, notifierErrs
+}
+
+// buildNotifiers builds the configured channels of factories. Each channel receives events of
+// at least its MONDAY_NOTIFY_SEVERITY_<NAME> severity, or MONDAY_NOTIFY_SEVERITY, or all events.
+func buildNotifiers(factories []notifierFactory) (*notify.Fanout, []error) {
+	var channels []notify.Channel
+	var errs []error
+	for _, factory := range factories {
+		notifier, err := factory.build()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", factory.name, err))
+			continue
+		}
+		if notifier == nil {
+			continue
+		}
+		severity, err := channelSeverity(factory.name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		channels = append(channels, notify.Channel{Name: factory.name, Notifier: notifier, MinSeverity: severity})
+	}
+	return notify.NewFanout(channels...), errs
+}
+
+// channelSeverity returns the minimum severity of events sent to the named channel.
+func channelSeverity(name string) (notify.Severity, error) {
+	for _, key := range []string{"MONDAY_NOTIFY_SEVERITY_" + strings.ToUpper(name), "MONDAY_NOTIFY_SEVERITY"} {
+		if value := os.Getenv(key); value != "" {
+			severity, err := notify.ParseSeverity(value)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			return severity, nil
+		}
+	}
+	return notify.SeverityInfo, nil
+}
+
+// newSlackNotifier returns the Slack notifier configured in the environment, or nil.
+func newSlackNotifier() (*notify.Slack, error) {
+	config := notify.SlackConfig{
+		WebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+		Token:      os.Getenv("SLACK_BOT_TOKEN"),
+		Channel:    os.Getenv("SLACK_CHANNEL"),
+	}
+	if config.WebhookURL == "" && config.Token == "" {
+		return nil, nil
+	}
+	if overrides := os.Getenv("SLACK_REPO_CHANNELS"); overrides != "" {
+		var err error
+		if config.RepoChannels, err = notify.ParseRepoChannels(overrides); err != nil {
+			return nil, err
+		}
+	}
+	return notify.NewSlack(config)
+}
+
+// defaultDesktopNotifyAfter is how long a run must take before it raises a desktop notification.
+const defaultDesktopNotifyAfter = time.Minute
+
+// desktopNotifier raises desktop notifications for CLI runs on macOS; it is nil otherwise.
+var desktopNotifier *notify.Desktop
+
+// newDesktopNotifier returns a desktop notifier for runs that take longer than
+// MONDAY_DESKTOP_NOTIFY_AFTER, a Go duration defaulting to one minute.
+func newDesktopNotifier() (*notify.Desktop, error) {
+	after := defaultDesktopNotifyAfter
+	if value := os.Getenv("MONDAY_DESKTOP_NOTIFY_AFTER"); value != "" {
+		var err error
+		if after, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid MONDAY_DESKTOP_NOTIFY_AFTER %q: %w", value, err)
+		}
+	}
+	return notify.NewDesktop(after), nil
+}
+
+// notifyRun sends a lifecycle event of the run described by sum with sendNotification: every
+// run announces its start and its success or failure. Dry runs send nothing.
+func notifyRun(log *zap.Logger, kind notify.EventKind, sum *summary.Summary) {
+	if sum.DryRun {
+		return
+	}
+	sendNotification(log, runEvent(kind, sum))
+}
+
+// sendNotification delivers event to the configured chat channels and, for CLI runs on macOS,
+// the desktop. Notification failures are logged and otherwise ignored.
+func sendNotification(log *zap.Logger, event notify.Event) {
+	fanout, errs := getNotifiers()
+	for _, err := range errs {
+		log.Warn("Notification channel is misconfigured", zap.Error(err))
+	}
+	if desktopNotifier != nil {
+		severity, err := channelSeverity("desktop")
+		if err != nil {
+			log.Warn("Notification channel is misconfigured", zap.Error(err))
+		}
+		fanout = fanout.With(notify.Channel{Name: "desktop", Notifier: desktopNotifier, MinSeverity: severity})
+	}
+	if fanout.Len() == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := fanout.Notify(ctx, event); err != nil {
+		log.Warn("Failed to send notification", zap.String("event", string(event.Kind)), zap.Error(err))
+	}
+}
+
+// runEvent describes the run recorded in sum as a notification event.
+func runEvent(kind notify.EventKind, sum *summary.Summary) notify.Event {
+	event := notify.Event{
+		Kind:       kind,
+		RunID:      sum.RunID,
+		IssueID:    sum.IssueID,
+		IssueTitle: sum.IssueTitle,
+		IssueURL:   sum.IssueURL,
+		Repo:       redact.String(sum.Repo),
+		Branch:     sum.Branch,
+		PRURL:      sum.PRURL,
+		Duration:   time.Duration(sum.DurationSeconds * float64(time.Second)),
+		CostUSD:    sum.AgentCostUSD,
+	}
+	if kind == notify.RunFailed {
+		event.Stage = sum.FailedStage()
+		if len(sum.Errors) > 0 {
+			event.Error = redact.String(sum.Errors[len(sum.Errors)-1])
+		}
+	}
+	return event
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats selected with --output.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var outputFormat string
+
+// resultOut receives the results of commands. In JSON mode it is the process's standard output
+// and os.Stdout is pointed at standard error, so progress lines, agent output, and everything
+// else printed for humans stays out of the JSON that scripts read.
+var resultOut io.Writer = os.Stdout
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "Output format: text or json")
+}
+
+// jsonFlag is the --json flag some commands had before --output; setting it selects JSON output.
+type jsonFlag struct{}
+
+func (jsonFlag) String() string { return "false" }
+func (jsonFlag) Type() string   { return "bool" }
+
+func (jsonFlag) Set(value string) error {
+	on, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if on {
+		outputFormat = outputJSON
+	}
+	return nil
+}
+
+// addDeprecatedJSONFlag keeps the --json flag of cmd working as --output json, with a
+// deprecation warning.
+func addDeprecatedJSONFlag(cmd *cobra.Command) {
+	cmd.Flags().VarPF(jsonFlag{}, "json", "", "Print the results as JSON").NoOptDefVal = "true"
+	cmd.Flags().MarkDeprecated("json", "use --output json")
+}
+
+// setupOutput validates --output and, in JSON mode, moves human-oriented output to stderr.
+func setupOutput() error {
+	switch outputFormat {
+	case outputText:
+	case outputJSON:
+		resultOut = os.Stdout
+		os.Stdout = os.Stderr
+	default:
+		return fmt.Errorf("invalid output format %q: must be text or json", outputFormat)
+	}
+	return nil
+}
+
+// silenceStdout discards everything printed to os.Stdout, such as progress lines, until the
+// returned function is called. Results written to resultOut are unaffected.
+func silenceStdout() (restore func(), err error) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}, nil
+}
+
+// jsonOutput reports whether results are printed as JSON.
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// writeJSON prints v as the indented JSON result of a command.
+func writeJSON(v any) error {
+	enc := json.NewEncoder(resultOut)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSetupOutput(t *testing.T) {
+	stdout, format := os.Stdout, outputFormat
+	t.Cleanup(func() {
+		os.Stdout, resultOut, outputFormat = stdout, stdout, format
+	})
+
+	outputFormat = "yaml"
+	if err := setupOutput(); err == nil {
+		t.Error("setupOutput() accepted an unknown format")
+	}
+
+	outputFormat = outputJSON
+	if err := setupOutput(); err != nil {
+		t.Fatalf("setupOutput() error = %v", err)
+	}
+	if !jsonOutput() {
+		t.Error("jsonOutput() = false in JSON mode")
+	}
+	if resultOut != stdout || os.Stdout != os.Stderr {
+		t.Error("setupOutput() did not move human output to stderr in JSON mode")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	orig := resultOut
+	resultOut = &b
+	t.Cleanup(func() { resultOut = orig })
+
+	if err := writeJSON(map[string]int{"runs": 2}); err != nil {
+		t.Fatalf("writeJSON() error = %v", err)
+	}
+	if want := "{\n  \"runs\": 2\n}\n"; b.String() != want {
+		t.Errorf("writeJSON() wrote %q, want %q", b.String(), want)
+	}
+}
+
+func TestDeprecatedJSONFlag(t *testing.T) {
+	format := outputFormat
+	t.Cleanup(func() { outputFormat = format })
+	outputFormat = outputText
+
+	cmd := &cobra.Command{Use: "stats"}
+	addDeprecatedJSONFlag(cmd)
+	cmd.SetErr(io.Discard)
+	if err := cmd.ParseFlags([]string{"--json"}); err != nil {
+		t.Fatalf("ParseFlags(--json) error = %v", err)
+	}
+	if !jsonOutput() {
+		t.Error("jsonOutput() = false after --json")
+	}
+}
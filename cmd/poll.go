@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"monday/history"
+	"monday/linear"
+)
+
+// defaultPollInterval is how long monday poll waits between polls unless told otherwise.
+const defaultPollInterval = 10 * time.Minute
+
+var (
+	// pollInterval is how long monday poll waits between polls.
+	pollInterval time.Duration
+	// pollLabel is the Linear label of the issues monday poll works on.
+	pollLabel string
+	// pollOnce polls once instead of until interrupted.
+	pollOnce bool
+)
+
+// pollFlags are the flags of monday poll that are not passed on to the runs it starts.
+var pollFlags = []string{"interval", "linear-tag", "once"}
+
+var pollCmd = &cobra.Command{
+	Use:   "poll [-- <run flags>]",
+	Short: "Poll Linear for labeled issues and work on new ones",
+	Long: `Poll Linear every --interval for the issues with the --linear-tag label and work on the ones
+no run has worked on yet, as an alternative to the server's webhook receiver where Linear
+cannot reach monday. Only issues that are not started, completed, or canceled are picked up,
+oldest first, at most --max per poll. An issue counts as processed once the run history has a
+run of it in the repository, whether started by a poll, the CLI, or the server, so failed runs
+are not retried; use monday resume for them.
+
+Each issue runs in its own monday process, --concurrency at a time, and the next poll starts
+--interval after the runs of the last one finished. Flags after -- are passed on to the runs,
+e.g. monday poll --repo-url https://github.com/acme/app -- --draft. Polling stops on SIGINT or
+SIGTERM once the runs in flight stopped.`,
+	RunE: runPoll,
+}
+
+func init() {
+	pollCmd.Flags().DurationVar(&pollInterval, "interval", 0, "How long to wait between polls, e.g. 10m (default: $MONDAY_POLL_INTERVAL or 10m)")
+	pollCmd.Flags().StringVar(&pollLabel, "linear-tag", "", "Work on the issues with this Linear label (default: $MONDAY_POLL_LABEL or "+defaultWebhookLabel+")")
+	pollCmd.Flags().BoolVar(&pollOnce, "once", false, "Poll once, wait for the runs, and exit, e.g. from cron")
+	pollCmd.Flags().StringVar(&issueTeam, "team", "", "Only the issues of this Linear team key")
+	pollCmd.Flags().StringVar(&issueProject, "project", "", "Only the issues of this Linear project")
+	pollCmd.Flags().IntVar(&maxIssues, "max", 0, "Work on at most this many new issues per poll (default: no limit)")
+	pollCmd.Flags().IntVar(&concurrency, "concurrency", 2, "Number of issues worked on at the same time")
+	pollCmd.Flags().StringSliceVar(&repoURLs, "repo-url", nil, "Repository URL the issues are worked on in; repeat it or separate URLs with commas for several (default: $MONDAY_REPO_URL)")
+	pollCmd.Flags().StringVar(&localRepo, "local-repo", "", "Path to an existing local clone to work in per-issue worktrees of instead")
+	rootCmd.AddCommand(pollCmd)
+}
+
+// resolvePollInterval returns the interval selected with --interval or MONDAY_POLL_INTERVAL,
+// or defaultPollInterval.
+func resolvePollInterval() (time.Duration, error) {
+	d, err := timeoutSetting("interval", pollInterval, "MONDAY_POLL_INTERVAL")
+	if err != nil {
+		return 0, err
+	}
+	if d == 0 {
+		return defaultPollInterval, nil
+	}
+	return d, nil
+}
+
+// resolvePollLabel returns the label selected with --linear-tag or MONDAY_POLL_LABEL, or
+// defaultWebhookLabel.
+func resolvePollLabel() string {
+	if pollLabel != "" {
+		return pollLabel
+	}
+	if label := os.Getenv("MONDAY_POLL_LABEL"); label != "" {
+		return label
+	}
+	return defaultWebhookLabel
+}
+
+// fetchPollIssues returns the issues matching filter, newest first.
+var fetchPollIssues = func(ctx context.Context, filter linear.IssueFilter) ([]linear.IssueDetails, error) {
+	client, err := newLinearClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.FetchIssuesByFilters(ctx, filter)
+}
+
+// startPollRuns runs jobs, each in a monday process of its own, at most concurrency at a time.
+var startPollRuns = runBatchProcesses
+
+func runPoll(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && cmd.ArgsLenAtDash() != 0 {
+		return withExitCode(exitConfig, fmt.Errorf("unexpected arguments %q: flags of the runs go after --", args))
+	}
+	interval, err := resolvePollInterval()
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if len(repoURLs) == 0 && localRepo == "" {
+		repoURLs = profileRepoURLs()
+	}
+	repos := repoURLs
+	if localRepo != "" {
+		repos = []string{localRepo}
+	}
+	if len(repos) == 0 {
+		return withExitCode(exitConfig, fmt.Errorf("one of --repo-url or --local-repo is required (or repo_url in a profile)"))
+	}
+	if _, err := newLinearClient(); err != nil {
+		return err
+	}
+
+	log := newLogger()
+	filter := linear.IssueFilter{Team: issueTeam, Project: issueProject, Label: resolvePollLabel()}
+	flags := append(forwardedFlags(cmd.Flags(), pollFlags...), args...)
+	// Issues whose runs left no record, such as runs that failed before they started, are
+	// not picked up again while polling.
+	launched := map[string]bool{}
+
+	ctx := cmd.Context()
+	if showProgress() && !pollOnce {
+		fmt.Printf("🔎 Polling Linear every %s for issues labeled %s\n", interval, filter.Label)
+	}
+	for {
+		results, err := pollIssues(ctx, log, filter, repos, flags, launched)
+		if pollOnce {
+			if err != nil {
+				return err
+			}
+			if len(results) == 0 {
+				if showProgress() {
+					fmt.Println("No new issues")
+				}
+				return nil
+			}
+			return reportResults(results)
+		}
+		switch {
+		case err != nil:
+			log.Warn("Failed to poll Linear", zap.Error(err))
+		case len(results) > 0:
+			// Failed runs are reported, but do not stop the polling.
+			if err := reportResults(results); err != nil {
+				log.Warn("Runs failed", zap.Error(err))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollIssues fetches the issues matching filter and works on those that are new to repos, each
+// run in its own process with flags, and returns their results. Issues are recorded in launched
+// once they are worked on.
+func pollIssues(ctx context.Context, log *zap.Logger, filter linear.IssueFilter, repos, flags []string, launched map[string]bool) ([]issueRunResult, error) {
+	issues, err := fetchPollIssues(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+	processed, err := processedIssues(repos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the run history: %w", err)
+	}
+	ids := newIssues(issues, processed, launched)
+	log.Info("Polled Linear", zap.String("label", filter.Label), zap.Int("issues", len(issues)), zap.Strings("new", ids))
+	if maxIssues > 0 && len(ids) > maxIssues {
+		ids = ids[:maxIssues]
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	jobs := make([]batchJob, len(ids))
+	for i, id := range ids {
+		launched[id] = true
+		jobs[i] = batchJob{IssueID: id, Flags: flags}
+	}
+	if showProgress() {
+		fmt.Printf("📋 %d new issue(s) labeled %s: %s\n", len(ids), filter.Label, strings.Join(ids, ", "))
+	}
+	return startPollRuns(ctx, jobs, concurrency)
+}
+
+// newIssues returns the identifiers of the issues, oldest first, that are waiting to be worked
+// on and neither processed nor launched already. issues are newest first.
+func newIssues(issues []linear.IssueDetails, processed, launched map[string]bool) []string {
+	var ids []string
+	for i := len(issues) - 1; i >= 0; i-- {
+		issue := issues[i]
+		id := strings.ToUpper(issue.Identifier)
+		switch issue.State.Type {
+		case "started", "completed", "canceled":
+			continue
+		}
+		if processed[id] || launched[id] {
+			continue
+		}
+		ids = append(ids, issue.Identifier)
+	}
+	return ids
+}
+
+// processedIssues returns the identifiers, in upper case, of the issues the run history has a
+// run of in one of repos, other than a dry run.
+func processedIssues(repos []string) (map[string]bool, error) {
+	runs, err := queryRuns(history.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	processed := map[string]bool{}
+	for _, run := range runs {
+		if !run.DryRun && slices.Contains(repos, run.Repo) {
+			processed[strings.ToUpper(run.IssueID)] = true
+		}
+	}
+	return processed, nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/linear"
+	"monday/summary"
+)
+
+func TestNewIssues(t *testing.T) {
+	issue := func(id, stateType string) linear.IssueDetails {
+		return linear.IssueDetails{Identifier: id, State: linear.WorkflowState{Type: stateType}}
+	}
+	// Newest first, as Linear returns them.
+	issues := []linear.IssueDetails{
+		issue("DEL-6", "unstarted"),
+		issue("DEL-5", "completed"),
+		issue("DEL-4", "started"),
+		issue("DEL-3", "backlog"),
+		issue("del-2", "unstarted"),
+		issue("DEL-1", "triage"),
+	}
+	got := newIssues(issues, map[string]bool{"DEL-2": true}, map[string]bool{"DEL-3": true})
+	if want := []string{"DEL-1", "DEL-6"}; !slices.Equal(got, want) {
+		t.Errorf("newIssues() = %v, want %v", got, want)
+	}
+}
+
+func TestPollIssues(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	repo := "https://github.com/acme/app"
+	for id, run := range map[string]*summary.Summary{
+		"run-1": summary.New("run-1", "DEL-1", repo),
+		"run-2": summary.New("run-2", "DEL-2", "https://github.com/acme/other"),
+		"run-3": summary.New("run-3", "DEL-3", repo),
+	} {
+		run.DryRun = id == "run-3"
+		run.Finish(nil)
+		if _, err := run.WriteFiles(filepath.Join(home, "runs", id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	origFetch, origStart, origMax := fetchPollIssues, startPollRuns, maxIssues
+	t.Cleanup(func() { fetchPollIssues, startPollRuns, maxIssues = origFetch, origStart, origMax })
+	maxIssues = 0
+	var filters []linear.IssueFilter
+	fetchPollIssues = func(ctx context.Context, filter linear.IssueFilter) ([]linear.IssueDetails, error) {
+		filters = append(filters, filter)
+		return []linear.IssueDetails{{Identifier: "DEL-3"}, {Identifier: "DEL-2"}, {Identifier: "DEL-1"}}, nil
+	}
+	var started [][]batchJob
+	startPollRuns = func(ctx context.Context, jobs []batchJob, concurrency int) ([]issueRunResult, error) {
+		started = append(started, jobs)
+		results := make([]issueRunResult, len(jobs))
+		for i, job := range jobs {
+			results[i] = issueRunResult{IssueID: job.IssueID, Status: summary.StatusFailed}
+		}
+		return results, nil
+	}
+
+	filter := linear.IssueFilter{Team: "DEL", Label: "ai-ready"}
+	launched := map[string]bool{}
+	results, err := pollIssues(context.Background(), zap.NewNop(), filter, []string{repo}, []string{"--repo-url=" + repo}, launched)
+	if err != nil {
+		t.Fatalf("pollIssues() error = %v", err)
+	}
+	if len(results) != 2 || len(started) != 1 {
+		t.Fatalf("pollIssues() = %v, started %v, want runs of DEL-2 and DEL-3", results, started)
+	}
+	if job := started[0][0]; job.IssueID != "DEL-2" || !slices.Equal(job.Flags, []string{"--repo-url=" + repo}) {
+		t.Errorf("first job = %+v, want DEL-2 with the forwarded flags", job)
+	}
+	if started[0][1].IssueID != "DEL-3" {
+		t.Errorf("second job = %+v, want DEL-3", started[0][1])
+	}
+	if filters[0] != filter {
+		t.Errorf("fetched issues with %+v, want %+v", filters[0], filter)
+	}
+
+	// The runs left no record, so only the launched issues keep them from running again.
+	results, err = pollIssues(context.Background(), zap.NewNop(), filter, []string{repo}, nil, launched)
+	if err != nil || len(results) != 0 || len(started) != 1 {
+		t.Errorf("second pollIssues() = %v, %v, started %d batches, want nothing started", results, err, len(started))
+	}
+}
+
+func TestResolvePollInterval(t *testing.T) {
+	orig := pollInterval
+	t.Cleanup(func() { pollInterval = orig })
+	pollInterval = 0
+
+	t.Setenv("MONDAY_POLL_INTERVAL", "")
+	if got, err := resolvePollInterval(); err != nil || got != defaultPollInterval {
+		t.Errorf("resolvePollInterval() = %v, %v, want %v", got, err, defaultPollInterval)
+	}
+	t.Setenv("MONDAY_POLL_INTERVAL", "90s")
+	if got, err := resolvePollInterval(); err != nil || got != 90*time.Second {
+		t.Errorf("resolvePollInterval() = %v, %v, want 1m30s", got, err)
+	}
+	pollInterval = time.Hour
+	if got, err := resolvePollInterval(); err != nil || got != time.Hour {
+		t.Errorf("resolvePollInterval() = %v, %v, want the flag's 1h", got, err)
+	}
+	pollInterval = 0
+	t.Setenv("MONDAY_POLL_INTERVAL", "often")
+	if _, err := resolvePollInterval(); err == nil {
+		t.Error("resolvePollInterval() error = nil, want an invalid MONDAY_POLL_INTERVAL")
+	}
+}
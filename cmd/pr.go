@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"monday/summary"
+)
+
+var (
+	prRepo           string
+	prIssue          string
+	prSince          string
+	prNeedsAttention bool
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Inspect the pull requests created by monday",
+}
+
+var prStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List pull requests created by monday with their review and CI status",
+	Long: `List the pull requests recorded in the run history with their state, review decision,
+and CI status as reported by GitHub, oldest first. A pull request needs attention when it is
+open and its checks fail, changes were requested, or it conflicts with its base branch.
+
+The status is looked up with the GitHub CLI (gh), which authenticates with GITHUB_TOKEN.`,
+	Args: cobra.NoArgs,
+	RunE: runPRStatus,
+}
+
+func init() {
+	prStatusCmd.Flags().StringVar(&prRepo, "repo", "", "Only pull requests of runs whose repository URL or path contains this value")
+	prStatusCmd.Flags().StringVar(&prIssue, "issue", "", "Only pull requests for this Linear issue")
+	prStatusCmd.Flags().StringVar(&prSince, "since", "", "Only pull requests of runs started at or after this time")
+	prStatusCmd.Flags().BoolVar(&prNeedsAttention, "needs-attention", false, "Only pull requests that need attention")
+	prCmd.AddCommand(prStatusCmd)
+	rootCmd.AddCommand(prCmd)
+}
+
+// ghPullRequest is the part of `gh pr view --json` output monday reads.
+type ghPullRequest struct {
+	Number         int    `json:"number"`
+	Title          string `json:"title"`
+	URL            string `json:"url"`
+	State          string `json:"state"`
+	IsDraft        bool   `json:"isDraft"`
+	ReviewDecision string `json:"reviewDecision"`
+	Mergeable      string `json:"mergeable"`
+	// StatusCheckRollup holds check runs, which report status and conclusion, and commit
+	// statuses, which report state
+	StatusCheckRollup []struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+		State      string `json:"state"`
+	} `json:"statusCheckRollup"`
+}
+
+// pullRequestStatus is a pull request created by a run, as listed by monday pr status.
+type pullRequestStatus struct {
+	// URL is the pull request URL recorded by the run
+	URL string `json:"url"`
+	// Number is the pull request number
+	Number int `json:"number,omitempty"`
+	// Title is the pull request title
+	Title string `json:"title,omitempty"`
+	// IssueID is the Linear issue the run worked on
+	IssueID string `json:"issue_id"`
+	// RunID is the most recent run that recorded the pull request
+	RunID string `json:"run_id"`
+	// State is open, closed, merged, or draft
+	State string `json:"state,omitempty"`
+	// Review is the review decision: approved, changes_requested, review_required, or none
+	Review string `json:"review,omitempty"`
+	// Checks is the combined CI status: passing, failing, pending, or none
+	Checks string `json:"checks,omitempty"`
+	// Conflicting is set when the pull request cannot be merged into its base branch
+	Conflicting bool `json:"conflicting,omitempty"`
+	// NeedsAttention is set for open pull requests that are blocked on their author
+	NeedsAttention bool `json:"needs_attention"`
+	// Error is why the status could not be looked up
+	Error string `json:"error,omitempty"`
+}
+
+// fetchPullRequest looks up the pull request at url on GitHub.
+var fetchPullRequest = func(ctx context.Context, url string) (*ghPullRequest, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "view", url, "--json",
+		"number,title,url,state,isDraft,reviewDecision,mergeable,statusCheckRollup")
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to look up pull request: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to look up pull request: %w", err)
+	}
+	var pr ghPullRequest
+	if err := json.Unmarshal(out, &pr); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request: %w", err)
+	}
+	return &pr, nil
+}
+
+func runPRStatus(cmd *cobra.Command, args []string) error {
+	filter, err := buildHistoryFilter(prRepo, prIssue, "", prSince, "")
+	if err != nil {
+		return err
+	}
+	runs, err := queryRuns(filter)
+	if err != nil {
+		return err
+	}
+
+	statuses := pullRequestStatuses(cmd.Context(), runs)
+	if prNeedsAttention {
+		var attention []pullRequestStatus
+		for _, status := range statuses {
+			if status.NeedsAttention || status.Error != "" {
+				attention = append(attention, status)
+			}
+		}
+		statuses = attention
+	}
+
+	if jsonOutput() {
+		if statuses == nil {
+			statuses = []pullRequestStatus{}
+		}
+		return writeJSON(statuses)
+	}
+	printPullRequestStatuses(resultOut, statuses)
+	return nil
+}
+
+// pullRequestStatuses looks up the pull requests recorded by runs, once each and attributed to
+// the most recent run that recorded them. Pull requests that cannot be looked up are returned
+// with Error set.
+func pullRequestStatuses(ctx context.Context, runs []*summary.Summary) []pullRequestStatus {
+	latest := map[string]*summary.Summary{}
+	var urls []string
+	for _, run := range runs {
+		if run.PRURL == "" {
+			continue
+		}
+		if _, ok := latest[run.PRURL]; !ok {
+			urls = append(urls, run.PRURL)
+		}
+		latest[run.PRURL] = run
+	}
+
+	statuses := make([]pullRequestStatus, 0, len(urls))
+	for _, url := range urls {
+		run := latest[url]
+		status := pullRequestStatus{URL: url, IssueID: run.IssueID, RunID: run.RunID}
+		pr, err := fetchPullRequest(ctx, url)
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.apply(pr)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// apply fills in the status from the pull request as reported by GitHub.
+func (s *pullRequestStatus) apply(pr *ghPullRequest) {
+	s.Number = pr.Number
+	s.Title = pr.Title
+	s.State = strings.ToLower(pr.State)
+	if pr.IsDraft && s.State == "open" {
+		s.State = "draft"
+	}
+	s.Review = strings.ToLower(pr.ReviewDecision)
+	if s.Review == "" {
+		s.Review = "none"
+	}
+	s.Checks = checksStatus(pr)
+	s.Conflicting = pr.Mergeable == "CONFLICTING"
+	s.NeedsAttention = (s.State == "open" || s.State == "draft") &&
+		(s.Checks == "failing" || s.Review == "changes_requested" || s.Conflicting)
+}
+
+// checksStatus combines the check runs and commit statuses of pr: failing if any failed,
+// pending if any has not finished, passing otherwise, and none if there are none.
+func checksStatus(pr *ghPullRequest) string {
+	if len(pr.StatusCheckRollup) == 0 {
+		return "none"
+	}
+	pending := false
+	for _, check := range pr.StatusCheckRollup {
+		switch {
+		case check.State == "FAILURE" || check.State == "ERROR":
+			return "failing"
+		case check.State == "PENDING" || check.State == "EXPECTED":
+			pending = true
+		case check.State != "":
+		case check.Status != "" && check.Status != "COMPLETED":
+			pending = true
+		default:
+			switch check.Conclusion {
+			case "FAILURE", "TIMED_OUT", "CANCELLED", "ACTION_REQUIRED", "STARTUP_FAILURE":
+				return "failing"
+			}
+		}
+	}
+	if pending {
+		return "pending"
+	}
+	return "passing"
+}
+
+// printPullRequestStatuses writes a table of pull request statuses, marking those that need
+// attention.
+func printPullRequestStatuses(out io.Writer, statuses []pullRequestStatus) {
+	if len(statuses) == 0 {
+		fmt.Fprintln(out, "No matching pull requests")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\tISSUE\tPULL REQUEST\tSTATE\tREVIEW\tCHECKS\tTITLE")
+	for _, s := range statuses {
+		mark := ""
+		if s.NeedsAttention || s.Error != "" {
+			mark = "⚠️"
+		}
+		if s.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\t\t\n", mark, s.IssueID, s.URL, s.Error)
+			continue
+		}
+		state := s.State
+		if s.Conflicting {
+			state += " (conflicts)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", mark, s.IssueID, s.URL, state,
+			strings.ReplaceAll(s.Review, "_", " "), s.Checks, shorten(s.Title, maxTitleWidth))
+	}
+	w.Flush()
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"monday/summary"
+)
+
+func TestChecksStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		rollup string
+		want   string
+	}{
+		{"no checks", `[]`, "none"},
+		{"passing", `[{"status":"COMPLETED","conclusion":"SUCCESS"},{"state":"SUCCESS"}]`, "passing"},
+		{"skipped", `[{"status":"COMPLETED","conclusion":"SKIPPED"}]`, "passing"},
+		{"running", `[{"status":"IN_PROGRESS"},{"status":"COMPLETED","conclusion":"SUCCESS"}]`, "pending"},
+		{"pending status", `[{"state":"PENDING"}]`, "pending"},
+		{"failed check run", `[{"status":"IN_PROGRESS"},{"status":"COMPLETED","conclusion":"FAILURE"}]`, "failing"},
+		{"errored status", `[{"state":"ERROR"}]`, "failing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pr ghPullRequest
+			if err := json.Unmarshal([]byte(`{"statusCheckRollup":`+tt.rollup+`}`), &pr); err != nil {
+				t.Fatal(err)
+			}
+			if got := checksStatus(&pr); got != tt.want {
+				t.Errorf("checksStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequestStatuses(t *testing.T) {
+	prs := map[string]*ghPullRequest{
+		"https://github.com/o/r/pull/1": {Number: 1, Title: "feat: Fix login", State: "OPEN", ReviewDecision: "APPROVED"},
+		"https://github.com/o/r/pull/2": {Number: 2, Title: "feat: Add export", State: "OPEN", ReviewDecision: "CHANGES_REQUESTED"},
+		"https://github.com/o/r/pull/3": {Number: 3, Title: "feat: Old", State: "MERGED", Mergeable: "CONFLICTING"},
+		"https://github.com/o/r/pull/4": {Number: 4, Title: "feat: Draft", State: "OPEN", IsDraft: true, Mergeable: "CONFLICTING"},
+	}
+	orig := fetchPullRequest
+	fetchPullRequest = func(ctx context.Context, url string) (*ghPullRequest, error) {
+		if pr, ok := prs[url]; ok {
+			return pr, nil
+		}
+		return nil, errors.New("not found")
+	}
+	t.Cleanup(func() { fetchPullRequest = orig })
+
+	runs := []*summary.Summary{
+		{RunID: "r1", IssueID: "DEL-1", PRURL: "https://github.com/o/r/pull/1"},
+		{RunID: "r2", IssueID: "DEL-2", PRURL: "https://github.com/o/r/pull/2"},
+		{RunID: "r3", IssueID: "DEL-3"},
+		{RunID: "r4", IssueID: "DEL-1", PRURL: "https://github.com/o/r/pull/1"},
+		{RunID: "r5", IssueID: "DEL-5", PRURL: "https://github.com/o/r/pull/3"},
+		{RunID: "r6", IssueID: "DEL-6", PRURL: "https://github.com/o/r/pull/4"},
+		{RunID: "r7", IssueID: "DEL-7", PRURL: "https://github.com/o/r/pull/9"},
+	}
+	statuses := pullRequestStatuses(context.Background(), runs)
+	if len(statuses) != 5 {
+		t.Fatalf("got %d statuses, want 5: %+v", len(statuses), statuses)
+	}
+
+	want := []struct {
+		runID, state, review string
+		attention            bool
+	}{
+		{"r4", "open", "approved", false},
+		{"r2", "open", "changes_requested", true},
+		{"r5", "merged", "none", false},
+		{"r6", "draft", "none", true},
+		{"r7", "", "", false},
+	}
+	for i, w := range want {
+		s := statuses[i]
+		if s.RunID != w.runID || s.State != w.state || s.Review != w.review || s.NeedsAttention != w.attention {
+			t.Errorf("statuses[%d] = %+v, want run %s, state %q, review %q, attention %v", i, s, w.runID, w.state, w.review, w.attention)
+		}
+	}
+	if statuses[4].Error != "not found" {
+		t.Errorf("statuses[4].Error = %q, want %q", statuses[4].Error, "not found")
+	}
+
+	var out bytes.Buffer
+	printPullRequestStatuses(&out, statuses)
+	if !strings.Contains(out.String(), "changes requested") || !strings.Contains(out.String(), "draft (conflicts)") {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"monday/issues"
+	"monday/vcs"
+)
+
+var (
+	// prLabels are added to the pull request.
+	prLabels []string
+	// prReviewers are asked to review the pull request.
+	prReviewers []string
+	// prAssignees are assigned the pull request.
+	prAssignees []string
+	// prMilestone is the milestone the pull request is added to.
+	prMilestone string
+	// prLabelMap maps labels of the issue to labels of the pull request, as LINEAR=GITHUB.
+	prLabelMap []string
+)
+
+func init() {
+	rootCmd.Flags().StringSliceVar(&prLabels, "pr-label", nil, "Label to add to the pull request; repeat it or separate labels with commas (default: $MONDAY_PR_LABELS)")
+	rootCmd.Flags().StringSliceVar(&prReviewers, "pr-reviewer", nil, "User or team (org/team) to request a review of the pull request from; repeat it or separate them with commas (default: $MONDAY_PR_REVIEWERS)")
+	rootCmd.Flags().StringSliceVar(&prAssignees, "pr-assignee", nil, "User to assign the pull request to, or @me; repeat it or separate users with commas (default: $MONDAY_PR_ASSIGNEES)")
+	rootCmd.Flags().StringVar(&prMilestone, "pr-milestone", "", "Title of the milestone to add the pull request to (default: $MONDAY_PR_MILESTONE)")
+	rootCmd.Flags().StringSliceVar(&prLabelMap, "pr-label-map", nil, "Add a pull request label for an issue label, as ISSUE_LABEL=PR_LABEL; repeat it or separate mappings with commas (default: $MONDAY_PR_LABEL_MAP)")
+}
+
+// flagOrEnvList returns values, or else the comma-separated list in the environment variable
+// env.
+func flagOrEnvList(values []string, env string) []string {
+	if len(values) > 0 {
+		return values
+	}
+	var list []string
+	for _, v := range strings.Split(os.Getenv(env), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// parseLabelMap parses ISSUE_LABEL=PR_LABEL mappings into a map keyed by the lowercased issue
+// label.
+func parseLabelMap(mappings []string) (map[string]string, error) {
+	labels := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		from, to, ok := strings.Cut(m, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid --pr-label-map entry %q: use ISSUE_LABEL=PR_LABEL", m)
+		}
+		labels[strings.ToLower(from)] = to
+	}
+	return labels, nil
+}
+
+// addPRMetadata sets the labels, reviewers, assignees, and milestone of cr from the flags or
+// the environment. The labels of issue that --pr-label-map maps are added as their pull
+// request labels; the others are left out, as the repository may not have them.
+func addPRMetadata(cr *vcs.ChangeRequest, issue *issues.Issue) error {
+	labelMap, err := parseLabelMap(flagOrEnvList(prLabelMap, "MONDAY_PR_LABEL_MAP"))
+	if err != nil {
+		return err
+	}
+	labels := slices.Clone(flagOrEnvList(prLabels, "MONDAY_PR_LABELS"))
+	for _, label := range issue.Labels.Nodes {
+		if mapped, ok := labelMap[strings.ToLower(label.Name)]; ok && !slices.Contains(labels, mapped) {
+			labels = append(labels, mapped)
+		}
+	}
+	cr.Labels = labels
+	cr.Reviewers = flagOrEnvList(prReviewers, "MONDAY_PR_REVIEWERS")
+	cr.Assignees = flagOrEnvList(prAssignees, "MONDAY_PR_ASSIGNEES")
+	cr.Milestone = prMilestone
+	if cr.Milestone == "" {
+		cr.Milestone = os.Getenv("MONDAY_PR_MILESTONE")
+	}
+	return nil
+}
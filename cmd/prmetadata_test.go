@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"monday/linear"
+	"monday/vcs"
+)
+
+// resetPRMetadata clears the pull request metadata flags and variables for the test.
+func resetPRMetadata(t *testing.T) {
+	t.Helper()
+	origLabels, origReviewers, origAssignees, origMilestone, origMap := prLabels, prReviewers, prAssignees, prMilestone, prLabelMap
+	t.Cleanup(func() {
+		prLabels, prReviewers, prAssignees, prMilestone, prLabelMap = origLabels, origReviewers, origAssignees, origMilestone, origMap
+	})
+	prLabels, prReviewers, prAssignees, prMilestone, prLabelMap = nil, nil, nil, "", nil
+	for _, env := range []string{"MONDAY_PR_LABELS", "MONDAY_PR_REVIEWERS", "MONDAY_PR_ASSIGNEES", "MONDAY_PR_MILESTONE", "MONDAY_PR_LABEL_MAP"} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestAddPRMetadata(t *testing.T) {
+	resetPRMetadata(t)
+	prLabels = []string{"monday"}
+	prReviewers = []string{"alice", "acme/platform"}
+	prLabelMap = []string{"Bug=bug", "Feature=enhancement", "Tech Debt=debt"}
+	t.Setenv("MONDAY_PR_ASSIGNEES", "@me, bob")
+	t.Setenv("MONDAY_PR_MILESTONE", "v2.4")
+	issue := &linear.IssueDetails{Title: "Fix login", Labels: linear.LabelsConnection{Nodes: []linear.Label{{Name: "bug"}, {Name: "Frontend"}, {Name: "tech debt"}}}}
+
+	cr, err := pullRequest(nil, issue, "feature/del_163", nil, "")
+	if err != nil {
+		t.Fatalf("pullRequest() error = %v", err)
+	}
+	if want := []string{"monday", "bug", "debt"}; !slices.Equal(cr.Labels, want) {
+		t.Errorf("labels = %v, want %v", cr.Labels, want)
+	}
+	if want := []string{"alice", "acme/platform"}; !slices.Equal(cr.Reviewers, want) {
+		t.Errorf("reviewers = %v, want %v", cr.Reviewers, want)
+	}
+	if want := []string{"@me", "bob"}; !slices.Equal(cr.Assignees, want) {
+		t.Errorf("assignees = %v, want %v", cr.Assignees, want)
+	}
+	if cr.Milestone != "v2.4" {
+		t.Errorf("milestone = %q, want v2.4", cr.Milestone)
+	}
+	if !slices.Equal(prLabels, []string{"monday"}) {
+		t.Errorf("--pr-label was changed to %v", prLabels)
+	}
+}
+
+func TestAddPRMetadataRejectsInvalidLabelMap(t *testing.T) {
+	resetPRMetadata(t)
+	t.Setenv("MONDAY_PR_LABEL_MAP", "Bug")
+
+	_, err := pullRequest(nil, &linear.IssueDetails{Title: "Fix login"}, "feature/del_163", nil, "")
+	if err == nil || !strings.Contains(err.Error(), "ISSUE_LABEL=PR_LABEL") {
+		t.Errorf("pullRequest() error = %v, want one about the label map", err)
+	}
+}
+
+func TestPullRequestArgsWithMetadata(t *testing.T) {
+	cr := vcs.ChangeRequest{Title: "feat: Fix login", Body: "Details", SourceBranch: "feature/del_163", Labels: []string{"bug", "monday"}, Reviewers: []string{"alice"}, Assignees: []string{"@me"}, Milestone: "v2.4"}
+	got := strings.Join(pullRequestArgs(cr), " ")
+	want := "pr create --title feat: Fix login --body Details --head feature/del_163 --label bug --label monday --reviewer alice --assignee @me --milestone v2.4"
+	if got != want {
+		t.Errorf("pullRequestArgs() = %q, want %q", got, want)
+	}
+}
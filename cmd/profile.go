@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// profileFile is the name of the profiles holding the defaults of a user, in their home
+// directory, and of a repository, at its root. It is named apart from the repository's
+// .monday.yml of gates and hooks, which has another schema.
+const profileFile = ".monday-profile.yaml"
+
+// profilePaths returns the profiles that apply, highest precedence first: that of the
+// repository the current directory is in, then the user's.
+func profilePaths() []string {
+	var paths []string
+	if dir, err := os.Getwd(); err == nil {
+		if path := repoProfile(dir); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, profileFile))
+	}
+	return paths
+}
+
+// repoProfile returns the profile at the root of the repository dir is in, or "" when dir is
+// not in a repository. The root is the nearest directory up from dir with a .git entry.
+func repoProfile(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return filepath.Join(dir, profileFile)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// profileRepoURLs returns the repositories given by MONDAY_REPO_URL, which a profile may set,
+// for runs without --repo-url or --local-repo.
+func profileRepoURLs() []string {
+	var urls []string
+	for _, url := range strings.Split(os.Getenv("MONDAY_REPO_URL"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRepoProfile(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(repo, "services", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := repoProfile(nested), filepath.Join(repo, profileFile); got != want {
+		t.Errorf("repoProfile() = %q, want %q", got, want)
+	}
+}
+
+func TestProfileRepoURLs(t *testing.T) {
+	t.Setenv("MONDAY_REPO_URL", "https://github.com/acme/api, https://github.com/acme/web")
+
+	want := []string{"https://github.com/acme/api", "https://github.com/acme/web"}
+	if got := profileRepoURLs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("profileRepoURLs() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadConfigEnvProfiles(t *testing.T) {
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	write(filepath.Join(home, profileFile), "base_branch: develop\ntest_command: make test\nprompt_template: /home/prompt.tmpl\n")
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("MONDAY_CONFIG", config)
+	write(config, "base_branch: main\nworktree_root: /tmp/worktrees\ntest_command: go test ./...\n")
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write(filepath.Join(repo, profileFile), "repo_url: https://github.com/acme/api\ntest_command: make check\n")
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []string{"MONDAY_REPO_URL", "MONDAY_BASE_BRANCH", "MONDAY_TEST_COMMAND", "MONDAY_WORKTREE_ROOT"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+	t.Setenv("MONDAY_PROMPT_TEMPLATE", "/env/prompt.tmpl")
+
+	if err := loadConfigEnv(); err != nil {
+		t.Fatalf("loadConfigEnv() error = %v", err)
+	}
+
+	for env, want := range map[string]string{
+		"MONDAY_REPO_URL":        "https://github.com/acme/api",
+		"MONDAY_TEST_COMMAND":    "make check",
+		"MONDAY_BASE_BRANCH":     "develop",
+		"MONDAY_WORKTREE_ROOT":   "/tmp/worktrees",
+		"MONDAY_PROMPT_TEMPLATE": "/env/prompt.tmpl",
+	} {
+		if got := os.Getenv(env); got != want {
+			t.Errorf("%s = %q, want %q", env, got, want)
+		}
+	}
+}
+
+func TestLoadConfigEnvRejectsUnknownProfileKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MONDAY_CONFIG", filepath.Join(home, "config.yaml"))
+	if err := os.WriteFile(filepath.Join(home, profileFile), []byte("repo_ur: https://github.com/acme/api\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loadConfigEnv(); err == nil || exitCodeFor(err) != exitConfig {
+		t.Errorf("loadConfigEnv() error = %v, want a config error", err)
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"monday/issues"
+	"monday/prompt"
+)
+
+// repoContextDir is the directory, relative to the root of the repository a run works on,
+// whose files are added to the agent prompt.
+const repoContextDir = ".monday/context"
+
+// maxContextBytes is the size each context file or URL is truncated to.
+const maxContextBytes = 256 << 10
+
+var (
+	contextFiles []string
+	contextURLs  []string
+	// promptTemplatePath is the Go template file the agent prompt is rendered from.
+	promptTemplatePath string
+)
+
+func init() {
+	rootCmd.Flags().StringArrayVar(&contextFiles, "context-file", nil, "File whose contents are added to the agent prompt, e.g. a design doc or error log (repeatable)")
+	rootCmd.Flags().StringArrayVar(&contextURLs, "context-url", nil, "URL whose contents are added to the agent prompt, e.g. an API spec (repeatable)")
+	rootCmd.Flags().StringVar(&promptTemplatePath, "prompt-template", "", "Go template file to render the agent prompt from (default: $MONDAY_PROMPT_TEMPLATE or the built-in template)")
+}
+
+// promptContext is additional material for the agent prompt.
+type promptContext = prompt.Context
+
+// loadPromptTemplate parses the template selected with --prompt-template or
+// MONDAY_PROMPT_TEMPLATE, or returns the built-in one if there is none.
+func loadPromptTemplate() (*prompt.Template, error) {
+	path := promptTemplatePath
+	if path == "" {
+		path = os.Getenv("MONDAY_PROMPT_TEMPLATE")
+	}
+	if path == "" {
+		return prompt.Default(), nil
+	}
+	return prompt.Load(path)
+}
+
+// loadContextFlags reads the files of --context-file and downloads the URLs of --context-url.
+func loadContextFlags(ctx context.Context) ([]promptContext, error) {
+	var contexts []promptContext
+	for _, path := range contextFiles {
+		content, err := readContextFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read context file: %w", err)
+		}
+		contexts = append(contexts, promptContext{Source: path, Content: content})
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	for _, url := range contextURLs {
+		content, err := fetchContextURL(ctx, client, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch context URL %s: %w", url, err)
+		}
+		contexts = append(contexts, promptContext{Source: url, Content: content})
+	}
+	return contexts, nil
+}
+
+// loadRepoContext reads the files directly in dir, in name order, skipping hidden files.
+// A missing dir holds no context.
+func loadRepoContext(dir string) ([]promptContext, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var contexts []promptContext
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := readContextFile(path)
+		if err != nil {
+			return nil, err
+		}
+		contexts = append(contexts, promptContext{Source: filepath.ToSlash(path), Content: content})
+	}
+	return contexts, nil
+}
+
+// readContextFile returns the contents of the file at path, truncated to maxContextBytes.
+func readContextFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return readContext(file)
+}
+
+// fetchContextURL returns the body of a GET request for url, truncated to maxContextBytes.
+func fetchContextURL(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return readContext(resp.Body)
+}
+
+// readContext reads r up to maxContextBytes, noting in the result when it was truncated.
+func readContext(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxContextBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxContextBytes {
+		return string(data[:maxContextBytes]) + "\n[truncated]", nil
+	}
+	return string(data), nil
+}
+
+// buildPrompt renders the agent prompt for issue with tmpl, from the issue, its acceptance
+// criteria and comments, the repository's conventions, and the contexts.
+func buildPrompt(tmpl *prompt.Template, issue *issues.Issue, conventions string, contexts []promptContext) (string, error) {
+	return tmpl.Build(prompt.NewData(issue, conventions, contexts))
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"monday/linear"
+	"monday/prompt"
+)
+
+func TestLoadContextFlags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/spec.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("openapi: 3.0.0\n"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "design.md")
+	if err := os.WriteFile(path, []byte("# Design\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	origFiles, origURLs := contextFiles, contextURLs
+	t.Cleanup(func() { contextFiles, contextURLs = origFiles, origURLs })
+
+	contextFiles, contextURLs = []string{path}, []string{srv.URL + "/spec.yaml"}
+	contexts, err := loadContextFlags(context.Background())
+	if err != nil {
+		t.Fatalf("loadContextFlags() error = %v", err)
+	}
+	if len(contexts) != 2 || contexts[0].Content != "# Design\n" || contexts[1].Content != "openapi: 3.0.0\n" {
+		t.Errorf("loadContextFlags() = %+v", contexts)
+	}
+
+	contextFiles, contextURLs = nil, []string{srv.URL + "/missing"}
+	if _, err := loadContextFlags(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("loadContextFlags() error = %v, want status 404", err)
+	}
+
+	contextFiles, contextURLs = []string{filepath.Join(t.TempDir(), "missing.md")}, nil
+	if _, err := loadContextFlags(context.Background()); err == nil {
+		t.Error("loadContextFlags() succeeded for a missing file")
+	}
+}
+
+func TestLoadRepoContext(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".monday", "context")
+	if contexts, err := loadRepoContext(dir); err != nil || contexts != nil {
+		t.Fatalf("loadRepoContext() of a missing dir = %v, %v", contexts, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"b-errors.log": "panic", "a-api.md": "GET /users", ".hidden": "x", "nested/c.md": "y"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	contexts, err := loadRepoContext(dir)
+	if err != nil {
+		t.Fatalf("loadRepoContext() error = %v", err)
+	}
+	if len(contexts) != 2 || !strings.HasSuffix(contexts[0].Source, "a-api.md") || contexts[1].Content != "panic" {
+		t.Errorf("loadRepoContext() = %+v", contexts)
+	}
+}
+
+func TestReadContextTruncates(t *testing.T) {
+	content, err := readContext(strings.NewReader(strings.Repeat("x", maxContextBytes+10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) != maxContextBytes+len("\n[truncated]") || !strings.HasSuffix(content, "\n[truncated]") {
+		t.Errorf("readContext() returned %d bytes", len(content))
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	tmpl := prompt.Default()
+	issue := &linear.IssueDetails{Title: "Fix login", Description: "Users cannot log in"}
+	if got, err := buildPrompt(tmpl, issue, "", nil); err != nil || got != "Fix login\n\nUsers cannot log in" {
+		t.Errorf("buildPrompt() = %q, %v", got, err)
+	}
+	got, err := buildPrompt(tmpl, issue, "", []promptContext{{Source: "errors.log", Content: "panic: nil map\n"}})
+	want := "Fix login\n\nUsers cannot log in\n\n## Additional context: errors.log\n\npanic: nil map"
+	if err != nil || got != want {
+		t.Errorf("buildPrompt() = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestLoadPromptTemplate(t *testing.T) {
+	orig := promptTemplatePath
+	t.Cleanup(func() { promptTemplatePath = orig })
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	if err := os.WriteFile(path, []byte("Fix {{.Issue.Identifier}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	promptTemplatePath = ""
+	t.Setenv("MONDAY_PROMPT_TEMPLATE", path)
+	tmpl, err := loadPromptTemplate()
+	if err != nil {
+		t.Fatalf("loadPromptTemplate() error = %v", err)
+	}
+	if got, _ := buildPrompt(tmpl, &linear.IssueDetails{Identifier: "DEL-1"}, "", nil); got != "Fix DEL-1" {
+		t.Errorf("buildPrompt() with $MONDAY_PROMPT_TEMPLATE = %q, want %q", got, "Fix DEL-1")
+	}
+
+	promptTemplatePath = filepath.Join(t.TempDir(), "missing.tmpl")
+	if _, err := loadPromptTemplate(); err == nil {
+		t.Error("loadPromptTemplate() succeeded for a missing --prompt-template")
+	}
+}
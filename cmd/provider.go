@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"monday/issues"
+	"monday/jira"
+	"monday/workflow"
+)
+
+// Issue trackers, selected with --provider, that runs fetch their issue from.
+const (
+	providerLinear = "linear"
+	providerJira   = "jira"
+)
+
+var issueProvider string
+
+func init() {
+	rootCmd.Flags().StringVar(&issueProvider, "provider", "", "Issue tracker the issue comes from: linear or jira (default: $MONDAY_ISSUE_PROVIDER or linear)")
+
+	workflow.RegisterProvider(providerLinear, newLinearProvider)
+	workflow.RegisterProvider(providerJira, newJiraProvider)
+}
+
+// selectedProvider returns the issue tracker called name or, if name is empty, the one
+// selected with MONDAY_ISSUE_PROVIDER, which defaults to linear.
+func selectedProvider(name string) string {
+	if name == "" {
+		name = os.Getenv("MONDAY_ISSUE_PROVIDER")
+	}
+	if name == "" {
+		return providerLinear
+	}
+	return name
+}
+
+// providerName returns the issue tracker selected with --provider or MONDAY_ISSUE_PROVIDER.
+func providerName() string {
+	return selectedProvider(issueProvider)
+}
+
+// newIssueProvider returns a client of the issue tracker selected with --provider or
+// MONDAY_ISSUE_PROVIDER.
+func newIssueProvider() (issues.Provider, error) {
+	return openIssueProvider(issueProvider)
+}
+
+// openIssueProvider returns a client of the issue tracker selectedProvider returns for name,
+// one of those registered with the workflow package.
+func openIssueProvider(name string) (issues.Provider, error) {
+	return workflow.NewProvider(selectedProvider(name))
+}
+
+// newLinearProvider returns a Linear client authenticated from the environment:
+// LINEAR_API_KEY, LINEAR_ACCESS_TOKEN, or the token of monday auth linear.
+func newLinearProvider() (issues.Provider, error) {
+	client, err := newLinearAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// newJiraProvider returns a Jira client authenticated from JIRA_URL, JIRA_API_TOKEN, and, on
+// Jira Cloud, JIRA_EMAIL.
+func newJiraProvider() (issues.Provider, error) {
+	baseURL, token := os.Getenv("JIRA_URL"), os.Getenv("JIRA_API_TOKEN")
+	if baseURL == "" || token == "" {
+		return nil, fmt.Errorf("JIRA_URL and JIRA_API_TOKEN environment variables are required for Jira issues")
+	}
+	return jira.NewClient(baseURL, os.Getenv("JIRA_EMAIL"), token), nil
+}
+
+// issueReference returns the line that links commits and pull requests to issue.
+func issueReference(issue *issues.Issue) string {
+	tracker := "Linear"
+	if providerName() == providerJira {
+		tracker = "Jira"
+	}
+	return fmt.Sprintf("%s Issue: %s", tracker, issue.URL)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"monday/issues"
+	"monday/jira"
+	"monday/linear"
+)
+
+func TestNewIssueProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     string
+		env      map[string]string
+		wantType string
+		wantErr  string
+	}{
+		{name: "default", env: map[string]string{"LINEAR_API_KEY": "lin_api_key"}, wantType: "linear"},
+		{name: "linear without key", flag: "linear", wantErr: "LINEAR_API_KEY"},
+		{name: "jira flag", flag: "jira", env: map[string]string{"JIRA_URL": "https://acme.atlassian.net", "JIRA_API_TOKEN": "token"}, wantType: "jira"},
+		{name: "jira env", env: map[string]string{"MONDAY_ISSUE_PROVIDER": "jira", "JIRA_URL": "https://acme.atlassian.net", "JIRA_API_TOKEN": "token"}, wantType: "jira"},
+		{name: "jira without token", flag: "jira", env: map[string]string{"JIRA_URL": "https://acme.atlassian.net"}, wantErr: "JIRA_API_TOKEN"},
+		{name: "unknown", flag: "trello", wantErr: "invalid issue provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := issueProvider
+			t.Cleanup(func() { issueProvider = orig })
+			issueProvider = tt.flag
+			for _, name := range []string{"MONDAY_ISSUE_PROVIDER", "LINEAR_API_KEY", "JIRA_URL", "JIRA_API_TOKEN"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			provider, err := newIssueProvider()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("newIssueProvider() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newIssueProvider() error = %v", err)
+			}
+			switch provider.(type) {
+			case *linear.Client:
+				if tt.wantType != "linear" {
+					t.Errorf("newIssueProvider() = Linear, want %s", tt.wantType)
+				}
+			case *jira.Client:
+				if tt.wantType != "jira" {
+					t.Errorf("newIssueProvider() = Jira, want %s", tt.wantType)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueReference(t *testing.T) {
+	orig := issueProvider
+	t.Cleanup(func() { issueProvider = orig })
+	t.Setenv("MONDAY_ISSUE_PROVIDER", "")
+	issue := &issues.Issue{URL: "https://acme.atlassian.net/browse/PROJ-42"}
+
+	issueProvider = "jira"
+	if got, want := issueReference(issue), "Jira Issue: https://acme.atlassian.net/browse/PROJ-42"; got != want {
+		t.Errorf("issueReference() = %q, want %q", got, want)
+	}
+	issueProvider = ""
+	if got := issueReference(issue); !strings.HasPrefix(got, "Linear Issue: ") {
+		t.Errorf("issueReference() = %q, want a Linear reference", got)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"monday/describe"
+	"monday/issues"
+	"monday/redact"
+)
+
+var (
+	// noPRSummary leaves the summary of the diff out of the pull request.
+	noPRSummary bool
+	// prSummaryModel is the OpenAI model the diff is summarized with.
+	prSummaryModel string
+)
+
+func init() {
+	rootCmd.Flags().BoolVar(&noPRSummary, "no-pr-summary", false, "Do not add a file-by-file summary of the diff and testing notes to the pull request (MONDAY_PR_SUMMARY=false does the same)")
+	rootCmd.Flags().StringVar(&prSummaryModel, "pr-summary-model", "", "OpenAI model to summarize the diff for the pull request with (default: $MONDAY_PR_SUMMARY_MODEL or "+describe.DefaultModel+")")
+}
+
+// prSummaryEnabled reports whether the diff is summarized for the pull request: unless
+// --no-pr-summary is set or MONDAY_PR_SUMMARY is false.
+func prSummaryEnabled() (bool, error) {
+	if noPRSummary {
+		return false, nil
+	}
+	value := os.Getenv("MONDAY_PR_SUMMARY")
+	if value == "" {
+		return true, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid MONDAY_PR_SUMMARY %q: must be true or false", value)
+	}
+	return enabled, nil
+}
+
+// summarizeChanges returns the Changes and Testing notes sections the OpenAI API writes for the
+// commit of the run in workDir, with credentials redacted. It returns "" if summaries are
+// turned off or the summary fails, which only costs the pull request the sections.
+func summarizeChanges(ctx context.Context, log *zap.Logger, workDir string, issue *issues.Issue) string {
+	if enabled, err := prSummaryEnabled(); err != nil || !enabled {
+		return ""
+	}
+	diff, err := gitCommand(workDir, "show", "--format=", "--stat", "--patch", "HEAD").Output()
+	if err != nil {
+		log.Warn("Failed to read the diff to summarize", zap.Error(err))
+		return ""
+	}
+	model := prSummaryModel
+	if model == "" {
+		model = os.Getenv("MONDAY_PR_SUMMARY_MODEL")
+	}
+	describer := &describe.Describer{APIKey: os.Getenv("OPENAI_API_KEY"), Model: model, BaseURL: os.Getenv("OPENAI_BASE_URL")}
+	progressf("   summarizing the changes\n")
+	changes, err := describer.Describe(ctx, issue.Title, string(diff))
+	if err != nil {
+		log.Warn("Failed to summarize the changes for the pull request", zap.Error(err))
+		return ""
+	}
+	return redact.String(changes)
+}
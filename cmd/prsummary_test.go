@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/linear"
+)
+
+func TestSummarizeChanges(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"choices": [{"message": {"content": "## Changes\n- ` + "`login.go`" + `: validates the session\n\n## Testing notes\n- go test ./..."}}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("OPENAI_BASE_URL", server.URL)
+	t.Setenv("MONDAY_PR_SUMMARY", "")
+	t.Setenv("MONDAY_PR_SUMMARY_MODEL", "")
+
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "login.go"), []byte("package login\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, repo, "add", ".")
+	git(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "feat: Fix login")
+	issue := &linear.IssueDetails{Title: "Fix login", Description: "Users cannot log in."}
+
+	changes := summarizeChanges(context.Background(), zap.NewNop(), repo, issue)
+	if !strings.HasPrefix(changes, "## Changes\n- `login.go`") {
+		t.Errorf("summarizeChanges() = %q, want the Changes section", changes)
+	}
+	cr := changeRequest(issue, "feature/del_163", changes)
+	if !strings.HasPrefix(cr.Body, "Users cannot log in.\n\n## Changes") || !strings.Contains(cr.Body, "## Testing notes") {
+		t.Errorf("pull request body = %q, want the description followed by the summary", cr.Body)
+	}
+
+	t.Setenv("MONDAY_PR_SUMMARY", "false")
+	if changes := summarizeChanges(context.Background(), zap.NewNop(), repo, issue); changes != "" || requests != 1 {
+		t.Errorf("summarizeChanges() with MONDAY_PR_SUMMARY=false = %q after %d requests, want none", changes, requests)
+	}
+}
+
+func TestSummarizeChangesFailureIsIgnored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	t.Setenv("OPENAI_BASE_URL", server.URL)
+	t.Setenv("MONDAY_PR_SUMMARY", "")
+
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+	git(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+
+	if changes := summarizeChanges(context.Background(), zap.NewNop(), repo, &linear.IssueDetails{Title: "Fix login"}); changes != "" {
+		t.Errorf("summarizeChanges() = %q, want nothing when the API fails", changes)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"monday/issues"
+	"monday/vcs"
+)
+
+// prTemplatePath is the Go template file that pull request titles and bodies are rendered from.
+var prTemplatePath string
+
+func init() {
+	rootCmd.Flags().StringVar(&prTemplatePath, "pr-template", "", "Go template file to render the pull request from: its first line is the title, the rest the body (default: $MONDAY_PR_TEMPLATE)")
+}
+
+// prTemplateData is what pull request templates are executed with.
+type prTemplateData struct {
+	// Issue is the issue the run works on
+	Issue *issues.Issue
+	// Branch is the branch with the changes
+	Branch string
+	// BaseBranch is the branch the pull request targets; empty for the repository's default branch
+	BaseBranch string
+	// ChangedFiles lists the files changed by the run's commit
+	ChangedFiles []string
+	// Changes is the summary of the diff, with its Changes and Testing notes sections; empty
+	// if there is none
+	Changes string
+}
+
+// prTemplate renders pull request titles and bodies.
+type prTemplate struct {
+	tmpl *template.Template
+}
+
+// loadPRTemplate parses the template selected with --pr-template or MONDAY_PR_TEMPLATE, or
+// returns nil if there is none. Templates that fail on an empty issue are rejected up front
+// instead of after the agent ran.
+func loadPRTemplate() (*prTemplate, error) {
+	path := prTemplatePath
+	if path == "" {
+		path = os.Getenv("MONDAY_PR_TEMPLATE")
+	}
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pull request template: %w", err)
+	}
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pull request template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, prTemplateData{Issue: &issues.Issue{}}); err != nil {
+		return nil, fmt.Errorf("invalid pull request template: %w", err)
+	}
+	return &prTemplate{tmpl: tmpl}, nil
+}
+
+// apply replaces the title and body of cr with the template rendered for issue, the changed
+// files, and the summary of the diff.
+func (t *prTemplate) apply(cr *vcs.ChangeRequest, issue *issues.Issue, changedFiles []string, changes string) error {
+	var out bytes.Buffer
+	data := prTemplateData{Issue: issue, Branch: cr.SourceBranch, BaseBranch: cr.TargetBranch, ChangedFiles: changedFiles, Changes: changes}
+	if err := t.tmpl.Execute(&out, data); err != nil {
+		return fmt.Errorf("failed to render pull request template: %w", err)
+	}
+	title, body, _ := strings.Cut(strings.TrimLeft(out.String(), "\n"), "\n")
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("pull request template rendered an empty title")
+	}
+	cr.Title = title
+	cr.Body = strings.TrimSpace(body)
+	return nil
+}
+
+// pullRequest returns the pull request of issue from branch, rendered with tmpl if not nil.
+func pullRequest(tmpl *prTemplate, issue *issues.Issue, branch string, changedFiles []string, changes string) (vcs.ChangeRequest, error) {
+	cr := changeRequest(issue, branch, changes)
+	if err := addPRMetadata(&cr, issue); err != nil {
+		return cr, err
+	}
+	if tmpl == nil {
+		return cr, nil
+	}
+	err := tmpl.apply(&cr, issue, changedFiles, changes)
+	return cr, err
+}
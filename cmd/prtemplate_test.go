@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"monday/linear"
+)
+
+func TestPullRequestTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		wantTitle string
+		wantBody  string
+		wantError bool
+	}{
+		{
+			name:      "title and body",
+			template:  "{{.Issue.Identifier}}: {{.Issue.Title}}\n\n{{.Issue.Description}}\n{{range .ChangedFiles}}- {{.}}\n{{end}}\nBranch {{.Branch}}\n",
+			wantTitle: "DEL-163: Fix login",
+			wantBody:  "Users cannot log in\n- auth.go\n- auth_test.go\n\nBranch feature/del_163",
+		},
+		{name: "title only", template: "\n{{.Issue.Title}}\n", wantTitle: "Fix login"},
+		{name: "changes", template: "{{.Issue.Title}}\n{{.Changes}}", wantTitle: "Fix login", wantBody: "## Changes\n- `auth.go`: checks the session"},
+		{name: "unknown field", template: "{{.Issue.Summary}}", wantError: true},
+		{name: "syntax error", template: "{{.Issue.Title", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pr.tmpl")
+			if err := os.WriteFile(path, []byte(tt.template), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			orig := prTemplatePath
+			t.Cleanup(func() { prTemplatePath = orig })
+			prTemplatePath = path
+
+			tmpl, err := loadPRTemplate()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("loadPRTemplate() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			issue := &linear.IssueDetails{Identifier: "DEL-163", Title: "Fix login", Description: "Users cannot log in"}
+			cr, err := pullRequest(tmpl, issue, "feature/del_163", []string{"auth.go", "auth_test.go"}, "## Changes\n- `auth.go`: checks the session")
+			if err != nil {
+				t.Fatalf("pullRequest() error = %v", err)
+			}
+			if cr.Title != tt.wantTitle || cr.Body != tt.wantBody {
+				t.Errorf("pullRequest() = %q, %q, want %q, %q", cr.Title, cr.Body, tt.wantTitle, tt.wantBody)
+			}
+			if cr.SourceBranch != "feature/del_163" {
+				t.Errorf("pullRequest() branch = %q, want feature/del_163", cr.SourceBranch)
+			}
+		})
+	}
+}
+
+func TestPullRequestWithoutTemplate(t *testing.T) {
+	t.Setenv("MONDAY_PR_TEMPLATE", "")
+	orig := prTemplatePath
+	t.Cleanup(func() { prTemplatePath = orig })
+	prTemplatePath = ""
+
+	tmpl, err := loadPRTemplate()
+	if err != nil || tmpl != nil {
+		t.Fatalf("loadPRTemplate() = %v, %v, want no template", tmpl, err)
+	}
+	cr, err := pullRequest(tmpl, &linear.IssueDetails{Title: "Fix login"}, "feature/del_163", nil, "")
+	if err != nil || cr.Title != "fix: Fix login" {
+		t.Errorf("pullRequest() = %q, %v, want fix: Fix login", cr.Title, err)
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"monday/retry"
+)
+
+// maxFallbackBranches is how many suffixed branch names a run tries once origin rejects the
+// push of its branch.
+const maxFallbackBranches = 5
+
+// pushRejectedError is a push of branch that origin refused for reason, such as branch
+// protection, rather than one that failed on the way.
+type pushRejectedError struct {
+	branch string
+	reason string
+	err    error
+}
+
+func (e *pushRejectedError) Error() string {
+	return fmt.Sprintf("push to %s was rejected (%s): %v", e.branch, e.reason, e.err)
+}
+
+func (e *pushRejectedError) Unwrap() error { return e.err }
+
+// pushRejection returns why origin rejected a push according to the stderr output of git
+// push, or "" if the push was not rejected.
+func pushRejection(output string) string {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "protected branch"), strings.Contains(lower, "gh006"):
+		return "protected branch"
+	case strings.Contains(lower, "[rejected]") && (strings.Contains(lower, "non-fast-forward") || strings.Contains(lower, "fetch first")),
+		strings.Contains(lower, "stale info"):
+		return "the remote branch has commits the run's branch lacks"
+	case strings.Contains(lower, "[remote rejected]"):
+		return "rejected by the remote"
+	}
+	return ""
+}
+
+// publishedBranch is the branch a run's commits were pushed to.
+type publishedBranch struct {
+	// name is the branch on origin
+	name string
+	// rejected explains why the push to the run's own branch was rejected, if name is a
+	// fallback branch
+	rejected *pushRejectedError
+}
+
+// pushBranch pushes branch of the working copy in workDir to origin, retrying failed pushes
+// with retries. When origin rejects the push, because the branch is protected or has commits
+// the run's branch lacks, the branch is renamed with a numbered suffix, e.g. feature/del_163-2,
+// one that origin does not have, and pushed under that name instead.
+func pushBranch(ctx context.Context, log *zap.Logger, retries retry.Policy, host codeHost, workDir, branch string) (publishedBranch, error) {
+	err := pushOnce(ctx, log, retries, host, workDir, branch)
+	var rejected *pushRejectedError
+	if !errors.As(err, &rejected) {
+		return publishedBranch{name: branch}, err
+	}
+	log.Warn("Push was rejected; pushing to a new branch instead", zap.String("branch", branch), zap.String("reason", rejected.reason))
+
+	current, reason := branch, rejected.reason
+	for n := 2; n < 2+maxFallbackBranches; n++ {
+		candidate := fmt.Sprintf("%s-%d", branch, n)
+		if remoteBranchExists(ctx, workDir, candidate) {
+			continue
+		}
+		progressf("   %s was rejected (%s); pushing to %s instead\n", current, reason, candidate)
+		if err := runGitCommand(ctx, log, workDir, "branch", "-m", current, candidate); err != nil {
+			return publishedBranch{name: current}, fmt.Errorf("failed to rename %s to %s: %w", current, candidate, err)
+		}
+		current = candidate
+
+		err := pushOnce(ctx, log, retries, host, workDir, candidate)
+		var again *pushRejectedError
+		if errors.As(err, &again) {
+			reason = again.reason
+			continue
+		}
+		if err != nil {
+			return publishedBranch{name: current}, err
+		}
+		return publishedBranch{name: candidate, rejected: rejected}, nil
+	}
+	return publishedBranch{name: current}, fmt.Errorf("%w; no fallback branch up to %s-%d was accepted either", rejected, branch, 1+maxFallbackBranches)
+}
+
+// pushOnce pushes branch to origin, retrying failed pushes with retries, and returns a
+// *pushRejectedError right away if origin rejects it.
+func pushOnce(ctx context.Context, log *zap.Logger, retries retry.Policy, host codeHost, workDir, branch string) error {
+	return retries.Do(ctx, func() error {
+		// An installation token of a GitHub App may have expired while the agent ran.
+		if err := host.refreshCredentials(ctx); err != nil {
+			return err
+		}
+		log.Info("Running git command", zap.Strings("args", []string{"push", "--set-upstream", "origin", branch}), zap.String("working_dir", workDir))
+		var stderr bytes.Buffer
+		cmd := newCommand(ctx, workDir, gitEnv(ctx), "git", "push", "--set-upstream", "origin", branch)
+		cmd.Stderr = &stderr
+		err := runWithRedactedOutput(cmd, showChildStdout(), showChildStderr())
+		if err == nil {
+			return nil
+		}
+		if reason := pushRejection(stderr.String()); reason != "" {
+			return retry.Permanent(&pushRejectedError{branch: branch, reason: reason, err: err})
+		}
+		return err
+	}, logRetry(log, "git push"))
+}
+
+// remoteBranchExists reports whether origin of the working copy in workDir has branch.
+func remoteBranchExists(ctx context.Context, workDir, branch string) bool {
+	cmd := newCommand(ctx, workDir, gitEnv(ctx), "git", "ls-remote", "--exit-code", "--heads", "origin", "refs/heads/"+branch)
+	return cmd.Run() == nil
+}
+
+// fallbackNote returns the note a pull request from a fallback branch opens with.
+func fallbackNote(pushed publishedBranch) string {
+	return fmt.Sprintf("> **Note:** monday could not push to `%s` (%s), so it pushed to `%s` and opened this pull request as a draft.",
+		pushed.rejected.branch, pushed.rejected.reason, pushed.name)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/retry"
+)
+
+func TestPushRejection(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "GitHub protected branch", output: "remote: error: GH006: Protected branch update failed for refs/heads/main.\n ! [remote rejected] main -> main (protected branch hook declined)", want: "protected branch"},
+		{name: "GitLab protected branch", output: "remote: GitLab: You are not allowed to push code to protected branches on this project.", want: "protected branch"},
+		{name: "non-fast-forward", output: " ! [rejected]        feature/del_1 -> feature/del_1 (non-fast-forward)\nerror: failed to push some refs", want: "the remote branch has commits the run's branch lacks"},
+		{name: "fetch first", output: " ! [rejected]        feature/del_1 -> feature/del_1 (fetch first)", want: "the remote branch has commits the run's branch lacks"},
+		{name: "hook", output: " ! [remote rejected] feature/del_1 -> feature/del_1 (pre-receive hook declined)", want: "rejected by the remote"},
+		{name: "network", output: "fatal: unable to access 'https://github.com/acme/app/': Could not resolve host: github.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pushRejection(tt.output); got != tt.want {
+				t.Errorf("pushRejection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushBranchFallsBackWhenRejected(t *testing.T) {
+	ctx := context.Background()
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	git(t, t.TempDir(), "init", "-q", "--bare", origin)
+	// The hook declines the run's branch and the first fallback name, as branch protection
+	// rules matching them would.
+	hook := "#!/bin/sh\nwhile read old new ref; do\n  case $ref in refs/heads/feature/del_1|refs/heads/feature/del_1-2) exit 1;; esac\ndone\n"
+	if err := os.WriteFile(filepath.Join(origin, "hooks", "pre-receive"), []byte(hook), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(t.TempDir(), "repo")
+	git(t, filepath.Dir(repo), "clone", "-q", origin, repo)
+	git(t, repo, "checkout", "-q", "-b", "feature/del_1")
+	git(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "change")
+
+	pushed, err := pushBranch(ctx, zap.NewNop(), retry.Policy{MaxAttempts: 1}, &gitlabHost{}, repo, "feature/del_1")
+	if err != nil {
+		t.Fatalf("pushBranch() error = %v", err)
+	}
+	if pushed.name != "feature/del_1-3" {
+		t.Errorf("pushBranch() pushed to %q, want feature/del_1-3", pushed.name)
+	}
+	if pushed.rejected == nil || pushed.rejected.branch != "feature/del_1" || pushed.rejected.reason != "rejected by the remote" {
+		t.Errorf("pushBranch() rejection = %+v", pushed.rejected)
+	}
+	if !remoteBranchExists(ctx, repo, "feature/del_1-3") {
+		t.Errorf("feature/del_1-3 was not pushed")
+	}
+	if note := fallbackNote(pushed); !strings.Contains(note, "`feature/del_1`") || !strings.Contains(note, "`feature/del_1-3`") {
+		t.Errorf("fallbackNote() = %q", note)
+	}
+}
+
+func TestPushBranchKeepsOtherFailures(t *testing.T) {
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "feature/del_1")
+	git(t, repo, "remote", "add", "origin", filepath.Join(t.TempDir(), "missing.git"))
+	git(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "change")
+
+	pushed, err := pushBranch(context.Background(), zap.NewNop(), retry.Policy{MaxAttempts: 1}, &gitlabHost{}, repo, "feature/del_1")
+	if err == nil {
+		t.Fatal("pushBranch() to a missing origin succeeded")
+	}
+	if pushed.name != "feature/del_1" || pushed.rejected != nil {
+		t.Errorf("pushBranch() = %+v, want the run's branch without a fallback", pushed)
+	}
+}
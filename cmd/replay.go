@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"monday/issues"
+	"monday/redact"
+	"monday/workflow"
+)
+
+// inputsFile records, in a run's directory, the inputs the run was started with.
+const inputsFile = "inputs.json"
+
+// errNoRunInputs is returned for runs recorded before inputsFile existed.
+var errNoRunInputs = errors.New("recorded no inputs to replay")
+
+var replayPublish bool
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <run-id>",
+	Short: "Re-execute a run with its recorded inputs",
+	Long: `Re-execute a previous run with the inputs it recorded: the issue as it was fetched from
+Linear, the repository, the clone and worktree options, and the agent prompt. Options given on
+the command line override the recorded ones.
+
+The replay works on a branch and workspace of its own, replay/<new-run-id>, and does not touch
+the Linear issue. It stops after committing the agent's changes unless --publish is set, in
+which case the branch is pushed and a pull request is opened.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().BoolVar(&replayPublish, "publish", false, "Push the replay's branch and open a pull request")
+	rootCmd.AddCommand(replayCmd)
+}
+
+// runInputs are the inputs a run was started with.
+type runInputs struct {
+	// RunID identifies the run
+	RunID string `json:"run_id"`
+	// Issue is the Linear issue as the run fetched it
+	Issue issues.Issue `json:"issue"`
+	// RepoURL is the repository cloned by the run (clone mode only)
+	RepoURL string `json:"repo_url,omitempty"`
+	// LocalRepo is the local repository the run created a worktree of (worktree mode only)
+	LocalRepo string `json:"local_repo,omitempty"`
+	// Prompt is the prompt the agent was given
+	Prompt string `json:"prompt"`
+	// Options are the workspace options of the run
+	Options runOptions `json:"options"`
+}
+
+// runOptions are the command-line options that shape a run's workspace.
+type runOptions struct {
+	CacheDir        string   `json:"cache_dir,omitempty"`
+	NoMirror        bool     `json:"no_mirror,omitempty"`
+	ReferenceClone  bool     `json:"reference_clone,omitempty"`
+	Dissociate      bool     `json:"dissociate,omitempty"`
+	FullFetch       bool     `json:"full_fetch,omitempty"`
+	CloneFilter     string   `json:"clone_filter,omitempty"`
+	CloneDepth      int      `json:"clone_depth,omitempty"`
+	SingleBranch    bool     `json:"single_branch,omitempty"`
+	SparsePaths     []string `json:"sparse_paths,omitempty"`
+	WorktreeMode    bool     `json:"worktree_mode,omitempty"`
+	WorktreeRoot    string   `json:"worktree_root,omitempty"`
+	WorkspaceRoot   string   `json:"workspace_root,omitempty"`
+	Rollback        bool     `json:"rollback,omitempty"`
+	BaseBranch      string   `json:"base_branch,omitempty"`
+	Draft           bool     `json:"draft,omitempty"`
+	PRLabels        []string `json:"pr_labels,omitempty"`
+	PRReviewers     []string `json:"pr_reviewers,omitempty"`
+	PRAssignees     []string `json:"pr_assignees,omitempty"`
+	PRMilestone     string   `json:"pr_milestone,omitempty"`
+	PRLabelMap      []string `json:"pr_label_map,omitempty"`
+	NoPRSummary     bool     `json:"no_pr_summary,omitempty"`
+	PRSummaryModel  string   `json:"pr_summary_model,omitempty"`
+	CommitType      string   `json:"commit_type,omitempty"`
+	RequireApproval bool     `json:"require_approval,omitempty"`
+	Provider        string   `json:"provider,omitempty"`
+	PRTemplate      string   `json:"pr_template,omitempty"`
+	PromptTemplate  string   `json:"prompt_template,omitempty"`
+	Containerized   bool     `json:"containerized,omitempty"`
+	ContainerImage  string   `json:"container_image,omitempty"`
+	Memory          string   `json:"memory,omitempty"`
+	CPUs            string   `json:"cpus,omitempty"`
+}
+
+// currentRunOptions returns the options of this invocation.
+func currentRunOptions() runOptions {
+	return runOptions{
+		CacheDir:        cacheDir,
+		NoMirror:        noMirror,
+		ReferenceClone:  referenceClone,
+		Dissociate:      dissociateClone,
+		FullFetch:       fullFetch,
+		CloneFilter:     cloneFilter,
+		CloneDepth:      cloneDepth,
+		SingleBranch:    singleBranch,
+		SparsePaths:     sparsePaths,
+		WorktreeMode:    worktreeMode,
+		WorktreeRoot:    worktreeRoot,
+		WorkspaceRoot:   workspaceRoot,
+		Rollback:        rollbackOnFailure,
+		BaseBranch:      baseBranch,
+		Draft:           draftPR,
+		PRLabels:        prLabels,
+		PRReviewers:     prReviewers,
+		PRAssignees:     prAssignees,
+		PRMilestone:     prMilestone,
+		PRLabelMap:      prLabelMap,
+		NoPRSummary:     noPRSummary,
+		PRSummaryModel:  prSummaryModel,
+		CommitType:      commitTypeFlag,
+		RequireApproval: requireApproval,
+		Provider:        issueProvider,
+		PRTemplate:      prTemplatePath,
+		PromptTemplate:  promptTemplatePath,
+		Containerized:   containerized,
+		ContainerImage:  containerImage,
+		Memory:          containerMemory,
+		CPUs:            containerCPUs,
+	}
+}
+
+// runOptionsFor returns the options of this invocation, with those of the run with opts.
+func runOptionsFor(opts workflow.Options) runOptions {
+	o := currentRunOptions()
+	o.WorktreeMode, o.Rollback, o.Provider, o.Containerized = opts.WorktreeMode, opts.Rollback, opts.Provider, opts.Containerized
+	return o
+}
+
+// apply sets the options of this invocation to o, except those whose flag changed reports as
+// given on the command line.
+func (o runOptions) apply(changed func(name string) bool) {
+	set := func(name string, apply func()) {
+		if !changed(name) {
+			apply()
+		}
+	}
+	set("cache-dir", func() { cacheDir = o.CacheDir })
+	set("no-mirror", func() { noMirror = o.NoMirror })
+	set("reference-clone", func() { referenceClone = o.ReferenceClone })
+	set("dissociate", func() { dissociateClone = o.Dissociate })
+	set("full-fetch", func() { fullFetch = o.FullFetch })
+	set("clone-filter", func() { cloneFilter = o.CloneFilter })
+	set("clone-depth", func() { cloneDepth = o.CloneDepth })
+	set("single-branch", func() { singleBranch = o.SingleBranch })
+	set("sparse-paths", func() { sparsePaths = o.SparsePaths })
+	set("worktree-mode", func() { worktreeMode = o.WorktreeMode })
+	set("worktree-root", func() { worktreeRoot = o.WorktreeRoot })
+	set("workspace-root", func() { workspaceRoot = o.WorkspaceRoot })
+	set("rollback", func() { rollbackOnFailure = o.Rollback })
+	set("base", func() { baseBranch = o.BaseBranch })
+	set("draft", func() { draftPR = o.Draft })
+	set("pr-label", func() { prLabels = o.PRLabels })
+	set("pr-reviewer", func() { prReviewers = o.PRReviewers })
+	set("pr-assignee", func() { prAssignees = o.PRAssignees })
+	set("pr-milestone", func() { prMilestone = o.PRMilestone })
+	set("pr-label-map", func() { prLabelMap = o.PRLabelMap })
+	set("no-pr-summary", func() { noPRSummary = o.NoPRSummary })
+	set("pr-summary-model", func() { prSummaryModel = o.PRSummaryModel })
+	set("commit-type", func() { commitTypeFlag = o.CommitType })
+	set("require-approval", func() { requireApproval = o.RequireApproval })
+	set("provider", func() { issueProvider = o.Provider })
+	set("pr-template", func() { prTemplatePath = o.PRTemplate })
+	set("prompt-template", func() { promptTemplatePath = o.PromptTemplate })
+	set("containerized", func() { containerized = o.Containerized })
+	set("container-image", func() { containerImage = o.ContainerImage })
+	set("memory", func() { containerMemory = o.Memory })
+	set("cpus", func() { containerCPUs = o.CPUs })
+}
+
+// write saves the inputs in dir, with credentials redacted.
+func (in *runInputs) write(dir string) error {
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, inputsFile), []byte(redact.String(string(data))+"\n"), 0o600)
+}
+
+// loadRunInputs returns the inputs recorded by the run with the given ID.
+func loadRunInputs(runID string) (*runInputs, error) {
+	dir, err := runDir(runID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, inputsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		if _, statErr := os.Stat(dir); statErr != nil {
+			return nil, fmt.Errorf("run %s not found", runID)
+		}
+		return nil, fmt.Errorf("run %s %w", runID, errNoRunInputs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var in runInputs
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse inputs of run %s: %w", runID, err)
+	}
+	return &in, nil
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	if !validRunID(runID) {
+		return withExitCode(exitConfig, fmt.Errorf("invalid run ID %q", runID))
+	}
+	in, err := loadRunInputs(runID)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+
+	in.Options.apply(cmd.Flags().Changed)
+	opts := workflowOptions("", in.Issue.Identifier, in.RepoURL)
+	opts.LocalRepo, opts.Replay, opts.Publish = in.LocalRepo, runID, replayPublish
+	return runIssue(cmd, opts)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"monday/errreport"
+	"monday/redact"
+	"monday/summary"
+)
+
+// logTailLines is the number of run log lines attached to error reports.
+const logTailLines = 50
+
+var (
+	errorReporter     *errreport.Client
+	errorReporterErr  error
+	errorReporterOnce sync.Once
+)
+
+// getErrorReporter returns the process-wide error reporting client configured by SENTRY_DSN,
+// or nil when error reporting is disabled.
+func getErrorReporter() (*errreport.Client, error) {
+	errorReporterOnce.Do(func() {
+		dsn := os.Getenv("SENTRY_DSN")
+		if dsn == "" {
+			return
+		}
+		errorReporter, errorReporterErr = errreport.New(dsn, os.Getenv("SENTRY_ENVIRONMENT"))
+	})
+	return errorReporter, errorReporterErr
+}
+
+// reportFailure sends a failed run to the configured error reporting service with the issue,
+// repository, failing stage, and the redacted tail of the run log. stack is set for panics.
+// Nothing is sent unless SENTRY_DSN is set, and canceled runs are not reported. Reporting
+// problems are logged and otherwise ignored.
+func reportFailure(log *zap.Logger, sum *summary.Summary, logPath string, runErr error, stack string) {
+	reporter, err := getErrorReporter()
+	if err != nil {
+		log.Warn("Error reporting is misconfigured", zap.Error(err))
+		return
+	}
+	if reporter == nil {
+		return
+	}
+
+	log.Sync()
+	event := errreport.Event{
+		Message: redact.Error(runErr),
+		Tags: map[string]string{
+			"run_id": sum.RunID,
+			"issue":  sum.IssueID,
+			"repo":   redact.String(sum.Repo),
+			"stage":  sum.FailedStage(),
+		},
+		Extra: map[string]any{
+			"branch":   sum.Branch,
+			"log_tail": redact.String(logTail(logPath, logTailLines)),
+		},
+		Stacktrace: redact.String(stack),
+	}
+	if stack != "" {
+		event.Level = "fatal"
+	}
+
+	id, err := reporter.Capture(context.Background(), event)
+	if err != nil {
+		log.Warn("Failed to report error", zap.Error(err))
+		return
+	}
+	log.Info("Reported failure", zap.String("event_id", id))
+}
+
+// logTail returns the last n lines of the file at path, or the empty string if it cannot be read.
+func logTail(path string, n int) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		expected string
+	}{
+		{name: "fewer lines than available", n: 2, expected: "two\nthree\n"},
+		{name: "more lines than available", n: 10, expected: "one\ntwo\nthree\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := logTail(path, tt.n); result != tt.expected {
+				t.Errorf("logTail(%d) = %q, want %q", tt.n, result, tt.expected)
+			}
+		})
+	}
+
+	if result := logTail(filepath.Join(t.TempDir(), "missing"), 5); result != "" {
+		t.Errorf("logTail of missing file = %q, want empty", result)
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/store"
+	"monday/summary"
+)
+
+// checkpointFile records, in a run's directory, the phase the run reached and what is needed
+// to pick up after it.
+const checkpointFile = "checkpoint.json"
+
+// runPhase is how far a run got. A run moves through runPhases in order, one phase at a time,
+// and records every move in its checkpoint.
+type runPhase string
+
+const (
+	phaseFetched   runPhase = "fetched"
+	phasePrepared  runPhase = "prepared"
+	phaseAgentDone runPhase = "agent_done"
+	phaseCommitted runPhase = "committed"
+	phasePushed    runPhase = "pushed"
+	phasePRCreated runPhase = "pr_created"
+)
+
+// runPhases are the phases of a run, in order.
+var runPhases = []runPhase{phaseFetched, phasePrepared, phaseAgentDone, phaseCommitted, phasePushed, phasePRCreated}
+
+// phaseStages are the stages whose completion moves a run into each phase.
+var phaseStages = map[runPhase]string{
+	phaseFetched:   "fetch_issue",
+	phasePrepared:  "prepare_workspace",
+	phaseAgentDone: "agent",
+	phaseCommitted: "commit",
+	phasePushed:    "push",
+	phasePRCreated: "pull_request",
+}
+
+// reached reports whether a run in phase p got to phase q. A run in no phase yet reached none.
+func (p runPhase) reached(q runPhase) bool {
+	return p != "" && slices.Index(runPhases, p) >= slices.Index(runPhases, q)
+}
+
+// next returns the phase after p, or "" if p is the last one.
+func (p runPhase) next() runPhase {
+	i := slices.Index(runPhases, p) + 1
+	if i >= len(runPhases) {
+		return ""
+	}
+	return runPhases[i]
+}
+
+// continueRunID is the run continued with --continue.
+var continueRunID string
+
+var resumeCmd = &cobra.Command{
+	Use:     "resume <run-id>",
+	Aliases: []string{"retry"},
+	Short:   "Resume a failed or crashed run",
+	Long: `Resume a run that failed, or whose process died, after the last phase it reached, in the
+same workspace and with the clone, worktree, and pull request options it was started with. It
+is monday --continue <run-id> with the recorded options. Run IDs are listed by monday status
+and monday history.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResume,
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&continueRunID, "continue", "", "Continue a failed run after the last phase it reached, given its run ID")
+	rootCmd.AddCommand(resumeCmd)
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	if !validRunID(runID) {
+		return withExitCode(exitConfig, fmt.Errorf("invalid run ID %q", runID))
+	}
+	if err := applyRunOptions(runID); err != nil {
+		return err
+	}
+	continueRunID = runID
+	return runMondayWorkflow(cmd, nil)
+}
+
+// applyRunOptions restores the options the run runID was started with. Runs recorded before
+// inputs.json existed resume with the default options.
+func applyRunOptions(runID string) error {
+	in, err := loadRunInputs(runID)
+	if errors.Is(err, errNoRunInputs) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	in.Options.apply(func(string) bool { return false })
+	return nil
+}
+
+// checkpoint is the persisted state of a run.
+type checkpoint struct {
+	// RunID identifies the run
+	RunID string `json:"run_id"`
+	// IssueID is the Linear issue the run works on
+	IssueID string `json:"issue_id"`
+	// RepoURL is the repository cloned by the run (clone mode only)
+	RepoURL string `json:"repo_url,omitempty"`
+	// LocalRepo is the local repository the run created a worktree of (worktree mode only)
+	LocalRepo string `json:"local_repo,omitempty"`
+	// Branch is the issue branch
+	Branch string `json:"branch"`
+	// BranchCreated is set when the run, or the run it continued, created Branch
+	BranchCreated bool `json:"branch_created,omitempty"`
+	// Workspace is the clone or worktree the run works in, once prepared
+	Workspace string `json:"workspace,omitempty"`
+	// State is the phase the run reached; empty until the issue was fetched
+	State runPhase `json:"state"`
+	// Transitions lists when the run moved into each phase
+	Transitions []phaseTransition `json:"transitions,omitempty"`
+}
+
+// phaseTransition is a move of a run into a phase.
+type phaseTransition struct {
+	Phase runPhase  `json:"phase"`
+	At    time.Time `json:"at"`
+}
+
+// advance moves the run into phase to and rewrites the checkpoint in dir. Moving into a phase
+// the run already reached, as a continued run does, changes nothing; skipping a phase is an
+// error.
+func (c *checkpoint) advance(dir string, to runPhase) error {
+	if c.State.reached(to) {
+		return nil
+	}
+	want := runPhases[0]
+	if c.State != "" {
+		want = c.State.next()
+	}
+	if to != want {
+		return fmt.Errorf("invalid run state transition from %q to %q", c.State, to)
+	}
+	c.State = to
+	c.Transitions = append(c.Transitions, phaseTransition{Phase: to, At: time.Now().UTC()})
+	return c.write(dir)
+}
+
+// write saves the checkpoint in dir.
+func (c *checkpoint) write(dir string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, checkpointFile), append(data, '\n'), 0o644)
+}
+
+// loadContinuedRun returns the checkpoint of the run with the given ID, which must have
+// stopped without succeeding according to the database of runs; alive reports whether a
+// process still exists.
+func loadContinuedRun(runID string, alive func(pid int) bool) (*checkpoint, error) {
+	run, err := lookupRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	// The checkpoint is kept next to the run's log.
+	dir := filepath.Dir(run.LogPath)
+	if run.LogPath == "" {
+		if dir, err = runDir(runID); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case run.Status == store.StatusQueued:
+		return nil, fmt.Errorf("run %s has not started yet", runID)
+	case run.Status == summary.StatusSucceeded:
+		return nil, fmt.Errorf("run %s already succeeded", runID)
+	case run.Status == summary.StatusRunning && run.PID > 0 && alive(run.PID):
+		return nil, fmt.Errorf("run %s is still running", runID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("run %s has no checkpoint to continue from", runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp struct {
+		checkpoint
+		// Completed lists the stages completed by runs of earlier monday versions
+		Completed []string `json:"completed"`
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint of run %s: %w", runID, err)
+	}
+	if cp.State == "" {
+		cp.State = legacyPhase(cp.Completed)
+	}
+	if cp.State.reached(phasePrepared) {
+		if _, err := os.Stat(cp.Workspace); err != nil {
+			return nil, fmt.Errorf("workspace %s of run %s no longer exists", cp.Workspace, runID)
+		}
+	}
+	return &cp.checkpoint, nil
+}
+
+// legacyPhase returns the phase of a run whose checkpoint lists its completed stages instead.
+// Those checkpoints were written once the issue was fetched.
+func legacyPhase(completed []string) runPhase {
+	state := phaseFetched
+	for _, phase := range runPhases {
+		if slices.Contains(completed, phaseStages[phase]) {
+			state = phase
+		}
+	}
+	return state
+}
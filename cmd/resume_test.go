@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+)
+
+func TestCheckpointAdvance(t *testing.T) {
+	dir := t.TempDir()
+	cp := &checkpoint{RunID: "run-1", IssueID: "DEL-1", Branch: "feature/del-1"}
+
+	if err := cp.advance(dir, phasePrepared); err == nil {
+		t.Error("advance() skipped the fetched phase")
+	}
+	for _, phase := range []runPhase{phaseFetched, phasePrepared, phaseAgentDone, phasePrepared} {
+		if err := cp.advance(dir, phase); err != nil {
+			t.Fatalf("advance(%s) error = %v", phase, err)
+		}
+	}
+	if err := cp.advance(dir, phasePushed); err == nil {
+		t.Error("advance() skipped the committed phase")
+	}
+
+	if cp.State != phaseAgentDone || len(cp.Transitions) != 3 {
+		t.Errorf("State = %s with %d transitions, want agent_done with 3", cp.State, len(cp.Transitions))
+	}
+	if !cp.State.reached(phasePrepared) || cp.State.reached(phaseCommitted) {
+		t.Errorf("reached() = %v/%v, want prepared reached and committed not", cp.State.reached(phasePrepared), cp.State.reached(phaseCommitted))
+	}
+	if runPhase("").reached(phaseFetched) {
+		t.Error("a run in no phase reached fetched")
+	}
+	if _, err := os.Stat(filepath.Join(dir, checkpointFile)); err != nil {
+		t.Errorf("checkpoint not written: %v", err)
+	}
+}
+
+func TestLegacyPhase(t *testing.T) {
+	tests := []struct {
+		completed []string
+		want      runPhase
+	}{
+		{nil, phaseFetched},
+		{[]string{"prepare_workspace"}, phasePrepared},
+		{[]string{"prepare_workspace", "agent", "commit", "push"}, phasePushed},
+	}
+	for _, tt := range tests {
+		if got := legacyPhase(tt.completed); got != tt.want {
+			t.Errorf("legacyPhase(%v) = %s, want %s", tt.completed, got, tt.want)
+		}
+	}
+}
+
+func TestLoadContinuedRun(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	workspace := t.TempDir()
+
+	writeRun := func(runID, status string, pid int, cp *checkpoint) {
+		t.Helper()
+		dir := filepath.Join(home, "runs", runID)
+		s := summary.New(runID, "DEL-1", "repo")
+		s.PID = pid
+		if status != summary.StatusRunning {
+			var err error
+			if status == summary.StatusFailed {
+				err = errors.New("failed to create pull request")
+			}
+			s.Finish(err)
+		}
+		if _, err := s.WriteFiles(dir); err != nil {
+			t.Fatal(err)
+		}
+		// Runs of monday versions before the database of runs have only their summary.json.
+		if runID != "old" {
+			recordRun(zap.NewNop(), s, filepath.Join(dir, "run.log"))
+		}
+		if cp != nil {
+			if err := cp.write(dir); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeRun("failed", summary.StatusFailed, 0, &checkpoint{RunID: "failed", IssueID: "DEL-1", Workspace: workspace, State: phaseAgentDone})
+	writeRun("gone", summary.StatusFailed, 0, &checkpoint{RunID: "gone", Workspace: filepath.Join(home, "missing"), State: phasePrepared})
+	writeRun("interrupted", summary.StatusRunning, 100, &checkpoint{RunID: "interrupted", State: phaseFetched})
+	writeRun("live", summary.StatusRunning, 200, &checkpoint{RunID: "live"})
+	writeRun("done", summary.StatusSucceeded, 0, &checkpoint{RunID: "done"})
+	writeRun("old", summary.StatusFailed, 0, nil)
+	recordQueuedRun(zap.NewNop(), "queued", "DEL-2", "repo")
+
+	alive := func(pid int) bool { return pid == 200 }
+	tests := []struct {
+		runID   string
+		wantErr string
+	}{
+		{runID: "failed"},
+		{runID: "interrupted"},
+		{runID: "gone", wantErr: "no longer exists"},
+		{runID: "live", wantErr: "still running"},
+		{runID: "done", wantErr: "already succeeded"},
+		{runID: "old", wantErr: "no checkpoint"},
+		{runID: "queued", wantErr: "not started yet"},
+		{runID: "unknown", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.runID, func(t *testing.T) {
+			cp, err := loadContinuedRun(tt.runID, alive)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadContinuedRun() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadContinuedRun() error = %v", err)
+			}
+			if cp.RunID != tt.runID {
+				t.Errorf("checkpoint run ID = %q, want %q", cp.RunID, tt.runID)
+			}
+		})
+	}
+}
+
+func TestResumeRestoresRecordedOptions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	origContinue, origBase, origDraft := continueRunID, baseBranch, draftPR
+	t.Cleanup(func() { continueRunID, baseBranch, draftPR = origContinue, origBase, origDraft })
+
+	dir := filepath.Join(home, "runs", "done")
+	s := summary.New("done", "DEL-1", "repo")
+	s.Finish(nil)
+	if _, err := s.WriteFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	in := &runInputs{RunID: "done", Options: runOptions{BaseBranch: "release-2.4", Draft: true}}
+	if err := in.write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runResume(resumeCmd, []string{"done"})
+	if err == nil || !strings.Contains(err.Error(), "already succeeded") || exitCodeFor(err) != exitConfig {
+		t.Errorf("runResume() error = %v, want a config error for a succeeded run", err)
+	}
+	if continueRunID != "done" || baseBranch != "release-2.4" || !draftPR {
+		t.Errorf("runResume() continued %q with base %q, draft %v", continueRunID, baseBranch, draftPR)
+	}
+	if err := runResume(resumeCmd, []string{"../etc"}); exitCodeFor(err) != exitConfig {
+		t.Errorf("runResume() of an invalid run ID = %v", err)
+	}
+}
+
+func TestApplyRunOptions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	for _, runID := range []string{"old", "broken"} {
+		if err := os.MkdirAll(filepath.Join(home, "runs", runID), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(home, "runs", "broken", inputsFile), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyRunOptions("old"); err != nil {
+		t.Errorf("applyRunOptions() of a run without inputs = %v, want the default options", err)
+	}
+	if err := applyRunOptions("broken"); err == nil {
+		t.Error("applyRunOptions() of unreadable inputs = nil, want an error")
+	}
+	if err := applyRunOptions("unknown"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("applyRunOptions() of an unknown run = %v, want not found", err)
+	}
+}
+
+func TestRetryIsResume(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"retry", "20250615-180409-del-163-9f2c"})
+	if err != nil {
+		t.Fatalf("Find(retry) error = %v", err)
+	}
+	if cmd != resumeCmd {
+		t.Errorf("Find(retry) = %s, want the resume command", cmd.Name())
+	}
+}
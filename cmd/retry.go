@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/linear"
+	"monday/retry"
+)
+
+var (
+	linearLimiterOnce sync.Once
+	linearLimiter     *linear.RateLimiter
+	linearLimiterErr  error
+)
+
+// retryPolicy returns the policy transient failures of Linear API calls, git pushes, and gh
+// are retried with: retry.DefaultPolicy, changed by MONDAY_RETRY_MAX_ATTEMPTS,
+// MONDAY_RETRY_BACKOFF, and MONDAY_RETRY_ON, a comma-separated list of HTTP statuses.
+func retryPolicy() (retry.Policy, error) {
+	policy := retry.DefaultPolicy
+	if value := os.Getenv("MONDAY_RETRY_MAX_ATTEMPTS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return policy, fmt.Errorf("invalid MONDAY_RETRY_MAX_ATTEMPTS %q: must be a positive integer", value)
+		}
+		policy.MaxAttempts = n
+	}
+	if value := os.Getenv("MONDAY_RETRY_BACKOFF"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return policy, fmt.Errorf("invalid MONDAY_RETRY_BACKOFF %q: must be a duration such as 2s", value)
+		}
+		policy.InitialBackoff = d
+	}
+	if value := os.Getenv("MONDAY_RETRY_ON"); value != "" {
+		policy.RetryOn = nil
+		for _, field := range strings.Split(value, ",") {
+			status, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || status < 100 || status > 599 {
+				return policy, fmt.Errorf("invalid MONDAY_RETRY_ON %q: must be a comma-separated list of HTTP statuses", value)
+			}
+			policy.RetryOn = append(policy.RetryOn, status)
+		}
+	}
+	return policy, nil
+}
+
+// linearRateLimiter returns the limiter throttling the Linear API calls of this process. It
+// shares the rate limits Linear reports with other monday processes, such as the runs of a
+// batch, through linear-ratelimit.json in the state directory, and waits at most
+// MONDAY_LINEAR_MAX_WAIT (default 2m) for them to reset.
+func linearRateLimiter() (*linear.RateLimiter, error) {
+	linearLimiterOnce.Do(func() {
+		var stateFile string
+		if dir, err := stateDir(); err == nil {
+			stateFile = filepath.Join(dir, "linear-ratelimit.json")
+		}
+		limiter := linear.NewRateLimiter(stateFile)
+		if value := os.Getenv("MONDAY_LINEAR_MAX_WAIT"); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				linearLimiterErr = fmt.Errorf("invalid MONDAY_LINEAR_MAX_WAIT %q: must be a positive duration such as 5m", value)
+				return
+			}
+			limiter.MaxWait = d
+		}
+		linearLimiter = limiter
+	})
+	return linearLimiter, linearLimiterErr
+}
+
+// logRetry returns the function that logs the retries of the operation called what.
+func logRetry(log *zap.Logger, what string) func(err error, wait time.Duration) {
+	return func(err error, wait time.Duration) {
+		log.Warn("Retrying after a failure", zap.String("operation", what), zap.Duration("wait", wait), zap.Error(err))
+	}
+}
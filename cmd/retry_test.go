@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"monday/retry"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts string
+		backoff     string
+		retryOn     string
+		want        retry.Policy
+		wantError   bool
+	}{
+		{name: "default", want: retry.DefaultPolicy},
+		{
+			name:        "environment",
+			maxAttempts: "6",
+			backoff:     "2s",
+			retryOn:     "500, 502",
+			want:        retry.Policy{MaxAttempts: 6, InitialBackoff: 2 * time.Second, MaxBackoff: retry.DefaultPolicy.MaxBackoff, RetryOn: []int{500, 502}},
+		},
+		{name: "invalid attempts", maxAttempts: "0", wantError: true},
+		{name: "invalid backoff", backoff: "soon", wantError: true},
+		{name: "invalid status", retryOn: "502,bad gateway", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MONDAY_RETRY_MAX_ATTEMPTS", tt.maxAttempts)
+			t.Setenv("MONDAY_RETRY_BACKOFF", tt.backoff)
+			t.Setenv("MONDAY_RETRY_ON", tt.retryOn)
+
+			got, err := retryPolicy()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("retryPolicy() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if got.MaxAttempts != tt.want.MaxAttempts || got.InitialBackoff != tt.want.InitialBackoff ||
+				got.MaxBackoff != tt.want.MaxBackoff || !slices.Equal(got.RetryOn, tt.want.RetryOn) {
+				t.Errorf("retryPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
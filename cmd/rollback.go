@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"monday/gitops"
+)
+
+// rollback records the artifacts a workflow run created so they can be removed again
+// when the run fails and --rollback is set.
+type rollback struct {
+	// log receives progress and failures of the rollback
+	log *zap.Logger
+	// repoPath is the main repository that owns the worktree (worktree mode only)
+	repoPath string
+	// worktree is the worktree created by this run (worktree mode only)
+	worktree string
+	// cloneDir is the fresh clone created by this run (clone mode only)
+	cloneDir string
+	// branch is the issue branch the run works on
+	branch string
+	// branchCreated is set when the run created branch rather than reusing an existing one
+	branchCreated bool
+	// pushed is set once branch has been pushed to origin
+	pushed bool
+}
+
+// repo returns the repository whose origin the run pushes to: the main repository in worktree
+// mode and the clone otherwise.
+func (r *rollback) repo() string {
+	if r.repoPath != "" {
+		return r.repoPath
+	}
+	return r.cloneDir
+}
+
+// adopt makes the rollback cover the workspace and branch of the run continued from cp, which
+// this run works in without preparing them itself.
+func (r *rollback) adopt(cp *checkpoint) {
+	if cp.LocalRepo == "" {
+		r.cloneDir = cp.Workspace
+		return
+	}
+	r.repoPath, _ = filepath.Abs(cp.LocalRepo)
+	r.worktree = cp.Workspace
+	r.branchCreated = cp.BranchCreated
+}
+
+// run removes everything recorded in r, best effort. Each step is logged and a failure in
+// one step does not prevent the others.
+func (r *rollback) run(ctx context.Context) {
+	fmt.Printf("↩️  Rolling back partially created artifacts...\n")
+	r.log.Info("Rolling back failed run",
+		zap.String("branch", r.branch),
+		zap.String("worktree", r.worktree),
+		zap.String("clone_dir", r.cloneDir),
+		zap.Bool("pushed", r.pushed))
+
+	repo := r.repo()
+	if r.pushed && repo != "" {
+		hasPR, err := branchHasPullRequest(ctx, repo, r.branch)
+		switch {
+		case err != nil:
+			r.log.Warn("Rollback: keeping remote branch with unknown PR status", zap.String("branch", r.branch), zap.Error(err))
+		case hasPR:
+			r.log.Info("Rollback: keeping remote branch referenced by a pull request", zap.String("branch", r.branch))
+		default:
+			if err := gitops.DeleteRemoteBranch(ctx, repo, r.branch); err != nil {
+				r.log.Warn("Rollback: failed to delete remote branch", zap.Error(err))
+			} else {
+				fmt.Printf("   deleted remote branch %s\n", r.branch)
+			}
+		}
+	}
+
+	if r.worktree != "" {
+		if err := gitops.RemoveWorktree(ctx, r.worktree); err != nil {
+			r.log.Warn("Rollback: failed to remove worktree", zap.Error(err))
+		} else {
+			fmt.Printf("   removed worktree %s\n", r.worktree)
+		}
+	}
+	if r.branchCreated && r.repoPath != "" {
+		if err := gitops.DeleteBranch(ctx, r.repoPath, r.branch); err != nil {
+			r.log.Warn("Rollback: failed to delete local branch", zap.Error(err))
+		} else {
+			fmt.Printf("   deleted local branch %s\n", r.branch)
+		}
+	}
+
+	if r.cloneDir != "" {
+		if err := os.RemoveAll(r.cloneDir); err != nil {
+			r.log.Warn("Rollback: failed to remove clone", zap.Error(err))
+		} else {
+			fmt.Printf("   removed clone %s\n", r.cloneDir)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/gitops"
+)
+
+func TestRollback_RemovesWorktreeAndBranch(t *testing.T) {
+	ctx := context.Background()
+
+	origin := t.TempDir()
+	git(t, origin, "init", "-q", "-b", "main")
+	git(t, origin, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+	repo := filepath.Join(t.TempDir(), "repo")
+	git(t, origin, "clone", "-q", ".", repo)
+
+	root := t.TempDir()
+	worktree, err := gitops.CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/del-1", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktreeForIssue: %v", err)
+	}
+
+	rb := &rollback{
+		log:           zap.NewNop(),
+		repoPath:      repo,
+		worktree:      worktree,
+		branch:        "feature/del-1",
+		branchCreated: true,
+	}
+	rb.run(ctx)
+
+	if _, err := os.Stat(worktree); !os.IsNotExist(err) {
+		t.Errorf("worktree %s still exists", worktree)
+	}
+	if gitops.BranchExists(ctx, repo, "feature/del-1") {
+		t.Errorf("branch feature/del-1 still exists")
+	}
+}
+
+func TestRollback_RemovesClone(t *testing.T) {
+
+	cloneDir := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(cloneDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	rb := &rollback{log: zap.NewNop(), cloneDir: cloneDir, branch: "feature/del-1"}
+	rb.run(context.Background())
+
+	if _, err := os.Stat(cloneDir); !os.IsNotExist(err) {
+		t.Errorf("clone %s still exists", cloneDir)
+	}
+}
+
+func TestRollbackAdopt(t *testing.T) {
+	rb := &rollback{}
+	rb.adopt(&checkpoint{LocalRepo: "repo", Workspace: "/wt/repo/DEL-1", BranchCreated: true})
+	if !filepath.IsAbs(rb.repoPath) || rb.worktree != "/wt/repo/DEL-1" || !rb.branchCreated || rb.cloneDir != "" {
+		t.Errorf("adopt() of a worktree run = %+v", rb)
+	}
+
+	rb = &rollback{}
+	rb.adopt(&checkpoint{RepoURL: "https://github.com/o/r", Workspace: "/tmp/r"})
+	if rb.cloneDir != "/tmp/r" || rb.worktree != "" {
+		t.Errorf("adopt() of a clone run = %+v", rb)
+	}
+}
+
+// git runs git in dir and fails the test on error.
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
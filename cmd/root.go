@@ -1,66 +1,150 @@
 package cmd
 
 import (
+        "context"
         "fmt"
         "os"
 
         "github.com/spf13/cobra"
         "go.uber.org/zap"
+        "go.uber.org/zap/zapcore"
+
+        "monday/redact"
 )
 
 var (
-        logger   *zap.Logger
-        repoURL  string
-        verbose  bool
+        repoURL           string
+        repoURLs          []string
+        cacheDir          string
+        noMirror          bool
+        worktreeRoot      string
+        localRepo         string
+        worktreeMode      bool
+        rollbackOnFailure bool
+        referenceClone    bool
+        dissociateClone   bool
+        fullFetch         bool
+        cloneFilter       string
+        noDesktopNotify   bool
+        baseBranch        string
+        draftPR           bool
 )
 
 var rootCmd = &cobra.Command{
-        Use:   "monday <linear_issue_id>",
+        Use:   "monday <linear_issue_id>...",
         Short: "DevFlow Orchestrator - Automate Linear issue development workflow",
         Long: `Monday CLI automates the development workflow by:
-1. Fetching Linear issue details
+1. Fetching issue details from Linear or Jira
 2. Cloning GitHub repository and creating feature branch
 3. Running Codex CLI for automated development
-4. Committing changes and creating pull request`,
-        Args: cobra.ExactArgs(1),
-        PersistentPreRun: func(cmd *cobra.Command, args []string) {
-                initLogger()
+4. Committing changes and creating pull request
+
+Several issues, given as arguments or selected with --team, --project, and --label,
+are worked on in parallel, --concurrency at a time, each in its own workspace.`,
+        Args: validateIssueArgs,
+        PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+                if err := loadConfigEnv(); err != nil {
+                        return err
+                }
+                registerSecrets()
+                return setupOutput()
         },
         RunE: runMondayWorkflow,
 }
 
-// Execute runs the root CLI command and handles any execution errors by logging or printing them, then exits with a non-zero status on failure.
+// Execute runs the root CLI command and handles any execution errors by logging or printing them, then exits with the exit code of the failure.
+// Everything printed here, including cobra's own error output, has credentials redacted.
+// With --output json, the error is also printed as a JSON object on stdout.
 func Execute() {
-        if err := rootCmd.Execute(); err != nil {
-                if logger != nil {
-                        logger.Error("Command execution failed", zap.Error(err))
-                } else {
-                        fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+        stderr := redact.NewWriter(os.Stderr)
+        rootCmd.SetErr(stderr)
+        markUsageErrors(rootCmd)
+        ctx, stop := interruptContext(context.Background())
+        err := rootCmd.ExecuteContext(ctx)
+        stop()
+        stderr.Flush()
+        if err != nil {
+                newLogger().Error("Command execution failed", zap.Error(err))
+                if jsonOutput() {
+                        writeJSON(map[string]any{"error": redact.Error(err), "exit_code": executeExitCode(err)})
                 }
-                os.Exit(1)
+                os.Exit(executeExitCode(err))
         }
 }
 
-// init configures persistent and required flags for the CLI, including verbose logging and the GitHub repository URL.
+// init configures persistent and required flags for the CLI, including the mirror cache and the GitHub repository URL.
 func init() {
-        rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
-        rootCmd.Flags().StringVar(&repoURL, "repo-url", "", "GitHub repository URL (required)")
-        rootCmd.MarkFlagRequired("repo-url")
+        rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "Directory for bare mirror clones (default: user cache dir/monday/mirrors)")
+        rootCmd.PersistentFlags().BoolVar(&noMirror, "no-mirror", false, "Clone directly from the remote instead of through the mirror cache")
+        rootCmd.PersistentFlags().BoolVar(&referenceClone, "reference-clone", false, "Clone from the remote with --reference to the mirror cache instead of cloning the mirror")
+        rootCmd.PersistentFlags().BoolVar(&dissociateClone, "dissociate", false, "With --reference-clone, copy borrowed objects so the clone does not depend on the mirror cache")
+        rootCmd.PersistentFlags().BoolVar(&fullFetch, "full-fetch", false, "Fetch all refs when cloning instead of only the default branch and the issue branch")
+        rootCmd.PersistentFlags().StringVar(&cloneFilter, "clone-filter", "", "Partial clone filter for clones from the remote, e.g. blob:none")
+        rootCmd.PersistentFlags().BoolVar(&worktreeMode, "worktree-mode", false, "Work in a per-issue worktree of a bare clone cached per repository instead of a fresh clone")
+        rootCmd.PersistentFlags().BoolVar(&rollbackOnFailure, "rollback", false, "On failure, remove the worktree or clone and delete branches the run created")
+        rootCmd.Flags().StringSliceVar(&repoURLs, "repo-url", nil, "GitHub repository URL (required unless --local-repo is set); repeat it or separate URLs with commas to work on an issue in several repositories")
+        rootCmd.Flags().StringVar(&localRepo, "local-repo", "", "Path to an existing local clone; work happens in a per-issue worktree instead of a fresh clone")
+        rootCmd.Flags().StringVar(&baseBranch, "base", "", "Branch to start the issue branch from and open the pull request against, also --base-branch (default: $MONDAY_BASE_BRANCH or the repository's default branch)")
+        rootCmd.Flags().BoolVar(&draftPR, "draft", false, "Open the pull request as a draft, also --pr-draft")
+        rootCmd.Flags().BoolVar(&noDesktopNotify, "no-desktop-notify", false, "Do not raise a macOS desktop notification when a long run finishes")
 }
 
-// initLogger initializes the global logger with either development or production settings based on the verbose flag.
+// registerSecrets registers the API keys and credentials monday reads from the environment for redaction,
+// so they are masked in every log entry, terminal line, and error message. It is safe to call repeatedly.
+func registerSecrets() {
+        redact.AddSecrets(
+                os.Getenv("LINEAR_API_KEY"),
+                os.Getenv("LINEAR_ACCESS_TOKEN"),
+                os.Getenv("LINEAR_CLIENT_SECRET"),
+                os.Getenv("GITHUB_TOKEN"),
+                os.Getenv("GITHUB_APP_PRIVATE_KEY"),
+                os.Getenv("GITLAB_TOKEN"),
+                os.Getenv("JIRA_API_TOKEN"),
+                os.Getenv("OPENAI_API_KEY"),
+                os.Getenv("ANTHROPIC_API_KEY"),
+                os.Getenv("SERVER_API_KEY"),
+                os.Getenv("LINEAR_WEBHOOK_SECRET"),
+                os.Getenv("SENTRY_DSN"),
+                os.Getenv("AWS_SECRET_ACCESS_KEY"),
+                os.Getenv("AWS_SESSION_TOKEN"),
+                os.Getenv("GCS_HMAC_SECRET"),
+                os.Getenv("VAULT_TOKEN"),
+                os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+                os.Getenv("SLACK_BOT_TOKEN"),
+                os.Getenv("SLACK_WEBHOOK_URL"),
+                os.Getenv("DISCORD_WEBHOOK_URL"),
+                os.Getenv("TEAMS_WEBHOOK_URL"),
+        )
+}
+
+// newLogger returns a logger at the level selected with -q or -v: production settings by default
+// and with -q, human-readable development settings with -v and -vv.
+// Every entry has registered secrets masked.
 // Exits the program if logger initialization fails.
-func initLogger() {
+func newLogger() *zap.Logger {
+        registerSecrets()
+
+        var logger *zap.Logger
         var err error
-        if verbose {
-                logger, err = zap.NewDevelopment()
+        if outputLevel() >= levelVerbose {
+                config := zap.NewDevelopmentConfig()
+                config.Level = zap.NewAtomicLevelAt(logLevel())
+                logger, err = config.Build()
         } else {
                 config := zap.NewProductionConfig()
-                config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-                logger, err = config.Build()
+                config.Level = zap.NewAtomicLevelAt(logLevel())
+                var opts []zap.Option
+                if spinner != nil {
+                        // Log entries are printed above the stage spinner instead of through it.
+                        opts = append(opts, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+                                return zapcore.NewCore(zapcore.NewJSONEncoder(config.EncoderConfig), zapcore.AddSync(spinner), config.Level)
+                        }))
+                }
+                logger, err = config.Build(opts...)
         }
         if err != nil {
                 fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
                 os.Exit(1)
         }
+        return logger.WithOptions(zap.WrapCore(redact.Core))
 }
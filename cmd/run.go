@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/notify"
+	"monday/summary"
+	"monday/workflow"
+)
+
+func init() {
+	workflow.RegisterObserver(runObserver{})
+}
+
+// workflowOptions returns the options of a run of the issue issueID in repoURL with the flags of
+// this invocation. An empty runID gets a new one.
+func workflowOptions(runID, issueID, repoURL string) workflow.Options {
+	return workflow.Options{
+		RunID:         runID,
+		IssueID:       issueID,
+		RepoURL:       repoURL,
+		LocalRepo:     localRepo,
+		Provider:      issueProvider,
+		DryRun:        dryRun,
+		Rollback:      rollbackOnFailure,
+		Containerized: containerized,
+		WorktreeMode:  worktreeMode,
+	}
+}
+
+// runObserver gives the runs of the workflow package their scope, and checks their
+// configuration before their first stage, after which the run's timeouts apply. Runs are
+// canceled by canceling their context. The run log receives the entries of Options.Log, each
+// carrying run_id, issue_id, repo, and, within a stage, stage fields.
+type runObserver struct{}
+
+func (runObserver) Start(ctx context.Context, run *workflow.Run) (context.Context, error) {
+	registerSecrets()
+	input := run.IssueID
+	run.IssueID = extractIssueID(input)
+	if run.ID == "" {
+		run.ID = newRunID(run.IssueID)
+	}
+	sum := summary.New(run.ID, run.IssueID, run.Repo)
+	sum.DryRun = run.Options.DryRun
+	ctx, scope, err := startRun(ctx, run.Log, sum, zap.String("issue_id", run.IssueID), zap.String("repo", run.Repo))
+	if err != nil {
+		return ctx, err
+	}
+	st := &issueRun{scope: scope}
+	run.Set(issueRunKey{}, st)
+	run.Summary, run.Dir, run.Log = sum, scope.dir, scope.log
+
+	fmt.Printf("🚀 Starting Monday workflow for %s (run %s)\n", input, run.ID)
+	fmt.Printf("📄 Run log: %s\n", scope.logPath)
+	scope.log.Info("Starting Monday workflow", zap.String("input", input))
+	if err := st.configure(ctx, run.Options); err != nil {
+		return ctx, err
+	}
+	return scope.limit()
+}
+
+// End ends the run's scope; see runScope.end.
+func (runObserver) End(run *workflow.Run, err error) error {
+	if st := issueRunOf(run); st != nil {
+		st.scope.end(&err)
+	}
+	return err
+}
+
+// runScope is what every run, of an issue or a follow-up, has around its stages: a log, a
+// summary written next to it, cancellation with monday cancel, step and total timeouts, and,
+// when it ends, panic recovery, failure reports, artifact uploads, and notifications.
+type runScope struct {
+	log     *zap.Logger
+	logPath string
+	// dir is the run's directory, holding its log and summary
+	dir string
+	sum *summary.Summary
+
+	ctx          context.Context
+	cancel       context.CancelFunc
+	stopTimeouts func()
+	closeLog     func()
+}
+
+// startRun opens the log of the run of sum, with fields on every entry, and records the run as
+// running, in its summary and the database of runs, so it shows up while in progress. The returned context is canceled
+// when the run's cancellation is requested. The run must be ended with end.
+func startRun(ctx context.Context, base *zap.Logger, sum *summary.Summary, fields ...zap.Field) (context.Context, *runScope, error) {
+	if base == nil {
+		base = zap.NewNop()
+	}
+	log, logPath, closeLog, err := openRunLogger(base, sum.RunID)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("failed to set up run log: %w", err)
+	}
+	r := &runScope{log: log.With(fields...), logPath: logPath, dir: filepath.Dir(logPath), sum: sum, closeLog: closeLog}
+	sum.PID = os.Getpid()
+	if _, err := sum.WriteFiles(r.dir); err != nil {
+		r.log.Warn("Failed to write run summary", zap.Error(err))
+	}
+	recordRun(r.log, sum, logPath)
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	go watchCancelRequest(r.ctx, r.dir, r.cancel, time.Second)
+	return r.ctx, r, nil
+}
+
+// limit applies the step and total timeouts of runTimeouts to the run, whose stages then run
+// with the returned context.
+func (r *runScope) limit() (context.Context, error) {
+	step, total, err := runTimeouts()
+	if err != nil {
+		return r.ctx, withExitCode(exitConfig, err)
+	}
+	r.ctx, r.stopTimeouts = withTimeouts(r.ctx, step, total)
+	return r.ctx, nil
+}
+
+// end finishes the run with the error *errp, which is replaced by the error of a panic, by
+// errRunCanceled if the run was canceled, and by the timeout if it timed out. Failures are
+// reported, and the summary is written, recorded, uploaded, and notified. It must be deferred,
+// or be called with the run's error once the run ended.
+func (r *runScope) end(errp *error) {
+	if p := recover(); p != nil {
+		*errp = &workflow.PanicError{Value: p, Stack: string(debug.Stack())}
+	}
+	var stack string
+	var panicErr *workflow.PanicError
+	if errors.As(*errp, &panicErr) {
+		stack = panicErr.Stack
+		r.log.Error("Workflow panicked", zap.Any("panic", panicErr.Value), zap.String("stack", stack))
+	}
+	// Whatever a canceled run failed on, it stopped because it was canceled or timed out. This
+	// is settled before the timeouts are stopped, as stopping them cancels the context.
+	if *errp != nil && r.ctx.Err() != nil {
+		if cause := timeoutCause(r.ctx); cause != nil {
+			*errp = withExitCode(exitTimedOut, cause)
+			r.log.Warn("Run timed out", zap.Error(cause))
+		} else {
+			*errp = errRunCanceled
+			r.log.Info("Run canceled")
+		}
+	}
+	if r.stopTimeouts != nil {
+		r.stopTimeouts()
+	}
+	r.cancel()
+	defer r.closeLog()
+
+	err := *errp
+	if err != nil && !errors.Is(err, context.Canceled) {
+		reportFailure(r.log, r.sum, r.logPath, err, stack)
+	}
+	r.sum.Finish(err)
+	summaryPath, writeErr := r.sum.WriteFiles(r.dir)
+	if writeErr != nil {
+		r.log.Warn("Failed to write run summary", zap.Error(writeErr))
+	} else {
+		fmt.Printf("🧾 Run summary: %s\n", summaryPath)
+	}
+	recordRun(r.log, r.sum, r.logPath)
+	uploadArtifacts(r.log, r.sum.RunID, r.dir)
+	if err != nil {
+		notifyRun(r.log, notify.RunFailed, r.sum)
+	} else {
+		notifyRun(r.log, notify.RunSucceeded, r.sum)
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// runFile is the batch specification given with --from-file.
+var runFile string
+
+var runCmd = &cobra.Command{
+	Use:   "run --from-file <batch.yaml>",
+	Short: "Work on the issues listed in a batch specification file",
+	Long: `Work on the issues listed in a batch specification file, each in its own monday process
+as for several issue IDs. The file can be kept in git to review and repeat a batch:
+
+  concurrency: 3
+  defaults:
+    repo_url: https://github.com/owner/repo
+    base: main
+    draft: true
+  issues:
+    - DEL-163
+    - id: DEL-164
+      base: release-2.4
+    - id: DEL-170
+      local_repo: ../other-repo
+      draft: false
+
+Each issue takes the defaults, overridden by its own settings: repo_url or local_repo, base
+(the branch to start from and open the pull request against), agent (only codex is
+available), and draft. Relative local_repo paths are relative to the file. --concurrency
+overrides the file's concurrency.`,
+	Args: cobra.NoArgs,
+	RunE: runFromFile,
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runFile, "from-file", "", "Batch specification file listing the issues to work on")
+	runCmd.Flags().IntVar(&concurrency, "concurrency", 2, "Number of issues worked on at the same time")
+	runCmd.MarkFlagRequired("from-file")
+	batchFlags["from-file"] = true
+	rootCmd.AddCommand(runCmd)
+}
+
+// batchSpec is a batch specification file.
+type batchSpec struct {
+	// Concurrency is the number of issues worked on at the same time
+	Concurrency int `yaml:"concurrency"`
+	// Defaults are the settings of issues that do not set them
+	Defaults batchIssue `yaml:"defaults"`
+	// Issues are the issues to work on, in order
+	Issues []batchIssue `yaml:"issues"`
+}
+
+// batchIssue is an issue of a batch specification with its settings.
+type batchIssue struct {
+	// ID is the Linear issue ID or URL
+	ID string `yaml:"id"`
+	// RepoURL is the repository to clone
+	RepoURL string `yaml:"repo_url"`
+	// LocalRepo is the local clone to create a worktree of
+	LocalRepo string `yaml:"local_repo"`
+	// Base is the branch to start from and open the pull request against
+	Base string `yaml:"base"`
+	// Agent is the coding agent to run
+	Agent string `yaml:"agent"`
+	// Draft opens the pull request as a draft
+	Draft *bool `yaml:"draft"`
+}
+
+// batchIssueKeys are the keys an issue of a batch specification may set.
+var batchIssueKeys = map[string]bool{"id": true, "repo_url": true, "local_repo": true, "base": true, "agent": true, "draft": true}
+
+// UnmarshalYAML decodes an issue given either as its ID alone or as a mapping of settings.
+func (b *batchIssue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		b.ID = node.Value
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; !batchIssueKeys[key.Value] {
+				return fmt.Errorf("line %d: unknown issue setting %q", key.Line, key.Value)
+			}
+		}
+	}
+	type plain batchIssue
+	return node.Decode((*plain)(b))
+}
+
+// loadBatchSpec reads the batch specification file at path. Relative local_repo paths are
+// resolved against the directory of the file.
+func loadBatchSpec(path string) (*batchSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+	var spec batchSpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse batch file %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(issue *batchIssue) {
+		if issue.LocalRepo != "" && !filepath.IsAbs(issue.LocalRepo) {
+			issue.LocalRepo = filepath.Join(dir, issue.LocalRepo)
+		}
+	}
+	resolve(&spec.Defaults)
+	for i := range spec.Issues {
+		resolve(&spec.Issues[i])
+	}
+	return &spec, nil
+}
+
+// jobs returns the runs of the issues of the specification, each with flags followed by the
+// flags of its settings.
+func (s *batchSpec) jobs(flags []string) ([]batchJob, error) {
+	if len(s.Issues) == 0 {
+		return nil, fmt.Errorf("batch file lists no issues")
+	}
+	seen := map[string]bool{}
+	jobs := make([]batchJob, 0, len(s.Issues))
+	for i, issue := range s.Issues {
+		issue = s.Defaults.merge(issue)
+		id := strings.ToUpper(extractIssueID(issue.ID))
+		if id == "" {
+			return nil, fmt.Errorf("issue %d: missing issue ID", i+1)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("issue %s is listed more than once", id)
+		}
+		seen[id] = true
+		issueFlags, err := issue.flags()
+		if err != nil {
+			return nil, fmt.Errorf("issue %s: %w", id, err)
+		}
+		jobs = append(jobs, batchJob{IssueID: issue.ID, Flags: append(append([]string{}, flags...), issueFlags...)})
+	}
+	return jobs, nil
+}
+
+// merge returns issue with the settings it leaves unset taken from the defaults b. An issue
+// that sets either repository setting replaces both.
+func (b batchIssue) merge(issue batchIssue) batchIssue {
+	if issue.RepoURL == "" && issue.LocalRepo == "" {
+		issue.RepoURL, issue.LocalRepo = b.RepoURL, b.LocalRepo
+	}
+	if issue.Base == "" {
+		issue.Base = b.Base
+	}
+	if issue.Agent == "" {
+		issue.Agent = b.Agent
+	}
+	if issue.Draft == nil {
+		issue.Draft = b.Draft
+	}
+	return issue
+}
+
+// flags returns the command-line flags of the run of the issue.
+func (b batchIssue) flags() ([]string, error) {
+	var flags []string
+	switch {
+	case b.RepoURL != "" && b.LocalRepo != "":
+		return nil, fmt.Errorf("repo_url and local_repo cannot both be set")
+	case b.RepoURL != "":
+		flags = append(flags, "--repo-url="+b.RepoURL)
+	case b.LocalRepo != "":
+		flags = append(flags, "--local-repo="+b.LocalRepo)
+	default:
+		return nil, fmt.Errorf("one of repo_url or local_repo is required")
+	}
+	if b.Agent != "" && b.Agent != "codex" {
+		return nil, fmt.Errorf("unsupported agent %q: only codex is available", b.Agent)
+	}
+	if b.Base != "" {
+		flags = append(flags, "--base="+b.Base)
+	}
+	if b.Draft != nil {
+		flags = append(flags, "--draft="+strconv.FormatBool(*b.Draft))
+	}
+	return flags, nil
+}
+
+func runFromFile(cmd *cobra.Command, args []string) error {
+	spec, err := loadBatchSpec(runFile)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	jobs, err := spec.jobs(forwardedFlags(cmd.Flags()))
+	if err != nil {
+		return withExitCode(exitConfig, fmt.Errorf("%s: %w", runFile, err))
+	}
+	if !cmd.Flags().Changed("concurrency") && spec.Concurrency > 0 {
+		concurrency = spec.Concurrency
+	}
+	return reportBatch(cmd.Context(), jobs, concurrency)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeBatchFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "batch.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBatchSpecJobs(t *testing.T) {
+	path := writeBatchFile(t, `
+concurrency: 3
+defaults:
+  repo_url: https://github.com/owner/repo
+  base: main
+  agent: codex
+  draft: true
+issues:
+  - DEL-163
+  - id: DEL-164
+    base: release-2.4
+    draft: false
+  - id: https://linear.app/team/issue/DEL-170/fix-login
+    local_repo: ../other
+`)
+	spec, err := loadBatchSpec(path)
+	if err != nil {
+		t.Fatalf("loadBatchSpec() error = %v", err)
+	}
+	if spec.Concurrency != 3 {
+		t.Errorf("Concurrency = %d, want 3", spec.Concurrency)
+	}
+
+	jobs, err := spec.jobs([]string{"-v=1"})
+	if err != nil {
+		t.Fatalf("jobs() error = %v", err)
+	}
+	want := []batchJob{
+		{IssueID: "DEL-163", Flags: []string{"-v=1", "--repo-url=https://github.com/owner/repo", "--base=main", "--draft=true"}},
+		{IssueID: "DEL-164", Flags: []string{"-v=1", "--repo-url=https://github.com/owner/repo", "--base=release-2.4", "--draft=false"}},
+		{IssueID: "https://linear.app/team/issue/DEL-170/fix-login", Flags: []string{"-v=1", "--local-repo=" + filepath.Join(filepath.Dir(filepath.Dir(path)), "other"), "--base=main", "--draft=true"}},
+	}
+	if !reflect.DeepEqual(jobs, want) {
+		t.Errorf("jobs() =\n%v\nwant\n%v", jobs, want)
+	}
+}
+
+func TestBatchSpecErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no issues", "concurrency: 2\n", "lists no issues"},
+		{"unknown top-level key", "issue:\n  - DEL-1\n", "field issue not found"},
+		{"unknown issue key", "issues:\n  - id: DEL-1\n    repo: x\n", `unknown issue setting "repo"`},
+		{"missing repository", "issues:\n  - DEL-1\n", "one of repo_url or local_repo is required"},
+		{"both repositories", "defaults:\n  repo_url: x\nissues:\n  - id: DEL-1\n    repo_url: y\n    local_repo: z\n", "cannot both be set"},
+		{"unsupported agent", "defaults:\n  repo_url: x\n  agent: claude\nissues:\n  - DEL-1\n", `unsupported agent "claude"`},
+		{"duplicate issue", "defaults:\n  repo_url: x\nissues:\n  - DEL-1\n  - del-1\n", "more than once"},
+		{"missing ID", "defaults:\n  repo_url: x\nissues:\n  - base: main\n", "missing issue ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := loadBatchSpec(writeBatchFile(t, tt.content))
+			if err == nil {
+				_, err = spec.jobs(nil)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
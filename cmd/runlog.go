@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"monday/redact"
+)
+
+// stateDir returns the directory monday keeps per-run state in: $MONDAY_HOME or ~/.monday.
+func stateDir() (string, error) {
+	if dir := os.Getenv("MONDAY_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".monday"), nil
+}
+
+// runsDir returns the directory holding one subdirectory per run.
+func runsDir() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "runs"), nil
+}
+
+// runDir returns the directory holding the state of the run with the given ID.
+func runDir(runID string) (string, error) {
+	dir, err := runsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, runID), nil
+}
+
+// newRunID returns a sortable, unique identifier for a run of issueID, such as
+// "20250615-180409-del-163-9f2c".
+func newRunID(issueID string) string {
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	slug := strings.ToLower(unsafeRunIDChars.Replace(issueID))
+	return fmt.Sprintf("%s-%s-%s", time.Now().Format("20060102-150405"), slug, hex.EncodeToString(suffix))
+}
+
+// unsafeRunIDChars replaces characters that must not appear in a run ID directory name.
+var unsafeRunIDChars = strings.NewReplacer("/", "_", "\\", "_", " ", "_", ":", "_")
+
+// openRunLogger returns a logger that writes to base and, as JSON at debug level, to the
+// run's log file. Every entry carries the run_id field. The returned close function flushes
+// and closes the log file.
+func openRunLogger(base *zap.Logger, runID string) (*zap.Logger, string, func(), error) {
+	dir, err := runDir(runID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "run.log")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to open run log: %w", err)
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	fileCore := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(file), zapcore.DebugLevel)
+
+	runLogger := base.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, redact.Core(fileCore))
+	})).With(zap.String("run_id", runID))
+
+	closeFn := func() {
+		runLogger.Sync()
+		file.Close()
+	}
+	return runLogger, path, closeFn, nil
+}
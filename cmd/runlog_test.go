@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewRunID(t *testing.T) {
+	id := newRunID("DEL-163")
+	if !regexp.MustCompile(`^\d{8}-\d{6}-del-163-[0-9a-f]{4}$`).MatchString(id) {
+		t.Errorf("newRunID(%q) = %q, unexpected format", "DEL-163", id)
+	}
+	if other := newRunID("DEL-163"); other == id {
+		t.Errorf("newRunID returned the same ID twice: %q", id)
+	}
+}
+
+func TestOpenRunLogger(t *testing.T) {
+	t.Setenv("MONDAY_HOME", t.TempDir())
+
+	log, path, closeLog, err := openRunLogger(zap.NewNop(), "run-1")
+	if err != nil {
+		t.Fatalf("openRunLogger returned error: %v", err)
+	}
+	log.Debug("debug entry", zap.String("issue_id", "DEL-1"))
+	log.Info("info entry")
+	closeLog()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read run log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), data)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+	if entry["run_id"] != "run-1" || entry["issue_id"] != "DEL-1" || entry["msg"] != "debug entry" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+var (
+	// maxConcurrentRuns is how many runs the server works on at once.
+	maxConcurrentRuns int
+	// runQueueSize is how many more runs the server accepts and starts once others finish.
+	runQueueSize int
+)
+
+func init() {
+	serverCmd.Flags().IntVar(&maxConcurrentRuns, "max-concurrent-runs", 4, "How many runs the server works on at once (default also from $MONDAY_MAX_CONCURRENT_RUNS)")
+	serverCmd.Flags().IntVar(&runQueueSize, "queue-size", 20, "How many more runs the server queues before answering 429 (default also from $MONDAY_QUEUE_SIZE)")
+}
+
+// errQueueFull is returned for runs submitted while the server runs and queues all it can.
+var errQueueFull = errors.New("too many runs in flight and queued")
+
+// resolveRunLimits returns the concurrency limit and queue size selected with
+// --max-concurrent-runs and --queue-size, or else with MONDAY_MAX_CONCURRENT_RUNS and
+// MONDAY_QUEUE_SIZE, or else the flags' defaults.
+func resolveRunLimits(flags *pflag.FlagSet) (maxConcurrent, queueSize int, err error) {
+	if maxConcurrent, err = intSetting(flags, "max-concurrent-runs", maxConcurrentRuns, "MONDAY_MAX_CONCURRENT_RUNS"); err != nil {
+		return 0, 0, err
+	}
+	if maxConcurrent < 1 {
+		return 0, 0, fmt.Errorf("invalid max concurrent runs %d: must be at least 1", maxConcurrent)
+	}
+	if queueSize, err = intSetting(flags, "queue-size", runQueueSize, "MONDAY_QUEUE_SIZE"); err != nil {
+		return 0, 0, err
+	}
+	if queueSize < 0 {
+		return 0, 0, fmt.Errorf("invalid queue size %d: must not be negative", queueSize)
+	}
+	return maxConcurrent, queueSize, nil
+}
+
+// intSetting returns the value of the flag called name if it was set, or else the integer in
+// env, or else the flag's default.
+func intSetting(flags *pflag.FlagSet, name string, flag int, env string) (int, error) {
+	value := os.Getenv(env)
+	if flags.Changed(name) || value == "" {
+		return flag, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be an integer", env, value)
+	}
+	return n, nil
+}
+
+// runPool runs at most a fixed number of runs at once, and queues a bounded number of further
+// runs until a slot frees up.
+type runPool struct {
+	log *zap.Logger
+	// slots holds a token per running run
+	slots chan struct{}
+	// accepted holds a token per running or queued run
+	accepted chan struct{}
+	// runs tracks the running and queued runs, so the server can drain them
+	runs *sync.WaitGroup
+}
+
+// newRunPool returns a pool running up to maxConcurrent runs at once with up to queueSize
+// more waiting, tracked in runs.
+func newRunPool(log *zap.Logger, maxConcurrent, queueSize int, runs *sync.WaitGroup) *runPool {
+	return &runPool{
+		log:      log,
+		slots:    make(chan struct{}, maxConcurrent),
+		accepted: make(chan struct{}, maxConcurrent+queueSize),
+		runs:     runs,
+	}
+}
+
+// submit runs run, the run called runID, in the background once a slot is free, and reports
+// whether all slots were taken when it was submitted. It returns errQueueFull without running it if the pool and
+// its queue are full. A queued run whose ctx is canceled before it starts is dropped.
+func (p *runPool) submit(ctx context.Context, runID string, run func()) (queued bool, err error) {
+	select {
+	case p.accepted <- struct{}{}:
+	default:
+		return false, errQueueFull
+	}
+	queued = len(p.slots) == cap(p.slots)
+
+	p.runs.Add(1)
+	go func() {
+		defer p.runs.Done()
+		defer func() { <-p.accepted }()
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			p.log.Warn("Dropped queued run", zap.String("run_id", runID), zap.Error(ctx.Err()))
+			return
+		}
+		defer func() { <-p.slots }()
+		run()
+	}()
+	return queued, nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+func TestResolveRunLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		maxEnv    string
+		queueEnv  string
+		wantMax   int
+		wantQueue int
+		wantErr   bool
+	}{
+		{name: "defaults", wantMax: 4, wantQueue: 20},
+		{name: "flags", args: []string{"--max-concurrent-runs", "2", "--queue-size", "0"}, maxEnv: "8", queueEnv: "50", wantMax: 2, wantQueue: 0},
+		{name: "environment", maxEnv: "8", queueEnv: "50", wantMax: 8, wantQueue: 50},
+		{name: "no runs at once", args: []string{"--max-concurrent-runs", "0"}, wantErr: true},
+		{name: "invalid environment", queueEnv: "many", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origMax, origQueue := maxConcurrentRuns, runQueueSize
+			t.Cleanup(func() { maxConcurrentRuns, runQueueSize = origMax, origQueue })
+			flags := pflag.NewFlagSet("server", pflag.ContinueOnError)
+			flags.IntVar(&maxConcurrentRuns, "max-concurrent-runs", 4, "")
+			flags.IntVar(&runQueueSize, "queue-size", 20, "")
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			t.Setenv("MONDAY_MAX_CONCURRENT_RUNS", tt.maxEnv)
+			t.Setenv("MONDAY_QUEUE_SIZE", tt.queueEnv)
+
+			gotMax, gotQueue, err := resolveRunLimits(flags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveRunLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotMax != tt.wantMax || gotQueue != tt.wantQueue {
+				t.Errorf("resolveRunLimits() = %d, %d, want %d, %d", gotMax, gotQueue, tt.wantMax, tt.wantQueue)
+			}
+		})
+	}
+}
+
+func TestRunPool(t *testing.T) {
+	var runs sync.WaitGroup
+	pool := newRunPool(zap.NewNop(), 1, 1, &runs)
+	release := make(chan struct{})
+	started := make(chan string, 3)
+	run := func(id string) func() {
+		return func() {
+			started <- id
+			<-release
+		}
+	}
+
+	if queued, err := pool.submit(context.Background(), "first", run("first")); err != nil || queued {
+		t.Fatalf("submit(first) = %v, %v, want false, nil", queued, err)
+	}
+	if id := <-started; id != "first" {
+		t.Fatalf("started %s, want first", id)
+	}
+	if queued, err := pool.submit(context.Background(), "second", run("second")); err != nil || !queued {
+		t.Fatalf("submit(second) = %v, %v, want true, nil", queued, err)
+	}
+	if _, err := pool.submit(context.Background(), "third", run("third")); !errors.Is(err, errQueueFull) {
+		t.Fatalf("submit(third) error = %v, want errQueueFull", err)
+	}
+
+	close(release)
+	runs.Wait()
+	if id := <-started; id != "second" {
+		t.Errorf("started %s, want second", id)
+	}
+	if len(started) != 0 {
+		t.Errorf("started %d more runs, want none", len(started))
+	}
+}
+
+func TestRunPoolDropsCanceledQueuedRuns(t *testing.T) {
+	var runs sync.WaitGroup
+	pool := newRunPool(zap.NewNop(), 1, 1, &runs)
+	release := make(chan struct{})
+	ran := make(chan string, 2)
+
+	if _, err := pool.submit(context.Background(), "first", func() {
+		ran <- "first"
+		<-release
+	}); err != nil {
+		t.Fatalf("submit(first) error = %v", err)
+	}
+	<-ran
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := pool.submit(ctx, "second", func() { ran <- "second" }); err != nil {
+		t.Fatalf("submit(second) error = %v", err)
+	}
+	cancel()
+	// The dropped run frees its place in the queue.
+	for {
+		if _, err := pool.submit(context.Background(), "third", func() { ran <- "third" }); err == nil {
+			break
+		}
+	}
+	close(release)
+	runs.Wait()
+
+	if got := <-ran; got != "third" {
+		t.Errorf("ran %s, want third", got)
+	}
+	if len(ran) != 0 {
+		t.Errorf("ran %d more runs, want none", len(ran))
+	}
+}
@@ -1,13 +1,25 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+
+	"monday/history"
+	"monday/redact"
+	"monday/summary"
+	"monday/workflow"
 )
 
 var (
@@ -19,7 +31,14 @@ var serverCmd = &cobra.Command{
 	Short: "Run HTTP server for Monday workflow",
 	Long: `Start an HTTP server that exposes endpoints to trigger the Monday workflow:
 			- GET /health - Health check endpoint
-			- POST /trigger - Trigger workflow with linear_id and github_url`,
+			- GET /metrics - Per-stage run metrics and agent cost in Prometheus format
+			- GET /export - Run history as CSV or JSON
+			- GET /status - In-flight and recent runs
+			- GET /runs, GET /runs/{id} - Run states and full run summaries
+			- GET /runs/{id}/logs - Agent output of a run
+			- POST /runs/{id}/approve, POST /runs/{id}/reject - Answer a run waiting for approval
+			- POST /trigger - Trigger workflow with linear_id and github_url
+			- POST /webhooks/linear - Start runs for issues labeled in Linear (with LINEAR_WEBHOOK_SECRET)`,
 	RunE: runServer,
 }
 
@@ -29,7 +48,7 @@ func init() {
 }
 
 func runServer(cmd *cobra.Command, args []string) error {
-	initLogger()
+	logger := newLogger()
 	
 	port := serverPort
 	if port == "" {
@@ -43,10 +62,59 @@ func runServer(cmd *cobra.Command, args []string) error {
 	if apiKey == "" {
 		return fmt.Errorf("SERVER_API_KEY environment variable is required")
 	}
+	webhook, webhookEnabled, err := loadWebhookConfig()
+	if err != nil {
+		return err
+	}
+	drain, err := resolveDrainTimeout()
+	if err != nil {
+		return err
+	}
+	maxConcurrent, queueSize, err := resolveRunLimits(cmd.Flags())
+	if err != nil {
+		return err
+	}
+	window, err := resolveDedupWindow()
+	if err != nil {
+		return err
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", healthHandler)
-	mux.HandleFunc("/trigger", makeTriggerHandler(logger, apiKey))
+	mux.HandleFunc("/metrics", makeMetricsHandler(logger, apiKey))
+	// Runs outlive the signal stopping the server, so they can finish while it drains; those
+	// still in flight when the drain timeout runs out are canceled and clean up before it exits.
+	ctx := cmd.Context()
+	runCtx, cancelRuns := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRuns()
+	var runs sync.WaitGroup
+	pool := newRunPool(logger, maxConcurrent, queueSize, &runs)
+	// Triggers and webhooks share one record of runs, so neither starts a duplicate of the other.
+	dedup := newRunDedup(window)
+	mux.HandleFunc("/trigger", makeTriggerHandler(runCtx, logger, apiKey, pool, dedup))
+	mux.HandleFunc("/export", makeExportHandler(logger, apiKey))
+	mux.HandleFunc("/status", makeStatusHandler(logger, apiKey))
+	mux.HandleFunc("/logs", makeLogsHandler(logger, apiKey))
+	mux.HandleFunc("/runs", makeRunsHandler(logger, apiKey))
+	mux.HandleFunc("/runs/", makeRunsHandler(logger, apiKey))
+	if webhookEnabled {
+		mux.HandleFunc("/webhooks/linear", makeLinearWebhookHandler(logger, webhook, dedup, func(runID, issueID, userID string, done func()) error {
+			_, err := pool.submit(runCtx, runID, func() {
+				defer done()
+				opts := workflowOptions(runID, issueID, webhook.RepoURL)
+				opts.Log = logger
+				if _, err := workflow.New(opts).Run(withAssignee(runCtx, userID)); err != nil {
+					logger.Error("Workflow failed", zap.Error(err), zap.String("linear_id", issueID))
+				} else {
+					logger.Info("Workflow completed successfully", zap.String("linear_id", issueID))
+				}
+			})
+			if err == nil {
+				recordQueuedRun(logger, runID, issueID, webhook.RepoURL)
+			}
+			return err
+		}))
+	}
 
 	srv := &http.Server{
 		Addr:    ":" + port,
@@ -56,9 +124,33 @@ func runServer(cmd *cobra.Command, args []string) error {
 	logger.Info("Starting Monday HTTP server", zap.String("port", port))
 	fmt.Printf("🚀 Monday server starting on port %s\n", port)
 	fmt.Printf("📋 Health check: GET http://localhost:%s/health\n", port)
+	fmt.Printf("📊 Metrics: GET http://localhost:%s/metrics\n", port)
 	fmt.Printf("🔗 Trigger workflow: POST http://localhost:%s/trigger\n", port)
+	if webhookEnabled {
+		fmt.Printf("🪝 Linear webhook: POST http://localhost:%s/webhooks/linear (label %q)\n", port, webhook.Label)
+	}
 	
-	return srv.ListenAndServe()
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down; no new runs are accepted, waiting for in-flight runs to finish", zap.Duration("drain_timeout", drain))
+	shutdownCtx, cancel := cleanupContext()
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Failed to shut down HTTP server", zap.Error(err))
+	}
+	if drainRuns(&runs, cancelRuns, drain) {
+		logger.Info("In-flight runs finished")
+	} else {
+		logger.Warn("In-flight runs did not finish within the drain timeout; canceled them")
+	}
+	logger.Sync()
+	return ctx.Err()
 }
 
 func healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -72,17 +164,350 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
+// makeMetricsHandler serves aggregate stage metrics and agent usage of all recorded runs in the
+// Prometheus text format. Like the other endpoints about runs, it requires the API key.
+func makeMetricsHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Header.Get("X-API-Key") != apiKey {
+			logger.Warn("Unauthorized request", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		stats, err := loadStats("")
+		if err != nil {
+			logger.Error("Failed to load run metrics", zap.Error(err))
+			http.Error(w, "failed to load metrics", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		stats.WritePrometheus(w)
+	}
+}
+
+// makeExportHandler serves the run history for BI tools. The format query parameter selects
+// csv (the default) or json; repo, issue, status, since, and until filter the runs like the
+// flags of monday export.
+func makeExportHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Header.Get("X-API-Key") != apiKey {
+			logger.Warn("Unauthorized request", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = "csv"
+		}
+		write, err := exportWriter(format)
+		if err != nil {
+			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter, err := buildHistoryFilter(query.Get("repo"), query.Get("issue"), query.Get("status"), query.Get("since"), query.Get("until"))
+		if err != nil {
+			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		runs, err := queryRuns(filter)
+		if err != nil {
+			logger.Error("Failed to load run history", zap.Error(err))
+			http.Error(w, "failed to load run history", http.StatusInternalServerError)
+			return
+		}
+
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="monday-runs.csv"`)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		write(w, runs)
+	}
+}
+
+// makeStatusHandler serves the states of the server's in-flight runs and of the most recent
+// finished ones, as shown by monday status. The recent query parameter defaults to 5.
+func makeStatusHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Header.Get("X-API-Key") != apiKey {
+			logger.Warn("Unauthorized request", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		recent := 5
+		if value := r.URL.Query().Get("recent"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				http.Error(w, "bad request: recent must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+			recent = n
+		}
+
+		states, err := localRunStates(recent, time.Now())
+		if err != nil {
+			logger.Error("Failed to load run states", zap.Error(err))
+			http.Error(w, "failed to load run states", http.StatusInternalServerError)
+			return
+		}
+		if states == nil {
+			states = []runState{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(states)
+	}
+}
+
+// makeLogsHandler serves the log of one of the server's runs, as shown by monday logs, from the
+// byte offset given by the offset query parameter on. The run's status is sent in the
+// X-Run-Status header so clients following the log know when to stop.
+func makeLogsHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Header.Get("X-API-Key") != apiKey {
+			logger.Warn("Unauthorized request", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		runID := r.URL.Query().Get("run_id")
+		if !validRunID(runID) {
+			http.Error(w, "bad request: run_id is required", http.StatusBadRequest)
+			return
+		}
+		offset, ok := offsetParam(w, r)
+		if !ok {
+			return
+		}
+
+		data, status, err := localLogReader(runID, processAlive)(offset)
+		if err != nil && status == "" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to read run log", zap.String("run_id", runID), zap.Error(err))
+			http.Error(w, "failed to read run log", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("X-Run-Status", status)
+		w.Write(data)
+	}
+}
+
+// offsetParam returns the offset query parameter of r, 0 if there is none. It answers an
+// invalid one with 400 Bad Request and returns false.
+func offsetParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	value := r.URL.Query().Get("offset")
+	if value == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		http.Error(w, "bad request: offset must be a non-negative integer", http.StatusBadRequest)
+		return 0, false
+	}
+	return n, true
+}
+
+// makeRunsHandler serves the runs of the server as JSON. GET /runs lists the state of every
+// run, newest first, optionally only those with the status query parameter and at most limit
+// of them (default 50). GET /runs/{id} returns the full summary of one run, with its stages,
+// gates, and errors, and GET /runs/{id}/logs the output of its agent from the offset query
+// parameter on, as plain text with the run's status in the X-Run-Status header. POST
+// /runs/{id}/approve and /runs/{id}/reject answer a run waiting for approval of its changes.
+func makeRunsHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			serveApproval(w, r, logger, apiKey)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Header.Get("X-API-Key") != apiKey {
+			logger.Warn("Unauthorized request", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		dir, err := runsDir()
+		if err != nil {
+			logger.Error("Failed to locate runs", zap.Error(err))
+			http.Error(w, "failed to load runs", http.StatusInternalServerError)
+			return
+		}
+
+		if runID := strings.TrimPrefix(r.URL.Path, "/runs/"); runID != r.URL.Path && runID != "" {
+			if runID, ok := strings.CutSuffix(runID, "/logs"); ok && validRunID(runID) {
+				serveAgentLog(w, r, logger, runID)
+				return
+			}
+			if !validRunID(runID) {
+				http.Error(w, "bad request: invalid run ID", http.StatusBadRequest)
+				return
+			}
+			run, err := summary.Load(filepath.Join(dir, runID, "summary.json"))
+			if errors.Is(err, os.ErrNotExist) {
+				http.Error(w, fmt.Sprintf("run %s not found", runID), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				logger.Error("Failed to load run", zap.String("run_id", runID), zap.Error(err))
+				http.Error(w, "failed to load run", http.StatusInternalServerError)
+				return
+			}
+			data, err := json.Marshal(run)
+			if err != nil {
+				logger.Error("Failed to encode run", zap.String("run_id", runID), zap.Error(err))
+				http.Error(w, "failed to encode run", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(redact.String(string(data)) + "\n"))
+			return
+		}
+
+		limit := 50
+		if value := r.URL.Query().Get("limit"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				http.Error(w, "bad request: limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		runs, err := queryRuns(history.Filter{})
+		if err != nil {
+			logger.Error("Failed to load runs", zap.Error(err))
+			http.Error(w, "failed to load runs", http.StatusInternalServerError)
+			return
+		}
+		status := r.URL.Query().Get("status")
+		states := []runState{}
+		now := time.Now()
+		for i := len(runs) - 1; i >= 0 && len(states) < limit; i-- {
+			state := describeRun(runs[i], now, processAlive)
+			if status == "" || state.Status == status {
+				states = append(states, state)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(states)
+	}
+}
+
+// serveApproval answers POST /runs/{id}/approve and /runs/{id}/reject by approving or
+// rejecting the changes of the run, which must be waiting for approval.
+func serveApproval(w http.ResponseWriter, r *http.Request, logger *zap.Logger, apiKey string) {
+	if r.Header.Get("X-API-Key") != apiKey {
+		logger.Warn("Unauthorized request", zap.String("remote_addr", r.RemoteAddr))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	runID, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/runs/"), "/")
+	if action != "approve" && action != "reject" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !validRunID(runID) {
+		http.Error(w, "bad request: invalid run ID", http.StatusBadRequest)
+		return
+	}
+	dir, err := runDir(runID)
+	if err != nil {
+		logger.Error("Failed to locate runs", zap.Error(err))
+		http.Error(w, "failed to load run", http.StatusInternalServerError)
+		return
+	}
+	if _, err := os.Stat(filepath.Join(dir, "summary.json")); errors.Is(err, os.ErrNotExist) {
+		http.Error(w, fmt.Sprintf("run %s not found", runID), http.StatusNotFound)
+		return
+	}
+	if err := requestApproval(dir, action == "approve", processAlive); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	status := "approved"
+	if action == "reject" {
+		status = "rejected"
+	}
+	logger.Info("Run changes "+status, zap.String("run_id", runID))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"run_id": runID, "status": status})
+}
+
+// serveAgentLog answers r with the agent output of the run called runID.
+func serveAgentLog(w http.ResponseWriter, r *http.Request, logger *zap.Logger, runID string) {
+	offset, ok := offsetParam(w, r)
+	if !ok {
+		return
+	}
+	data, status, err := localRunFileReader(runID, transcriptFile, processAlive)(offset)
+	if err != nil && status == "" {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to read agent output", zap.String("run_id", runID), zap.Error(err))
+		http.Error(w, "failed to read agent output", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Run-Status", status)
+	w.Write(data)
+}
+
 type triggerRequest struct {
 	LinearID  string `json:"linear_id"`
 	GithubURL string `json:"github_url"`
+	// AssigneeID is the Linear user the issue is assigned to, e.g. the one who triggered the run
+	AssigneeID string `json:"assignee_id,omitempty"`
 }
 
 type triggerResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// RunID identifies the started run in /runs/{id}
+	RunID string `json:"run_id,omitempty"`
 }
 
-func makeTriggerHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
+// makeTriggerHandler starts a run in pool for every trigger request, unless dedup has a run
+// for the same idempotency key, or issue and repository, whose run ID it answers with instead.
+func makeTriggerHandler(ctx context.Context, logger *zap.Logger, apiKey string, pool *runPool, dedup *runDedup) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -98,7 +523,7 @@ func makeTriggerHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
 		var req triggerRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			logger.Error("Failed to decode request", zap.Error(err))
-			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			http.Error(w, "bad request: "+redact.Error(err), http.StatusBadRequest)
 			return
 		}
 
@@ -112,8 +537,25 @@ func makeTriggerHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
 			zap.String("github_url", req.GithubURL),
 			zap.String("remote_addr", r.RemoteAddr))
 
-		go func() {
-			if err := runWorkflow(req.LinearID, req.GithubURL); err != nil {
+		issueID := extractIssueID(req.LinearID)
+		key := triggerKey(r.Header.Get("Idempotency-Key"), issueID, req.GithubURL)
+		runID := newRunID(issueID)
+		if existing, ok := dedup.claim(key, runID); !ok {
+			logger.Info("Coalesced duplicate workflow trigger request", zap.String("linear_id", req.LinearID), zap.String("run_id", existing))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(triggerResponse{
+				Status:  "duplicate",
+				Message: fmt.Sprintf("A run for Linear issue %s is already in flight or just ended", req.LinearID),
+				RunID:   existing,
+			})
+			return
+		}
+		queued, err := pool.submit(ctx, runID, func() {
+			defer dedup.end(key)
+			opts := workflowOptions(runID, req.LinearID, req.GithubURL)
+			opts.Log = logger
+			if _, err := workflow.New(opts).Run(withAssignee(ctx, req.AssigneeID)); err != nil {
 				logger.Error("Workflow failed", zap.Error(err),
 					zap.String("linear_id", req.LinearID),
 					zap.String("github_url", req.GithubURL))
@@ -122,7 +564,14 @@ func makeTriggerHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
 					zap.String("linear_id", req.LinearID),
 					zap.String("github_url", req.GithubURL))
 			}
-		}()
+		})
+		if err != nil {
+			dedup.release(key)
+			logger.Warn("Rejected workflow trigger request", zap.Error(err), zap.String("linear_id", req.LinearID))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		recordQueuedRun(logger, runID, req.LinearID, req.GithubURL)
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusAccepted)
@@ -130,6 +579,11 @@ func makeTriggerHandler(logger *zap.Logger, apiKey string) http.HandlerFunc {
 		response := triggerResponse{
 			Status:  "started",
 			Message: fmt.Sprintf("Workflow started for Linear issue %s", req.LinearID),
+			RunID:   runID,
+		}
+		if queued {
+			response.Status = "queued"
+			response.Message = fmt.Sprintf("Workflow queued for Linear issue %s", req.LinearID)
 		}
 		
 		json.NewEncoder(w).Encode(response)
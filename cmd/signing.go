@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"monday/sandbox"
+)
+
+var (
+	// gitAuthorName is the name runs commit as.
+	gitAuthorName string
+	// gitAuthorEmail is the email runs commit as.
+	gitAuthorEmail string
+	// gitSigningKey is the GPG key ID or SSH key commits are signed with.
+	gitSigningKey string
+	// signCommits signs the commits of runs.
+	signCommits bool
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&gitAuthorName, "git-author-name", "", "Name to commit as (default: $MONDAY_GIT_AUTHOR_NAME or git's user.name)")
+	rootCmd.Flags().StringVar(&gitAuthorEmail, "git-author-email", "", "Email to commit as (default: $MONDAY_GIT_AUTHOR_EMAIL or git's user.email)")
+	rootCmd.Flags().StringVar(&gitSigningKey, "git-signing-key", "", "GPG key ID, or SSH key file or key:: literal, to sign commits with; implies --sign-commits (default: $MONDAY_GIT_SIGNING_KEY or git's user.signingkey)")
+	rootCmd.Flags().BoolVar(&signCommits, "sign-commits", false, "Sign the commit of the run, as branch protection requiring signed commits needs (default: $MONDAY_SIGN_COMMITS)")
+}
+
+// commitIdentity returns the name and email runs commit as from the flags or the environment;
+// either is empty when git's own configuration applies.
+func commitIdentity() (name, email string) {
+	name, email = gitAuthorName, gitAuthorEmail
+	if name == "" {
+		name = os.Getenv("MONDAY_GIT_AUTHOR_NAME")
+	}
+	if email == "" {
+		email = os.Getenv("MONDAY_GIT_AUTHOR_EMAIL")
+	}
+	return name, email
+}
+
+// resolveSigningKey returns the key commits are signed with from the flag or the environment,
+// or "" for git's user.signingkey.
+func resolveSigningKey() string {
+	if gitSigningKey != "" {
+		return gitSigningKey
+	}
+	return os.Getenv("MONDAY_GIT_SIGNING_KEY")
+}
+
+// signingEnabled reports whether the commits of runs are signed: with --sign-commits,
+// MONDAY_SIGN_COMMITS, or a signing key.
+func signingEnabled() (bool, error) {
+	if signCommits || resolveSigningKey() != "" {
+		return true, nil
+	}
+	value := os.Getenv("MONDAY_SIGN_COMMITS")
+	if value == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid MONDAY_SIGN_COMMITS %q: must be true or false", value)
+	}
+	return enabled, nil
+}
+
+// signingFormat returns the gpg.format of key: "ssh" for SSH keys, given as a file or a
+// key:: or ssh- literal, and "" for GPG key IDs.
+func signingFormat(key string) string {
+	if strings.HasPrefix(key, "key::") || strings.HasPrefix(key, "ssh-") {
+		return "ssh"
+	}
+	if _, err := os.Stat(key); err == nil {
+		return "ssh"
+	}
+	return ""
+}
+
+// commitArgs returns the git arguments committing the staged changes with message msg as the
+// configured identity, signed if signing is enabled.
+func commitArgs(msg string) ([]string, error) {
+	var args []string
+	name, email := commitIdentity()
+	if name != "" {
+		args = append(args, "-c", "user.name="+name)
+	}
+	if email != "" {
+		args = append(args, "-c", "user.email="+email)
+	}
+
+	sign, err := signingEnabled()
+	if err != nil {
+		return nil, err
+	}
+	if !sign {
+		return append(args, "commit", "-m", msg), nil
+	}
+	if key := resolveSigningKey(); key != "" {
+		if format := signingFormat(key); format != "" {
+			args = append(args, "-c", "gpg.format="+format)
+		}
+		args = append(args, "-c", "user.signingkey="+key)
+	}
+	return append(args, "commit", "--gpg-sign", "-m", msg), nil
+}
+
+// commitContext returns the context the commit of the run of ctx is made in. Signed commits
+// are made on the host, where the signing key and agent are, so they never enter the container
+// of a --containerized run; the workspace has the same path on both.
+func commitContext(ctx context.Context) (context.Context, error) {
+	sign, err := signingEnabled()
+	if err != nil || !sign || runContainer(ctx) == nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, containerKey{}, (*sandbox.Container)(nil)), nil
+}
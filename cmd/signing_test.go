@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/linear"
+	"monday/sandbox"
+)
+
+// resetSigning restores the identity and signing flags when t ends and clears them for it.
+func resetSigning(t *testing.T) {
+	name, email, key, sign := gitAuthorName, gitAuthorEmail, gitSigningKey, signCommits
+	t.Cleanup(func() { gitAuthorName, gitAuthorEmail, gitSigningKey, signCommits = name, email, key, sign })
+	gitAuthorName, gitAuthorEmail, gitSigningKey, signCommits = "", "", "", false
+	for _, key := range []string{"MONDAY_GIT_AUTHOR_NAME", "MONDAY_GIT_AUTHOR_EMAIL", "MONDAY_GIT_SIGNING_KEY", "MONDAY_SIGN_COMMITS"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestCommitArgs(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyFile, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  []string
+	}{
+		{
+			name: "defaults",
+			want: []string{"commit", "-m", "msg"},
+		},
+		{
+			name: "identity from the environment",
+			setup: func(t *testing.T) {
+				t.Setenv("MONDAY_GIT_AUTHOR_NAME", "Monday Bot")
+				gitAuthorEmail = "bot@example.com"
+			},
+			want: []string{"-c", "user.name=Monday Bot", "-c", "user.email=bot@example.com", "commit", "-m", "msg"},
+		},
+		{
+			name:  "signed with git's key",
+			setup: func(t *testing.T) { t.Setenv("MONDAY_SIGN_COMMITS", "true") },
+			want:  []string{"commit", "--gpg-sign", "-m", "msg"},
+		},
+		{
+			name:  "GPG key",
+			setup: func(t *testing.T) { gitSigningKey = "3AA5C34371567BD2" },
+			want:  []string{"-c", "user.signingkey=3AA5C34371567BD2", "commit", "--gpg-sign", "-m", "msg"},
+		},
+		{
+			name:  "SSH key file",
+			setup: func(t *testing.T) { t.Setenv("MONDAY_GIT_SIGNING_KEY", keyFile) },
+			want:  []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + keyFile, "commit", "--gpg-sign", "-m", "msg"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetSigning(t)
+			if test.setup != nil {
+				test.setup(t)
+			}
+			got, err := commitArgs("msg")
+			if err != nil {
+				t.Fatalf("commitArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("commitArgs() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSigningEnabledRejectsInvalidSetting(t *testing.T) {
+	resetSigning(t)
+	t.Setenv("MONDAY_SIGN_COMMITS", "sometimes")
+
+	if _, err := signingEnabled(); err == nil || !strings.Contains(err.Error(), "MONDAY_SIGN_COMMITS") {
+		t.Errorf("signingEnabled() error = %v, want an invalid MONDAY_SIGN_COMMITS", err)
+	}
+}
+
+func TestCommitContextSignsOnTheHost(t *testing.T) {
+	resetSigning(t)
+	ctx := context.WithValue(context.Background(), containerKey{}, &sandbox.Container{})
+
+	got, err := commitContext(ctx)
+	if err != nil || runContainer(got) == nil {
+		t.Errorf("commitContext() without signing = %v, %v, want the container", got, err)
+	}
+
+	signCommits = true
+	got, err = commitContext(ctx)
+	if err != nil || runContainer(got) != nil {
+		t.Errorf("commitContext() with signing = %v, %v, want the host", got, err)
+	}
+}
+
+func TestCommitChangesSignsWithSSHKey(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+	resetSigning(t)
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v: %s", err, out)
+	}
+	gitSigningKey, gitAuthorName, gitAuthorEmail = key, "Monday Bot", "bot@example.com"
+
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("hi"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := commitChanges(context.Background(), zap.NewNop(), repo, &linear.IssueDetails{Title: "Add login"}); err != nil {
+		t.Fatalf("commitChanges() error = %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", repo, "log", "-1", "--format=%an <%ae>").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "Monday Bot <bot@example.com>\n") {
+		t.Errorf("commit author = %q, want Monday Bot <bot@example.com>", out)
+	}
+	sig, err := exec.Command("git", "-C", repo, "cat-file", "commit", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(sig), "-----BEGIN SSH SIGNATURE-----") {
+		t.Errorf("commit is not SSH-signed:\n%s", sig)
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// spinner shows the stages of interactive CLI runs; it is nil when progress is printed as
+// plain lines.
+var spinner *stageSpinner
+
+// stageLabel describes a stage on the terminal.
+type stageLabel struct {
+	emoji string
+	text  string
+}
+
+// stageLabels describes the stages of a run.
+var stageLabels = map[string]stageLabel{
+	"fetch_issue":           {"📋", "Fetching issue details"},
+	"fetch_review_comments": {"💬", "Fetching review comments"},
+	"mark_in_progress":      {"🏷️ ", "Marking issue as In Progress"},
+	"assign_issue":          {"👤", "Assigning issue"},
+	"prepare_workspace":     {"📦", "Preparing workspace"},
+	"agent":                 {"🤖", "Running Codex CLI"},
+	"tests":                 {"🧪", "Running tests"},
+	"fix_gates":             {"🔧", "Fixing failing gates"},
+	"approval":              {"⏸️ ", "Waiting for approval"},
+	"commit":                {"📝", "Committing changes"},
+	"push":                  {"⬆️ ", "Pushing branch"},
+	"pull_request":          {"🚀", "Creating pull request"},
+	"mark_in_review":        {"👀", "Marking issue as In Review"},
+}
+
+// labelFor returns the label of the named stage.
+func labelFor(name string) stageLabel {
+	if label, ok := stageLabels[name]; ok {
+		return label
+	}
+	if gate, ok := strings.CutPrefix(name, "gate_"); ok {
+		return stageLabel{"🚦", "Running " + gate}
+	}
+	if hook, ok := strings.CutPrefix(name, "hook_"); ok {
+		return stageLabel{"🪝", "Running " + hook + " hook"}
+	}
+	if step, ok := strings.CutPrefix(name, "step_"); ok {
+		return stageLabel{"🧩", "Running " + step}
+	}
+	return stageLabel{"▶️ ", name}
+}
+
+// spinnersEnabled reports whether stages are shown with spinners: only at the default output
+// level, outside JSON mode, when stdout is a terminal.
+func spinnersEnabled() bool {
+	return outputLevel() == levelNormal && !jsonOutput() && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// announceStage shows that the named stage started and returns the function that shows it ended.
+func announceStage(name string) func(err error) {
+	label := labelFor(name)
+	if spinner == nil {
+		fmt.Printf("%s %s...\n", label.emoji, label.text)
+		return func(error) {}
+	}
+	spinner.Start(label.text)
+	return spinner.Stop
+}
+
+// stageOut returns where output printed while a stage runs goes.
+func stageOut() io.Writer {
+	if spinner != nil {
+		return spinner
+	}
+	return os.Stdout
+}
+
+// stageErr returns where the standard error of commands run by a stage goes.
+func stageErr() io.Writer {
+	if spinner != nil {
+		return spinner
+	}
+	return os.Stderr
+}
+
+// progressf prints a progress line of the running stage.
+func progressf(format string, args ...any) {
+	fmt.Fprintf(stageOut(), format, args...)
+}
+
+// spinnerFrames are drawn in turn in front of the running stage.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// stageSpinner draws the running stage with a spinner and its elapsed time on the last line of
+// a terminal. Output written to it while a stage runs is printed above that line.
+type stageSpinner struct {
+	out      io.Writer
+	interval time.Duration
+
+	mu      sync.Mutex
+	label   string
+	started time.Time
+	frame   int
+	drawn   bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// newStageSpinner returns a spinner drawing on out.
+func newStageSpinner(out io.Writer) *stageSpinner {
+	return &stageSpinner{out: out, interval: 100 * time.Millisecond}
+}
+
+// Start starts drawing the stage described by label, ending the previous stage if needed.
+func (s *stageSpinner) Start(label string) {
+	s.Stop(nil)
+
+	s.mu.Lock()
+	s.label = label
+	s.started = time.Now()
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.draw()
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.mu.Lock()
+				s.frame++
+				s.draw()
+				s.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop replaces the spinner line with the outcome and duration of the stage.
+func (s *stageSpinner) Stop(err error) {
+	s.mu.Lock()
+	if s.label == "" {
+		s.mu.Unlock()
+		return
+	}
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	close(stop)
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+	mark := "✅"
+	if err != nil {
+		mark = "❌"
+	}
+	fmt.Fprintf(s.out, "%s %s (%s)\n", mark, s.label, time.Since(s.started).Round(100*time.Millisecond))
+	s.label = ""
+}
+
+// Write prints p above the spinner line.
+func (s *stageSpinner) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+	n, err := s.out.Write(p)
+	if s.label != "" {
+		s.draw()
+	}
+	return n, err
+}
+
+// draw redraws the spinner line; the caller holds s.mu.
+func (s *stageSpinner) draw() {
+	s.clear()
+	fmt.Fprintf(s.out, "%s %s (%s)", spinnerFrames[s.frame%len(spinnerFrames)], s.label, time.Since(s.started).Round(time.Second))
+	s.drawn = true
+}
+
+// clear erases the spinner line; the caller holds s.mu.
+func (s *stageSpinner) clear() {
+	if s.drawn {
+		fmt.Fprint(s.out, "\r\033[K")
+		s.drawn = false
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStageSpinner(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "success", want: "✅ Pushing branch ("},
+		{name: "failure", err: errors.New("boom"), want: "❌ Pushing branch ("},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s := newStageSpinner(&out)
+			s.interval = time.Millisecond
+
+			s.Start("Pushing branch")
+			if _, err := s.Write([]byte("remote: done\n")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			time.Sleep(5 * time.Millisecond)
+			s.Stop(tt.err)
+
+			got := out.String()
+			if !strings.Contains(got, "\r\033[Kremote: done\n") {
+				t.Errorf("output = %q, want the written line above the spinner", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("output = %q, want it to contain %q", got, tt.want)
+			}
+			if !strings.HasSuffix(got, ")\n") {
+				t.Errorf("output = %q, want it to end with the stage outcome", got)
+			}
+		})
+	}
+}
+
+func TestStageSpinnerStartEndsPreviousStage(t *testing.T) {
+	var out bytes.Buffer
+	s := newStageSpinner(&out)
+
+	s.Start("Committing changes")
+	s.Start("Pushing branch")
+	s.Stop(nil)
+	s.Stop(nil)
+
+	got := out.String()
+	if strings.Count(got, "✅") != 2 {
+		t.Errorf("output = %q, want both stages to end once", got)
+	}
+	if strings.Index(got, "✅ Committing changes") > strings.Index(got, "✅ Pushing branch") {
+		t.Errorf("output = %q, want the stages to end in order", got)
+	}
+}
+
+func TestLabelFor(t *testing.T) {
+	if got := labelFor("agent").text; got != "Running Codex CLI" {
+		t.Errorf("labelFor(agent) = %q, want %q", got, "Running Codex CLI")
+	}
+	if got := labelFor("custom").text; got != "custom" {
+		t.Errorf("labelFor(custom) = %q, want %q", got, "custom")
+	}
+}
@@ -0,0 +1,603 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/notify"
+	"monday/prompt"
+	"monday/retry"
+	"monday/vcs"
+	"monday/workflow"
+)
+
+// monday's own stages of a run, in order. Programs embedding monday add theirs between them
+// with workflow.InsertStage.
+func init() {
+	for _, stage := range []runStage{
+		{name: "fetch_issue", run: (*issueRun).fetchIssue, end: (*issueRun).cleanUp},
+		{name: "mark_in_progress", run: (*issueRun).markInProgress},
+		{name: "prompt", run: (*issueRun).writePrompt},
+		{name: "dry_run", run: (*issueRun).planDryRun},
+		{name: "checkpoint", run: (*issueRun).startCheckpoint},
+		{name: "container", run: (*issueRun).runInContainer, end: (*issueRun).endContainer},
+		{name: "prepare_workspace", run: (*issueRun).prepare},
+		{name: "repo_context", run: (*issueRun).readRepo},
+		{name: "agent", run: (*issueRun).runAgent},
+		{name: "commit", run: (*issueRun).commit},
+		{name: "push", run: (*issueRun).push},
+		{name: "pull_request", run: (*issueRun).openPullRequest},
+		{name: "post_pr", run: (*issueRun).finish},
+	} {
+		workflow.RegisterStage(stage)
+	}
+}
+
+// runStage is one of monday's own stages, which share the issueRun of their run.
+type runStage struct {
+	name string
+	run  func(st *issueRun, ctx context.Context, run *workflow.Run) error
+	// end, if set, is called with the error the run ends with once it ended
+	end func(st *issueRun, ctx context.Context, run *workflow.Run, err error)
+}
+
+func (s runStage) Name() string { return s.name }
+
+// Run runs the stage, in the container of the run if it has one, and then describes the run's
+// progress in run to the stages after it.
+func (s runStage) Run(ctx context.Context, run *workflow.Run) error {
+	st := issueRunOf(run)
+	if st == nil {
+		return fmt.Errorf("stage %s needs the run observer of package cmd", s.name)
+	}
+	if st.container != nil {
+		ctx = context.WithValue(ctx, containerKey{}, st.container)
+	}
+	err := s.run(st, ctx, run)
+	sum := st.scope.sum
+	run.Issue, run.IssueTitle, run.IssueURL = st.issue, sum.IssueTitle, sum.IssueURL
+	run.Workspace, run.Branch, run.PRURL = sum.Workspace, sum.Branch, sum.PRURL
+	return err
+}
+
+func (s runStage) End(ctx context.Context, run *workflow.Run, err error) {
+	if st := issueRunOf(run); st != nil && s.end != nil {
+		s.end(st, ctx, run, err)
+	}
+}
+
+// issueRunKey is the key of the issueRun of a run in its workflow.Run.
+type issueRunKey struct{}
+
+// issueRun is what monday's stages of a run share: its scope, the configuration checked before
+// the first stage, and what each stage leaves to the stages after it.
+type issueRun struct {
+	scope *runScope
+
+	tracker       issues.Provider
+	host          codeHost
+	needsApproval bool
+	prTmpl        *prTemplate
+	promptTmpl    *prompt.Template
+	apiKey        string
+	policy        string
+	retries       retry.Policy
+	// replay holds the inputs of the run being replayed; nil for other runs
+	replay *runInputs
+	// resume is the checkpoint of the run being continued; nil for fresh runs
+	resume *checkpoint
+
+	issue   *issues.Issue
+	rb      *rollback
+	effects *remoteEffects
+	// workspaceKey names the worktree of the run
+	workspaceKey   string
+	promptContexts []promptContext
+	prompt         string
+	inputs         *runInputs
+	cp             *checkpoint
+	// container is the container of a --containerized run, which containerStop removes
+	container     any
+	containerStop func()
+	workDir       string
+	repoCfg       *repoConfig
+	gates         []gate
+	pushed        publishedBranch
+}
+
+// issueRunOf returns the issueRun of run, or nil if the run observer did not start it.
+func issueRunOf(run *workflow.Run) *issueRun {
+	st, _ := run.Value(issueRunKey{}).(*issueRun)
+	return st
+}
+
+// configure checks the configuration of a run with opts, before any of its stages, and loads
+// the run it replays or continues.
+func (st *issueRun) configure(ctx context.Context, opts workflow.Options) error {
+	var err error
+	if opts.Replay != "" {
+		if st.replay, err = loadRunInputs(opts.Replay); err != nil {
+			return withExitCode(exitConfig, err)
+		}
+	}
+	if opts.Continue != "" {
+		if st.resume, err = loadContinuedRun(opts.Continue, processAlive); err != nil {
+			return withExitCode(exitConfig, err)
+		}
+	}
+	// Replays take the issue from the replayed run and leave the issue tracker alone.
+	if st.replay == nil {
+		if st.tracker, err = openIssueProvider(opts.Provider); err != nil {
+			return withExitCode(exitConfig, err)
+		}
+	}
+
+	if st.host, err = newCodeHost(ctx, opts.RepoURL, opts.LocalRepo); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if opts.Containerized && opts.LocalRepo != "" {
+		return withExitCode(exitConfig, fmt.Errorf("--containerized cannot be used with --local-repo"))
+	}
+	if opts.Containerized && opts.WorktreeMode {
+		return withExitCode(exitConfig, fmt.Errorf("--containerized cannot be used with --worktree-mode"))
+	}
+	if err := validateCloneScope(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if _, err := signingEnabled(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if st.needsApproval, err = approvalRequired(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if st.prTmpl, err = loadPRTemplate(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if _, err := parseLabelMap(flagOrEnvList(prLabelMap, "MONDAY_PR_LABEL_MAP")); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if _, err := prSummaryEnabled(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if _, err := resolveCommitType(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if st.promptTmpl, err = loadPromptTemplate(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+
+	st.apiKey = os.Getenv("OPENAI_API_KEY")
+	if st.apiKey == "" {
+		return withExitCode(exitConfig, fmt.Errorf("OPENAI_API_KEY environment variable is required"))
+	}
+	if st.policy, err = failurePolicy(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if st.retries, err = retryPolicy(); err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	return nil
+}
+
+// fetchIssue fetches the issue from the issue tracker or, for replays, takes the one the
+// replayed run recorded.
+func (st *issueRun) fetchIssue(ctx context.Context, run *workflow.Run) error {
+	log, sum := st.scope.log, st.scope.sum
+	if st.replay != nil {
+		st.issue = &st.replay.Issue
+		sum.ReplayOf = st.replay.RunID
+		fmt.Printf("📋 Replaying run %s: %s\n", st.replay.RunID, st.issue.Title)
+		log.Info("Replaying run", zap.String("replayed_run_id", st.replay.RunID))
+	} else {
+		stageLog, endStage := startStage(ctx, log, sum, st.scope.dir, "fetch_issue")
+		stageLog.Info("Fetching issue details")
+		issue, err := st.tracker.FetchIssueDetails(ctx, run.IssueID)
+		endStage(err)
+		if err != nil {
+			return fmt.Errorf("failed to fetch issue details: %w", err)
+		}
+
+		fmt.Printf("✅ Issue: %s\n", issue.Title)
+		stageLog.Info("Issue fetched successfully",
+			zap.String("title", issue.Title),
+			zap.String("branch_name", issue.BranchName))
+		st.issue = issue
+	}
+	sum.IssueTitle = st.issue.Title
+	sum.IssueURL = st.issue.URL
+	notifyRun(log, notify.RunStarted, sum)
+
+	st.rb = &rollback{log: log.With(zap.String("stage", "rollback"))}
+	st.effects = &remoteEffects{log: log.With(zap.String("stage", "cleanup")), policy: st.policy, host: st.host, tracker: st.tracker, issue: st.issue}
+	return nil
+}
+
+// cleanUp ends a failed run: it first deals with what the run changed outside this machine, as
+// the failure policy says, and then rolls back its local artifacts if asked to or canceled.
+func (st *issueRun) cleanUp(ctx context.Context, run *workflow.Run, err error) {
+	if err == nil || st.effects == nil {
+		return
+	}
+	cleanupCtx, cancelCleanup := cleanupContext()
+	defer cancelCleanup()
+	// A timed out run failed; only a cancelled one is undone whatever the policy.
+	canceled := ctx.Err() != nil && timeoutCause(ctx) == nil
+	st.effects.cleanUp(cleanupCtx, st.rb.repo(), st.scope.sum, err, canceled)
+	st.rb.pushed = st.effects.pushed
+	if run.Options.Rollback || canceled {
+		st.rb.run(cleanupCtx)
+	}
+}
+
+// markInProgress moves the issue to In Progress and assigns it, except in dry runs and replays.
+func (st *issueRun) markInProgress(ctx context.Context, run *workflow.Run) error {
+	if st.tracker == nil || run.Options.DryRun {
+		return nil
+	}
+	log, sum := st.scope.log, st.scope.sum
+	stageLog, endStage := startStage(ctx, log, sum, st.scope.dir, "mark_in_progress")
+	stageLog.Info("Marking issue as In Progress")
+	err := st.tracker.MarkIssueInProgress(ctx, st.issue)
+	endStage(err)
+	if err != nil {
+		stageLog.Warn("Failed to mark issue as In Progress", zap.Error(err))
+	} else {
+		st.effects.previousState = st.issue.State
+	}
+	assignIssue(ctx, log, sum, st.scope.dir, st.tracker, st.issue)
+	return nil
+}
+
+// setBranch makes branch the branch the run works on.
+func (st *issueRun) setBranch(branch string) {
+	st.scope.sum.Branch = branch
+	st.rb.branch = branch
+	st.effects.branch = branch
+}
+
+// writePrompt names the branch and builds the prompt, and records them with the other inputs of
+// the run.
+func (st *issueRun) writePrompt(ctx context.Context, run *workflow.Run) error {
+	branch := st.issue.BranchName
+	if branch == "" {
+		branch = fmt.Sprintf("feature/%s", strings.ToLower(strings.ReplaceAll(run.IssueID, "-", "_")))
+	}
+	st.workspaceKey = run.IssueID
+	switch {
+	case st.resume != nil:
+		branch = st.resume.Branch
+	case st.replay != nil:
+		// Replays work on a branch and worktree of their own next to the replayed run's.
+		branch = "replay/" + run.ID
+		st.workspaceKey = run.ID
+	}
+	st.setBranch(branch)
+
+	// Replays give the agent the recorded prompt, context included.
+	if st.replay == nil {
+		contexts, err := loadContextFlags(ctx)
+		if err != nil {
+			return withExitCode(exitConfig, err)
+		}
+		st.promptContexts = contexts
+	}
+	codexPrompt, err := buildPrompt(st.promptTmpl, st.issue, "", st.promptContexts)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	if st.replay != nil {
+		codexPrompt = st.replay.Prompt
+	}
+	st.prompt = codexPrompt
+	st.inputs = &runInputs{RunID: run.ID, Issue: *st.issue, RepoURL: run.Options.RepoURL, LocalRepo: run.Options.LocalRepo, Prompt: codexPrompt, Options: runOptionsFor(run.Options)}
+	if err := st.inputs.write(st.scope.dir); err != nil {
+		st.scope.log.Warn("Failed to record run inputs", zap.Error(err))
+	}
+	return nil
+}
+
+// planDryRun prints what a dry run would do and ends it. A dry run stops before the workspace
+// exists, so the prompt lacks the repository's context files and conventions, and the plan its
+// gates and hooks.
+func (st *issueRun) planDryRun(ctx context.Context, run *workflow.Run) error {
+	if !run.Options.DryRun {
+		return nil
+	}
+	cr, err := pullRequest(st.prTmpl, st.issue, st.scope.sum.Branch, nil, "")
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	printDryRun(os.Stdout, st.issue, st.prompt, cr, st.host)
+	st.scope.log.Info("Dry run completed")
+	fmt.Printf("✅ Dry run completed; nothing was cloned or pushed and the issue was not changed\n")
+	return workflow.SkipRest
+}
+
+// startCheckpoint starts the checkpoint that persists the phase the run reached, so --continue
+// can pick up after it. A continued run starts out in the phase the run it continues reached.
+func (st *issueRun) startCheckpoint(ctx context.Context, run *workflow.Run) error {
+	opts := run.Options
+	st.cp = &checkpoint{RunID: run.ID, IssueID: run.IssueID, RepoURL: opts.RepoURL, LocalRepo: opts.LocalRepo, Branch: st.scope.sum.Branch}
+	if st.resume != nil {
+		st.scope.sum.ResumedFrom = st.resume.RunID
+		st.cp.Workspace = st.resume.Workspace
+		st.cp.BranchCreated = st.resume.BranchCreated
+		st.cp.State = st.resume.State
+		if err := st.cp.write(st.scope.dir); err != nil {
+			st.scope.log.Warn("Failed to write run checkpoint", zap.Error(err))
+		}
+	}
+	st.advance(phaseFetched)
+	return nil
+}
+
+// advance moves the run into phase.
+func (st *issueRun) advance(phase runPhase) {
+	if err := st.cp.advance(st.scope.dir, phase); err != nil {
+		st.scope.log.Warn("Failed to write run checkpoint", zap.Error(err))
+	}
+	st.scope.sum.Phase = string(st.cp.State)
+}
+
+// skipPhase reports whether the continued run already reached phase, whose stage is then
+// skipped.
+func (st *issueRun) skipPhase(phase runPhase) bool {
+	if st.resume == nil || !st.resume.State.reached(phase) {
+		return false
+	}
+	stage := phaseStages[phase]
+	fmt.Printf("⏭️  %s: done in run %s\n", labelFor(stage).text, st.resume.RunID)
+	st.scope.log.Info("Skipping stage completed by the continued run", zap.String("stage", stage))
+	return true
+}
+
+// runInContainer starts the container of a --containerized run, which the commands of the
+// stages after it run in.
+func (st *issueRun) runInContainer(ctx context.Context, run *workflow.Run) error {
+	if !run.Options.Containerized {
+		return nil
+	}
+	var continued string
+	if st.skipPhase(phasePrepared) {
+		continued = st.cp.Workspace
+	}
+	dir, err := containerWorkspace(run.ID, continued)
+	if err != nil {
+		return err
+	}
+	ctx, stop, err := startContainer(ctx, st.scope.log, run.ID, dir)
+	if err != nil {
+		return err
+	}
+	st.container, st.containerStop = ctx.Value(containerKey{}), stop
+	return nil
+}
+
+// endContainer removes the container of the run.
+func (st *issueRun) endContainer(ctx context.Context, run *workflow.Run, err error) {
+	if st.containerStop != nil {
+		st.containerStop()
+	}
+}
+
+// prepare creates the workspace of the run, or opens the one of the run it continues. Every
+// command of the run works in it; the process's working directory is left alone so that runs
+// of the server do not get in each other's way.
+func (st *issueRun) prepare(ctx context.Context, run *workflow.Run) error {
+	sum := st.scope.sum
+	if st.skipPhase(phasePrepared) {
+		if _, err := os.Stat(st.cp.Workspace); err != nil {
+			return fmt.Errorf("failed to open the workspace of run %s: %w", st.resume.RunID, err)
+		}
+		st.workDir = st.cp.Workspace
+		st.rb.adopt(st.cp)
+	} else {
+		stageLog, endStage := startStage(ctx, st.scope.log, sum, st.scope.dir, "prepare_workspace")
+		dir, err := prepareWorkspace(ctx, stageLog, run.Options, st.workspaceKey, run.ID, sum.Branch, st.rb)
+		endStage(err)
+		if err != nil {
+			return err
+		}
+		st.workDir = dir
+		st.cp.Workspace = dir
+		st.cp.BranchCreated = st.rb.branchCreated
+		// A continued run treats the cached repository of --worktree-mode like --local-repo.
+		if st.rb.repoPath != "" {
+			st.cp.LocalRepo = st.rb.repoPath
+		}
+		st.advance(phasePrepared)
+	}
+	sum.Workspace = st.workDir
+	return nil
+}
+
+// readRepo adds the repository's context files and conventions to the prompt, except in
+// replays, and loads its .monday.yml.
+func (st *issueRun) readRepo(ctx context.Context, run *workflow.Run) error {
+	log := st.scope.log
+	if st.replay == nil {
+		repoContexts, err := loadRepoContext(filepath.Join(st.workDir, repoContextDir))
+		if err != nil {
+			return err
+		}
+		conventions, err := prompt.LoadConventions(st.workDir)
+		if err != nil {
+			log.Warn("Failed to read the repository's contribution guidelines", zap.Error(err))
+		}
+		if len(repoContexts) > 0 || conventions != "" {
+			log.Info("Adding repository context to the prompt", zap.Int("files", len(repoContexts)), zap.Bool("conventions", conventions != ""))
+			codexPrompt, err := buildPrompt(st.promptTmpl, st.issue, conventions, append(st.promptContexts, repoContexts...))
+			if err != nil {
+				return withExitCode(exitConfig, err)
+			}
+			st.prompt = codexPrompt
+			st.inputs.Prompt = codexPrompt
+			if err := st.inputs.write(st.scope.dir); err != nil {
+				log.Warn("Failed to record run inputs", zap.Error(err))
+			}
+		}
+	}
+
+	cfg, err := loadRepoConfig(st.workDir)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	st.repoCfg = cfg
+	st.gates = resolveGates(st.workDir, cfg)
+	return nil
+}
+
+// runAgent runs the pre_agent hook and the agent.
+func (st *issueRun) runAgent(ctx context.Context, run *workflow.Run) error {
+	if st.skipPhase(phaseAgentDone) {
+		return nil
+	}
+	log, sum, dir := st.scope.log, st.scope.sum, st.scope.dir
+	if err := runHook(ctx, log, sum, dir, st.repoCfg.Hooks, hookPreAgent); err != nil {
+		return err
+	}
+	stageLog, endStage := startStage(ctx, log, sum, dir, "agent")
+	stageLog.Info("Running Codex CLI", zap.String("description", st.issue.Description))
+	usage, err := runCodex(ctx, stageLog, st.workDir, st.prompt, st.apiKey, dir)
+	endStage(err)
+	sum.AgentInputTokens = usage.InputTokens
+	sum.AgentOutputTokens = usage.OutputTokens
+	if usage.HasCost {
+		sum.AgentCostUSD = &usage.CostUSD
+	}
+	if err != nil {
+		return withExitCode(exitAgentFailed, fmt.Errorf("failed to run Codex: %w", err))
+	}
+	st.advance(phaseAgentDone)
+	return nil
+}
+
+// commit runs the post_agent hook, the gates, the pre_commit hook, and the approval, and then
+// commits the agent's changes. A replay that is not published ends there.
+func (st *issueRun) commit(ctx context.Context, run *workflow.Run) error {
+	log, sum, dir := st.scope.log, st.scope.sum, st.scope.dir
+	if st.skipPhase(phaseCommitted) {
+		files, err := committedFiles(st.workDir)
+		if err != nil {
+			log.Warn("Failed to list committed files", zap.Error(err))
+		}
+		sum.FilesChanged = files
+	} else {
+		if err := runHook(ctx, log, sum, dir, st.repoCfg.Hooks, hookPostAgent); err != nil {
+			return err
+		}
+		if err := runGates(ctx, log, sum, dir, st.gates, st.prompt, st.apiKey); err != nil {
+			return err
+		}
+		if err := runHook(ctx, log, sum, dir, st.repoCfg.Hooks, hookPreCommit); err != nil {
+			return err
+		}
+		if st.needsApproval && (st.replay == nil || run.Options.Publish) {
+			if err := awaitApproval(ctx, log, sum, dir, st.workDir, st.tracker, st.issue); err != nil {
+				return err
+			}
+		}
+		stageLog, endStage := startStage(ctx, log, sum, dir, "commit")
+		files, err := commitChanges(ctx, stageLog, st.workDir, st.issue)
+		endStage(err)
+		if err != nil {
+			return err
+		}
+		sum.FilesChanged = files
+		st.advance(phaseCommitted)
+	}
+	if err := saveDiff(st.workDir, filepath.Join(dir, "diff.patch")); err != nil {
+		log.Warn("Failed to save diff", zap.Error(err))
+	}
+	if st.replay != nil && !run.Options.Publish {
+		fmt.Printf("✅ Replay committed to %s in %s\n", sum.Branch, st.workDir)
+		log.Info("Replay completed without publishing", zap.String("work_dir", st.workDir))
+		return workflow.SkipRest
+	}
+	return nil
+}
+
+// push pushes the branch to origin.
+func (st *issueRun) push(ctx context.Context, run *workflow.Run) error {
+	if !st.skipPhase(phasePushed) {
+		stageLog, endStage := startStage(ctx, st.scope.log, st.scope.sum, st.scope.dir, "push")
+		stageLog.Info("Pushing branch to origin")
+		pushed, err := pushBranch(ctx, stageLog, st.retries, st.host, st.workDir, st.scope.sum.Branch)
+		st.pushed = pushed
+		// A rejected push leaves the commits on a renamed branch, which is what the
+		// rollback and the failure policy have to clean up.
+		st.setBranch(pushed.name)
+		st.cp.Branch = pushed.name
+		endStage(err)
+		if err != nil {
+			return withExitCode(exitPublishFailed, fmt.Errorf("failed to push branch: %w", err))
+		}
+		st.advance(phasePushed)
+	}
+	st.effects.pushed = true
+	return nil
+}
+
+// openPullRequest opens the pull request of the branch, or reuses the one an earlier attempt
+// opened, and links it to the issue.
+func (st *issueRun) openPullRequest(ctx context.Context, run *workflow.Run) error {
+	log, sum := st.scope.log, st.scope.sum
+	stageLog, endStage := startStage(ctx, log, sum, st.scope.dir, "pull_request")
+	// A retried run reuses the pull request an earlier attempt opened for the branch.
+	prURL, err := st.host.open(ctx, sum.Branch)
+	if err != nil {
+		stageLog.Warn("Failed to look up an open pull request for the branch", zap.Error(err))
+		err = nil
+	}
+	if prURL != "" {
+		stageLog.Info("Reusing the open pull request of the branch", zap.String("pr_url", prURL))
+	} else {
+		stageLog.Info("Creating pull request")
+		files, filesErr := committedFiles(st.workDir)
+		if filesErr != nil {
+			stageLog.Warn("Failed to list the committed files", zap.Error(filesErr))
+		}
+		changes := summarizeChanges(ctx, stageLog, st.workDir, st.issue)
+		var cr vcs.ChangeRequest
+		if cr, err = pullRequest(st.prTmpl, st.issue, sum.Branch, files, changes); err == nil {
+			if st.pushed.rejected != nil {
+				cr.Draft = true
+				cr.Body = fallbackNote(st.pushed) + "\n\n" + cr.Body
+			}
+			prURL, err = st.host.create(ctx, stageLog, cr)
+		}
+	}
+	endStage(err)
+	if err != nil {
+		return withExitCode(exitPublishFailed, fmt.Errorf("failed to create pull request: %w", err))
+	}
+	sum.PRURL = prURL
+	st.advance(phasePRCreated)
+	if st.tracker != nil {
+		attachPullRequest(ctx, stageLog, st.tracker, st.issue, prURL)
+		postCompletionComment(ctx, stageLog, st.tracker, st.issue, sum)
+		markInReview(ctx, log, sum, st.scope.dir, st.tracker, st.issue)
+	}
+	return nil
+}
+
+// finish runs the post_pr hook and removes the clone of the run. The pull request is out, so
+// a failing post_pr hook does not fail the run.
+func (st *issueRun) finish(ctx context.Context, run *workflow.Run) error {
+	log := st.scope.log
+	if err := runHook(ctx, log, st.scope.sum, st.scope.dir, st.repoCfg.Hooks, hookPostPR); err != nil {
+		log.Warn("Hook failed", zap.Error(err))
+	}
+
+	removeWorkspace(log, st.rb.cloneDir)
+
+	fmt.Printf("✅ Monday workflow completed successfully!\n")
+	log.Info("Monday workflow completed successfully", zap.String("pr_url", st.scope.sum.PRURL))
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/history"
+	"monday/summary"
+)
+
+var (
+	statsSince string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show per-stage durations, retries, and success rates, and the agent cost of past runs",
+	Args:  cobra.NoArgs,
+	RunE:  runStats,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Only include runs started within this period, e.g. 7d or 12h")
+	addDeprecatedJSONFlag(statsCmd)
+	statsCmd.Flags().MarkDeprecated("json", "use --output json")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	stats, err := loadStats(statsSince)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		return writeJSON(stats)
+	}
+
+	if stats.Runs == 0 {
+		fmt.Println("No finished runs recorded")
+		return nil
+	}
+	fmt.Printf("Runs: %d (%d succeeded, %d failed, %.0f%% success)\n", stats.Runs, stats.Succeeded, stats.Failed, stats.SuccessRate*100)
+	fmt.Printf("Agent: $%.2f reported cost (%d unpriced runs), %d input and %d output tokens\n\n",
+		stats.Agent.CostUSD, stats.Agent.UnpricedRuns, stats.Agent.InputTokens, stats.Agent.OutputTokens)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tRUNS\tSUCCESS\tRETRIES\tMEAN\tP95\tMAX")
+	for _, s := range stats.Stages {
+		fmt.Fprintf(w, "%s\t%d\t%.0f%%\t%d\t%s\t%s\t%s\n", s.Name, s.Runs, s.SuccessRate*100, s.Retries,
+			summary.FormatSeconds(s.MeanSeconds), summary.FormatSeconds(s.P95Seconds), summary.FormatSeconds(s.MaxSeconds))
+	}
+	return w.Flush()
+}
+
+// loadStats aggregates the recorded runs started within since, or all runs when since is empty.
+func loadStats(since string) (history.Stats, error) {
+	var filter history.Filter
+	if since != "" {
+		period, err := parseSince(since)
+		if err != nil {
+			return history.Stats{}, fmt.Errorf("invalid --since: %w", err)
+		}
+		filter.Since = time.Now().Add(-period)
+	}
+	runs, err := queryRuns(filter)
+	if err != nil {
+		return history.Stats{}, err
+	}
+	return history.Aggregate(runs), nil
+}
+
+// parseSince parses a look-back period: a Go duration such as "12h", or a number of days
+// such as "30d".
+func parseSince(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative period %q", s)
+	}
+	return d, nil
+}
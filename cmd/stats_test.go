@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "30d", expected: 30 * 24 * time.Hour},
+		{input: "12h", expected: 12 * time.Hour},
+		{input: "90m", expected: 90 * time.Minute},
+		{input: "xd", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseSince(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseSince(%q) expected an error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSince(%q) returned error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("parseSince(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetricsHandlerRequiresAPIKey(t *testing.T) {
+	t.Setenv("MONDAY_HOME", t.TempDir())
+	handler := makeMetricsHandler(zap.NewNop(), "secret")
+
+	for key, want := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "secret": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != want {
+			t.Errorf("GET /metrics with key %q status = %d, want %d", key, rec.Code, want)
+		}
+	}
+}
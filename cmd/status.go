@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/history"
+	"monday/redact"
+	"monday/summary"
+)
+
+// statusInterrupted marks runs recorded as running whose process is gone.
+const statusInterrupted = "interrupted"
+
+var (
+	statusRecent int
+	statusServer string
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show in-flight and recent runs",
+	Long: `Show every in-flight run along with the most recent finished ones: the stage each run is
+in, how long it has taken, its branch and pull request, and whether the agent is still working.
+Runs recorded as running whose process has exited are shown as interrupted.
+
+With --server or MONDAY_SERVER_URL, the runs of a monday server are shown as well; requests
+authenticate with SERVER_API_KEY.`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().IntVar(&statusRecent, "recent", 5, "Also show this many of the most recent finished runs")
+	statusCmd.Flags().StringVar(&statusServer, "server", "", "Base URL of a monday server whose runs to include (default: $MONDAY_SERVER_URL)")
+	addDeprecatedJSONFlag(statusCmd)
+	statusCmd.Flags().MarkDeprecated("json", "use --output json")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// runState is the state of one run as shown by monday status and served at GET /status.
+type runState struct {
+	Source          string    `json:"source"`
+	RunID           string    `json:"run_id"`
+	IssueID         string    `json:"issue_id"`
+	Repo            string    `json:"repo"`
+	Status          string    `json:"status"`
+	Stage           string    `json:"stage,omitempty"`
+	Phase           string    `json:"phase,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Branch          string    `json:"branch,omitempty"`
+	PRURL           string    `json:"pr_url,omitempty"`
+	AgentRunning    bool      `json:"agent_running"`
+	Error           string    `json:"error,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	states, err := localRunStates(statusRecent, time.Now())
+	if err != nil {
+		return err
+	}
+
+	server := statusServer
+	if server == "" {
+		server = os.Getenv("MONDAY_SERVER_URL")
+	}
+	if server != "" {
+		remote, err := fetchRunStates(server, os.Getenv("SERVER_API_KEY"), statusRecent)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not fetch runs from %s: %s\n", server, redact.Error(err))
+		}
+		states = append(states, remote...)
+	}
+
+	if jsonOutput() {
+		if states == nil {
+			states = []runState{}
+		}
+		return writeJSON(states)
+	}
+
+	if len(states) == 0 {
+		fmt.Println("No runs")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tRUN ID\tISSUE\tSTATUS\tSTAGE\tPHASE\tDURATION\tAGENT\tBRANCH\tPULL REQUEST")
+	for _, state := range states {
+		agent := "-"
+		if state.AgentRunning {
+			agent = "running"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", state.Source, state.RunID, state.IssueID, state.Status,
+			dashIfEmpty(state.Stage), dashIfEmpty(state.Phase), summary.FormatSeconds(state.DurationSeconds), agent, dashIfEmpty(state.Branch), dashIfEmpty(state.PRURL))
+	}
+	return w.Flush()
+}
+
+// localRunStates returns the states of the runs recorded in the state directory: every run that
+// is in flight or was interrupted, then the most recent finished ones.
+func localRunStates(recent int, now time.Time) ([]runState, error) {
+	runs, err := queryRuns(history.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	return selectRunStates(runs, recent, now, processAlive), nil
+}
+
+// selectRunStates describes the runs that are running, plus the last recent finished runs,
+// oldest first. alive reports whether the process of a running run still exists.
+func selectRunStates(runs []*summary.Summary, recent int, now time.Time, alive func(pid int) bool) []runState {
+	var active, finished []runState
+	for _, run := range runs {
+		state := describeRun(run, now, alive)
+		if state.Status == summary.StatusRunning || state.Status == statusInterrupted {
+			active = append(active, state)
+		} else {
+			finished = append(finished, state)
+		}
+	}
+
+	if len(finished) > recent {
+		finished = finished[len(finished)-recent:]
+	}
+	return append(finished, active...)
+}
+
+// describeRun returns the state of run at now: the stage a running run is in or a failed run
+// stopped in, with the error it stopped on. Running runs whose process alive reports gone are
+// interrupted.
+func describeRun(run *summary.Summary, now time.Time, alive func(pid int) bool) runState {
+	state := runState{
+		Source:          "local",
+		RunID:           run.RunID,
+		IssueID:         run.IssueID,
+		Repo:            redact.String(run.Repo),
+		Status:          run.Status,
+		StartedAt:       run.StartedAt,
+		DurationSeconds: run.DurationSeconds,
+		Branch:          run.Branch,
+		PRURL:           run.PRURL,
+		Phase:           run.Phase,
+	}
+	if run.Status == summary.StatusSucceeded {
+		return state
+	}
+	state.Stage = run.FailedStage()
+	if n := len(run.Errors); n > 0 {
+		state.Error = redact.String(run.Errors[n-1])
+	}
+	if run.Status != summary.StatusRunning {
+		return state
+	}
+
+	state.DurationSeconds = now.Sub(run.StartedAt).Seconds()
+	if run.PID == 0 || !alive(run.PID) {
+		state.Status = statusInterrupted
+	} else {
+		state.AgentRunning = state.Stage == "agent"
+	}
+	return state
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// fetchRunStates returns the run states served by the monday server at baseURL.
+func fetchRunStates(baseURL, apiKey string, recent int) ([]runState, error) {
+	endpoint := strings.TrimRight(baseURL, "/") + "/status?recent=" + url.QueryEscape(strconv.Itoa(recent))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var states []runState
+	if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
+		return nil, fmt.Errorf("failed to decode run states: %w", err)
+	}
+	for i := range states {
+		states[i].Source = "server"
+	}
+	return states, nil
+}
+
+// dashIfEmpty returns s, or "-" when s is empty.
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
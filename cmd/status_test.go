@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+)
+
+func TestSelectRunStates(t *testing.T) {
+	now := time.Now()
+	var runs []*summary.Summary
+	for i, status := range []string{summary.StatusSucceeded, summary.StatusFailed, summary.StatusSucceeded} {
+		s := summary.New("done-"+string(rune('a'+i)), "DEL-1", "repo")
+		s.StartStage("agent")(nil)
+		if status == summary.StatusFailed {
+			s.StartStage("push")(errors.New("rejected"))
+			s.Finish(errors.New("failed to push branch"))
+		} else {
+			s.Finish(nil)
+		}
+		runs = append(runs, s)
+	}
+	live := summary.New("live", "DEL-2", "repo")
+	live.PID = 100
+	live.StartedAt = now.Add(-time.Minute)
+	live.StartStage("agent")
+	live.Phase = string(phasePrepared)
+	dead := summary.New("dead", "DEL-3", "repo")
+	dead.PID = 200
+	runs = append(runs, live, dead)
+
+	states := selectRunStates(runs, 2, now, func(pid int) bool { return pid == 100 })
+
+	var ids []string
+	for _, state := range states {
+		ids = append(ids, state.RunID)
+	}
+	want := []string{"done-b", "done-c", "live", "dead"}
+	if len(ids) != len(want) {
+		t.Fatalf("run IDs = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("run IDs = %v, want %v", ids, want)
+		}
+	}
+
+	if states[0].Stage != "push" {
+		t.Errorf("failed run stage = %q, want %q", states[0].Stage, "push")
+	}
+	if !states[2].AgentRunning || states[2].Stage != "agent" || states[2].Phase != "prepared" || states[2].DurationSeconds < 60 {
+		t.Errorf("live run state = %+v, want agent running for at least a minute", states[2])
+	}
+	if states[3].Status != statusInterrupted || states[3].AgentRunning {
+		t.Errorf("dead run state = %+v, want interrupted", states[3])
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("processAlive() = false for the current process")
+	}
+}
+
+func TestStatusHandlerAndFetch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	s := summary.New("run-1", "DEL-1", "https://github.com/owner/repo")
+	s.PID = os.Getpid()
+	s.StartStage("agent")
+	if _, err := s.WriteFiles(filepath.Join(home, "runs", "run-1")); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(makeStatusHandler(zap.NewNop(), "secret"))
+	defer server.Close()
+
+	states, err := fetchRunStates(server.URL+"/", "secret", 5)
+	if err != nil {
+		t.Fatalf("fetchRunStates() error = %v", err)
+	}
+	if len(states) != 1 || states[0].RunID != "run-1" || states[0].Source != "server" || !states[0].AgentRunning {
+		t.Errorf("fetchRunStates() = %+v, want the running agent of run-1", states)
+	}
+
+	if _, err := fetchRunStates(server.URL, "wrong", 5); err == nil {
+		t.Error("fetchRunStates() with a wrong API key succeeded")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/status?recent=-1", nil)
+	req.Header.Set("X-API-Key", "secret")
+	rec := httptest.NewRecorder()
+	makeStatusHandler(zap.NewNop(), "secret")(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d for a negative recent, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestRunsHandler(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	failed := summary.New("run-1", "DEL-1", "https://github.com/owner/repo")
+	endStage := failed.StartStage("push")
+	endStage(errors.New("failed to push branch"))
+	failed.Finish(errors.New("failed to push branch"))
+	running := summary.New("run-2", "DEL-2", "https://github.com/owner/repo")
+	running.StartedAt = failed.StartedAt.Add(time.Minute)
+	running.PID = os.Getpid()
+	running.StartStage("agent")
+	for _, s := range []*summary.Summary{failed, running} {
+		if _, err := s.WriteFiles(filepath.Join(home, "runs", s.RunID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := makeRunsHandler(zap.NewNop(), "secret")
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-API-Key", "secret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	var states []runState
+	if err := json.NewDecoder(get("/runs").Body).Decode(&states); err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[0].RunID != "run-2" || !states[0].AgentRunning {
+		t.Fatalf("GET /runs = %+v, want run-2 with its agent running first", states)
+	}
+	if states[1].Stage != "push" || states[1].Error != "push: failed to push branch" {
+		t.Errorf("GET /runs run-1 = %+v, want it failed in push with its error", states[1])
+	}
+
+	states = nil
+	if err := json.NewDecoder(get("/runs?status=failed").Body).Decode(&states); err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 || states[0].RunID != "run-1" {
+		t.Errorf("GET /runs?status=failed = %+v, want run-1", states)
+	}
+
+	var run summary.Summary
+	if err := json.NewDecoder(get("/runs/run-1").Body).Decode(&run); err != nil {
+		t.Fatal(err)
+	}
+	if run.RunID != "run-1" || run.Status != summary.StatusFailed || len(run.Stages) != 1 {
+		t.Errorf("GET /runs/run-1 = run %s, %s, with %d stages, want the failed run-1 with 1 stage", run.RunID, run.Status, len(run.Stages))
+	}
+
+	for target, want := range map[string]int{"/runs/run-9": http.StatusNotFound, "/runs/..": http.StatusBadRequest, "/runs?limit=0": http.StatusBadRequest} {
+		if rec := get(target); rec.Code != want {
+			t.Errorf("GET %s status = %d, want %d", target, rec.Code, want)
+		}
+	}
+}
+
+func TestRunsHandlerApproval(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	write := func(runID, stage string) {
+		run := summary.New(runID, "DEL-1", "https://github.com/owner/repo")
+		run.PID = os.Getpid()
+		run.StartStage(stage)
+		if _, err := run.WriteFiles(filepath.Join(home, "runs", runID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := makeRunsHandler(zap.NewNop(), "secret")
+
+	tests := []struct {
+		name       string
+		stage      string
+		target     string
+		key        string
+		wantCode   int
+		wantAnswer string
+	}{
+		{name: "approve", stage: approvalStage, target: "/runs/run-1/approve", key: "secret", wantCode: http.StatusOK, wantAnswer: "approved"},
+		{name: "reject", stage: approvalStage, target: "/runs/run-1/reject", key: "secret", wantCode: http.StatusOK, wantAnswer: "rejected"},
+		{name: "unknown run", target: "/runs/run-9/approve", key: "secret", wantCode: http.StatusNotFound},
+		{name: "not waiting", stage: "agent", target: "/runs/run-1/approve", key: "secret", wantCode: http.StatusConflict},
+		{name: "missing API key", stage: approvalStage, target: "/runs/run-1/approve", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.RemoveAll(filepath.Join(home, "runs")); err != nil {
+				t.Fatal(err)
+			}
+			if tt.stage != "" {
+				write("run-1", tt.stage)
+			}
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("POST %s status = %d, want %d: %s", tt.target, rec.Code, tt.wantCode, rec.Body)
+			}
+
+			answer, err := os.ReadFile(filepath.Join(home, "runs", "run-1", approvalFile))
+			if tt.wantAnswer == "" {
+				if err == nil {
+					t.Errorf("POST %s recorded %q, want no answer", tt.target, answer)
+				}
+				return
+			}
+			if strings.TrimSpace(string(answer)) != tt.wantAnswer {
+				t.Errorf("POST %s recorded %q, want %s", tt.target, answer, tt.wantAnswer)
+			}
+			var body map[string]string
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body["run_id"] != "run-1" || body["status"] != tt.wantAnswer {
+				t.Errorf("POST %s = %v, %v, want run-1 %s", tt.target, body, err, tt.wantAnswer)
+			}
+		})
+	}
+}
+
+func TestRunsHandlerAgentLog(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	run := summary.New("run-1", "DEL-1", "https://github.com/owner/repo")
+	run.Finish(nil)
+	dir := filepath.Join(home, "runs", "run-1")
+	if _, err := run.WriteFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, transcriptFile), []byte("editing auth.go\nrunning go test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handler := makeRunsHandler(zap.NewNop(), "secret")
+
+	tests := []struct {
+		target     string
+		wantCode   int
+		wantBody   string
+		wantStatus string
+	}{
+		{target: "/runs/run-1/logs", wantCode: http.StatusOK, wantBody: "editing auth.go\nrunning go test\n", wantStatus: summary.StatusSucceeded},
+		{target: "/runs/run-1/logs?offset=16", wantCode: http.StatusOK, wantBody: "running go test\n", wantStatus: summary.StatusSucceeded},
+		{target: "/runs/run-9/logs", wantCode: http.StatusNotFound},
+		{target: "/runs/run-1/logs?offset=-1", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("X-API-Key", "secret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != tt.wantCode {
+			t.Errorf("GET %s status = %d, want %d", tt.target, rec.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		if got := rec.Body.String(); got != tt.wantBody {
+			t.Errorf("GET %s = %q, want %q", tt.target, got, tt.wantBody)
+		}
+		if got := rec.Header().Get("X-Run-Status"); got != tt.wantStatus {
+			t.Errorf("GET %s X-Run-Status = %q, want %q", tt.target, got, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"monday/history"
+	"monday/store"
+	"monday/summary"
+)
+
+// runStoreFile is the SQLite database of runs in the state directory.
+const runStoreFile = "monday.db"
+
+// openRunStore opens the database of runs in the state directory. The first time, it imports
+// the runs of monday versions before the database from their summary.json.
+func openRunStore() (*store.Store, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	db, err := store.Open(filepath.Join(dir, runStoreFile))
+	if err != nil {
+		return nil, err
+	}
+	err = db.Import(func() ([]store.Run, error) {
+		runs, err := history.ReadDir(filepath.Join(dir, "runs"))
+		if err != nil {
+			return nil, err
+		}
+		records := make([]store.Run, 0, len(runs))
+		for _, run := range runs {
+			record, err := runRecord(run, filepath.Join(dir, "runs", run.RunID, "run.log"))
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// runRecord returns the record of the run of sum, whose log is at logPath.
+func runRecord(sum *summary.Summary, logPath string) (store.Run, error) {
+	data, err := sum.JSON()
+	if err != nil {
+		return store.Run{}, fmt.Errorf("failed to encode summary of run %s: %w", sum.RunID, err)
+	}
+	return store.Run{
+		RunID:      sum.RunID,
+		IssueID:    sum.IssueID,
+		Repo:       sum.Repo,
+		Branch:     sum.Branch,
+		PRURL:      sum.PRURL,
+		Status:     sum.Status,
+		PID:        sum.PID,
+		StartedAt:  sum.StartedAt,
+		FinishedAt: sum.FinishedAt,
+		LogPath:    logPath,
+		Summary:    data,
+	}, nil
+}
+
+// recordRun saves the state of the run of sum, whose log is at logPath, in the database of
+// runs. Failures are logged; the run's summary.json is still written.
+func recordRun(log *zap.Logger, sum *summary.Summary, logPath string) {
+	db, err := openRunStore()
+	if err != nil {
+		log.Warn("Failed to record run", zap.Error(err))
+		return
+	}
+	defer db.Close()
+	record, err := runRecord(sum, logPath)
+	if err == nil {
+		err = db.Save(record)
+	}
+	if err != nil {
+		log.Warn("Failed to record run", zap.Error(err))
+	}
+}
+
+// recordQueuedRun records a run the server accepted, unless it already started and recorded
+// itself. Failures are logged.
+func recordQueuedRun(log *zap.Logger, runID, issueID, repoURL string) {
+	db, err := openRunStore()
+	if err != nil {
+		log.Warn("Failed to record queued run", zap.Error(err), zap.String("run_id", runID))
+		return
+	}
+	defer db.Close()
+	logPath := ""
+	if dir, err := runDir(runID); err == nil {
+		logPath = filepath.Join(dir, "run.log")
+	}
+	run := store.Run{RunID: runID, IssueID: extractIssueID(issueID), Repo: repoURL, Status: store.StatusQueued, StartedAt: time.Now(), LogPath: logPath}
+	if err := db.Add(run); err != nil {
+		log.Warn("Failed to record queued run", zap.Error(err), zap.String("run_id", runID))
+	}
+}
+
+// lookupRun returns the record of the run with the given ID from the database of runs. Runs
+// recorded before the database existed are read from their summary.json.
+func lookupRun(runID string) (*store.Run, error) {
+	db, err := openRunStore()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	run, err := db.Get(runID)
+	if !errors.Is(err, store.ErrNotFound) {
+		return run, err
+	}
+
+	dir, err := runDir(runID)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := summary.Load(filepath.Join(dir, "summary.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("run %s not found", runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	record, err := runRecord(sum, filepath.Join(dir, "run.log"))
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// queryRuns returns the recorded runs that pass filter, oldest first.
+func queryRuns(filter history.Filter) ([]*summary.Summary, error) {
+	db, err := openRunStore()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return history.NewStore(db).Query(filter)
+}
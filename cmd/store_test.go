@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/history"
+	"monday/store"
+	"monday/summary"
+)
+
+func TestRecordQueuedRunKeepsStartedRun(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	started := summary.New("run-1", "DEL-1", "https://github.com/acme/app")
+	started.PID = 100
+	recordRun(zap.NewNop(), started, filepath.Join(home, "runs", "run-1", "run.log"))
+
+	recordQueuedRun(zap.NewNop(), "run-1", "DEL-1", "https://github.com/acme/app")
+	recordQueuedRun(zap.NewNop(), "run-2", "https://linear.app/acme/issue/DEL-2/fix", "https://github.com/acme/app")
+
+	run, err := lookupRun("run-1")
+	if err != nil || run.Status != summary.StatusRunning || run.PID != 100 {
+		t.Errorf("lookupRun(run-1) = %+v, %v, want it running in process 100", run, err)
+	}
+	run, err = lookupRun("run-2")
+	if err != nil || run.Status != store.StatusQueued || run.IssueID != "DEL-2" || run.LogPath != filepath.Join(home, "runs", "run-2", "run.log") {
+		t.Errorf("lookupRun(run-2) = %+v, %v, want DEL-2 queued with its log path", run, err)
+	}
+	if _, err := lookupRun("run-9"); err == nil {
+		t.Error("lookupRun(run-9) found an unknown run")
+	}
+}
+
+func TestQueryRunsImportsOldRuns(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MONDAY_HOME", home)
+	old := summary.New("run-1", "DEL-1", "https://github.com/acme/app")
+	old.Finish(nil)
+	if _, err := old.WriteFiles(filepath.Join(home, "runs", "run-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := queryRuns(history.Filter{})
+	if err != nil || len(runs) != 1 || runs[0].RunID != "run-1" || runs[0].Status != summary.StatusSucceeded {
+		t.Fatalf("queryRuns() = %v, %v, want the imported run-1", runs, err)
+	}
+
+	running := summary.New("run-2", "DEL-2", "https://github.com/acme/app")
+	running.StartStage("agent")
+	recordRun(zap.NewNop(), running, filepath.Join(home, "runs", "run-2", "run.log"))
+	runs, err = queryRuns(history.Filter{Issue: "del-2"})
+	if err != nil || len(runs) != 1 || runs[0].RunID != "run-2" || len(runs[0].Stages) != 1 {
+		t.Errorf("queryRuns(DEL-2) = %v, %v, want run-2 in its agent stage", runs, err)
+	}
+}
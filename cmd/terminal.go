@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"monday/gitops"
+	"monday/terminal"
+)
+
+var (
+	// terminalName selects the terminal worktrees are opened in.
+	terminalName string
+	// terminalCommand is run in the terminals worktrees are opened in.
+	terminalCommand string
+	// itermSplit splits the current iTerm2 session instead of opening a window.
+	itermSplit string
+	// openRepo narrows the worktrees of an issue to one repository.
+	openRepo string
+)
+
+var worktreesOpenCmd = &cobra.Command{
+	Use:   "open <issue-id>",
+	Short: "Open a terminal in the worktree of an issue",
+	Long: `Open a terminal window in the worktree of an issue, titled with the issue, to continue the
+agent's work by hand. --terminal selects the terminal; by default it is tmux inside a tmux
+session, iTerm2 when monday runs in it, Terminal.app on other macOS terminals, and the first
+installed of gnome-terminal, konsole, kitty, and alacritty on Linux, and Windows Terminal
+on Windows. --terminal-command is run in the new terminal, before its shell. With
+--iterm-split, iTerm2 splits the current session of its frontmost window into a new pane
+instead of opening a window.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorktreesOpen,
+}
+
+func init() {
+	worktreesOpenCmd.Flags().StringVar(&terminalName, "terminal", "", "Terminal to open: "+strings.Join(terminal.Names, ", ")+" (default: $MONDAY_TERMINAL or detected)")
+	worktreesOpenCmd.Flags().StringVar(&terminalCommand, "terminal-command", "", "Shell command to run in the terminal, e.g. \"claude --continue\" (default: $MONDAY_TERMINAL_COMMAND or none)")
+	worktreesOpenCmd.Flags().StringVar(&itermSplit, "iterm-split", "", "Open iTerm2 as a pane split off the current session: "+strings.Join(terminal.SplitDirections, " or ")+" (default: $MONDAY_ITERM_SPLIT or a new window)")
+	worktreesOpenCmd.Flags().StringVar(&openRepo, "repo", "", "Repository of the worktree, when the issue has worktrees in several")
+	worktreesCmd.AddCommand(worktreesOpenCmd)
+}
+
+// resolveTerminal returns the launcher selected with --terminal or MONDAY_TERMINAL, or the
+// default one. iTerm2 splits its current session as --iterm-split or MONDAY_ITERM_SPLIT say.
+func resolveTerminal() (terminal.Launcher, error) {
+	name := terminalName
+	if name == "" {
+		name = os.Getenv("MONDAY_TERMINAL")
+	}
+	split := itermSplit
+	if split == "" {
+		split = os.Getenv("MONDAY_ITERM_SPLIT")
+	}
+	if split != "" && !slices.Contains(terminal.SplitDirections, split) {
+		return nil, fmt.Errorf("invalid iTerm2 split %q: must be one of %s", split, strings.Join(terminal.SplitDirections, ", "))
+	}
+	launcher, err := terminal.New(name)
+	if err != nil {
+		return nil, err
+	}
+	if iterm, ok := launcher.(*terminal.ITerm); ok {
+		iterm.Split = split
+	}
+	return launcher, nil
+}
+
+// resolveTerminalCommand returns the command selected with --terminal-command or
+// MONDAY_TERMINAL_COMMAND.
+func resolveTerminalCommand() string {
+	if terminalCommand != "" {
+		return terminalCommand
+	}
+	return os.Getenv("MONDAY_TERMINAL_COMMAND")
+}
+
+func runWorktreesOpen(cmd *cobra.Command, args []string) error {
+	launcher, err := resolveTerminal()
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+	root, err := resolveWorktreeRoot()
+	if err != nil {
+		return err
+	}
+	worktrees, err := gitops.ListWorktrees(root)
+	if err != nil {
+		return err
+	}
+	wt, err := findIssueWorktree(worktrees, extractIssueID(args[0]), openRepo)
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("%s (%s)", wt.Issue, wt.Repo)
+	if err := launcher.Open(cmd.Context(), wt.Path, title, resolveTerminalCommand()); err != nil {
+		return err
+	}
+	fmt.Printf("🖥️  Opened %s in %s\n", wt.Path, launcher.Name())
+	if jsonOutput() {
+		return writeJSON(map[string]string{"issue": wt.Issue, "repo": wt.Repo, "path": wt.Path, "terminal": launcher.Name()})
+	}
+	return nil
+}
+
+// findIssueWorktree returns the worktree of issueID among worktrees, in repo if it is given.
+func findIssueWorktree(worktrees []gitops.Worktree, issueID, repo string) (gitops.Worktree, error) {
+	var found []gitops.Worktree
+	for _, wt := range worktrees {
+		if strings.EqualFold(wt.Issue, issueID) && (repo == "" || wt.Repo == repo) {
+			found = append(found, wt)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return gitops.Worktree{}, fmt.Errorf("no worktree of %s; monday worktrees list shows the worktrees", issueID)
+	case 1:
+		return found[0], nil
+	default:
+		repos := make([]string, len(found))
+		for i, wt := range found {
+			repos[i] = wt.Repo
+		}
+		return gitops.Worktree{}, fmt.Errorf("%s has worktrees in %s; choose one with --repo", issueID, strings.Join(repos, ", "))
+	}
+}
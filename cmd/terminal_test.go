@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"monday/gitops"
+	"monday/terminal"
+)
+
+func TestFindIssueWorktree(t *testing.T) {
+	worktrees := []gitops.Worktree{
+		{Repo: "api", Issue: "DEL-1", Path: "/w/api/DEL-1"},
+		{Repo: "web", Issue: "DEL-1", Path: "/w/web/DEL-1"},
+		{Repo: "api", Issue: "DEL-2", Path: "/w/api/DEL-2"},
+	}
+	tests := []struct {
+		name    string
+		issue   string
+		repo    string
+		want    string
+		wantErr string
+	}{
+		{name: "one worktree", issue: "del-2", want: "/w/api/DEL-2"},
+		{name: "several repositories", issue: "DEL-1", wantErr: "has worktrees in api, web"},
+		{name: "repository chosen", issue: "DEL-1", repo: "web", want: "/w/web/DEL-1"},
+		{name: "none", issue: "DEL-3", wantErr: "no worktree of DEL-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findIssueWorktree(worktrees, tt.issue, tt.repo)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("findIssueWorktree() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findIssueWorktree() error = %v", err)
+			}
+			if got.Path != tt.want {
+				t.Errorf("findIssueWorktree() = %s, want %s", got.Path, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTerminal(t *testing.T) {
+	orig := terminalName
+	t.Cleanup(func() { terminalName = orig })
+	terminalName = ""
+	t.Setenv("MONDAY_TERMINAL", "iterm")
+
+	launcher, err := resolveTerminal()
+	if err != nil {
+		t.Fatalf("resolveTerminal() error = %v", err)
+	}
+	if launcher.Name() != "iterm" {
+		t.Errorf("resolveTerminal() = %s, want iterm from MONDAY_TERMINAL", launcher.Name())
+	}
+
+	terminalName = "tmux"
+	if launcher, err = resolveTerminal(); err != nil || launcher.Name() != "tmux" {
+		t.Errorf("resolveTerminal() = %v, %v, want tmux from --terminal", launcher, err)
+	}
+}
+
+func TestResolveTerminalCommand(t *testing.T) {
+	orig := terminalCommand
+	t.Cleanup(func() { terminalCommand = orig })
+	terminalCommand = ""
+	t.Setenv("MONDAY_TERMINAL_COMMAND", "claude --continue")
+
+	if got := resolveTerminalCommand(); got != "claude --continue" {
+		t.Errorf("resolveTerminalCommand() = %q, want the command of MONDAY_TERMINAL_COMMAND", got)
+	}
+	terminalCommand = "make test"
+	if got := resolveTerminalCommand(); got != "make test" {
+		t.Errorf("resolveTerminalCommand() = %q, want make test from --terminal-command", got)
+	}
+}
+
+func TestResolveTerminalITermSplit(t *testing.T) {
+	origName, origSplit := terminalName, itermSplit
+	t.Cleanup(func() { terminalName, itermSplit = origName, origSplit })
+	terminalName, itermSplit = "iterm", ""
+	t.Setenv("MONDAY_ITERM_SPLIT", "vertical")
+
+	launcher, err := resolveTerminal()
+	if err != nil {
+		t.Fatalf("resolveTerminal() error = %v", err)
+	}
+	if iterm, ok := launcher.(*terminal.ITerm); !ok || iterm.Split != "vertical" {
+		t.Errorf("resolveTerminal() = %+v, want iTerm2 split vertically from MONDAY_ITERM_SPLIT", launcher)
+	}
+
+	itermSplit = "diagonal"
+	if _, err := resolveTerminal(); err == nil {
+		t.Error("resolveTerminal() error = nil, want an invalid split")
+	}
+}
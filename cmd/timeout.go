@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	// stepTimeout limits how long one stage of a run may take.
+	stepTimeout time.Duration
+	// totalTimeout limits how long a run may take.
+	totalTimeout time.Duration
+)
+
+func init() {
+	rootCmd.Flags().DurationVar(&stepTimeout, "step-timeout", 0, "Fail the run when one of its stages, such as the agent or a gate, takes longer than this, e.g. 30m (default: $MONDAY_STEP_TIMEOUT or no limit)")
+	rootCmd.Flags().DurationVar(&totalTimeout, "total-timeout", 0, "Fail the run when it takes longer than this, e.g. 2h (default: $MONDAY_TOTAL_TIMEOUT or no limit)")
+}
+
+// errTimedOut is the cause of the cancellation of a run that exceeded its step or total timeout.
+var errTimedOut = errors.New("timed out")
+
+// runTimeouts returns the step and total timeouts selected with --step-timeout and
+// --total-timeout or MONDAY_STEP_TIMEOUT and MONDAY_TOTAL_TIMEOUT; zero is no limit.
+func runTimeouts() (step, total time.Duration, err error) {
+	if step, err = timeoutSetting("step-timeout", stepTimeout, "MONDAY_STEP_TIMEOUT"); err != nil {
+		return 0, 0, err
+	}
+	if total, err = timeoutSetting("total-timeout", totalTimeout, "MONDAY_TOTAL_TIMEOUT"); err != nil {
+		return 0, 0, err
+	}
+	return step, total, nil
+}
+
+// timeoutSetting returns the value of the flag called name, or else the duration in env.
+func timeoutSetting(name string, flag time.Duration, env string) (time.Duration, error) {
+	if flag < 0 {
+		return 0, fmt.Errorf("invalid --%s %s: must not be negative", name, flag)
+	}
+	value := os.Getenv(env)
+	if flag > 0 || value == "" {
+		return flag, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration such as 30m", env, value)
+	}
+	return d, nil
+}
+
+// stepTimerKey is the context key of the stepTimer of a run.
+type stepTimerKey struct{}
+
+// stepTimer cancels a run when one of its stages takes longer than limit.
+type stepTimer struct {
+	limit  time.Duration
+	cancel context.CancelCauseFunc
+}
+
+// withTimeouts returns a context that is canceled with errTimedOut once total has passed, and
+// once a stage started with it takes longer than step. Zero durations are no limit. stop
+// releases the timers.
+func withTimeouts(ctx context.Context, step, total time.Duration) (_ context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop = func() { cancel(nil) }
+	if total > 0 {
+		timer := time.AfterFunc(total, func() {
+			cancel(fmt.Errorf("run exceeded the total timeout of %s: %w", total, errTimedOut))
+		})
+		stop = func() {
+			timer.Stop()
+			cancel(nil)
+		}
+	}
+	if step > 0 {
+		ctx = context.WithValue(ctx, stepTimerKey{}, &stepTimer{limit: step, cancel: cancel})
+	}
+	return ctx, stop
+}
+
+// limitStage cancels the run of ctx if the stage called name takes longer than the step
+// timeout. The returned function ends the stage's time limit.
+func limitStage(ctx context.Context, name string) func() {
+	st, ok := ctx.Value(stepTimerKey{}).(*stepTimer)
+	if !ok {
+		return func() {}
+	}
+	timer := time.AfterFunc(st.limit, func() {
+		st.cancel(fmt.Errorf("stage %s exceeded the step timeout of %s: %w", name, st.limit, errTimedOut))
+	})
+	return func() { timer.Stop() }
+}
+
+// timeoutCause returns why ctx timed out, or nil if it did not.
+func timeoutCause(ctx context.Context) error {
+	if cause := context.Cause(ctx); errors.Is(cause, errTimedOut) {
+		return cause
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunTimeouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      time.Duration
+		env       string
+		want      time.Duration
+		wantError bool
+	}{
+		{name: "none"},
+		{name: "flag", flag: 30 * time.Minute, env: "1h", want: 30 * time.Minute},
+		{name: "environment", env: "1h", want: time.Hour},
+		{name: "invalid environment", env: "an hour", wantError: true},
+		{name: "negative flag", flag: -time.Minute, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := stepTimeout
+			t.Cleanup(func() { stepTimeout = orig })
+			stepTimeout = tt.flag
+			t.Setenv("MONDAY_STEP_TIMEOUT", tt.env)
+			t.Setenv("MONDAY_TOTAL_TIMEOUT", "")
+
+			step, _, err := runTimeouts()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("runTimeouts() error = %v, wantError %v", err, tt.wantError)
+			}
+			if step != tt.want {
+				t.Errorf("runTimeouts() step = %s, want %s", step, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepTimeoutCancelsRun(t *testing.T) {
+	ctx, stop := withTimeouts(context.Background(), 10*time.Millisecond, 0)
+	defer stop()
+
+	endFast := limitStage(ctx, "fetch_issue")
+	endFast()
+	time.Sleep(20 * time.Millisecond)
+	if ctx.Err() != nil {
+		t.Fatalf("run canceled by a stage that ended in time: %v", context.Cause(ctx))
+	}
+
+	limitStage(ctx, "agent")
+	<-ctx.Done()
+	cause := timeoutCause(ctx)
+	if !errors.Is(cause, errTimedOut) || !strings.Contains(cause.Error(), "stage agent") {
+		t.Errorf("timeoutCause() = %v, want the agent stage timing out", cause)
+	}
+}
+
+func TestTotalTimeoutCancelsRun(t *testing.T) {
+	ctx, stop := withTimeouts(context.Background(), 0, 10*time.Millisecond)
+	defer stop()
+
+	<-ctx.Done()
+	if cause := timeoutCause(ctx); cause == nil || !strings.Contains(cause.Error(), "total timeout") {
+		t.Errorf("timeoutCause() = %v, want the total timeout", cause)
+	}
+}
+
+func TestCanceledRunIsNotTimedOut(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, stop := withTimeouts(parent, time.Minute, time.Minute)
+	defer stop()
+
+	cancel()
+	<-ctx.Done()
+	if cause := timeoutCause(ctx); cause != nil {
+		t.Errorf("timeoutCause() = %v, want nil", cause)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"monday/history"
+	"monday/summary"
+)
+
+var (
+	usageSince string
+	usageBy    string
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report agent cost, run counts, and success rates per repository or team",
+	Long: `Aggregate the recorded runs by repository or Linear team and report their run counts,
+success rates, agent token usage, and agent cost. Runs whose agent did not report a cost
+are counted as unpriced.`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageSince, "since", "30d", "Only include runs started within this period, e.g. 30d or 12h")
+	usageCmd.Flags().StringVar(&usageBy, "by", "repo", "Group runs by repo or team")
+	addDeprecatedJSONFlag(usageCmd)
+	usageCmd.Flags().MarkDeprecated("json", "use --output json")
+	rootCmd.AddCommand(usageCmd)
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	var group func(*summary.Summary) string
+	switch usageBy {
+	case "repo":
+		group = history.ByRepo
+	case "team":
+		group = history.ByTeam
+	default:
+		return fmt.Errorf("invalid --by %q: must be repo or team", usageBy)
+	}
+
+	period, err := parseSince(usageSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	runs, err := queryRuns(history.Filter{Since: time.Now().Add(-period)})
+	if err != nil {
+		return err
+	}
+	usage := history.AggregateUsage(runs, group)
+
+	if jsonOutput() {
+		if usage == nil {
+			usage = []history.Usage{}
+		}
+		return writeJSON(usage)
+	}
+
+	if len(usage) == 0 {
+		fmt.Printf("No finished runs in the last %s\n", usageSince)
+		return nil
+	}
+
+	header := "REPO"
+	if usageBy == "team" {
+		header = "TEAM"
+	}
+	var total history.Usage
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tRUNS\tSUCCESS\tINPUT TOKENS\tOUTPUT TOKENS\tCOST\tUNPRICED\n", header)
+	for _, u := range usage {
+		fmt.Fprintf(w, "%s\t%d\t%.0f%%\t%d\t%d\t$%.2f\t%d\n", u.Group, u.Runs, u.SuccessRate*100, u.InputTokens, u.OutputTokens, u.CostUSD, u.UnpricedRuns)
+		total.Runs += u.Runs
+		total.Succeeded += u.Succeeded
+		total.InputTokens += u.InputTokens
+		total.OutputTokens += u.OutputTokens
+		total.CostUSD += u.CostUSD
+		total.UnpricedRuns += u.UnpricedRuns
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\nTotal: %d runs, %d succeeded, $%.2f reported agent cost over the last %s\n", total.Runs, total.Succeeded, total.CostUSD, usageSince)
+	return nil
+}
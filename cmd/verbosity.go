@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Output levels, from -q to -vv.
+const (
+	levelQuiet   = -1
+	levelNormal  = 0
+	levelVerbose = 1
+	levelDebug   = 2
+)
+
+var (
+	verbosity int
+	quiet     bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Show more output: -v adds git output and human-readable logs, -vv adds debug logs and the agent's full output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only show warnings, errors, and results")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+}
+
+// outputLevel returns the output level selected with -q or -v.
+func outputLevel() int {
+	if quiet {
+		return levelQuiet
+	}
+	return min(verbosity, levelDebug)
+}
+
+// logLevel returns the lowest level logged to the terminal.
+func logLevel() zapcore.Level {
+	switch outputLevel() {
+	case levelQuiet:
+		return zapcore.WarnLevel
+	case levelDebug:
+		return zapcore.DebugLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// showChildStdout reports whether the standard output of git and gh is shown.
+func showChildStdout() bool {
+	return outputLevel() >= levelVerbose
+}
+
+// showChildStderr reports whether the standard error of git and gh is shown. When it is not,
+// it is kept for the error of a failed command.
+func showChildStderr() bool {
+	return outputLevel() >= levelNormal
+}
+
+// showAgentOutput reports whether the agent's full output is shown instead of one progress
+// line per command, test run, or edit.
+func showAgentOutput() bool {
+	return outputLevel() >= levelDebug
+}
+
+// showProgress reports whether progress lines are printed.
+func showProgress() bool {
+	return outputLevel() >= levelNormal
+}
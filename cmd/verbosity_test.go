@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOutputLevels(t *testing.T) {
+	origQuiet, origVerbosity := quiet, verbosity
+	t.Cleanup(func() { quiet, verbosity = origQuiet, origVerbosity })
+
+	tests := []struct {
+		name        string
+		quiet       bool
+		verbosity   int
+		logLevel    zapcore.Level
+		childStdout bool
+		childStderr bool
+		agentOutput bool
+		progress    bool
+	}{
+		{name: "quiet", quiet: true, logLevel: zapcore.WarnLevel},
+		{name: "default", logLevel: zapcore.InfoLevel, childStderr: true, progress: true},
+		{name: "verbose", verbosity: 1, logLevel: zapcore.InfoLevel, childStdout: true, childStderr: true, progress: true},
+		{name: "very verbose", verbosity: 2, logLevel: zapcore.DebugLevel, childStdout: true, childStderr: true, agentOutput: true, progress: true},
+		{name: "beyond -vv", verbosity: 5, logLevel: zapcore.DebugLevel, childStdout: true, childStderr: true, agentOutput: true, progress: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiet, verbosity = tt.quiet, tt.verbosity
+			if got := logLevel(); got != tt.logLevel {
+				t.Errorf("logLevel() = %v, want %v", got, tt.logLevel)
+			}
+			if got := showChildStdout(); got != tt.childStdout {
+				t.Errorf("showChildStdout() = %v, want %v", got, tt.childStdout)
+			}
+			if got := showChildStderr(); got != tt.childStderr {
+				t.Errorf("showChildStderr() = %v, want %v", got, tt.childStderr)
+			}
+			if got := showAgentOutput(); got != tt.agentOutput {
+				t.Errorf("showAgentOutput() = %v, want %v", got, tt.agentOutput)
+			}
+			if got := showProgress(); got != tt.progress {
+				t.Errorf("showProgress() = %v, want %v", got, tt.progress)
+			}
+		})
+	}
+}
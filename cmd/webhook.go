@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultWebhookLabel is the Linear label whose addition to an issue starts a run.
+	defaultWebhookLabel = "ai-ready"
+	// maxWebhookAge is how far the timestamp of a Linear webhook may be from now; older
+	// deliveries are rejected as possible replays.
+	maxWebhookAge = time.Minute
+	// maxWebhookBytes limits the size of webhook payloads.
+	maxWebhookBytes = 1 << 20
+)
+
+// webhookConfig configures the Linear webhook receiver of the server.
+type webhookConfig struct {
+	// Secret is the signing secret of the Linear webhook
+	Secret string
+	// Label is the name of the label that starts a run when added to an issue
+	Label string
+	// RepoURL is the repository runs started by webhooks work on
+	RepoURL string
+}
+
+// loadWebhookConfig reads the webhook receiver configuration from the environment. ok is false
+// when LINEAR_WEBHOOK_SECRET is not set and the receiver is off.
+func loadWebhookConfig() (cfg webhookConfig, ok bool, err error) {
+	cfg = webhookConfig{
+		Secret:  os.Getenv("LINEAR_WEBHOOK_SECRET"),
+		Label:   os.Getenv("MONDAY_WEBHOOK_LABEL"),
+		RepoURL: os.Getenv("MONDAY_WEBHOOK_REPO_URL"),
+	}
+	if cfg.Secret == "" {
+		return cfg, false, nil
+	}
+	if cfg.RepoURL == "" {
+		return cfg, false, fmt.Errorf("MONDAY_WEBHOOK_REPO_URL is required with LINEAR_WEBHOOK_SECRET")
+	}
+	if cfg.Label == "" {
+		cfg.Label = defaultWebhookLabel
+	}
+	return cfg, true, nil
+}
+
+// linearWebhook is the part of a Linear webhook payload the receiver acts on.
+type linearWebhook struct {
+	// Action is create, update, or remove
+	Action string `json:"action"`
+	// Type is the kind of entity, e.g. Issue or Comment
+	Type string `json:"type"`
+	Data struct {
+		Identifier string `json:"identifier"`
+		Labels     []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"data"`
+	// UpdatedFrom holds the previous values of the fields an update changed
+	UpdatedFrom struct {
+		// LabelIDs is nil unless the update changed the labels
+		LabelIDs *[]string `json:"labelIds"`
+	} `json:"updatedFrom"`
+	// Actor is who caused the event: a user, or an integration or OAuth application
+	Actor struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"actor"`
+	// WebhookTimestamp is when Linear sent the webhook, in Unix milliseconds
+	WebhookTimestamp int64 `json:"webhookTimestamp"`
+}
+
+// userID returns the ID of the user who caused the event, or "" if no user did.
+func (e *linearWebhook) userID() string {
+	if e.Actor.Type != "" && e.Actor.Type != "user" {
+		return ""
+	}
+	return e.Actor.ID
+}
+
+// labelAdded reports whether the event created an issue with the label called name or added
+// that label to an issue.
+func (e *linearWebhook) labelAdded(name string) bool {
+	if e.Type != "Issue" {
+		return false
+	}
+	var id string
+	for _, label := range e.Data.Labels {
+		if strings.EqualFold(label.Name, name) {
+			id = label.ID
+		}
+	}
+	switch {
+	case id == "":
+		return false
+	case e.Action == "create":
+		return true
+	case e.Action == "update":
+		return e.UpdatedFrom.LabelIDs != nil && !slices.Contains(*e.UpdatedFrom.LabelIDs, id)
+	default:
+		return false
+	}
+}
+
+// validLinearSignature reports whether signature, the Linear-Signature header, is the
+// hex-encoded HMAC-SHA256 of body with secret.
+func validLinearSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// makeLinearWebhookHandler receives Linear webhooks signed with the secret of cfg and calls
+// start for every issue that gets the label of cfg, unless runs has a run for the issue and the
+// repository of cfg, such as one started by an earlier delivery. start starts the run called
+// runID in the background and calls done when it ends; it returns an error if the run cannot be
+// taken on.
+func makeLinearWebhookHandler(logger *zap.Logger, cfg webhookConfig, runs *runDedup, start func(runID, issueID, userID string, done func()) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+		if err != nil {
+			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !validLinearSignature(cfg.Secret, body, r.Header.Get("Linear-Signature")) {
+			logger.Warn("Rejected Linear webhook with an invalid signature", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var event linearWebhook
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if age := time.Since(time.UnixMilli(event.WebhookTimestamp)); age > maxWebhookAge || age < -maxWebhookAge {
+			logger.Warn("Rejected stale Linear webhook", zap.Duration("age", age))
+			http.Error(w, "stale webhook", http.StatusUnauthorized)
+			return
+		}
+
+		respond := func(status int, response triggerResponse) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(response)
+		}
+		issueID := event.Data.Identifier
+		if !event.labelAdded(cfg.Label) || issueID == "" {
+			respond(http.StatusOK, triggerResponse{Status: "ignored", Message: fmt.Sprintf("Event does not add the %s label to an issue", cfg.Label)})
+			return
+		}
+
+		key := triggerKey("", issueID, cfg.RepoURL)
+		runID := newRunID(issueID)
+		if existing, ok := runs.claim(key, runID); !ok {
+			respond(http.StatusOK, triggerResponse{Status: "ignored", Message: fmt.Sprintf("A run for Linear issue %s is already in flight", issueID), RunID: existing})
+			return
+		}
+
+		logger.Info("Received Linear webhook", zap.String("linear_id", issueID), zap.String("label", cfg.Label))
+		if err := start(runID, issueID, event.userID(), func() { runs.end(key) }); err != nil {
+			runs.release(key)
+			logger.Warn("Rejected Linear webhook", zap.Error(err), zap.String("linear_id", issueID))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		respond(http.StatusAccepted, triggerResponse{Status: "started", Message: fmt.Sprintf("Workflow started for Linear issue %s", issueID), RunID: runID})
+	}
+}
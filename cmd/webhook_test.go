@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestLabelAdded(t *testing.T) {
+	labels := `"labels": [{"id": "l1", "name": "AI-Ready"}, {"id": "l2", "name": "bug"}]`
+	tests := []struct {
+		name    string
+		payload string
+		want    bool
+	}{
+		{name: "created with label", payload: `{"action": "create", "type": "Issue", "data": {` + labels + `}}`, want: true},
+		{name: "label added", payload: `{"action": "update", "type": "Issue", "data": {` + labels + `}, "updatedFrom": {"labelIds": ["l2"]}}`, want: true},
+		{name: "other label added", payload: `{"action": "update", "type": "Issue", "data": {` + labels + `}, "updatedFrom": {"labelIds": ["l1"]}}`},
+		{name: "labels unchanged", payload: `{"action": "update", "type": "Issue", "data": {` + labels + `}, "updatedFrom": {"title": "Old"}}`},
+		{name: "without label", payload: `{"action": "create", "type": "Issue", "data": {"labels": []}}`},
+		{name: "comment", payload: `{"action": "create", "type": "Comment", "data": {` + labels + `}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event linearWebhook
+			if err := json.Unmarshal([]byte(tt.payload), &event); err != nil {
+				t.Fatal(err)
+			}
+			if got := event.labelAdded("ai-ready"); got != tt.want {
+				t.Errorf("labelAdded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinearWebhookHandler(t *testing.T) {
+	cfg := webhookConfig{Secret: "s3cret", Label: "ai-ready", RepoURL: "https://github.com/acme/app"}
+	added := func(issueID string, at time.Time) string {
+		return fmt.Sprintf(`{"action": "create", "type": "Issue", "webhookTimestamp": %d, "actor": {"id": "user-7", "type": "user"}, "data": {"identifier": %q, "labels": [{"id": "l1", "name": "ai-ready"}]}}`, at.UnixMilli(), issueID)
+	}
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(cfg.Secret))
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	var started []string
+	var done []func()
+	full := false
+	handler := makeLinearWebhookHandler(zap.NewNop(), cfg, newRunDedup(0), func(runID, issueID, userID string, finish func()) error {
+		if full {
+			return errQueueFull
+		}
+		started = append(started, issueID+"@"+userID)
+		done = append(done, finish)
+		return nil
+	})
+	post := func(body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/linear", strings.NewReader(body))
+		req.Header.Set("Linear-Signature", signature)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	body := added("DEL-1", time.Now())
+	if rec := post(body, sign(body+" ")); rec.Code != http.StatusUnauthorized {
+		t.Errorf("invalid signature: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	stale := added("DEL-1", time.Now().Add(-time.Hour))
+	if rec := post(stale, sign(stale)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("stale webhook: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := post(body, sign(body)); rec.Code != http.StatusAccepted {
+		t.Errorf("label added: status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if rec := post(body, sign(body)); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "already in flight") {
+		t.Errorf("redelivery: status = %d, body %s", rec.Code, rec.Body)
+	}
+	done[0]()
+	if rec := post(body, sign(body)); rec.Code != http.StatusAccepted {
+		t.Errorf("after the run: status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	done[1]()
+	full = true
+	if rec := post(body, sign(body)); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("queue full: status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	full = false
+	if rec := post(body, sign(body)); rec.Code != http.StatusAccepted {
+		t.Errorf("after the queue drained: status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if got := strings.Join(started, ","); got != "DEL-1@user-7,DEL-1@user-7,DEL-1@user-7" {
+		t.Errorf("started runs = %s, want DEL-1@user-7,DEL-1@user-7,DEL-1@user-7", got)
+	}
+}
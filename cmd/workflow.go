@@ -1,150 +1,248 @@
 package cmd
 
 import (
+        "bytes"
+        "context"
         "fmt"
+        "io"
         "os"
         "os/exec"
         "path/filepath"
+        "runtime"
         "strings"
+        "sync"
 
         "github.com/spf13/cobra"
         "go.uber.org/zap"
 
-        "monday/linear"
+        "monday/gitops"
+        "monday/issues"
+        "monday/progress"
+        "monday/redact"
+        "monday/summary"
+        "monday/workflow"
 )
 
-// runWorkflow executes the core Monday workflow logic for a given Linear issue and GitHub repository.
-// This function can be called from both CLI and HTTP server contexts.
-func runWorkflow(issueID, repoURL string) error {
-        fmt.Printf("🚀 Starting Monday workflow for %s\n", issueID)
-        logger.Info("Starting Monday workflow", 
-                zap.String("issue_id", issueID),
-                zap.String("repo_url", repoURL))
+var (
+        mirrorCache     *gitops.MirrorCache
+        mirrorCacheErr  error
+        mirrorCacheOnce sync.Once
+)
 
-        linearAPIKey := os.Getenv("LINEAR_API_KEY")
-        if linearAPIKey == "" {
-                return fmt.Errorf("LINEAR_API_KEY environment variable is required")
+// startStage starts the named stage of the run and returns a logger whose entries carry the
+// stage field, along with the function that ends the stage. The summary in dir is rewritten, and
+// the run recorded, so monday status shows the stage the run is in, and the stage is announced on the terminal. A
+// stage that outlasts --step-timeout cancels the run of ctx.
+func startStage(ctx context.Context, log *zap.Logger, sum *summary.Summary, dir, name string) (*zap.Logger, func(error)) {
+        stageLog := log.With(zap.String("stage", name))
+        endStage := sum.StartStage(name)
+        if _, err := sum.WriteFiles(dir); err != nil {
+                stageLog.Warn("Failed to write run summary", zap.Error(err))
         }
-
-        githubToken := os.Getenv("GITHUB_TOKEN")
-        if githubToken == "" {
-                return fmt.Errorf("GITHUB_TOKEN environment variable is required")
+        recordRun(stageLog, sum, filepath.Join(dir, "run.log"))
+        endDisplay := announceStage(name)
+        endLimit := limitStage(ctx, name)
+        return stageLog, func(err error) {
+                endLimit()
+                endStage(err)
+                endDisplay(err)
         }
+}
 
-        openaiAPIKey := os.Getenv("OPENAI_API_KEY")
-        if openaiAPIKey == "" {
-                return fmt.Errorf("OPENAI_API_KEY environment variable is required")
+// prepareWorkspace creates the working copy for the run with opts and returns its absolute
+// path: a per-issue worktree of opts.LocalRepo or, with opts.WorktreeMode, of the cached bare
+// clone of opts.RepoURL, or a fresh clone of opts.RepoURL with branchName checked out in the
+// run's own directory under the workspace root. Whatever it creates is recorded in rb.
+func prepareWorkspace(ctx context.Context, log *zap.Logger, opts workflow.Options, issueID, runID, branchName string, rb *rollback) (string, error) {
+        repoURL := opts.RepoURL
+        if opts.LocalRepo != "" {
+                return createIssueWorktree(ctx, log, opts.LocalRepo, issueID, branchName, rb)
         }
-
-        linearClient := linear.NewClient(linearAPIKey)
-
-        issueID = extractIssueID(issueID)
-        logger.Info("Extracted issue ID", zap.String("issue_id", issueID))
-
-        fmt.Printf("📋 Fetching Linear issue details...\n")
-        logger.Info("Fetching Linear issue details")
-        issue, err := linearClient.FetchIssueDetails(issueID)
-        if err != nil {
-                return fmt.Errorf("failed to fetch issue details: %w", err)
+        if opts.WorktreeMode {
+                return createCachedWorktree(ctx, log, repoURL, issueID, branchName, rb)
         }
 
-        fmt.Printf("✅ Issue: %s\n", issue.Title)
-        logger.Info("Issue fetched successfully", 
-                zap.String("title", issue.Title),
-                zap.String("branch_name", issue.BranchName))
-
-        logger.Info("Marking issue as In Progress")
-        if err := linearClient.MarkIssueInProgress(issue); err != nil {
-                logger.Warn("Failed to mark issue as In Progress", zap.Error(err))
+        workDir, err := runWorkspace(runID)
+        if err != nil {
+                return "", err
         }
-
-        repoName := extractRepoName(repoURL)
-        workDir := filepath.Join(".", repoName)
-
-        currentDir, _ := os.Getwd()
-        logger.Info("Starting repository operations", 
-                zap.String("current_dir", currentDir),
-                zap.String("repo_name", repoName),
+        log.Info("Starting repository operations",
+                zap.String("repo_name", extractRepoName(repoURL)),
                 zap.String("target_work_dir", workDir))
 
-        fmt.Printf("📦 Cloning repository...\n")
-        logger.Info("Cloning repository", zap.String("repo_url", repoURL))
-        if err := runGitCommand("clone", repoURL); err != nil {
-                return fmt.Errorf("failed to clone repository: %w", err)
+        progressf("   cloning %s into %s\n", redact.String(repoURL), workDir)
+        log.Info("Cloning repository", zap.String("repo_url", repoURL))
+        rb.cloneDir = workDir
+        if err := cloneRepository(ctx, log, repoURL, workDir, branchName); err != nil {
+                return "", fmt.Errorf("failed to clone repository: %w", err)
         }
-
-        logger.Info("Changing to repository directory", zap.String("work_dir", workDir))
-        if err := os.Chdir(workDir); err != nil {
-                return fmt.Errorf("failed to change directory: %w", err)
+        if err := applySparsePaths(ctx, log, workDir); err != nil {
+                return "", err
         }
-        
-        newDir, _ := os.Getwd()
-        logger.Info("Successfully changed directory", zap.String("new_dir", newDir))
 
-        branchName := issue.BranchName
-        if branchName == "" {
-                branchName = fmt.Sprintf("feature/%s", strings.ToLower(strings.ReplaceAll(issueID, "-", "_")))
+        progressf("   creating branch %s\n", branchName)
+        log.Info("Creating feature branch", zap.String("branch_name", branchName))
+        checkoutArgs := []string{"checkout", "-b", branchName}
+        if base := runBaseBranch(); base != "" {
+                checkoutArgs = append(checkoutArgs, "origin/"+base)
         }
-
-        fmt.Printf("🌿 Creating branch: %s\n", branchName)
-        logger.Info("Creating feature branch", zap.String("branch_name", branchName))
-        if err := runGitCommand("checkout", "-b", branchName); err != nil {
-                return fmt.Errorf("failed to create branch: %w", err)
+        if err := runGitCommand(ctx, log, workDir, checkoutArgs...); err != nil {
+                return "", fmt.Errorf("failed to create branch: %w", err)
         }
+        return workDir, nil
+}
 
-        fmt.Printf("🤖 Running Codex CLI...\n")
-        logger.Info("Running Codex CLI", zap.String("description", issue.Description))
-        codexPrompt := fmt.Sprintf("%s\n\n%s", issue.Title, issue.Description)
-        if err := runCodex(codexPrompt, openaiAPIKey); err != nil {
-                return fmt.Errorf("failed to run Codex: %w", err)
+// commitChanges stages and commits everything the agent changed in the workspace dir and
+// returns the committed files.
+func commitChanges(ctx context.Context, log *zap.Logger, dir string, issue *issues.Issue) ([]string, error) {
+        log.Info("Checking git status before staging")
+        if err := runGitCommand(ctx, log, dir, "status", "--porcelain"); err != nil {
+                log.Warn("Failed to check git status", zap.Error(err))
         }
-
-        fmt.Printf("📝 Committing and pushing changes...\n")
         
-        logger.Info("Checking git status before staging")
-        if err := runGitCommand("status", "--porcelain"); err != nil {
-                logger.Warn("Failed to check git status", zap.Error(err))
+        log.Info("Staging changes")
+        if err := runGitCommand(ctx, log, dir, "add", "."); err != nil {
+                return nil, fmt.Errorf("failed to stage changes: %w", err)
         }
         
-        logger.Info("Staging changes")
-        if err := runGitCommand("add", "."); err != nil {
-                return fmt.Errorf("failed to stage changes: %w", err)
+        log.Info("Checking staged changes")
+        files, err := stagedFiles(dir)
+        if err != nil {
+                log.Warn("Failed to check staged changes", zap.Error(err))
         }
-        
-        logger.Info("Checking staged changes")
-        if err := runGitCommand("diff", "--cached", "--name-only"); err != nil {
-                logger.Warn("Failed to check staged changes", zap.Error(err))
+        log.Info("Staged changes", zap.Strings("files", files))
+        if err == nil && len(files) == 0 {
+                return nil, errNothingToCommit
         }
 
-        commitMsg := fmt.Sprintf("feat: %s\n\n%s\n\nLinear Issue: %s", issue.Title, issue.Description, issue.URL)
-        logger.Info("Committing changes", zap.String("commit_message", commitMsg))
-        if err := runGitCommand("commit", "-m", commitMsg); err != nil {
-                return fmt.Errorf("failed to commit changes: %w", err)
+        commitMsg := commitMessage(issue)
+        args, err := commitArgs(commitMsg)
+        if err != nil {
+                return nil, err
+        }
+        commitCtx, err := commitContext(ctx)
+        if err != nil {
+                return nil, err
         }
+        log.Info("Committing changes", zap.String("commit_message", commitMsg))
+        if err := runGitCommand(commitCtx, log, dir, args...); err != nil {
+                return nil, fmt.Errorf("failed to commit changes: %w", err)
+        }
+        return files, nil
+}
+
+// commitMessage returns the message of the commit of the changes made for issue.
+func commitMessage(issue *issues.Issue) string {
+        return fmt.Sprintf("%s\n\n%s\n\n%s", commitSubject(issue), issue.Description, issueReference(issue))
+}
 
-        logger.Info("Pushing branch to origin")
-        if err := runGitCommand("push", "--set-upstream", "origin", branchName); err != nil {
-                return fmt.Errorf("failed to push branch: %w", err)
+// saveDiff writes the patch of the commit at HEAD of the repository in dir, with credentials
+// redacted, to path.
+func saveDiff(dir, path string) error {
+        out, err := gitCommand(dir, "show", "--format=fuller", "--patch", "HEAD").Output()
+        if err != nil {
+                return err
         }
+        return os.WriteFile(path, []byte(redact.String(string(out))), 0o644)
+}
 
-        fmt.Printf("🚀 Creating pull request...\n")
-        logger.Info("Creating pull request")
-        if err := createPullRequest(issue, githubToken); err != nil {
-                return fmt.Errorf("failed to create pull request: %w", err)
+// committedFiles lists the files changed by the commit at HEAD of the repository in dir.
+func committedFiles(dir string) ([]string, error) {
+        out, err := gitCommand(dir, "show", "--name-only", "--format=", "HEAD").Output()
+        if err != nil {
+                return nil, err
+        }
+        var files []string
+        for _, line := range strings.Split(string(out), "\n") {
+                if line != "" {
+                        files = append(files, line)
+                }
         }
+        return files, nil
+}
 
-        fmt.Printf("✅ Monday workflow completed successfully!\n")
-        logger.Info("Monday workflow completed successfully")
-        return nil
+// stagedFiles lists the files staged in the repository in dir.
+func stagedFiles(dir string) ([]string, error) {
+        out, err := gitCommand(dir, "diff", "--cached", "--name-only").Output()
+        if err != nil {
+                return nil, err
+        }
+        var files []string
+        for _, line := range strings.Split(string(out), "\n") {
+                if line != "" {
+                        files = append(files, line)
+                }
+        }
+        return files, nil
 }
 
-// runMondayWorkflow is the CLI command handler that delegates to runWorkflow.
+// runMondayWorkflow is the CLI command handler that delegates to runIssue, or to
+// runIssueBatch when several issues are selected.
 func runMondayWorkflow(cmd *cobra.Command, args []string) error {
-        issueID := args[0]
-        return runWorkflow(issueID, repoURL)
+        if continueRunID == "" && (len(args) != 1 || hasIssueFilter()) {
+                return runIssueBatch(cmd, args)
+        }
+
+        if len(repoURLs) > 0 {
+                repoURL = repoURLs[0]
+        }
+        if continueRunID == "" && len(repoURLs) > 1 {
+                return runMultiRepo(cmd, args[0])
+        }
+
+        opts := workflowOptions("", "", repoURL)
+        if continueRunID != "" {
+                cp, err := loadContinuedRun(continueRunID, processAlive)
+                if err != nil {
+                        return withExitCode(exitConfig, err)
+                }
+                opts.IssueID, opts.RepoURL, opts.LocalRepo, opts.Continue = cp.IssueID, cp.RepoURL, cp.LocalRepo, continueRunID
+        } else {
+                opts.IssueID = args[0]
+        }
+        return runIssue(cmd, opts)
+}
+
+// runIssue runs the workflow with opts in the foreground, logging to the terminal. On macOS, it
+// also raises a desktop notification when a long run finishes unless --no-desktop-notify is set.
+// With --output json, the run summary is printed as the result; with -q, progress lines are
+// dropped and only the pull request URL is printed.
+func runIssue(cmd *cobra.Command, opts workflow.Options) error {
+        if runtime.GOOS == "darwin" && !noDesktopNotify {
+                notifier, err := newDesktopNotifier()
+                if err != nil {
+                        return err
+                }
+                desktopNotifier = notifier
+        }
+        if needsApproval, _ := approvalRequired(); needsApproval && isTerminal(os.Stdin) {
+                approvalInput = os.Stdin
+        }
+        if outputLevel() == levelQuiet {
+                restore, err := silenceStdout()
+                if err != nil {
+                        return err
+                }
+                defer restore()
+        }
+        if spinnersEnabled() {
+                spinner = newStageSpinner(os.Stdout)
+        }
+        opts.Log = newLogger()
+        sum, err := workflow.New(opts).Run(cmd.Context())
+        switch {
+        case jsonOutput() && sum != nil:
+                if writeErr := writeJSON(sum); writeErr != nil && err == nil {
+                        err = writeErr
+                }
+        case outputLevel() == levelQuiet && sum != nil && sum.PRURL != "":
+                fmt.Fprintln(resultOut, sum.PRURL)
+        }
+        return err
 }
 
-// extractIssueID parses the input string to extract a Linear issue ID, handling both direct IDs and Linear issue URLs.
+// extractIssueID parses the input string to extract an issue ID, handling direct IDs, Linear issue URLs, and Jira
+// browse URLs.
 func extractIssueID(input string) string {
         if strings.Contains(input, "linear.app") {
                 parts := strings.Split(input, "/")
@@ -158,6 +256,10 @@ func extractIssueID(input string) string {
                         }
                 }
         }
+        if _, key, ok := strings.Cut(input, "/browse/"); ok {
+                key, _, _ = strings.Cut(key, "?")
+                return strings.TrimSuffix(key, "/")
+        }
         return input
 }
 
@@ -168,74 +270,273 @@ func extractRepoName(repoURL string) string {
         return strings.TrimSuffix(repoName, ".git")
 }
 
-// runGitCommand executes a git command with the specified arguments, logging its execution and output based on the verbosity setting.
+// cloneRepository clones repoURL into dest, going through the shared bare mirror cache
+// unless --no-mirror is set so repeated runs only download new objects. With
+// --reference-clone the remote is cloned using the mirror as an object reference.
+// Unless --full-fetch is set, only the remote's default branch and branch are fetched, and
+// --clone-depth, --single-branch, and --sparse-paths narrow the clone further.
+func cloneRepository(ctx context.Context, log *zap.Logger, repoURL, dest, branch string) error {
+        opts := gitops.CloneOptions{
+                Reference:  referenceClone,
+                Dissociate: dissociateClone,
+                Scope:      gitops.FetchScope{Filter: cloneFilter, Depth: cloneDepth},
+                Sparse:     len(sparsePaths) > 0,
+        }
+        if !fullFetch {
+                base := runBaseBranch()
+                if base == "" {
+                        var err error
+                        if base, err = gitops.RemoteDefaultBranch(ctx, "", repoURL); err != nil {
+                                return err
+                        }
+                }
+                opts.Scope = runFetchScope(base, branch)
+                log.Info("Limiting fetch to run branches", zap.Strings("branches", opts.Scope.Branches))
+        }
+
+        if runContainer(ctx) != nil {
+                // The mirror cache is on the host, out of the container's reach.
+                args := append([]string{"clone"}, containerCloneArgs()...)
+                return runGitCommand(ctx, log, dest, append(args, repoURL, ".")...)
+        }
+        if noMirror {
+                return gitops.CloneRemote(ctx, repoURL, dest, opts)
+        }
+
+        cache, err := getMirrorCache()
+        if err != nil {
+                return err
+        }
+
+        log.Info("Cloning through mirror cache",
+                zap.String("repo_url", repoURL),
+                zap.String("mirror_path", cache.MirrorPath(repoURL)))
+        return cache.Clone(ctx, repoURL, dest, opts)
+}
+
+// createIssueWorktree fetches the base branch into the local clone at repoPath and creates the
+// per-issue worktree for branchName from origin/<base>, without touching the user's checkout.
+// Whatever it creates is recorded in rb.
+func createIssueWorktree(ctx context.Context, log *zap.Logger, repoPath, issueID, branchName string, rb *rollback) (string, error) {
+        base := runBaseBranch()
+        if base == "" {
+                base = gitops.DefaultBranch(ctx, repoPath)
+        }
+        progressf("   fetching origin/%s\n", base)
+        log.Info("Preparing local repository",
+                zap.String("local_repo", repoPath),
+                zap.String("base_branch", base))
+        if err := gitops.PrepareRepository(ctx, repoPath, base, branchName); err != nil {
+                return "", fmt.Errorf("failed to prepare repository: %w", err)
+        }
+        return addIssueWorktree(ctx, log, repoPath, issueID, branchName, base, rb)
+}
+
+// createCachedWorktree updates the bare clone of repoURL kept in the mirror cache and creates
+// the per-issue worktree for branchName from origin/<base> in it, so repeated runs against a
+// repository only fetch the run's branches instead of cloning it again. Whatever it creates is
+// recorded in rb.
+func createCachedWorktree(ctx context.Context, log *zap.Logger, repoURL, issueID, branchName string, rb *rollback) (string, error) {
+        cache, err := getMirrorCache()
+        if err != nil {
+                return "", err
+        }
+        base := runBaseBranch()
+        if base == "" {
+                if base, err = gitops.RemoteDefaultBranch(ctx, "", repoURL); err != nil {
+                        return "", err
+                }
+        }
+
+        progressf("   fetching origin/%s into %s\n", base, cache.WorktreeRepoPath(repoURL))
+        log.Info("Updating cached repository",
+                zap.String("repo_url", repoURL),
+                zap.String("repo_path", cache.WorktreeRepoPath(repoURL)),
+                zap.String("base_branch", base))
+        repoPath, err := cache.UpdateWorktreeRepo(ctx, repoURL, runFetchScope(base, branchName))
+        if err != nil {
+                return "", fmt.Errorf("failed to prepare repository: %w", err)
+        }
+        return addIssueWorktree(ctx, log, repoPath, issueID, branchName, base, rb)
+}
+
+// addIssueWorktree creates the per-issue worktree for branchName of the repository at repoPath,
+// starting a new branch from origin/<base>, or reuses the one a previous run left behind.
+// Whatever it creates is recorded in rb.
+func addIssueWorktree(ctx context.Context, log *zap.Logger, repoPath, issueID, branchName, base string, rb *rollback) (string, error) {
+        root, err := resolveWorktreeRoot()
+        if err != nil {
+                return "", err
+        }
+        absRepo, err := filepath.Abs(repoPath)
+        if err != nil {
+                return "", fmt.Errorf("failed to resolve repository path: %w", err)
+        }
+        expected := gitops.WorktreePath(root, filepath.Base(absRepo), issueID)
+        _, statErr := os.Stat(expected)
+        worktreeExisted := statErr == nil
+        branchExisted := gitops.BranchExists(ctx, repoPath, branchName)
+
+        progressf("   creating worktree for branch %s\n", branchName)
+        workDir, err := gitops.CreateWorktreeForIssue(ctx, repoPath, root, issueID, branchName, base)
+        if err != nil {
+                return "", err
+        }
+
+        rb.repoPath = absRepo
+        rb.branchCreated = !branchExisted
+        if !worktreeExisted {
+                rb.worktree = workDir
+        }
+        if err := applySparsePaths(ctx, log, workDir); err != nil {
+                return "", err
+        }
+        log.Info("Worktree ready",
+                zap.String("branch_name", branchName),
+                zap.String("work_dir", workDir))
+        return workDir, nil
+}
+
+// getMirrorCache returns the process-wide mirror cache, creating it on first use so that
+// concurrent server runs share the same per-mirror locks.
+func getMirrorCache() (*gitops.MirrorCache, error) {
+        mirrorCacheOnce.Do(func() {
+                dir := cacheDir
+                if dir == "" {
+                        dir, mirrorCacheErr = gitops.DefaultCacheDir()
+                        if mirrorCacheErr != nil {
+                                return
+                        }
+                }
+                mirrorCache = gitops.NewMirrorCache(dir)
+        })
+        return mirrorCache, mirrorCacheErr
+}
+
+// runGitCommand executes a git command with the specified arguments in the repository in wd, logging its execution and output based on the verbosity setting.
+// Output shown on the terminal has credentials redacted.
 // Returns an error if the git command fails.
-func runGitCommand(args ...string) error {
-        wd, _ := os.Getwd()
-        logger.Info("Running git command", 
+func runGitCommand(ctx context.Context, log *zap.Logger, wd string, args ...string) error {
+        log.Info("Running git command", 
                 zap.Strings("args", args),
                 zap.String("working_dir", wd))
         
-        cmd := exec.Command("git", args...)
+        cmd := newCommand(ctx, wd, gitEnv(ctx), "git", args...)
         
-        if verbose {
-                cmd.Stdout = os.Stdout
-                cmd.Stderr = os.Stderr
-        } else {
-                cmd.Stdout = nil
-                cmd.Stderr = os.Stderr
-        }
-        
-        err := cmd.Run()
+        err := runWithRedactedOutput(cmd, showChildStdout(), showChildStderr())
         if err != nil {
-                logger.Error("Git command failed", 
+                log.Error("Git command failed", 
                         zap.Strings("args", args),
                         zap.String("working_dir", wd),
                         zap.Error(err))
         } else {
-                logger.Info("Git command completed successfully", zap.Strings("args", args))
+                log.Info("Git command completed successfully", zap.Strings("args", args))
         }
         
         return err
 }
 
+// runWithRedactedOutput runs cmd, forwarding its stdout and stderr to the terminal when
+// requested with any credentials masked, and discarding them otherwise. Stdout and stderr
+// writers already set on cmd keep receiving the output as well. Hidden stderr output is added,
+// redacted, to the error of a failed command.
+func runWithRedactedOutput(cmd *exec.Cmd, showStdout, showStderr bool) error {
+        var writers []*redact.Writer
+        if showStdout {
+                w := redact.NewWriter(stageOut())
+                if cmd.Stdout != nil {
+                        cmd.Stdout = io.MultiWriter(cmd.Stdout, w)
+                } else {
+                        cmd.Stdout = w
+                }
+                writers = append(writers, w)
+        }
+        var stderr bytes.Buffer
+        var stderrOut io.Writer = &stderr
+        if showStderr {
+                w := redact.NewWriter(stageErr())
+                stderrOut = w
+                writers = append(writers, w)
+        }
+        if cmd.Stderr != nil {
+                cmd.Stderr = io.MultiWriter(cmd.Stderr, stderrOut)
+        } else {
+                cmd.Stderr = stderrOut
+        }
+
+        err := cmd.Run()
+        for _, w := range writers {
+                w.Flush()
+        }
+        if err != nil && stderr.Len() > 0 {
+                return fmt.Errorf("%w: %s", err, redact.String(lastLines(stderr.String(), 5)))
+        }
+        return err
+}
+
+// lastLines returns the last n non-empty lines of s, joined by "; ".
+func lastLines(s string, n int) string {
+        var lines []string
+        for _, line := range strings.Split(s, "\n") {
+                if line = strings.TrimSpace(line); line != "" {
+                        lines = append(lines, line)
+                }
+        }
+        if len(lines) > n {
+                lines = lines[len(lines)-n:]
+        }
+        return strings.Join(lines, "; ")
+}
+
 // runCodex executes the Codex CLI tool with the provided prompt and OpenAI API key.
-// The function sets the approval mode to "full-auto" and controls output visibility based on the verbose flag.
+// The function sets the approval mode to "full-auto" and controls output visibility based on -v and -q:
+// with -vv, Codex's full output is shown; otherwise Codex is asked for its JSON event stream and
+// one short progress line is printed per command, test run, or edited file, or none with -q.
+// Either way, the redacted stdout and stderr are appended, as they come, to the agent log of the
+// run with its files in runDir. The token usage and cost Codex reports in its event stream are
+// returned; -vv runs report none.
+// Codex works in the workspace workDir.
 // Returns an error if the Codex command fails to execute.
-func runCodex(prompt, apiKey string) error {
-        cmd := exec.Command("codex", "--approval-mode", "full-auto", "-q", prompt)
-        cmd.Env = append(os.Environ(), fmt.Sprintf("OPENAI_API_KEY=%s", apiKey))
+func runCodex(ctx context.Context, log *zap.Logger, workDir, prompt, apiKey, runDir string) (progress.Usage, error) {
+        cmd := newCommand(ctx, workDir, []string{fmt.Sprintf("OPENAI_API_KEY=%s", apiKey)}, "codex", codexArgs(prompt)...)
         
-        if verbose {
-                cmd.Stdout = os.Stdout
-                cmd.Stderr = os.Stderr
-        } else {
-                cmd.Stdout = nil
-                cmd.Stderr = nil
+        agentOut, err := openAgentLog(runDir)
+        if err != nil {
+                return progress.Usage{}, fmt.Errorf("failed to create transcript: %w", err)
         }
-        
-        logger.Debug("Running Codex", zap.String("prompt", prompt))
-        return cmd.Run()
+        defer agentOut.Close()
+        transcript := redact.NewWriter(agentOut)
+        defer transcript.Flush()
+        // Stderr has a writer of its own so its lines do not end up in the middle of stdout's.
+        transcriptErr := redact.NewWriter(agentOut)
+        defer transcriptErr.Flush()
+        cmd.Stderr = transcriptErr
+
+        log.Debug("Running Codex", zap.String("prompt", prompt))
+        if showAgentOutput() {
+                cmd.Stdout = transcript
+                return progress.Usage{}, runWithRedactedOutput(cmd, true, true)
+        }
+
+        var display io.Writer = io.Discard
+        if showProgress() {
+                display = stageOut()
+        }
+        stdout := redact.NewWriter(display)
+        events := progress.NewWriter(stdout)
+        cmd.Stdout = io.MultiWriter(events, transcript)
+        err = runWithRedactedOutput(cmd, false, false)
+        events.Flush()
+        stdout.Flush()
+        return events.Usage(), err
 }
 
-// createPullRequest creates a GitHub pull request using the provided Linear issue details and authentication token.
-// The pull request title and body are generated from the issue's title, description, and URL.
-// Returns an error if the pull request creation fails.
-func createPullRequest(issue *linear.IssueDetails, token string) error {
-        prTitle := fmt.Sprintf("feat: %s", issue.Title)
-        prBody := fmt.Sprintf("%s\n\nLinear Issue: %s", issue.Description, issue.URL)
-        
-        cmd := exec.Command("gh", "pr", "create", "--title", prTitle, "--body", prBody)
-        cmd.Env = append(os.Environ(), fmt.Sprintf("GITHUB_TOKEN=%s", token))
-        
-        if verbose {
-                cmd.Stdout = os.Stdout
-                cmd.Stderr = os.Stderr
-        } else {
-                cmd.Stdout = nil
-                cmd.Stderr = os.Stderr
+// codexArgs returns the arguments of the Codex CLI run for prompt.
+func codexArgs(prompt string) []string {
+        args := []string{"--approval-mode", "full-auto", "-q"}
+        if !showAgentOutput() {
+                args = append(args, "--json")
         }
-        
-        logger.Info("Creating PR", zap.String("title", prTitle))
-        return cmd.Run()
+        return append(args, prompt)
 }
+
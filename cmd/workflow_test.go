@@ -1,6 +1,24 @@
 package cmd
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"monday/gitops"
+	"monday/linear"
+	"monday/summary"
+	"monday/workflow"
+)
 
 func TestExtractIssueID(t *testing.T) {
 	tests := []struct {
@@ -28,6 +46,11 @@ func TestExtractIssueID(t *testing.T) {
 			input:    "BACKEND-123",
 			expected: "BACKEND-123",
 		},
+		{
+			name:     "Jira browse URL",
+			input:    "https://acme.atlassian.net/browse/PROJ-42?focusedCommentId=1",
+			expected: "PROJ-42",
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,3 +100,128 @@ func TestExtractRepoName(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequestURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "URL only",
+			output:   "https://github.com/owner/repo/pull/42\n",
+			expected: "https://github.com/owner/repo/pull/42",
+		},
+		{
+			name:     "URL after progress output",
+			output:   "Creating pull request for feature/del_163 into main in owner/repo\n\nhttps://github.com/owner/repo/pull/7\n",
+			expected: "https://github.com/owner/repo/pull/7",
+		},
+		{
+			name:     "no URL",
+			output:   "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := pullRequestURL(tt.output)
+			if result != tt.expected {
+				t.Errorf("pullRequestURL(%q) = %q, want %q", tt.output, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunWithRedactedOutputReportsHiddenStderr(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "echo first >&2; echo; echo 'fatal: bad ref' >&2; exit 3")
+	err := runWithRedactedOutput(cmd, false, false)
+	if err == nil {
+		t.Fatal("runWithRedactedOutput() succeeded for a failing command")
+	}
+	if !strings.HasSuffix(err.Error(), "exit status 3: first; fatal: bad ref") {
+		t.Errorf("runWithRedactedOutput() error = %q, want the hidden stderr", err)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	if got := lastLines("a\n\nb\nc\n", 2); got != "b; c" {
+		t.Errorf("lastLines() = %q, want %q", got, "b; c")
+	}
+}
+
+func TestCommitChangesWithoutChanges(t *testing.T) {
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+
+	_, err := commitChanges(context.Background(), zap.NewNop(), repo, &linear.IssueDetails{Title: "Add login"})
+	if !errors.Is(err, errNothingToCommit) {
+		t.Errorf("commitChanges() error = %v, want %v", err, errNothingToCommit)
+	}
+}
+
+func TestPrepareWorkspaceWorktreeMode(t *testing.T) {
+	origin := t.TempDir()
+	git(t, origin, "init", "-q", "-b", "main")
+	git(t, origin, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init")
+
+	origRoot, origCache := worktreeRoot, cacheDir
+	t.Cleanup(func() {
+		worktreeRoot, cacheDir = origRoot, origCache
+		mirrorCacheOnce, mirrorCache = sync.Once{}, nil
+	})
+	worktreeRoot, cacheDir = t.TempDir(), t.TempDir()
+	mirrorCacheOnce, mirrorCache = sync.Once{}, nil
+
+	for _, issueID := range []string{"DEL-1", "DEL-2"} {
+		rb := &rollback{log: zap.NewNop()}
+		branch := "feature/" + strings.ToLower(issueID)
+		dir, err := prepareWorkspace(context.Background(), zap.NewNop(), workflow.Options{RepoURL: origin, WorktreeMode: true}, issueID, "run-"+issueID, branch, rb)
+		if err != nil {
+			t.Fatalf("prepareWorkspace(%s) error = %v", issueID, err)
+		}
+		if want := gitops.WorktreePath(worktreeRoot, filepath.Base(origin), issueID); dir != want {
+			t.Errorf("prepareWorkspace(%s) = %s, want %s", issueID, dir, want)
+		}
+		if rb.cloneDir != "" || rb.worktree != dir || !rb.branchCreated {
+			t.Errorf("rollback = %+v, want the worktree and branch of %s", rb, issueID)
+		}
+		if _, err := os.Stat(filepath.Join(rb.repoPath, "HEAD")); err != nil {
+			t.Errorf("cached repository %s: %v", rb.repoPath, err)
+		}
+	}
+}
+
+func TestWorkflowKeepsPlainFailure(t *testing.T) {
+	jiraServer := httptest.NewServer(http.NotFoundHandler())
+	defer jiraServer.Close()
+	t.Setenv("MONDAY_HOME", t.TempDir())
+	t.Setenv("MONDAY_ISSUE_PROVIDER", "jira")
+	t.Setenv("JIRA_URL", jiraServer.URL)
+	t.Setenv("JIRA_API_TOKEN", "jira-token")
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	// The failure comes after the timeouts are set up, which must not turn it into a cancellation.
+	t.Setenv("MONDAY_TOTAL_TIMEOUT", "1h")
+	t.Setenv("MONDAY_STEP_TIMEOUT", "30m")
+
+	sum, err := workflow.New(workflowOptions("", "DEL-1", "https://github.com/acme/app")).Run(context.Background())
+	if err == nil || errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "failed to fetch issue details") {
+		t.Fatalf("Run() error = %v, want the failure to fetch the issue", err)
+	}
+	if code := exitCodeFor(err); code == exitCanceled || code == exitTimedOut {
+		t.Errorf("exit code = %d, want the code of the failure", code)
+	}
+	if sum == nil || sum.Status != summary.StatusFailed {
+		t.Fatalf("summary = %+v, want status %s", sum, summary.StatusFailed)
+	}
+	db, err := openRunStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if run, err := db.Get(sum.RunID); err != nil || run.Status != summary.StatusFailed || run.FinishedAt.IsZero() || filepath.Base(run.LogPath) != "run.log" {
+		t.Errorf("recorded run = %+v, %v, want it finished as failed with its log", run, err)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// workspaceRoot is the directory the clones of runs are made in.
+	workspaceRoot string
+	// keepWorkspace keeps the clone of a successful run instead of removing it.
+	keepWorkspace bool
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&workspaceRoot, "workspace-root", "", "Directory each run clones the repository into a directory of its own under (default: $MONDAY_WORKSPACE_ROOT or monday in the system temp directory)")
+	rootCmd.Flags().BoolVar(&keepWorkspace, "keep-workspace", false, "Keep the clone of a successful run instead of removing it")
+}
+
+// resolveWorkspaceRoot returns the workspace root from the flag, the environment, or the default location.
+func resolveWorkspaceRoot() (string, error) {
+	root := workspaceRoot
+	if root == "" {
+		root = os.Getenv("MONDAY_WORKSPACE_ROOT")
+	}
+	if root == "" {
+		root = filepath.Join(os.TempDir(), "monday")
+	}
+	return filepath.Abs(root)
+}
+
+// runWorkspace returns the absolute path of the directory the run called runID clones its
+// repository into, creating the workspace root if needed.
+func runWorkspace(runID string) (string, error) {
+	root, err := resolveWorkspaceRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve workspace root: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create workspace root: %w", err)
+	}
+	return filepath.Join(root, runID), nil
+}
+
+// removeWorkspace removes the clone a successful run worked in, unless --keep-workspace is
+// set. Worktrees of --local-repo are kept for monday worktrees to manage; dir is empty then.
+func removeWorkspace(log *zap.Logger, dir string) {
+	if dir == "" || keepWorkspace {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		log.Warn("Failed to remove workspace", zap.String("work_dir", dir), zap.Error(err))
+		return
+	}
+	log.Info("Removed workspace", zap.String("work_dir", dir))
+}
+
+// gitCommand returns the git command with args run in the repository in dir.
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return cmd
+}
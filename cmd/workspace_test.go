@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRunWorkspace(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "workspaces")
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{name: "flag", flag: root, env: "/elsewhere", want: filepath.Join(root, "run-1")},
+		{name: "environment", env: root, want: filepath.Join(root, "run-1")},
+		{name: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := workspaceRoot
+			t.Cleanup(func() { workspaceRoot = orig })
+			workspaceRoot = tt.flag
+			t.Setenv("MONDAY_WORKSPACE_ROOT", tt.env)
+			t.Setenv("TMPDIR", t.TempDir())
+			if tt.want == "" {
+				tt.want = filepath.Join(os.TempDir(), "monday", "run-1")
+			}
+
+			got, err := runWorkspace("run-1")
+			if err != nil {
+				t.Fatalf("runWorkspace() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("runWorkspace() = %q, want %q", got, tt.want)
+			}
+			if _, err := os.Stat(filepath.Dir(got)); err != nil {
+				t.Errorf("workspace root not created: %v", err)
+			}
+		})
+	}
+}
+
+func TestRemoveWorkspace(t *testing.T) {
+	tests := []struct {
+		name     string
+		keep     bool
+		wantGone bool
+	}{
+		{name: "removed", wantGone: true},
+		{name: "kept", keep: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := keepWorkspace
+			t.Cleanup(func() { keepWorkspace = orig })
+			keepWorkspace = tt.keep
+			dir := filepath.Join(t.TempDir(), "run-1")
+			if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			removeWorkspace(zap.NewNop(), dir)
+
+			_, err := os.Stat(dir)
+			if gone := os.IsNotExist(err); gone != tt.wantGone {
+				t.Errorf("workspace removed = %v, want %v", gone, tt.wantGone)
+			}
+		})
+	}
+}
+
+func TestRunShellWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "marker"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output, err := runShell(context.Background(), dir, "ls", nil, filepath.Join(t.TempDir(), "shell.log"))
+	if err != nil || output != "marker\n" {
+		t.Errorf("runShell() = %q, %v, want the listing of the workspace", output, err)
+	}
+}
@@ -0,0 +1,521 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"monday/gitops"
+)
+
+var (
+	cleanupQuota      string
+	cleanupDryRun     bool
+	cleanupClosed     bool
+	cleanupArchiveDir string
+	cleanupOrphaned   bool
+	cleanupRepos      []string
+	migrateTo         string
+	migrateDryRun     bool
+)
+
+var worktreesCmd = &cobra.Command{
+	Use:   "worktrees",
+	Short: "Inspect and manage per-issue worktrees",
+}
+
+var worktreesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List worktrees with their disk usage",
+	Args:  cobra.NoArgs,
+	RunE:  runWorktreesList,
+}
+
+var worktreesMigrateCmd = &cobra.Command{
+	Use:   "migrate --to <new-root>",
+	Short: "Move all worktrees to a new worktree root",
+	Long: `Move every worktree under the current worktree root to a new root, preserving the
+<repo>/<issue> layout. Git worktrees are moved with "git worktree move" so their main
+repositories keep tracking them. Point --worktree-root or MONDAY_WORKTREE_ROOT at the new
+root afterwards.`,
+	Args: cobra.NoArgs,
+	RunE: runWorktreesMigrate,
+}
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove finished worktrees and enforce the worktree disk quota",
+	Long: `Remove worktrees that are no longer needed:
+  --closed    removes worktrees whose pull request was merged or closed; branches of
+              PRs closed without merging are archived as git bundles first
+  --quota     removes the oldest worktrees until total disk usage is within quota
+  --orphaned  prunes worktree registrations whose directories vanished and reports
+              directories git does not know about and issue branches with no
+              worktree and no pull request`,
+	Args: cobra.NoArgs,
+	RunE: runCleanup,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&worktreeRoot, "worktree-root", "", "Directory holding per-issue worktrees (default: ~/.monday/worktrees or $MONDAY_WORKTREE_ROOT)")
+
+	worktreesMigrateCmd.Flags().StringVar(&migrateTo, "to", "", "New worktree root (required)")
+	worktreesMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Print the moves without performing them")
+	worktreesMigrateCmd.MarkFlagRequired("to")
+
+	worktreesCmd.AddCommand(worktreesListCmd)
+	worktreesCmd.AddCommand(worktreesMigrateCmd)
+	rootCmd.AddCommand(worktreesCmd)
+
+	cleanupCmd.Flags().StringVar(&cleanupQuota, "quota", "", "Total disk quota for worktrees, e.g. 20GB (default: $MONDAY_WORKTREE_QUOTA)")
+	cleanupCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "Print the worktrees that would be removed without removing them")
+	cleanupCmd.Flags().BoolVar(&cleanupClosed, "closed", false, "Remove worktrees whose pull request was merged or closed")
+	cleanupCmd.Flags().StringVar(&cleanupArchiveDir, "archive-dir", "", "Directory for bundles of closed-unmerged branches (default: ~/.monday/archive)")
+	cleanupCmd.Flags().BoolVar(&cleanupOrphaned, "orphaned", false, "Prune stale worktree registrations and report orphaned directories and branches")
+	cleanupCmd.Flags().StringSliceVar(&cleanupRepos, "repo", nil, "Local repository to check for orphans, in addition to those found under the worktree root (repeatable)")
+	rootCmd.AddCommand(cleanupCmd)
+}
+
+// resolveWorktreeRoot returns the worktree root from the flag, the environment, or the default location.
+func resolveWorktreeRoot() (string, error) {
+	if worktreeRoot != "" {
+		return worktreeRoot, nil
+	}
+	if root := os.Getenv("MONDAY_WORKTREE_ROOT"); root != "" {
+		return root, nil
+	}
+	return gitops.DefaultWorktreeRoot()
+}
+
+// worktreeAction is a change monday cleanup or monday worktrees migrate made, or would make
+// with --dry-run, as reported with --output json.
+type worktreeAction struct {
+	// Action is remove, archive, prune, move, unregistered, or orphaned_branch; the last two
+	// are only reported.
+	Action string `json:"action"`
+	// Path is the worktree or directory acted on.
+	Path string `json:"path,omitempty"`
+	// Dest is where move moved the worktree.
+	Dest string `json:"dest,omitempty"`
+	// Repo is the main repository of prune, unregistered, and orphaned_branch.
+	Repo string `json:"repo,omitempty"`
+	// Branch is the branch of orphaned_branch.
+	Branch string `json:"branch,omitempty"`
+	// Reason is why the worktree is removed: the pull request was merged or closed, or the
+	// worktrees are over quota.
+	Reason string `json:"reason,omitempty"`
+	// Bundle is the git bundle the branch was archived to.
+	Bundle string `json:"bundle,omitempty"`
+	// Size is the disk usage of the worktree in bytes.
+	Size int64 `json:"size,omitempty"`
+	// DryRun is set for actions that were only printed.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Error is why the action failed.
+	Error string `json:"error,omitempty"`
+}
+
+// writeWorktreeActions prints actions as the JSON result of a worktree command.
+func writeWorktreeActions(actions []worktreeAction) error {
+	if actions == nil {
+		actions = []worktreeAction{}
+	}
+	return writeJSON(actions)
+}
+
+func runWorktreesList(cmd *cobra.Command, args []string) error {
+	root, err := resolveWorktreeRoot()
+	if err != nil {
+		return err
+	}
+	worktrees, err := gitops.ListWorktrees(root)
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		if worktrees == nil {
+			worktrees = []gitops.Worktree{}
+		}
+		return writeJSON(worktrees)
+	}
+	if len(worktrees) == 0 {
+		fmt.Printf("No worktrees under %s\n", root)
+		return nil
+	}
+
+	var total int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REPO\tISSUE\tSIZE\tMODIFIED\tPATH")
+	for _, wt := range worktrees {
+		total += wt.Size
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", wt.Repo, wt.Issue, formatSize(wt.Size), wt.ModTime.Format(time.DateTime), wt.Path)
+	}
+	w.Flush()
+	fmt.Printf("\nTotal: %s in %d worktrees\n", formatSize(total), len(worktrees))
+	return nil
+}
+
+func runWorktreesMigrate(cmd *cobra.Command, args []string) error {
+	root, err := resolveWorktreeRoot()
+	if err != nil {
+		return err
+	}
+	newRoot, err := filepath.Abs(migrateTo)
+	if err != nil {
+		return fmt.Errorf("failed to resolve new root: %w", err)
+	}
+	if oldRoot, err := filepath.Abs(root); err == nil && oldRoot == newRoot {
+		return fmt.Errorf("worktrees are already under %s", newRoot)
+	}
+
+	worktrees, err := gitops.ListWorktrees(root)
+	if err != nil {
+		return err
+	}
+
+	log := newLogger()
+	var failed int
+	var actions []worktreeAction
+	for _, wt := range worktrees {
+		dest := gitops.WorktreePath(newRoot, wt.Repo, wt.Issue)
+		action := worktreeAction{Action: "move", Path: wt.Path, Dest: dest, Size: wt.Size, DryRun: migrateDryRun}
+		if migrateDryRun {
+			fmt.Printf("Would move %s -> %s\n", wt.Path, dest)
+			actions = append(actions, action)
+			continue
+		}
+		if err := gitops.MoveWorktree(cmd.Context(), wt.Path, dest); err != nil {
+			failed++
+			fmt.Printf("❌ %v\n", err)
+			log.Error("Failed to migrate worktree", zap.String("path", wt.Path), zap.Error(err))
+			action.Error = err.Error()
+			actions = append(actions, action)
+			continue
+		}
+		fmt.Printf("📁 Moved %s -> %s\n", wt.Path, dest)
+		actions = append(actions, action)
+		// Drop the repository directory once its last worktree has moved.
+		os.Remove(filepath.Dir(wt.Path))
+	}
+
+	if jsonOutput() {
+		if err := writeWorktreeActions(actions); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d worktrees could not be migrated", failed, len(worktrees))
+	}
+	if !migrateDryRun {
+		fmt.Printf("✅ Migrated %d worktrees. Use --worktree-root %s or set MONDAY_WORKTREE_ROOT=%s from now on.\n", len(worktrees), newRoot, newRoot)
+	}
+	return nil
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	quotaStr := cleanupQuota
+	if quotaStr == "" {
+		quotaStr = os.Getenv("MONDAY_WORKTREE_QUOTA")
+	}
+	if quotaStr == "" && !cleanupClosed && !cleanupOrphaned {
+		return fmt.Errorf("--closed, --orphaned, --quota, or MONDAY_WORKTREE_QUOTA is required")
+	}
+
+	root, err := resolveWorktreeRoot()
+	if err != nil {
+		return err
+	}
+	log := newLogger()
+	var actions []worktreeAction
+
+	if cleanupClosed {
+		archiveDir := cleanupArchiveDir
+		if archiveDir == "" {
+			if archiveDir, err = gitops.DefaultArchiveDir(); err != nil {
+				return err
+			}
+		}
+		closed, err := cleanupClosedWorktrees(cmd.Context(), log, root, archiveDir, cleanupDryRun)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, closed...)
+	}
+
+	if cleanupOrphaned {
+		orphans, err := cleanupOrphans(cmd.Context(), log, root, cleanupRepos, cleanupDryRun)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, orphans...)
+	}
+
+	if quotaStr != "" {
+		removed, err := cleanupOverQuota(cmd.Context(), log, root, quotaStr)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, removed...)
+	}
+
+	if jsonOutput() {
+		return writeWorktreeActions(actions)
+	}
+	return nil
+}
+
+// cleanupOverQuota removes the oldest worktrees under root until they fit within quotaStr.
+func cleanupOverQuota(ctx context.Context, log *zap.Logger, root, quotaStr string) ([]worktreeAction, error) {
+	quota, err := parseSize(quotaStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quota: %w", err)
+	}
+	removed, err := enforceWorktreeQuota(ctx, log, root, quota, cleanupDryRun)
+	if err != nil {
+		return nil, err
+	}
+	if len(removed) == 0 {
+		fmt.Printf("✅ Worktrees are within the %s quota\n", formatSize(quota))
+	}
+	actions := make([]worktreeAction, 0, len(removed))
+	for _, wt := range removed {
+		actions = append(actions, worktreeAction{Action: "remove", Path: wt.Path, Reason: "over_quota", Size: wt.Size, DryRun: cleanupDryRun})
+	}
+	return actions, nil
+}
+
+// cleanupClosedWorktrees removes worktrees whose pull request is no longer open. Branches of
+// pull requests that were closed without merging are bundled into archiveDir first so the
+// abandoned work stays recoverable. It returns what it removed and archived.
+func cleanupClosedWorktrees(ctx context.Context, log *zap.Logger, root, archiveDir string, dryRun bool) ([]worktreeAction, error) {
+	worktrees, err := gitops.ListWorktrees(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []worktreeAction
+	for _, wt := range worktrees {
+		state, err := pullRequestState(ctx, wt.Path)
+		if err != nil {
+			log.Warn("Skipping worktree with unknown PR state", zap.String("path", wt.Path), zap.Error(err))
+			continue
+		}
+		if state != "MERGED" && state != "CLOSED" {
+			continue
+		}
+
+		removal := worktreeAction{Action: "remove", Path: wt.Path, Reason: strings.ToLower(state), Size: wt.Size, DryRun: dryRun}
+		if dryRun {
+			fmt.Printf("Would remove %s (PR %s)\n", wt.Path, strings.ToLower(state))
+			actions = append(actions, removal)
+			continue
+		}
+
+		if state == "CLOSED" {
+			bundle, err := gitops.ArchiveBranch(ctx, wt.Path, archiveDir)
+			if err != nil {
+				return actions, err
+			}
+			fmt.Printf("📦 Archived %s to %s\n", wt.Path, bundle)
+			log.Info("Archived closed-unmerged branch", zap.String("path", wt.Path), zap.String("bundle", bundle))
+			actions = append(actions, worktreeAction{Action: "archive", Path: wt.Path, Bundle: bundle})
+		}
+
+		fmt.Printf("🧹 Removing %s (PR %s)\n", wt.Path, strings.ToLower(state))
+		if err := gitops.RemoveWorktree(ctx, wt.Path); err != nil {
+			return actions, err
+		}
+		actions = append(actions, removal)
+	}
+	return actions, nil
+}
+
+// cleanupOrphans prunes worktree registrations whose directories have vanished and reports
+// directories and per-issue branches that no longer belong to any live worktree. The main
+// repositories checked are those owning worktrees under root plus any given explicitly. It
+// returns what it pruned and reported.
+func cleanupOrphans(ctx context.Context, log *zap.Logger, root string, extraRepos []string, dryRun bool) ([]worktreeAction, error) {
+	repos, err := worktreeRepositories(ctx, log, root, extraRepos)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []worktreeAction
+	for _, repo := range repos {
+		orphans, err := gitops.FindOrphans(ctx, repo, root)
+		if err != nil {
+			return actions, err
+		}
+
+		for _, wt := range orphans.Stale {
+			if dryRun {
+				fmt.Printf("Would prune registration of missing worktree %s\n", wt.Path)
+			} else {
+				fmt.Printf("🧹 Pruning registration of missing worktree %s\n", wt.Path)
+			}
+		}
+		if len(orphans.Stale) > 0 && !dryRun {
+			if err := gitops.PruneWorktrees(ctx, repo); err != nil {
+				return actions, err
+			}
+		}
+		for _, wt := range orphans.Stale {
+			actions = append(actions, worktreeAction{Action: "prune", Path: wt.Path, Repo: repo, DryRun: dryRun})
+		}
+
+		for _, dir := range orphans.Unregistered {
+			fmt.Printf("⚠️  %s is not a registered worktree of %s\n", dir, repo)
+			actions = append(actions, worktreeAction{Action: "unregistered", Path: dir, Repo: repo})
+		}
+
+		for _, branch := range orphans.Branches {
+			hasPR, err := branchHasPullRequest(ctx, repo, branch)
+			if err != nil {
+				log.Warn("Failed to look up pull request for branch", zap.String("branch", branch), zap.Error(err))
+				fmt.Printf("⚠️  Branch %s in %s has no worktree (PR status unknown)\n", branch, repo)
+				continue
+			}
+			if !hasPR {
+				fmt.Printf("⚠️  Branch %s in %s has no worktree and no pull request\n", branch, repo)
+				actions = append(actions, worktreeAction{Action: "orphaned_branch", Repo: repo, Branch: branch})
+			}
+		}
+	}
+	return actions, nil
+}
+
+// worktreeRepositories returns the distinct main repositories owning worktrees under root,
+// together with extraRepos.
+func worktreeRepositories(ctx context.Context, log *zap.Logger, root string, extraRepos []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var repos []string
+	add := func(repo string) {
+		if !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+
+	for _, repo := range extraRepos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve repository path: %w", err)
+		}
+		add(abs)
+	}
+
+	worktrees, err := gitops.ListWorktrees(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		repo, err := gitops.MainRepository(ctx, wt.Path)
+		if err != nil {
+			log.Warn("Skipping worktree without a main repository", zap.String("path", wt.Path), zap.Error(err))
+			continue
+		}
+		add(repo)
+	}
+	return repos, nil
+}
+
+// branchHasPullRequest reports whether any pull request, in any state, exists for branch.
+func branchHasPullRequest(ctx context.Context, repo, branch string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "list", "--head", branch, "--state", "all", "--json", "number", "--jq", "length")
+	cmd.Dir = repo
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	return strings.TrimSpace(string(out)) != "0", nil
+}
+
+// pullRequestState returns the GitHub state (OPEN, CLOSED, or MERGED) of the pull request
+// for the branch checked out in dir.
+func pullRequestState(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "view", "--json", "state", "--jq", ".state")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to look up pull request: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// enforceWorktreeQuota removes the oldest worktrees under root until their total size fits
+// within quota and returns the worktrees that were (or, in dry-run mode, would be) removed.
+func enforceWorktreeQuota(ctx context.Context, log *zap.Logger, root string, quota int64, dryRun bool) ([]gitops.Worktree, error) {
+	worktrees, err := gitops.ListWorktrees(root)
+	if err != nil {
+		return nil, err
+	}
+
+	victims := gitops.OverQuota(worktrees, quota)
+	for _, wt := range victims {
+		if dryRun {
+			fmt.Printf("Would remove %s (%s)\n", wt.Path, formatSize(wt.Size))
+			continue
+		}
+		fmt.Printf("🧹 Removing %s (%s)\n", wt.Path, formatSize(wt.Size))
+		log.Info("Removing worktree over quota",
+			zap.String("path", wt.Path),
+			zap.Int64("size", wt.Size),
+			zap.Int64("quota", quota))
+		if err := gitops.RemoveWorktree(ctx, wt.Path); err != nil {
+			return nil, err
+		}
+	}
+	return victims, nil
+}
+
+// sizeUnits maps the accepted size suffixes to their byte multipliers.
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"T", 1 << 40},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// parseSize parses a human-readable size such as "512MB" or "20G" into bytes.
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	factor := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			factor = unit.factor
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("size must be a non-negative number with an optional KB/MB/GB/TB suffix")
+	}
+	return int64(n * float64(factor)), nil
+}
+
+// formatSize renders a byte count using the largest unit that keeps the value at or above one.
+func formatSize(bytes int64) string {
+	for _, unit := range sizeUnits[:4] {
+		if bytes >= unit.factor {
+			return fmt.Sprintf("%.1f %s", float64(bytes)/float64(unit.factor), unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"1024", 1024},
+		{"512B", 512},
+		{"2KB", 2 << 10},
+		{"1.5G", 3 << 29},
+		{"20gb", 20 << 30},
+		{" 3 MB ", 3 << 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseSize(tt.input)
+			if err != nil {
+				t.Fatalf("parseSize(%q) returned error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("parseSize(%q) = %d, want %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSize_Invalid(t *testing.T) {
+	for _, input := range []string{"", "GB", "-1GB", "ten"} {
+		if _, err := parseSize(input); err == nil {
+			t.Errorf("parseSize(%q) expected error", input)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{500, "500 B"},
+		{2048, "2.0 KB"},
+		{3 << 29, "1.5 GB"},
+	}
+
+	for _, tt := range tests {
+		if result := formatSize(tt.input); result != tt.expected {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestCleanupOverQuotaDryRun(t *testing.T) {
+	root := t.TempDir()
+	for i, issue := range []string{"DEL-1", "DEL-2"} {
+		dir := filepath.Join(root, "repo", issue)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "file"), make([]byte, 1024), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(time.Duration(i-2) * time.Hour)
+		if err := os.Chtimes(dir, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cleanupDryRun = true
+	defer func() { cleanupDryRun = false }()
+	actions, err := cleanupOverQuota(context.Background(), zap.NewNop(), root, "1500B")
+	if err != nil {
+		t.Fatalf("cleanupOverQuota() error = %v", err)
+	}
+	want := []worktreeAction{{Action: "remove", Path: filepath.Join(root, "repo", "DEL-1"), Reason: "over_quota", Size: 1024, DryRun: true}}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("cleanupOverQuota() = %+v, want %+v", actions, want)
+	}
+	if _, err := os.Stat(filepath.Join(root, "repo", "DEL-1")); err != nil {
+		t.Errorf("dry run removed the worktree: %v", err)
+	}
+}
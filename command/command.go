@@ -0,0 +1,19 @@
+// Package command runs short-lived external commands, such as osascript and tmux, for the
+// packages that drive other programs.
+package command
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run runs a command, returning its output in the error when it fails.
+func Run(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
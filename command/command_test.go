@@ -0,0 +1,13 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	assert.NoError(t, Run(context.Background(), "sh", "-c", "exit 0"))
+	assert.ErrorContains(t, Run(context.Background(), "sh", "-c", "echo broken >&2; exit 1"), "exit status 1: broken")
+}
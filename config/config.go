@@ -0,0 +1,232 @@
+// Package config reads and writes monday's config file, a flat YAML mapping of settings such
+// as API keys and webhook URLs, and reads profiles, files of the same form with the defaults of
+// a user or repository. Every setting corresponds to an environment variable; values from the
+// files are used for the variables the environment does not set.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Setting describes a key of the config file.
+type Setting struct {
+	// Key is the name of the setting in the config file, e.g. "linear_api_key"
+	Key string
+	// Env is the environment variable the setting provides, e.g. "LINEAR_API_KEY"
+	Env string
+	// Secret marks credentials, which are masked when read back
+	Secret bool
+}
+
+// Settings lists every key the config file accepts. The Vault settings come first, so a Vault
+// token that is itself a reference, e.g. to a file, is resolved before the secrets read with it.
+var Settings = []Setting{
+	{Key: "vault_addr", Env: "VAULT_ADDR"},
+	{Key: "vault_token", Env: "VAULT_TOKEN", Secret: true},
+	{Key: "vault_namespace", Env: "VAULT_NAMESPACE"},
+	{Key: "linear_api_key", Env: "LINEAR_API_KEY", Secret: true},
+	{Key: "linear_access_token", Env: "LINEAR_ACCESS_TOKEN", Secret: true},
+	{Key: "linear_client_id", Env: "LINEAR_CLIENT_ID"},
+	{Key: "linear_client_secret", Env: "LINEAR_CLIENT_SECRET", Secret: true},
+	{Key: "issue_provider", Env: "MONDAY_ISSUE_PROVIDER"},
+	{Key: "jira_url", Env: "JIRA_URL"},
+	{Key: "jira_email", Env: "JIRA_EMAIL"},
+	{Key: "jira_api_token", Env: "JIRA_API_TOKEN", Secret: true},
+	{Key: "github_token", Env: "GITHUB_TOKEN", Secret: true},
+	{Key: "github_app_id", Env: "GITHUB_APP_ID"},
+	{Key: "github_app_private_key", Env: "GITHUB_APP_PRIVATE_KEY", Secret: true},
+	{Key: "github_app_private_key_file", Env: "GITHUB_APP_PRIVATE_KEY_FILE"},
+	{Key: "github_app_installation_id", Env: "GITHUB_APP_INSTALLATION_ID"},
+	{Key: "gitlab_token", Env: "GITLAB_TOKEN", Secret: true},
+	{Key: "gitlab_url", Env: "GITLAB_URL"},
+	{Key: "openai_api_key", Env: "OPENAI_API_KEY", Secret: true},
+	{Key: "anthropic_api_key", Env: "ANTHROPIC_API_KEY", Secret: true},
+	{Key: "server_api_key", Env: "SERVER_API_KEY", Secret: true},
+	{Key: "server_url", Env: "MONDAY_SERVER_URL"},
+	{Key: "linear_webhook_secret", Env: "LINEAR_WEBHOOK_SECRET", Secret: true},
+	{Key: "webhook_label", Env: "MONDAY_WEBHOOK_LABEL"},
+	{Key: "webhook_repo_url", Env: "MONDAY_WEBHOOK_REPO_URL"},
+	{Key: "poll_label", Env: "MONDAY_POLL_LABEL"},
+	{Key: "poll_interval", Env: "MONDAY_POLL_INTERVAL"},
+	{Key: "worktree_root", Env: "MONDAY_WORKTREE_ROOT"},
+	{Key: "worktree_quota", Env: "MONDAY_WORKTREE_QUOTA"},
+	{Key: "terminal", Env: "MONDAY_TERMINAL"},
+	{Key: "terminal_command", Env: "MONDAY_TERMINAL_COMMAND"},
+	{Key: "iterm_split", Env: "MONDAY_ITERM_SPLIT"},
+	{Key: "git_author_name", Env: "MONDAY_GIT_AUTHOR_NAME"},
+	{Key: "git_author_email", Env: "MONDAY_GIT_AUTHOR_EMAIL"},
+	{Key: "git_signing_key", Env: "MONDAY_GIT_SIGNING_KEY"},
+	{Key: "sign_commits", Env: "MONDAY_SIGN_COMMITS"},
+	{Key: "require_approval", Env: "MONDAY_REQUIRE_APPROVAL"},
+	{Key: "pr_labels", Env: "MONDAY_PR_LABELS"},
+	{Key: "pr_reviewers", Env: "MONDAY_PR_REVIEWERS"},
+	{Key: "pr_assignees", Env: "MONDAY_PR_ASSIGNEES"},
+	{Key: "pr_milestone", Env: "MONDAY_PR_MILESTONE"},
+	{Key: "pr_label_map", Env: "MONDAY_PR_LABEL_MAP"},
+	{Key: "pr_summary", Env: "MONDAY_PR_SUMMARY"},
+	{Key: "pr_summary_model", Env: "MONDAY_PR_SUMMARY_MODEL"},
+	{Key: "commit_type", Env: "MONDAY_COMMIT_TYPE"},
+	{Key: "artifact_store", Env: "MONDAY_ARTIFACT_STORE"},
+	{Key: "artifact_retention", Env: "MONDAY_ARTIFACT_RETENTION"},
+	{Key: "aws_access_key_id", Env: "AWS_ACCESS_KEY_ID"},
+	{Key: "aws_secret_access_key", Env: "AWS_SECRET_ACCESS_KEY", Secret: true},
+	{Key: "aws_session_token", Env: "AWS_SESSION_TOKEN", Secret: true},
+	{Key: "aws_region", Env: "AWS_REGION"},
+	{Key: "aws_endpoint_url", Env: "AWS_ENDPOINT_URL"},
+	{Key: "gcs_hmac_access_key", Env: "GCS_HMAC_ACCESS_KEY"},
+	{Key: "gcs_hmac_secret", Env: "GCS_HMAC_SECRET", Secret: true},
+	{Key: "sentry_dsn", Env: "SENTRY_DSN", Secret: true},
+	{Key: "sentry_environment", Env: "SENTRY_ENVIRONMENT"},
+	{Key: "slack_webhook_url", Env: "SLACK_WEBHOOK_URL", Secret: true},
+	{Key: "slack_bot_token", Env: "SLACK_BOT_TOKEN", Secret: true},
+	{Key: "slack_channel", Env: "SLACK_CHANNEL"},
+	{Key: "slack_repo_channels", Env: "SLACK_REPO_CHANNELS"},
+	{Key: "discord_webhook_url", Env: "DISCORD_WEBHOOK_URL", Secret: true},
+	{Key: "teams_webhook_url", Env: "TEAMS_WEBHOOK_URL", Secret: true},
+	{Key: "notify_severity", Env: "MONDAY_NOTIFY_SEVERITY"},
+	{Key: "desktop_notify_after", Env: "MONDAY_DESKTOP_NOTIFY_AFTER"},
+	{Key: "on_failure", Env: "MONDAY_ON_FAILURE"},
+	{Key: "step_timeout", Env: "MONDAY_STEP_TIMEOUT"},
+	{Key: "total_timeout", Env: "MONDAY_TOTAL_TIMEOUT"},
+	{Key: "drain_timeout", Env: "MONDAY_DRAIN_TIMEOUT"},
+	{Key: "max_concurrent_runs", Env: "MONDAY_MAX_CONCURRENT_RUNS"},
+	{Key: "queue_size", Env: "MONDAY_QUEUE_SIZE"},
+	{Key: "dedup_window", Env: "MONDAY_DEDUP_WINDOW"},
+	{Key: "retry_max_attempts", Env: "MONDAY_RETRY_MAX_ATTEMPTS"},
+	{Key: "retry_backoff", Env: "MONDAY_RETRY_BACKOFF"},
+	{Key: "retry_on", Env: "MONDAY_RETRY_ON"},
+	{Key: "linear_max_wait", Env: "MONDAY_LINEAR_MAX_WAIT"},
+	{Key: "workspace_root", Env: "MONDAY_WORKSPACE_ROOT"},
+	{Key: "log_dir", Env: "MONDAY_LOG_DIR"},
+	{Key: "repo_url", Env: "MONDAY_REPO_URL"},
+	{Key: "assignee", Env: "MONDAY_ASSIGNEE"},
+	{Key: "base_branch", Env: "MONDAY_BASE_BRANCH"},
+	{Key: "pr_template", Env: "MONDAY_PR_TEMPLATE"},
+	{Key: "container_image", Env: "MONDAY_CONTAINER_IMAGE"},
+	{Key: "prompt_template", Env: "MONDAY_PROMPT_TEMPLATE"},
+	{Key: "test_command", Env: "MONDAY_TEST_COMMAND"},
+	{Key: "hook_pre_agent", Env: "MONDAY_HOOK_PRE_AGENT"},
+	{Key: "hook_post_agent", Env: "MONDAY_HOOK_POST_AGENT"},
+	{Key: "hook_pre_commit", Env: "MONDAY_HOOK_PRE_COMMIT"},
+	{Key: "hook_post_pr", Env: "MONDAY_HOOK_POST_PR"},
+}
+
+// Lookup returns the setting called key.
+func Lookup(key string) (Setting, error) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown config key %q; valid keys are %s", key, strings.Join(Keys(), ", "))
+}
+
+// Keys returns the names of all settings, sorted.
+func Keys() []string {
+	keys := make([]string, 0, len(Settings))
+	for _, s := range Settings {
+		keys = append(keys, s.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Load reads the config file at path. A missing file is an empty config. Keys that are not
+// settings are returned too, so they can be listed and removed, but have no effect.
+func Load(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := map[string]string{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// LoadFile reads the profile at path. A missing file is an empty profile. Unlike the config
+// file, which monday config edits, profiles are written by hand, so keys that are not settings
+// are rejected rather than ignored.
+func LoadFile(path string) (map[string]string, error) {
+	values, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for key := range values {
+		if _, err := Lookup(key); err != nil {
+			return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+		}
+	}
+	return values, nil
+}
+
+// Save writes values to the config file at path, readable only by the user since it may
+// hold credentials.
+func Save(path string, values map[string]string) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// envReference matches a reference to an environment variable in a value, e.g. ${CI_TOKEN}.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Expand replaces the references to environment variables in value, e.g. ${CI_TOKEN}, with
+// their values; unset variables expand to "".
+func Expand(value string) string {
+	return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		return os.Getenv(ref[2 : len(ref)-1])
+	})
+}
+
+// ApplyEnv sets the environment variable of every setting in values that the environment
+// does not already set, with the references to environment variables in the value expanded.
+// Keys that are not settings are ignored.
+func ApplyEnv(values map[string]string) error {
+	for key, value := range values {
+		setting, err := Lookup(key)
+		if err != nil {
+			continue
+		}
+		if _, ok := os.LookupEnv(setting.Env); ok {
+			continue
+		}
+		if err := os.Setenv(setting.Env, Expand(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Display returns value as it is shown when read back: secrets are masked except for their
+// last four characters.
+func (s Setting) Display(value string) string {
+	if !s.Secret || value == "" {
+		return value
+	}
+	if len(value) < 12 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monday", "config.yaml")
+	values := map[string]string{"linear_api_key": "lin_api_123", "worktree_root": "/tmp/worktrees"}
+
+	require.NoError(t, Save(path, values))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, values, loaded)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "missing file", path: filepath.Join(dir, "missing.yaml"), want: map[string]string{}},
+		{name: "unknown keys", path: write("unknown.yaml", "linear_apikey: x\n"), want: map[string]string{"linear_apikey": "x"}},
+		{name: "not a mapping", path: write("list.yaml", "- linear_api_key\n"), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := Load(test.path)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, values)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".monday-profile.yaml")
+
+	values, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	require.NoError(t, os.WriteFile(path, []byte("repo_url: https://github.com/acme/app\nbase_branch: develop\n"), 0o644))
+	values, err = LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"repo_url": "https://github.com/acme/app", "base_branch": "develop"}, values)
+
+	require.NoError(t, os.WriteFile(path, []byte("base_brnch: develop\n"), 0o644))
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, `unknown config key "base_brnch"`)
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	t.Setenv("LINEAR_API_KEY", "")
+	os.Unsetenv("LINEAR_API_KEY")
+
+	require.NoError(t, ApplyEnv(map[string]string{
+		"github_token":   "from-file",
+		"linear_api_key": "lin_api_123",
+		"unknown":        "ignored",
+	}))
+
+	assert.Equal(t, "from-env", os.Getenv("GITHUB_TOKEN"))
+	assert.Equal(t, "lin_api_123", os.Getenv("LINEAR_API_KEY"))
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("CI_GITHUB_TOKEN", "ghp_ci")
+	t.Setenv("MONDAY_TEST_UNSET", "")
+
+	assert.Equal(t, "ghp_ci", Expand("${CI_GITHUB_TOKEN}"))
+	assert.Equal(t, "token ghp_ci, none ", Expand("token ${CI_GITHUB_TOKEN}, none ${MONDAY_TEST_UNSET}"))
+	assert.Equal(t, "$CI_GITHUB_TOKEN and ${1X}", Expand("$CI_GITHUB_TOKEN and ${1X}"))
+}
+
+func TestLookup(t *testing.T) {
+	setting, err := Lookup("server_url")
+	require.NoError(t, err)
+	assert.Equal(t, "MONDAY_SERVER_URL", setting.Env)
+
+	_, err = Lookup("linear_apikey")
+	assert.ErrorContains(t, err, "valid keys are")
+}
+
+func TestDisplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting Setting
+		value   string
+		want    string
+	}{
+		{name: "plain", setting: Setting{Key: "aws_region"}, value: "eu-west-1", want: "eu-west-1"},
+		{name: "secret", setting: Setting{Key: "github_token", Secret: true}, value: "ghp_abcdefghijklmnop", want: "****mnop"},
+		{name: "short secret", setting: Setting{Key: "github_token", Secret: true}, value: "abc", want: "****"},
+		{name: "empty secret", setting: Setting{Key: "github_token", Secret: true}, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.setting.Display(test.value))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Package describe summarizes the diff of a run for its pull request: a file-by-file account
+// of the changes and notes on how to test them, written by a model of the OpenAI API in one
+// inexpensive call.
+package describe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultModel is the model diffs are summarized with unless another one is given.
+	DefaultModel = "gpt-4o-mini"
+	// DefaultBaseURL is the base URL of the OpenAI API.
+	DefaultBaseURL = "https://api.openai.com/v1"
+)
+
+// MaxDiffBytes is how much of a diff is sent; the rest is cut off, so the file list of the
+// stat at its top is what covers it.
+const MaxDiffBytes = 60000
+
+// systemPrompt tells the model what to write.
+const systemPrompt = `You write the "Changes" section of a pull request description from its diff.
+Reply with GitHub Markdown only, in exactly this form:
+
+## Changes
+- ` + "`path/to/file`" + `: one sentence on what changed in the file and why
+
+## Testing notes
+- how a reviewer can verify the changes, and which tests were added or changed
+
+Cover every changed file. Be factual and brief; do not invent behavior the diff does not show.`
+
+// Describer summarizes diffs with a model of the OpenAI API.
+type Describer struct {
+	// APIKey authenticates with the API
+	APIKey string
+	// Model is the model to use; DefaultModel if empty
+	Model string
+	// BaseURL is the base URL of the API; DefaultBaseURL if empty
+	BaseURL string
+	// Client sends the requests; nil for a client with a 60s timeout
+	Client *http.Client
+}
+
+// Describe returns the Changes and Testing notes sections for diff, the patch of the change
+// made for the issue with the given title. Diffs longer than MaxDiffBytes are cut off.
+func (d *Describer) Describe(ctx context.Context, title, diff string) (string, error) {
+	if len(diff) > MaxDiffBytes {
+		diff = diff[:MaxDiffBytes] + "\n[diff truncated]\n"
+	}
+	reqBody, err := json.Marshal(map[string]any{
+		"model": firstNonEmpty(d.Model, DefaultModel),
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": fmt.Sprintf("Issue: %s\n\nDiff:\n%s", title, diff)},
+		},
+		"temperature": 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+	endpoint := strings.TrimSuffix(firstNonEmpty(d.BaseURL, DefaultBaseURL), "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := d.Client
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize diff: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize diff: %w", err)
+	}
+
+	var out struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to decode diff summary: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if out.Error != nil && out.Error.Message != "" {
+			return "", fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, out.Error.Message)
+		}
+		return "", fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+	}
+	if len(out.Choices) == 0 || strings.TrimSpace(out.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("OpenAI API returned no diff summary")
+	}
+	return normalize(out.Choices[0].Message.Content), nil
+}
+
+// normalize strips a Markdown code fence the model may have wrapped its reply in and makes
+// the reply start with the Changes heading.
+func normalize(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "```") {
+		reply = strings.TrimPrefix(reply, "```markdown")
+		reply = strings.TrimPrefix(reply, "```")
+		reply = strings.TrimSpace(strings.TrimSuffix(reply, "```"))
+	}
+	if !strings.HasPrefix(reply, "## Changes") {
+		reply = "## Changes\n\n" + reply
+	}
+	return reply
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package describe
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		var body struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, DefaultModel, body.Model)
+		require.Len(t, body.Messages, 2)
+		assert.Contains(t, body.Messages[1].Content, "Issue: Fix login")
+		assert.Contains(t, body.Messages[1].Content, "[diff truncated]")
+		assert.Less(t, len(body.Messages[1].Content), MaxDiffBytes+100)
+		w.Write([]byte(`{"choices": [{"message": {"content": "` + "```markdown\\n## Changes\\n- `auth/login.go`: checks the session\\n\\n## Testing notes\\n- run go test\\n```" + `"}}]}`))
+	}))
+	defer server.Close()
+
+	d := &Describer{APIKey: "sk-test", BaseURL: server.URL}
+	got, err := d.Describe(context.Background(), "Fix login", strings.Repeat("+x\n", MaxDiffBytes))
+	require.NoError(t, err)
+	assert.Equal(t, "## Changes\n- `auth/login.go`: checks the session\n\n## Testing notes\n- run go test", got)
+}
+
+func TestDescribeAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"message": "Incorrect API key provided"}}`))
+	}))
+	defer server.Close()
+
+	d := &Describer{APIKey: "sk-test", BaseURL: server.URL}
+	_, err := d.Describe(context.Background(), "Fix login", "diff")
+	assert.ErrorContains(t, err, "status 401: Incorrect API key provided")
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "## Changes\n\n- `a.go`: x", normalize("- `a.go`: x"))
+	assert.Equal(t, "## Changes\n- `a.go`: x", normalize("\n## Changes\n- `a.go`: x\n"))
+}
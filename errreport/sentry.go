@@ -0,0 +1,132 @@
+// Package errreport sends workflow failures to Sentry or a Sentry-compatible service such as
+// GlitchTip. It speaks the store API over plain HTTP, so no SDK is required; events are
+// expected to be redacted by the caller.
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Event describes one failure.
+type Event struct {
+	// Message is the error message
+	Message string
+	// Level is the Sentry level; it defaults to "error"
+	Level string
+	// Tags are indexed, searchable key/value pairs such as issue, repo, and stage
+	Tags map[string]string
+	// Extra carries additional context such as the tail of the run log
+	Extra map[string]any
+	// Stacktrace is an optional stack trace, e.g. of a recovered panic
+	Stacktrace string
+}
+
+// Client sends events to the project identified by a DSN.
+type Client struct {
+	endpoint    string
+	publicKey   string
+	environment string
+	httpClient  *http.Client
+}
+
+// New parses dsn, of the form https://<public-key>@<host>/<project-id>, and returns a client
+// reporting to it. environment, if set, is attached to every event.
+func New(dsn, environment string) (*Client, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid DSN: unsupported scheme %q", u.Scheme)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid DSN: missing public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	projectID := path[slash+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid DSN: missing project ID")
+	}
+	prefix := ""
+	if slash >= 0 {
+		prefix = "/" + path[:slash]
+	}
+
+	return &Client{
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, projectID),
+		publicKey:   u.User.Username(),
+		environment: environment,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Capture sends event and returns the ID it was stored under.
+func (c *Client) Capture(ctx context.Context, event Event) (string, error) {
+	id := newEventID()
+	payload := map[string]any{
+		"event_id":  id,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"platform":  "go",
+		"logger":    "monday",
+		"level":     event.Level,
+		"message":   map[string]string{"formatted": event.Message},
+		"tags":      event.Tags,
+		"extra":     event.Extra,
+	}
+	if event.Level == "" {
+		payload["level"] = "error"
+	}
+	if c.environment != "" {
+		payload["environment"] = c.environment
+	}
+	if event.Stacktrace != "" {
+		extra := map[string]any{"stacktrace": event.Stacktrace}
+		for k, v := range event.Extra {
+			extra[k] = v
+		}
+		payload["extra"] = extra
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=monday/1.0, sentry_key=%s", c.publicKey))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("error reporting returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return id, nil
+}
+
+// newEventID returns a random 32-character hexadecimal event ID.
+func newEventID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
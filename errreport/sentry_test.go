@@ -0,0 +1,77 @@
+package errreport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParsesDSN(t *testing.T) {
+	client, err := New("https://abc123@o1.ingest.sentry.io/42", "production")
+	require.NoError(t, err)
+	assert.Equal(t, "https://o1.ingest.sentry.io/api/42/store/", client.endpoint)
+	assert.Equal(t, "abc123", client.publicKey)
+
+	client, err = New("https://key@glitchtip.example.com/prefix/7", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://glitchtip.example.com/prefix/api/7/store/", client.endpoint)
+}
+
+func TestNewRejectsInvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"", "ftp://key@host/1", "https://host/1", "https://key@host/"} {
+		_, err := New(dsn, "")
+		assert.Error(t, err, dsn)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	var payload map[string]any
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/1/store/", r.URL.Path)
+		auth = r.Header.Get("X-Sentry-Auth")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(strings.Replace(server.URL, "://", "://pubkey@", 1)+"/1", "staging")
+	require.NoError(t, err)
+
+	id, err := client.Capture(context.Background(), Event{
+		Message:    "failed to run Codex",
+		Tags:       map[string]string{"issue": "DEL-163", "stage": "agent"},
+		Extra:      map[string]any{"log_tail": "last lines"},
+		Stacktrace: "goroutine 1",
+	})
+	require.NoError(t, err)
+	assert.Len(t, id, 32)
+	assert.Contains(t, auth, "sentry_key=pubkey")
+	assert.Equal(t, id, payload["event_id"])
+	assert.Equal(t, "error", payload["level"])
+	assert.Equal(t, "staging", payload["environment"])
+	assert.Equal(t, "agent", payload["tags"].(map[string]any)["stage"])
+	extra := payload["extra"].(map[string]any)
+	assert.Equal(t, "last lines", extra["log_tail"])
+	assert.Equal(t, "goroutine 1", extra["stacktrace"])
+}
+
+func TestCaptureReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, err := New(strings.Replace(server.URL, "://", "://pubkey@", 1)+"/1", "")
+	require.NoError(t, err)
+
+	_, err = client.Capture(context.Background(), Event{Message: "boom"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+}
@@ -0,0 +1,47 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CurrentBranch returns the branch checked out in the repository or worktree at dir.
+func CurrentBranch(ctx context.Context, dir string) (string, error) {
+	branch, err := gitOutput(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current branch: %w", err)
+	}
+	return branch, nil
+}
+
+// ArchiveBranch writes the branch checked out in the worktree at dir to a git bundle
+// under archiveDir and returns the bundle path. The branch can later be recovered with
+// `git fetch <bundle> <branch>`.
+func ArchiveBranch(ctx context.Context, dir, archiveDir string) (string, error) {
+	branch, err := CurrentBranch(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(archiveDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.bundle", unsafePathChars.ReplaceAllString(branch, "_"), time.Now().Format("20060102-150405"))
+	bundle := filepath.Join(archiveDir, name)
+	if err := runGit(ctx, LocalTimeout, dir, "bundle", "create", bundle, branch); err != nil {
+		return "", fmt.Errorf("failed to archive branch %s: %w", branch, err)
+	}
+	return bundle, nil
+}
+
+// DefaultArchiveDir returns the default directory for archived branches of abandoned work.
+func DefaultArchiveDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".monday", "archive"), nil
+}
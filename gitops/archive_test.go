@@ -0,0 +1,27 @@
+package gitops
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	gitIn(t, repo, "checkout", "-q", "-b", "feature/del-1")
+	commitFile(t, repo, "WORK.md")
+
+	archiveDir := filepath.Join(t.TempDir(), "archive")
+	bundle, err := ArchiveBranch(context.Background(), repo, archiveDir)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(filepath.Base(bundle), "feature_del-1-"))
+
+	out, err := exec.Command("git", "bundle", "list-heads", bundle).Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "refs/heads/feature/del-1")
+}
@@ -0,0 +1,22 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeleteBranch force-deletes the local branch in the repository at repoPath.
+func DeleteBranch(ctx context.Context, repoPath, branch string) error {
+	if err := runGit(ctx, LocalTimeout, repoPath, "branch", "-D", branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// DeleteRemoteBranch deletes branch from origin of the repository at repoPath.
+func DeleteRemoteBranch(ctx context.Context, repoPath, branch string) error {
+	if err := runGit(ctx, NetworkTimeout, repoPath, "push", "origin", "--delete", branch); err != nil {
+		return fmt.Errorf("failed to delete remote branch %s: %w", branch, err)
+	}
+	return nil
+}
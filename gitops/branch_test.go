@@ -0,0 +1,28 @@
+package gitops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteBranches(t *testing.T) {
+	ctx := context.Background()
+	origin := newTestRepo(t)
+	repo := t.TempDir()
+	gitIn(t, origin, "clone", "-q", ".", repo)
+
+	gitIn(t, repo, "branch", "feature/del-1")
+	gitIn(t, repo, "push", "-q", "origin", "feature/del-1")
+	require.True(t, BranchExists(ctx, origin, "feature/del-1"))
+
+	require.NoError(t, DeleteRemoteBranch(ctx, repo, "feature/del-1"))
+	assert.False(t, BranchExists(ctx, origin, "feature/del-1"))
+
+	require.NoError(t, DeleteBranch(ctx, repo, "feature/del-1"))
+	assert.False(t, BranchExists(ctx, repo, "feature/del-1"))
+
+	assert.Error(t, DeleteBranch(ctx, repo, "feature/missing"))
+}
@@ -0,0 +1,111 @@
+package gitops
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FetchScope limits what a run transfers from the remote. The zero value fetches everything.
+type FetchScope struct {
+	// Branches are the branches a run needs, base branch first. When set, only these refs are
+	// fetched; branches missing on the remote (such as a not-yet-pushed issue branch) are skipped.
+	Branches []string
+	// Filter is an optional partial clone filter such as "blob:none", applied to clones that
+	// talk to the remote directly
+	Filter string
+	// Depth truncates the history of working copies to that many commits; zero keeps all of it.
+	// Mirrors always keep the full history.
+	Depth int
+}
+
+// limited reports whether the scope restricts which refs are fetched.
+func (s FetchScope) limited() bool {
+	return len(s.Branches) > 0
+}
+
+// cloneArgs returns the clone flags implementing the scope. The filter is only applied when
+// remote is set, since local clones from the mirror cache cannot be partial.
+func (s FetchScope) cloneArgs(remote bool) []string {
+	var args []string
+	if s.limited() {
+		args = append(args, "--single-branch", "--branch", s.Branches[0])
+	}
+	if s.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(s.Depth))
+		if !s.limited() {
+			// --depth implies --single-branch, which an unlimited scope must not.
+			args = append(args, "--no-single-branch")
+		}
+	}
+	if remote && s.Filter != "" {
+		args = append(args, "--filter="+s.Filter)
+	}
+	return args
+}
+
+// RemoteDefaultBranch asks remote (a URL or remote name, resolved in dir) which branch its
+// HEAD points at, without fetching anything.
+func RemoteDefaultBranch(ctx context.Context, dir, remote string) (string, error) {
+	out, err := gitOutputTimeout(ctx, NetworkTimeout, dir, "ls-remote", "--symref", remote, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to query default branch: %w", err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "ref:" && fields[2] == "HEAD" {
+			return strings.TrimPrefix(fields[1], "refs/heads/"), nil
+		}
+	}
+	return "", fmt.Errorf("remote %s does not advertise a default branch", remote)
+}
+
+// remoteBranches returns the subset of branches that exist on remote, preserving order.
+func remoteBranches(ctx context.Context, dir, remote string, branches []string) ([]string, error) {
+	args := append([]string{"ls-remote", "--heads", remote}, branches...)
+	out, err := gitOutputTimeout(ctx, NetworkTimeout, dir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	advertised := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			advertised[strings.TrimPrefix(fields[1], "refs/heads/")] = true
+		}
+	}
+
+	var existing []string
+	for _, branch := range branches {
+		if advertised[branch] {
+			existing = append(existing, branch)
+		}
+	}
+	return existing, nil
+}
+
+// fetchBranches fetches the branches of scope that exist on origin into the repository at
+// dir, mapping refs/heads/<b> to <dstPrefix><b>. The first branch must exist.
+func fetchBranches(ctx context.Context, dir, dstPrefix string, scope FetchScope) error {
+	branches, err := remoteBranches(ctx, dir, "origin", scope.Branches)
+	if err != nil {
+		return err
+	}
+	if len(branches) == 0 || branches[0] != scope.Branches[0] {
+		return fmt.Errorf("branch %s does not exist on origin", scope.Branches[0])
+	}
+
+	args := []string{"fetch", "origin"}
+	if scope.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(scope.Depth))
+	}
+	for _, branch := range branches {
+		args = append(args, fmt.Sprintf("+refs/heads/%s:%s%s", branch, dstPrefix, branch))
+	}
+	return runGit(ctx, NetworkTimeout, dir, args...)
+}
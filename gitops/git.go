@@ -0,0 +1,77 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	// LocalTimeout bounds git commands that only touch the local repository.
+	LocalTimeout = 2 * time.Minute
+	// NetworkTimeout bounds git commands that talk to a remote, such as clone and fetch.
+	NetworkTimeout = 15 * time.Minute
+)
+
+// gitCommand builds a git command in dir bound to ctx. Terminal prompts are disabled so a
+// missing credential fails fast instead of waiting forever for input nobody will type.
+func gitCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+// runGit executes git with args in dir, killing it once timeout elapses or ctx is cancelled.
+// The returned error includes git's output on failure.
+func runGit(ctx context.Context, timeout time.Duration, dir string, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := gitCommand(ctx, dir, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return gitError(ctx, timeout, args, err, out.String())
+	}
+	return nil
+}
+
+// gitOutput runs a local git command in dir and returns its trimmed standard output.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	return gitOutputTimeout(ctx, LocalTimeout, dir, args...)
+}
+
+// gitOutputTimeout runs git in dir, bounded by timeout, and returns its trimmed standard output.
+func gitOutputTimeout(ctx context.Context, timeout time.Duration, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := gitCommand(ctx, dir, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", gitError(ctx, timeout, args, err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitError describes a failed git invocation, distinguishing timeouts and cancellation from
+// ordinary failures.
+func gitError(ctx context.Context, timeout time.Duration, args []string, err error, output string) error {
+	name := "git " + strings.Join(args, " ")
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s: timed out after %s", name, timeout)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s: %w", name, ctx.Err())
+	}
+	return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(output))
+}
@@ -0,0 +1,32 @@
+package gitops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunGit_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runGit(ctx, time.Minute, t.TempDir(), "init", "-q")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunGit_Timeout(t *testing.T) {
+	err := runGit(context.Background(), time.Nanosecond, t.TempDir(), "init", "-q")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after")
+}
+
+func TestRunGit_FailureIncludesOutput(t *testing.T) {
+	err := runGit(context.Background(), time.Minute, t.TempDir(), "rev-parse", "HEAD")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git rev-parse HEAD")
+	assert.Contains(t, err.Error(), "not a git repository")
+}
@@ -0,0 +1,285 @@
+// Package gitops wraps the git plumbing monday performs against repositories:
+// maintaining a local cache of bare mirrors and producing working copies from it.
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// unsafePathChars matches everything that should not appear in a mirror directory name.
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// MirrorCache maintains one bare mirror per remote URL under a cache directory so
+// repeated runs against the same repository only transfer new objects.
+type MirrorCache struct {
+	// dir is the root directory holding the bare mirrors
+	dir string
+
+	// mu guards locks
+	mu sync.Mutex
+	// locks serializes updates to a single mirror across concurrent runs
+	locks map[string]*sync.Mutex
+}
+
+// NewMirrorCache creates a mirror cache rooted at dir. The directory is created lazily.
+func NewMirrorCache(dir string) *MirrorCache {
+	return &MirrorCache{
+		dir:   dir,
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+// DefaultCacheDir returns the default mirror cache location under the user's cache directory.
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(base, "monday", "mirrors"), nil
+}
+
+// Dir returns the root directory of the cache.
+func (m *MirrorCache) Dir() string {
+	return m.dir
+}
+
+// MirrorPath returns the path of the bare mirror for repoURL.
+// URLs that differ only in scheme, credentials, or a ".git" suffix share a mirror.
+func (m *MirrorCache) MirrorPath(repoURL string) string {
+	return filepath.Join(m.dir, mirrorName(repoURL)+".git")
+}
+
+// Update makes sure an up-to-date bare mirror of repoURL exists in the cache and
+// returns its path. A missing mirror is created with `git clone --mirror`; an
+// existing one is refreshed with `git remote update --prune`. A limited scope
+// restricts both to the scope's branches; the scope's depth is ignored.
+func (m *MirrorCache) Update(ctx context.Context, repoURL string, scope FetchScope) (string, error) {
+	path := m.MirrorPath(repoURL)
+	scope.Depth = 0
+
+	lock := m.lockFor(path)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err == nil {
+		if err := runGit(ctx, LocalTimeout, "", "-C", path, "remote", "set-url", "origin", repoURL); err != nil {
+			return "", fmt.Errorf("failed to update mirror remote: %w", err)
+		}
+		if scope.limited() {
+			if err := fetchBranches(ctx, path, "refs/heads/", scope); err != nil {
+				return "", fmt.Errorf("failed to update mirror: %w", err)
+			}
+			return path, nil
+		}
+		if err := runGit(ctx, NetworkTimeout, "", "-C", path, "remote", "update", "--prune"); err != nil {
+			return "", fmt.Errorf("failed to update mirror: %w", err)
+		}
+		return path, nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create mirror cache directory: %w", err)
+	}
+	// Clone into a temporary sibling so an interrupted clone never leaves a
+	// half-populated mirror behind that later runs would trust.
+	tmp := path + ".tmp"
+	os.RemoveAll(tmp)
+	if err := createMirror(ctx, repoURL, tmp, scope); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to create mirror: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to finalize mirror: %w", err)
+	}
+	return path, nil
+}
+
+// createMirror creates a bare mirror of repoURL at path. With a limited scope only the
+// scope's branches are fetched and HEAD points at the first of them.
+func createMirror(ctx context.Context, repoURL, path string, scope FetchScope) error {
+	if !scope.limited() {
+		return runGit(ctx, NetworkTimeout, "", "clone", "--mirror", repoURL, path)
+	}
+	if err := runGit(ctx, LocalTimeout, "", "init", "-q", "--bare", path); err != nil {
+		return err
+	}
+	if err := runGit(ctx, LocalTimeout, path, "remote", "add", "--mirror=fetch", "origin", repoURL); err != nil {
+		return err
+	}
+	if err := fetchBranches(ctx, path, "refs/heads/", scope); err != nil {
+		return err
+	}
+	return runGit(ctx, LocalTimeout, path, "symbolic-ref", "HEAD", "refs/heads/"+scope.Branches[0])
+}
+
+// WorktreeRepoPath returns the path of the bare repository per-issue worktrees of repoURL are
+// created from. Its last element is the repository name, which names the worktrees' directory.
+func (m *MirrorCache) WorktreeRepoPath(repoURL string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(repoURL, "/"), ".git")
+	if i := strings.LastIndexAny(name, "/:"); i != -1 {
+		name = name[i+1:]
+	}
+	return filepath.Join(m.dir, "worktrees", mirrorName(repoURL), name)
+}
+
+// UpdateWorktreeRepo makes sure the bare repository for worktrees of repoURL exists in the
+// cache, fetches the branches of scope into its origin remote-tracking branches, and returns
+// its path. Unlike a mirror, it keeps origin's branches under refs/remotes/origin/ so the
+// issue branches of its worktrees are never pruned by a fetch. scope must name the base branch.
+func (m *MirrorCache) UpdateWorktreeRepo(ctx context.Context, repoURL string, scope FetchScope) (string, error) {
+	path := m.WorktreeRepoPath(repoURL)
+
+	lock := m.lockFor(path)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err == nil {
+		if err := runGit(ctx, LocalTimeout, "", "-C", path, "remote", "set-url", "origin", repoURL); err != nil {
+			return "", fmt.Errorf("failed to update worktree repository remote: %w", err)
+		}
+	} else if err := createWorktreeRepo(ctx, repoURL, path); err != nil {
+		return "", fmt.Errorf("failed to create worktree repository: %w", err)
+	}
+
+	if err := fetchBranches(ctx, path, "refs/remotes/origin/", scope); err != nil {
+		return "", fmt.Errorf("failed to update worktree repository: %w", err)
+	}
+	return path, nil
+}
+
+// createWorktreeRepo creates an empty bare repository at path with origin set to repoURL and
+// origin/HEAD pointing at the remote's default branch. Like mirrors, it is set up in a
+// temporary sibling so an interrupted run never leaves a half-configured repository behind.
+func createWorktreeRepo(ctx context.Context, repoURL, path string) error {
+	defaultBranch, err := RemoteDefaultBranch(ctx, "", repoURL)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	os.RemoveAll(tmp)
+	setup := [][]string{
+		{"init", "-q", "--bare", tmp},
+		{"-C", tmp, "remote", "add", "origin", repoURL},
+		{"-C", tmp, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/" + defaultBranch},
+	}
+	for _, args := range setup {
+		if err := runGit(ctx, LocalTimeout, "", args...); err != nil {
+			os.RemoveAll(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
+
+// CloneOptions controls how a working copy is produced.
+type CloneOptions struct {
+	// Reference clones from the real remote with `--reference <mirror>`, borrowing objects
+	// from the mirror instead of cloning the mirror itself
+	Reference bool
+	// Dissociate copies borrowed objects into the clone after a Reference clone so it no
+	// longer depends on the mirror staying intact
+	Dissociate bool
+	// Scope limits the refs fetched for the clone
+	Scope FetchScope
+	// Sparse checks out only the top-level files, for a sparse checkout to widen before the
+	// rest of the tree is checked out
+	Sparse bool
+}
+
+// args returns the clone flags implementing opts; remote is set for clones from the remote.
+func (opts CloneOptions) args(remote bool) []string {
+	args := opts.Scope.cloneArgs(remote)
+	if opts.Sparse {
+		args = append(args, "--sparse")
+	}
+	return args
+}
+
+// Clone refreshes the mirror for repoURL and produces a working copy of it in dest. By
+// default the mirror itself is cloned (hardlinking objects) and origin is pointed back at
+// repoURL; with opts.Reference the remote is cloned with the mirror as an object reference.
+// Either way pushes go to the real remote.
+func (m *MirrorCache) Clone(ctx context.Context, repoURL, dest string, opts CloneOptions) error {
+	mirror, err := m.Update(ctx, repoURL, opts.Scope)
+	if err != nil {
+		return err
+	}
+
+	if opts.Reference {
+		args := append([]string{"clone", "--reference", mirror}, opts.args(true)...)
+		if opts.Dissociate {
+			args = append(args, "--dissociate")
+		}
+		args = append(args, repoURL, dest)
+		if err := runGit(ctx, NetworkTimeout, "", args...); err != nil {
+			return fmt.Errorf("failed to clone with mirror reference: %w", err)
+		}
+		return nil
+	}
+
+	// Local clones ignore --depth unless the mirror is given as a file:// URL.
+	source := mirror
+	if opts.Scope.Depth > 0 {
+		source = "file://" + mirror
+	}
+	args := append([]string{"clone"}, opts.args(false)...)
+	args = append(args, source, dest)
+	if err := runGit(ctx, NetworkTimeout, "", args...); err != nil {
+		return fmt.Errorf("failed to clone from mirror: %w", err)
+	}
+	if err := runGit(ctx, LocalTimeout, "", "-C", dest, "remote", "set-url", "origin", repoURL); err != nil {
+		return fmt.Errorf("failed to set origin URL: %w", err)
+	}
+	return nil
+}
+
+// CloneRemote clones repoURL directly into dest, bypassing the mirror cache, limited by
+// opts.Scope.
+func CloneRemote(ctx context.Context, repoURL, dest string, opts CloneOptions) error {
+	args := append([]string{"clone"}, opts.args(true)...)
+	args = append(args, repoURL, dest)
+	if err := runGit(ctx, NetworkTimeout, "", args...); err != nil {
+		return fmt.Errorf("failed to clone: %w", err)
+	}
+	return nil
+}
+
+// lockFor returns the mutex guarding the mirror at path.
+func (m *MirrorCache) lockFor(path string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, ok := m.locks[path]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[path] = lock
+	}
+	return lock
+}
+
+// mirrorName derives a filesystem-safe, stable directory name from a repository URL.
+func mirrorName(repoURL string) string {
+	name := repoURL
+	if i := strings.Index(name, "://"); i != -1 {
+		name = name[i+3:]
+	}
+	if i := strings.LastIndex(name, "@"); i != -1 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "/"), ".git")
+	name = strings.ReplaceAll(name, ":", "/")
+	return strings.Trim(unsafePathChars.ReplaceAllString(name, "_"), "_.")
+}
@@ -0,0 +1,146 @@
+package gitops
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// issueBranchPattern matches branch names that embed a Linear issue identifier, such as
+// "user/del-163-fix-login" or "feature/del_163".
+var issueBranchPattern = regexp.MustCompile(`(?i)(^|[/_-])[a-z]+[-_]\d+($|[/_-])`)
+
+// RegisteredWorktree is an entry from `git worktree list --porcelain`.
+type RegisteredWorktree struct {
+	// Path is the worktree directory as recorded by git
+	Path string
+	// Branch is the short name of the checked-out branch, empty for a detached HEAD
+	Branch string
+	// Prunable is set when git reports the worktree directory no longer exists
+	Prunable bool
+}
+
+// Orphans summarizes leftovers of past runs found in one repository.
+type Orphans struct {
+	// Repo is the main repository the findings belong to
+	Repo string
+	// Stale are worktree registrations whose directories have vanished
+	Stale []RegisteredWorktree
+	// Unregistered are directories under the worktree root for this repository that git
+	// does not know about
+	Unregistered []string
+	// Branches are per-issue branches that are not checked out in any worktree
+	Branches []string
+}
+
+// ListRegisteredWorktrees returns the worktrees git has registered for the repository at repoPath.
+func ListRegisteredWorktrees(ctx context.Context, repoPath string) ([]RegisteredWorktree, error) {
+	out, err := gitOutput(ctx, repoPath, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	return parseWorktreeList(out), nil
+}
+
+// parseWorktreeList parses the porcelain output of `git worktree list`.
+func parseWorktreeList(out string) []RegisteredWorktree {
+	var worktrees []RegisteredWorktree
+	var current *RegisteredWorktree
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			worktrees = append(worktrees, RegisteredWorktree{Path: strings.TrimPrefix(line, "worktree ")})
+			current = &worktrees[len(worktrees)-1]
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "branch "):
+			current.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+		case line == "prunable" || strings.HasPrefix(line, "prunable "):
+			current.Prunable = true
+		}
+	}
+	return worktrees
+}
+
+// MainRepository returns the main working tree of the repository that the worktree at path belongs to.
+func MainRepository(ctx context.Context, path string) (string, error) {
+	commonDir, err := gitOutput(ctx, path, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate main repository: %w", err)
+	}
+	return filepath.Dir(commonDir), nil
+}
+
+// FindOrphans cross-references the worktrees registered in the repository at repoPath with
+// the per-issue directories under root and the repository's local branches.
+func FindOrphans(ctx context.Context, repoPath, root string) (*Orphans, error) {
+	registered, err := ListRegisteredWorktrees(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := &Orphans{Repo: repoPath}
+	known := make(map[string]bool)
+	checkedOut := make(map[string]bool)
+	for _, wt := range registered {
+		known[filepath.Clean(wt.Path)] = true
+		if wt.Prunable {
+			orphans.Stale = append(orphans.Stale, wt)
+			continue
+		}
+		if wt.Branch != "" {
+			checkedOut[wt.Branch] = true
+		}
+	}
+
+	onDisk, err := ListWorktrees(root)
+	if err != nil {
+		return nil, err
+	}
+	repoName := filepath.Base(repoPath)
+	for _, wt := range onDisk {
+		if wt.Repo != repoName {
+			continue
+		}
+		path, err := filepath.Abs(wt.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve worktree path: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		if !known[path] && !known[filepath.Clean(wt.Path)] {
+			orphans.Unregistered = append(orphans.Unregistered, wt.Path)
+		}
+	}
+
+	branches, err := gitOutput(ctx, repoPath, "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	for _, branch := range strings.Fields(branches) {
+		if !checkedOut[branch] && IsIssueBranch(branch) {
+			orphans.Branches = append(orphans.Branches, branch)
+		}
+	}
+	return orphans, nil
+}
+
+// IsIssueBranch reports whether branch looks like a per-issue branch created for a Linear issue.
+func IsIssueBranch(branch string) bool {
+	return issueBranchPattern.MatchString(branch)
+}
+
+// PruneWorktrees removes registrations of worktrees whose directories no longer exist.
+func PruneWorktrees(ctx context.Context, repoPath string) error {
+	if err := runGit(ctx, LocalTimeout, repoPath, "worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	out := `worktree /src/repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /wt/repo/DEL-1
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/del-1
+
+worktree /wt/repo/DEL-2
+HEAD 3333333333333333333333333333333333333333
+detached
+prunable gitdir file points to non-existent location
+`
+
+	worktrees := parseWorktreeList(out)
+	require.Len(t, worktrees, 3)
+	assert.Equal(t, RegisteredWorktree{Path: "/src/repo", Branch: "main"}, worktrees[0])
+	assert.Equal(t, RegisteredWorktree{Path: "/wt/repo/DEL-1", Branch: "feature/del-1"}, worktrees[1])
+	assert.Equal(t, RegisteredWorktree{Path: "/wt/repo/DEL-2", Prunable: true}, worktrees[2])
+}
+
+func TestIsIssueBranch(t *testing.T) {
+	assert.True(t, IsIssueBranch("feature/del_163"))
+	assert.True(t, IsIssueBranch("user/del-163-fix-login"))
+	assert.True(t, IsIssueBranch("ENG-42"))
+	assert.False(t, IsIssueBranch("main"))
+	assert.False(t, IsIssueBranch("release/v1"))
+}
+
+func TestFindOrphans(t *testing.T) {
+	ctx := context.Background()
+	repo := newClonedRepo(t)
+	root := t.TempDir()
+
+	live, err := CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/del-1", "main")
+	require.NoError(t, err)
+	vanished, err := CreateWorktreeForIssue(ctx, repo, root, "DEL-2", "feature/del-2", "main")
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(vanished))
+	stray := WorktreePath(root, filepath.Base(repo), "DEL-3")
+	require.NoError(t, os.MkdirAll(stray, 0o755))
+
+	orphans, err := FindOrphans(ctx, repo, root)
+	require.NoError(t, err)
+	require.Len(t, orphans.Stale, 1)
+	assert.Equal(t, "feature/del-2", orphans.Stale[0].Branch)
+	assert.Equal(t, []string{stray}, orphans.Unregistered)
+	assert.Equal(t, []string{"feature/del-2"}, orphans.Branches)
+
+	main, err := MainRepository(ctx, live)
+	require.NoError(t, err)
+	resolvedRepo, _ := filepath.EvalSymlinks(repo)
+	assert.Equal(t, resolvedRepo, main)
+
+	require.NoError(t, PruneWorktrees(ctx, repo))
+	orphans, err = FindOrphans(ctx, repo, root)
+	require.NoError(t, err)
+	assert.Empty(t, orphans.Stale)
+}
@@ -0,0 +1,80 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBranch returns the branch origin/HEAD points at in the repository at repoPath,
+// falling back to "main" when the remote HEAD is unknown.
+func DefaultBranch(ctx context.Context, repoPath string) string {
+	ref, err := gitOutput(ctx, repoPath, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil || ref == "" {
+		return "main"
+	}
+	return strings.TrimPrefix(ref, "origin/")
+}
+
+// PrepareRepository fetches baseBranch from origin into the local clone at repoPath so that
+// worktrees can be created from the latest origin/<baseBranch>. Any extra branches (such as
+// an already pushed issue branch) are fetched too when they exist on origin; nothing else
+// is transferred. It never checks out or updates local branches, leaving whatever the user
+// has checked out untouched.
+func PrepareRepository(ctx context.Context, repoPath, baseBranch string, extra ...string) error {
+	if _, err := gitOutput(ctx, repoPath, "rev-parse", "--git-dir"); err != nil {
+		return fmt.Errorf("%s is not a git repository: %w", repoPath, err)
+	}
+	scope := FetchScope{Branches: append([]string{baseBranch}, extra...)}
+	if err := fetchBranches(ctx, repoPath, "refs/remotes/origin/", scope); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", baseBranch, err)
+	}
+	return nil
+}
+
+// CreateWorktreeForIssue creates (or reuses) the worktree for issueID under root with
+// branch checked out. A new branch is started from origin/<baseBranch> as fetched by
+// PrepareRepository; an existing branch is checked out as-is. It returns the absolute
+// worktree path.
+func CreateWorktreeForIssue(ctx context.Context, repoPath, root, issueID, branch, baseBranch string) (string, error) {
+	repoAbs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository path: %w", err)
+	}
+	path, err := filepath.Abs(WorktreePath(root, filepath.Base(repoAbs), issueID))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve worktree path: %w", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		current, err := CurrentBranch(ctx, path)
+		if err != nil {
+			return "", fmt.Errorf("existing worktree %s is unusable: %w", path, err)
+		}
+		if current != branch {
+			return "", fmt.Errorf("existing worktree %s has %s checked out, expected %s", path, current, branch)
+		}
+		return path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	args := []string{"worktree", "add", path, branch}
+	if !BranchExists(ctx, repoAbs, branch) {
+		args = []string{"worktree", "add", "--no-track", "-b", branch, path, "origin/" + baseBranch}
+	}
+	if err := runGit(ctx, LocalTimeout, repoAbs, args...); err != nil {
+		return "", fmt.Errorf("failed to create worktree: %w", err)
+	}
+	return path, nil
+}
+
+// BranchExists reports whether a local branch named branch exists in the repository at repoPath.
+func BranchExists(ctx context.Context, repoPath, branch string) bool {
+	_, err := gitOutput(ctx, repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
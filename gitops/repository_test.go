@@ -0,0 +1,94 @@
+package gitops
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareRepository(t *testing.T) {
+	ctx := context.Background()
+	origin := newTestRepo(t)
+	local := filepath.Join(t.TempDir(), "local")
+	gitIn(t, origin, "clone", "-q", ".", local)
+
+	// The user is working on their own branch, which preparing must not disturb.
+	gitIn(t, local, "checkout", "-q", "-b", "my-work")
+	before, err := gitOutput(ctx, local, "rev-parse", "main")
+	require.NoError(t, err)
+
+	commitFile(t, origin, "NEW.md")
+	require.NoError(t, PrepareRepository(ctx, local, "main"))
+	assert.Equal(t, "main", DefaultBranch(ctx, local))
+
+	branch, err := CurrentBranch(ctx, local)
+	require.NoError(t, err)
+	assert.Equal(t, "my-work", branch)
+	after, err := gitOutput(ctx, local, "rev-parse", "main")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NoFileExists(t, filepath.Join(local, "NEW.md"))
+
+	// New worktrees start from the freshly fetched origin/main.
+	path, err := CreateWorktreeForIssue(ctx, local, t.TempDir(), "DEL-1", "feature/del-1", "main")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(path, "NEW.md"))
+}
+
+func TestPrepareRepository_NotARepository(t *testing.T) {
+	err := PrepareRepository(context.Background(), t.TempDir(), "main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a git repository")
+}
+
+func TestCreateWorktreeForIssue(t *testing.T) {
+	ctx := context.Background()
+	repo := newClonedRepo(t)
+	root := t.TempDir()
+
+	path, err := CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/del-1", "main")
+	require.NoError(t, err)
+	assert.Equal(t, WorktreePath(root, filepath.Base(repo), "DEL-1"), path)
+	assert.FileExists(t, filepath.Join(path, "README.md"))
+	assert.True(t, BranchExists(ctx, repo, "feature/del-1"))
+
+	branch, err := CurrentBranch(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, "feature/del-1", branch)
+
+	// Creating the same worktree again reuses it.
+	again, err := CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/del-1", "main")
+	require.NoError(t, err)
+	assert.Equal(t, path, again)
+
+	// The worktree is registered with git, so RemoveWorktree unregisters it.
+	require.NoError(t, RemoveWorktree(ctx, path))
+	assert.NoDirExists(t, path)
+	list, err := gitOutput(ctx, repo, "worktree", "list")
+	require.NoError(t, err)
+	assert.NotContains(t, list, path)
+}
+
+func TestCreateWorktreeForIssue_BranchMismatch(t *testing.T) {
+	ctx := context.Background()
+	repo := newClonedRepo(t)
+	root := t.TempDir()
+
+	_, err := CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/del-1", "main")
+	require.NoError(t, err)
+
+	_, err = CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/other", "main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected feature/other")
+}
+
+// newClonedRepo creates a local clone of a fresh test repository so origin/main exists.
+func newClonedRepo(t *testing.T) string {
+	t.Helper()
+	local := filepath.Join(t.TempDir(), "local")
+	gitIn(t, newTestRepo(t), "clone", "-q", ".", local)
+	return local
+}
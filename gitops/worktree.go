@@ -0,0 +1,180 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Worktree describes a per-issue working directory laid out as <root>/<repo>/<issue>.
+type Worktree struct {
+	// Repo is the repository name the worktree belongs to
+	Repo string `json:"repo"`
+	// Issue is the Linear issue identifier the worktree was created for
+	Issue string `json:"issue"`
+	// Path is the absolute path of the worktree directory
+	Path string `json:"path"`
+	// Size is the total size in bytes of all files in the worktree
+	Size int64 `json:"size"`
+	// ModTime is the last modification time of the worktree directory
+	ModTime time.Time `json:"mod_time"`
+}
+
+// DefaultWorktreeRoot returns the default directory that holds per-issue worktrees.
+func DefaultWorktreeRoot() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".monday", "worktrees"), nil
+}
+
+// WorktreePath returns the directory used for the worktree of issueID in repoName.
+func WorktreePath(root, repoName, issueID string) string {
+	return filepath.Join(root, repoName, issueID)
+}
+
+// ListWorktrees returns all worktrees under root, oldest first, with their disk usage.
+// A missing root is treated as empty.
+func ListWorktrees(root string) ([]Worktree, error) {
+	repos, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worktree root: %w", err)
+	}
+
+	var worktrees []Worktree
+	for _, repo := range repos {
+		if !repo.IsDir() {
+			continue
+		}
+		issues, err := os.ReadDir(filepath.Join(root, repo.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read repository directory: %w", err)
+		}
+		for _, issue := range issues {
+			if !issue.IsDir() {
+				continue
+			}
+			path := filepath.Join(root, repo.Name(), issue.Name())
+			info, err := issue.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat worktree: %w", err)
+			}
+			size, err := DirSize(path)
+			if err != nil {
+				return nil, err
+			}
+			worktrees = append(worktrees, Worktree{
+				Repo:    repo.Name(),
+				Issue:   issue.Name(),
+				Path:    path,
+				Size:    size,
+				ModTime: info.ModTime(),
+			})
+		}
+	}
+
+	sort.Slice(worktrees, func(i, j int) bool {
+		return worktrees[i].ModTime.Before(worktrees[j].ModTime)
+	})
+	return worktrees, nil
+}
+
+// DirSize returns the total size in bytes of the regular files below path.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute disk usage of %s: %w", path, err)
+	}
+	return size, nil
+}
+
+// OverQuota returns the oldest worktrees that must be removed to bring the total
+// size of worktrees within quota. worktrees must be sorted oldest first.
+func OverQuota(worktrees []Worktree, quota int64) []Worktree {
+	var total int64
+	for _, wt := range worktrees {
+		total += wt.Size
+	}
+
+	var victims []Worktree
+	for _, wt := range worktrees {
+		if total <= quota {
+			break
+		}
+		victims = append(victims, wt)
+		total -= wt.Size
+	}
+	return victims
+}
+
+// RemoveWorktree deletes a worktree directory. Linked git worktrees are removed
+// through their main repository so git's worktree registration is cleaned up too.
+func RemoveWorktree(ctx context.Context, path string) error {
+	if commonDir, ok := linkedCommonDir(ctx, path); ok {
+		if err := runGit(ctx, LocalTimeout, "", "--git-dir", commonDir, "worktree", "remove", "--force", path); err == nil {
+			return nil
+		}
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w", path, err)
+	}
+	return nil
+}
+
+// MoveWorktree moves the worktree at path to dest. Linked git worktrees are moved with
+// `git worktree move` so the main repository's registration follows them.
+func MoveWorktree(ctx context.Context, path, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("destination %s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	if commonDir, ok := linkedCommonDir(ctx, path); ok {
+		if err := runGit(ctx, LocalTimeout, "", "--git-dir", commonDir, "worktree", "move", path, dest); err != nil {
+			return fmt.Errorf("failed to move worktree %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := os.Rename(path, dest); err != nil {
+		return fmt.Errorf("failed to move worktree %s: %w", path, err)
+	}
+	return nil
+}
+
+// linkedCommonDir returns the common git directory of the main repository when path is a
+// linked git worktree, and false when it is a main working tree or not a git checkout.
+func linkedCommonDir(ctx context.Context, path string) (string, bool) {
+	commonDir, err := gitOutput(ctx, path, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", false
+	}
+	gitDir, err := gitOutput(ctx, path, "rev-parse", "--path-format=absolute", "--git-dir")
+	if err != nil || gitDir == commonDir {
+		return "", false
+	}
+	return commonDir, true
+}
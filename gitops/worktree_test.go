@@ -0,0 +1,93 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWorktrees(t *testing.T) {
+	root := t.TempDir()
+	old := writeWorktree(t, root, "repo", "DEL-1", 100)
+	writeWorktree(t, root, "repo", "DEL-2", 50)
+	require.NoError(t, os.Chtimes(old, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	worktrees, err := ListWorktrees(root)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.Equal(t, "DEL-1", worktrees[0].Issue)
+	assert.Equal(t, "repo", worktrees[0].Repo)
+	assert.Equal(t, int64(100), worktrees[0].Size)
+	assert.Equal(t, int64(50), worktrees[1].Size)
+}
+
+func TestListWorktrees_MissingRoot(t *testing.T) {
+	worktrees, err := ListWorktrees(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, worktrees)
+}
+
+func TestOverQuota(t *testing.T) {
+	worktrees := []Worktree{
+		{Issue: "DEL-1", Size: 100},
+		{Issue: "DEL-2", Size: 200},
+		{Issue: "DEL-3", Size: 300},
+	}
+
+	assert.Empty(t, OverQuota(worktrees, 600))
+
+	victims := OverQuota(worktrees, 350)
+	require.Len(t, victims, 2)
+	assert.Equal(t, "DEL-1", victims[0].Issue)
+	assert.Equal(t, "DEL-2", victims[1].Issue)
+}
+
+func TestRemoveWorktree_PlainDirectory(t *testing.T) {
+	root := t.TempDir()
+	path := writeWorktree(t, root, "repo", "DEL-1", 10)
+
+	require.NoError(t, RemoveWorktree(context.Background(), path))
+	assert.NoDirExists(t, path)
+}
+
+// writeWorktree creates a worktree directory containing a single file of size bytes.
+func writeWorktree(t *testing.T, root, repo, issue string, size int) string {
+	t.Helper()
+	path := WorktreePath(root, repo, issue)
+	require.NoError(t, os.MkdirAll(path, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "data"), make([]byte, size), 0o644))
+	return path
+}
+
+func TestMoveWorktree(t *testing.T) {
+	ctx := context.Background()
+	repo := newClonedRepo(t)
+	root := t.TempDir()
+	newRoot := t.TempDir()
+
+	path, err := CreateWorktreeForIssue(ctx, repo, root, "DEL-1", "feature/del-1", "main")
+	require.NoError(t, err)
+	plain := writeWorktree(t, root, "other", "DEL-2", 10)
+
+	dest := WorktreePath(newRoot, filepath.Base(repo), "DEL-1")
+	require.NoError(t, MoveWorktree(ctx, path, dest))
+	assert.NoDirExists(t, path)
+	assert.FileExists(t, filepath.Join(dest, "README.md"))
+
+	registered, err := ListRegisteredWorktrees(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, registered, 2)
+	resolved, _ := filepath.EvalSymlinks(dest)
+	assert.Equal(t, resolved, registered[1].Path)
+
+	plainDest := WorktreePath(newRoot, "other", "DEL-2")
+	require.NoError(t, MoveWorktree(ctx, plain, plainDest))
+	assert.FileExists(t, filepath.Join(plainDest, "data"))
+
+	assert.Error(t, MoveWorktree(ctx, dest, plainDest))
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"monday/summary"
+)
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{
+	"run_id", "issue_id", "issue_title", "repo", "branch", "pr_url", "status",
+	"started_at", "finished_at", "duration_seconds", "failed_stage", "stage_durations",
+	"files_changed", "agent_cost_usd", "agent_input_tokens", "agent_output_tokens", "errors",
+}
+
+// WriteCSV writes one row per run. Stage durations are encoded as "name=seconds" pairs separated
+// by semicolons; the agent cost column is empty when unknown.
+func WriteCSV(w io.Writer, runs []*summary.Summary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, run := range runs {
+		var stages []string
+		for _, stage := range run.Stages {
+			stages = append(stages, fmt.Sprintf("%s=%s", stage.Name, formatFloat(stage.DurationSeconds)))
+		}
+		cost := ""
+		if run.AgentCostUSD != nil {
+			cost = formatFloat(*run.AgentCostUSD)
+		}
+		finished := ""
+		if !run.FinishedAt.IsZero() {
+			finished = run.FinishedAt.UTC().Format(time.RFC3339)
+		}
+
+		record := []string{
+			run.RunID, run.IssueID, run.IssueTitle, run.Repo, run.Branch, run.PRURL, run.Status,
+			run.StartedAt.UTC().Format(time.RFC3339), finished, formatFloat(run.DurationSeconds),
+			run.FailedStage(), strings.Join(stages, ";"),
+			strconv.Itoa(len(run.FilesChanged)), cost,
+			strconv.FormatInt(run.AgentInputTokens, 10), strconv.FormatInt(run.AgentOutputTokens, 10),
+			strings.Join(run.Errors, "; "),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the full run summaries as a JSON array.
+func WriteJSON(w io.Writer, runs []*summary.Summary) error {
+	if runs == nil {
+		runs = []*summary.Summary{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(runs)
+}
+
+// formatFloat renders f rounded to milliseconds precision without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,61 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/summary"
+)
+
+func TestWriteCSV(t *testing.T) {
+	cost := 1.25
+	started := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	runs := []*summary.Summary{{
+		RunID:        "run-1",
+		IssueID:      "DEL-163",
+		IssueTitle:   "Fix login, again",
+		Repo:         "https://github.com/owner/repo",
+		Status:       summary.StatusFailed,
+		StartedAt:    started,
+		FinishedAt:   started.Add(90 * time.Second),
+		Stages:       []summary.Stage{{Name: "clone", Status: summary.StatusSucceeded, DurationSeconds: 12.3456}, {Name: "agent", Status: summary.StatusFailed, DurationSeconds: 60}},
+		FilesChanged: []string{"a.go", "b.go"},
+		AgentCostUSD: &cost,
+		Errors:       []string{"agent: exit status 1"},
+	}}
+
+	var b strings.Builder
+	require.NoError(t, WriteCSV(&b, runs))
+
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, csvHeader, records[0])
+
+	row := make(map[string]string)
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	assert.Equal(t, "Fix login, again", row["issue_title"])
+	assert.Equal(t, "2025-06-01T10:01:30Z", row["finished_at"])
+	assert.Equal(t, "agent", row["failed_stage"])
+	assert.Equal(t, "clone=12.346;agent=60", row["stage_durations"])
+	assert.Equal(t, "2", row["files_changed"])
+	assert.Equal(t, "1.25", row["agent_cost_usd"])
+	assert.Equal(t, "agent: exit status 1", row["errors"])
+}
+
+func TestWriteJSONEmpty(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, WriteJSON(&b, nil))
+
+	var decoded []any
+	require.NoError(t, json.Unmarshal([]byte(b.String()), &decoded))
+	assert.Empty(t, decoded)
+}
@@ -0,0 +1,232 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"monday/summary"
+)
+
+// Stats aggregates the outcomes of a set of runs.
+type Stats struct {
+	// Runs is the number of finished runs
+	Runs int `json:"runs"`
+	// Succeeded is the number of successful runs
+	Succeeded int `json:"succeeded"`
+	// Failed is the number of failed runs
+	Failed int `json:"failed"`
+	// SuccessRate is Succeeded divided by Runs
+	SuccessRate float64 `json:"success_rate"`
+	// Stages holds per-stage statistics in first-seen order
+	Stages []StageStats `json:"stages"`
+	// Agent totals the agent usage of the runs, canceled ones included as their agent still
+	// ran; Group is empty
+	Agent Usage `json:"agent"`
+}
+
+// StageStats aggregates one stage across runs.
+type StageStats struct {
+	// Name is the stage name
+	Name string `json:"name"`
+	// Runs is the number of runs that reached the stage
+	Runs int `json:"runs"`
+	// Succeeded is the number of runs in which the stage succeeded
+	Succeeded int `json:"succeeded"`
+	// Failed is the number of runs in which the stage failed
+	Failed int `json:"failed"`
+	// SuccessRate is Succeeded divided by Runs
+	SuccessRate float64 `json:"success_rate"`
+	// Retries is the total number of retries
+	Retries int `json:"retries"`
+	// MeanSeconds is the mean stage duration
+	MeanSeconds float64 `json:"mean_seconds"`
+	// P95Seconds is the 95th percentile stage duration
+	P95Seconds float64 `json:"p95_seconds"`
+	// MaxSeconds is the longest stage duration
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// Aggregate computes statistics over runs. Runs that are still in progress, were canceled, or
+// were dry runs are ignored: none says anything about how reliable the workflow is.
+func Aggregate(runs []*summary.Summary) Stats {
+	var stats Stats
+	durations := make(map[string][]float64)
+	index := make(map[string]int)
+
+	for _, run := range runs {
+		if run.Status == summary.StatusRunning || run.DryRun {
+			continue
+		}
+		stats.Agent.add(run)
+		if run.Status == summary.StatusCanceled {
+			continue
+		}
+		stats.Runs++
+		if run.Status == summary.StatusSucceeded {
+			stats.Succeeded++
+		} else {
+			stats.Failed++
+		}
+
+		for _, stage := range run.Stages {
+			i, ok := index[stage.Name]
+			if !ok {
+				i = len(stats.Stages)
+				index[stage.Name] = i
+				stats.Stages = append(stats.Stages, StageStats{Name: stage.Name})
+			}
+			s := &stats.Stages[i]
+			s.Runs++
+			s.Retries += stage.Retries
+			if stage.Status == summary.StatusSucceeded {
+				s.Succeeded++
+			} else {
+				s.Failed++
+			}
+			durations[stage.Name] = append(durations[stage.Name], stage.DurationSeconds)
+		}
+	}
+
+	stats.SuccessRate = rate(stats.Succeeded, stats.Runs)
+	stats.Agent.SuccessRate = rate(stats.Agent.Succeeded, stats.Agent.Runs)
+	for i := range stats.Stages {
+		s := &stats.Stages[i]
+		s.SuccessRate = rate(s.Succeeded, s.Runs)
+		d := durations[s.Name]
+		sort.Float64s(d)
+		var total float64
+		for _, v := range d {
+			total += v
+		}
+		s.MeanSeconds = total / float64(len(d))
+		s.P95Seconds = d[int(math.Ceil(0.95*float64(len(d))))-1]
+		s.MaxSeconds = d[len(d)-1]
+	}
+	return stats
+}
+
+// WritePrometheus writes stats in the Prometheus text exposition format.
+func (s Stats) WritePrometheus(w io.Writer) error {
+	metrics := []struct {
+		name, help, kind string
+		value            func(StageStats) float64
+	}{
+		{"monday_stage_runs_total", "Runs that reached the stage.", "counter", func(st StageStats) float64 { return float64(st.Runs) }},
+		{"monday_stage_failures_total", "Runs in which the stage failed.", "counter", func(st StageStats) float64 { return float64(st.Failed) }},
+		{"monday_stage_retries_total", "Retries of the stage.", "counter", func(st StageStats) float64 { return float64(st.Retries) }},
+		{"monday_stage_success_rate", "Fraction of runs in which the stage succeeded.", "gauge", func(st StageStats) float64 { return st.SuccessRate }},
+		{"monday_stage_duration_seconds_mean", "Mean stage duration.", "gauge", func(st StageStats) float64 { return st.MeanSeconds }},
+		{"monday_stage_duration_seconds_p95", "95th percentile stage duration.", "gauge", func(st StageStats) float64 { return st.P95Seconds }},
+		{"monday_stage_duration_seconds_max", "Longest stage duration.", "gauge", func(st StageStats) float64 { return st.MaxSeconds }},
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP monday_runs_total Finished workflow runs.\n# TYPE monday_runs_total counter\nmonday_runs_total{status=\"succeeded\"} %d\nmonday_runs_total{status=\"failed\"} %d\n", s.Succeeded, s.Failed); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "# HELP monday_agent_cost_usd_total Reported agent cost in US dollars.\n# TYPE monday_agent_cost_usd_total counter\nmonday_agent_cost_usd_total %g\n"+
+		"# HELP monday_agent_unpriced_runs_total Runs whose agent did not report a cost.\n# TYPE monday_agent_unpriced_runs_total counter\nmonday_agent_unpriced_runs_total %d\n"+
+		"# HELP monday_agent_tokens_total Reported agent tokens.\n# TYPE monday_agent_tokens_total counter\nmonday_agent_tokens_total{type=\"input\"} %d\nmonday_agent_tokens_total{type=\"output\"} %d\n",
+		s.Agent.CostUSD, s.Agent.UnpricedRuns, s.Agent.InputTokens, s.Agent.OutputTokens); err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, stage := range s.Stages {
+			if _, err := fmt.Fprintf(w, "%s{stage=%q} %g\n", m.name, stage.Name, m.value(stage)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rate returns n/total, or 0 when total is 0.
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// Usage aggregates the runs and agent usage of one group of runs, such as a repository or team.
+type Usage struct {
+	// Group is the repository or team the runs belong to
+	Group string `json:"group"`
+	// Runs is the number of finished runs
+	Runs int `json:"runs"`
+	// Succeeded is the number of successful runs
+	Succeeded int `json:"succeeded"`
+	// SuccessRate is Succeeded divided by Runs
+	SuccessRate float64 `json:"success_rate"`
+	// CostUSD is the total reported agent cost
+	CostUSD float64 `json:"cost_usd"`
+	// UnpricedRuns counts runs whose agent did not report a cost
+	UnpricedRuns int `json:"unpriced_runs"`
+	// InputTokens and OutputTokens total the reported agent tokens
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+// AggregateUsage groups finished runs, other than dry runs, by the key group returns and totals
+// their agent usage. Groups are sorted by cost, then by run count, highest first.
+func AggregateUsage(runs []*summary.Summary, group func(*summary.Summary) string) []Usage {
+	var usage []Usage
+	index := make(map[string]int)
+	for _, run := range runs {
+		if run.Status == summary.StatusRunning || run.DryRun {
+			continue
+		}
+		key := group(run)
+		i, ok := index[key]
+		if !ok {
+			i = len(usage)
+			index[key] = i
+			usage = append(usage, Usage{Group: key})
+		}
+		usage[i].add(run)
+	}
+
+	for i := range usage {
+		usage[i].SuccessRate = rate(usage[i].Succeeded, usage[i].Runs)
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].CostUSD != usage[j].CostUSD {
+			return usage[i].CostUSD > usage[j].CostUSD
+		}
+		return usage[i].Runs > usage[j].Runs
+	})
+	return usage
+}
+
+// ByRepo groups runs by repository.
+func ByRepo(run *summary.Summary) string {
+	return run.Repo
+}
+
+// ByTeam groups runs by the team key of their Linear issue, e.g. "DEL" for DEL-163.
+func ByTeam(run *summary.Summary) string {
+	if i := strings.LastIndex(run.IssueID, "-"); i > 0 {
+		return strings.ToUpper(run.IssueID[:i])
+	}
+	return run.IssueID
+}
+
+// add counts run and its agent usage in u.
+func (u *Usage) add(run *summary.Summary) {
+	u.Runs++
+	if run.Status == summary.StatusSucceeded {
+		u.Succeeded++
+	}
+	if run.AgentCostUSD != nil {
+		u.CostUSD += *run.AgentCostUSD
+	} else {
+		u.UnpricedRuns++
+	}
+	u.InputTokens += run.AgentInputTokens
+	u.OutputTokens += run.AgentOutputTokens
+}
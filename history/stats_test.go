@@ -0,0 +1,85 @@
+package history
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/summary"
+)
+
+func TestAggregate(t *testing.T) {
+	cost := 0.75
+	runs := []*summary.Summary{
+		{Status: summary.StatusSucceeded, AgentCostUSD: &cost, AgentInputTokens: 1000, AgentOutputTokens: 200, Stages: []summary.Stage{
+			{Name: "clone", Status: summary.StatusSucceeded, DurationSeconds: 10},
+			{Name: "agent", Status: summary.StatusSucceeded, DurationSeconds: 100, Retries: 1},
+		}},
+		{Status: summary.StatusFailed, Stages: []summary.Stage{
+			{Name: "clone", Status: summary.StatusFailed, DurationSeconds: 30},
+		}},
+		{Status: summary.StatusRunning, Stages: []summary.Stage{
+			{Name: "clone", Status: summary.StatusRunning},
+		}},
+		{Status: summary.StatusCanceled, AgentCostUSD: &cost, Stages: []summary.Stage{
+			{Name: "clone", Status: summary.StatusFailed, DurationSeconds: 5},
+		}},
+		{Status: summary.StatusSucceeded, DryRun: true, Stages: []summary.Stage{
+			{Name: "clone", Status: summary.StatusSucceeded, DurationSeconds: 1},
+		}},
+	}
+
+	stats := Aggregate(runs)
+	assert.Equal(t, 2, stats.Runs)
+	assert.Equal(t, 1, stats.Succeeded)
+	assert.Equal(t, 0.5, stats.SuccessRate)
+	require.Len(t, stats.Stages, 2)
+	assert.Equal(t, Usage{Runs: 3, Succeeded: 1, SuccessRate: 1.0 / 3, CostUSD: 1.5, UnpricedRuns: 1, InputTokens: 1000, OutputTokens: 200}, stats.Agent)
+
+	clone := stats.Stages[0]
+	assert.Equal(t, "clone", clone.Name)
+	assert.Equal(t, 2, clone.Runs)
+	assert.Equal(t, 1, clone.Failed)
+	assert.Equal(t, 20.0, clone.MeanSeconds)
+	assert.Equal(t, 30.0, clone.P95Seconds)
+	assert.Equal(t, 30.0, clone.MaxSeconds)
+	assert.Equal(t, 1, stats.Stages[1].Retries)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	cost := 1.25
+	stats := Aggregate([]*summary.Summary{{Status: summary.StatusSucceeded, AgentCostUSD: &cost, AgentInputTokens: 500, Stages: []summary.Stage{
+		{Name: "push", Status: summary.StatusSucceeded, DurationSeconds: 2.5},
+	}}})
+
+	var b strings.Builder
+	require.NoError(t, stats.WritePrometheus(&b))
+	assert.Contains(t, b.String(), `monday_runs_total{status="succeeded"} 1`)
+	assert.Contains(t, b.String(), `monday_stage_duration_seconds_mean{stage="push"} 2.5`)
+	assert.Contains(t, b.String(), "# TYPE monday_stage_runs_total counter")
+	assert.Contains(t, b.String(), "monday_agent_cost_usd_total 1.25")
+	assert.Contains(t, b.String(), `monday_agent_tokens_total{type="input"} 500`)
+}
+
+func TestAggregateUsage(t *testing.T) {
+	cost := func(v float64) *float64 { return &v }
+	runs := []*summary.Summary{
+		{IssueID: "DEL-1", Repo: "a", Status: summary.StatusSucceeded, AgentCostUSD: cost(1.5), AgentInputTokens: 100, AgentOutputTokens: 10},
+		{IssueID: "DEL-2", Repo: "b", Status: summary.StatusFailed, AgentCostUSD: cost(0.5)},
+		{IssueID: "eng-3", Repo: "a", Status: summary.StatusSucceeded},
+		{IssueID: "ENG-4", Repo: "a", Status: summary.StatusRunning, AgentCostUSD: cost(9)},
+	}
+
+	byTeam := AggregateUsage(runs, ByTeam)
+	require.Len(t, byTeam, 2)
+	assert.Equal(t, Usage{Group: "DEL", Runs: 2, Succeeded: 1, SuccessRate: 0.5, CostUSD: 2, InputTokens: 100, OutputTokens: 10}, byTeam[0])
+	assert.Equal(t, Usage{Group: "ENG", Runs: 1, Succeeded: 1, SuccessRate: 1, UnpricedRuns: 1}, byTeam[1])
+
+	byRepo := AggregateUsage(runs, ByRepo)
+	require.Len(t, byRepo, 2)
+	assert.Equal(t, "a", byRepo[0].Group)
+	assert.Equal(t, 2, byRepo[0].Runs)
+	assert.Equal(t, 1.5, byRepo[0].CostUSD)
+}
@@ -0,0 +1,90 @@
+// Package history gives access to past monday runs of both the CLI and the server. Every run
+// is recorded with its summary in the run database of package store when it starts, at each
+// of its stages, and when it ends; the store queries that database for reporting, filtering
+// runs in SQL.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"monday/store"
+	"monday/summary"
+)
+
+// Store queries the runs recorded in a run database.
+type Store struct {
+	db *store.Store
+}
+
+// NewStore returns a store over the run database db.
+func NewStore(db *store.Store) *Store {
+	return &Store{db: db}
+}
+
+// Runs returns the summaries of all recorded runs, oldest first.
+func (s *Store) Runs() ([]*summary.Summary, error) {
+	return s.Query(Filter{})
+}
+
+// Filter selects runs. Zero fields match everything.
+type Filter = store.Filter
+
+// Query returns the summaries of the recorded runs that pass filter, oldest first. Runs without
+// a summary, such as those the server queued, are skipped.
+func (s *Store) Query(filter Filter) ([]*summary.Summary, error) {
+	records, err := s.db.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+	var runs []*summary.Summary
+	for _, record := range records {
+		if len(record.Summary) == 0 {
+			continue
+		}
+		var run summary.Summary
+		if err := json.Unmarshal(record.Summary, &run); err != nil {
+			return nil, fmt.Errorf("run %s: failed to decode summary: %w", record.RunID, err)
+		}
+		runs = append(runs, &run)
+	}
+	return runs, nil
+}
+
+// ReadDir returns the summaries of the runs in the runs directory dir, laid out as
+// <dir>/<run-id>/summary.json, oldest first. It reads the runs of monday versions before the
+// run database, to import them. Run directories without a summary, such as those of runs that
+// failed before their first write, are skipped. A missing runs directory yields no runs.
+func ReadDir(dir string) ([]*summary.Summary, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	var runs []*summary.Summary
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name(), "summary.json")
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		run, err := summary.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("run %s: %w", entry.Name(), err)
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+	return runs, nil
+}
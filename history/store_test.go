@@ -0,0 +1,114 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/store"
+	"monday/summary"
+)
+
+// writeRun stores a finished run summary under dir.
+func writeRun(t *testing.T, dir, runID, status string, started time.Time) *summary.Summary {
+	t.Helper()
+	s := summary.New(runID, "DEL-1", "repo")
+	s.Status = status
+	s.StartedAt = started
+	_, err := s.WriteFiles(filepath.Join(dir, runID))
+	require.NoError(t, err)
+	return s
+}
+
+// recordRun saves a finished run summary in db.
+func recordRun(t *testing.T, db *store.Store, runID, status string, started time.Time) {
+	t.Helper()
+	s := summary.New(runID, "DEL-1", "repo")
+	s.Status = status
+	s.StartedAt = started
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.NoError(t, db.Save(store.Run{RunID: runID, IssueID: s.IssueID, Repo: s.Repo, Status: status, StartedAt: started, Summary: data}))
+}
+
+func openStore(t *testing.T) *store.Store {
+	t.Helper()
+	db, err := store.Open(filepath.Join(t.TempDir(), "monday.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestReadDirOldestFirst(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeRun(t, dir, "b", summary.StatusFailed, now)
+	writeRun(t, dir, "a", summary.StatusSucceeded, now.Add(-time.Hour))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "in-progress"), 0o755))
+
+	runs, err := ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "a", runs[0].RunID)
+	assert.Equal(t, "b", runs[1].RunID)
+}
+
+func TestReadDirMissingDirectory(t *testing.T) {
+	runs, err := ReadDir(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestRunsSkipsQueuedRuns(t *testing.T) {
+	db := openStore(t)
+	now := time.Now()
+	recordRun(t, db, "b", summary.StatusFailed, now)
+	recordRun(t, db, "a", summary.StatusSucceeded, now.Add(-time.Hour))
+	require.NoError(t, db.Add(store.Run{RunID: "queued", IssueID: "DEL-2", Repo: "repo", Status: store.StatusQueued, StartedAt: now}))
+
+	runs, err := NewStore(db).Runs()
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "a", runs[0].RunID)
+	assert.Equal(t, summary.StatusSucceeded, runs[0].Status)
+	assert.Equal(t, "b", runs[1].RunID)
+}
+
+func TestQuery(t *testing.T) {
+	db := openStore(t)
+	now := time.Now()
+	recordRun(t, db, "old", summary.StatusSucceeded, now.Add(-48*time.Hour))
+	recordRun(t, db, "failed", summary.StatusFailed, now.Add(-time.Hour))
+	recordRun(t, db, "new", summary.StatusSucceeded, now)
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{name: "no filter", filter: Filter{}, expected: []string{"old", "failed", "new"}},
+		{name: "status", filter: Filter{Status: summary.StatusSucceeded}, expected: []string{"old", "new"}},
+		{name: "issue is case-insensitive", filter: Filter{Issue: "del-1"}, expected: []string{"old", "failed", "new"}},
+		{name: "repo", filter: Filter{Repo: "other"}, expected: nil},
+		{name: "since", filter: Filter{Since: now.Add(-24 * time.Hour)}, expected: []string{"failed", "new"}},
+		{name: "until", filter: Filter{Until: now.Add(-24 * time.Hour)}, expected: []string{"old"}},
+	}
+
+	store := NewStore(db)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runs, err := store.Query(test.filter)
+			require.NoError(t, err)
+			var ids []string
+			for _, run := range runs {
+				ids = append(ids, run.RunID)
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package issues defines what monday needs from the tracker its issues come from, so runs can
+// work on Linear and Jira issues alike. The tracker clients translate their issues into the
+// types of this package.
+package issues
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by providers when no issue matches the requested identifier.
+var ErrNotFound = errors.New("issue not found")
+
+// Issue is an issue as runs see it, whatever tracker it comes from. Trackers leave out what they
+// do not have, such as suggested branch names or sub-issues.
+type Issue struct {
+	// ID is the tracker's internal ID of the issue, used for API operations
+	ID string `json:"id"`
+	// Identifier is the human-readable issue key, e.g. "DEL-163"
+	Identifier string `json:"identifier"`
+	// Title is the human-readable issue title
+	Title string `json:"title"`
+	// Description contains the detailed issue description/requirements
+	Description string `json:"description"`
+	// BranchName is the suggested git branch name for this issue
+	BranchName string `json:"branchName"`
+	// URL is the direct link to view the issue in the tracker's web interface
+	URL string `json:"url"`
+	// State is the issue's current workflow state, when fetched
+	State State `json:"state"`
+	// Comments are the discussion on the issue, when fetched
+	Comments CommentsConnection `json:"comments"`
+	// Priority is the issue's priority from 1 (urgent) to 4 (low); 0 for none
+	Priority int `json:"priority"`
+	// PriorityLabel names the priority, e.g. "High"
+	PriorityLabel string `json:"priorityLabel"`
+	// Estimate is the issue's estimate in points; nil if it is not estimated
+	Estimate *float64 `json:"estimate"`
+	// Labels are the labels of the issue, when fetched
+	Labels LabelsConnection `json:"labels"`
+	// Parent is the issue this one is a sub-issue of; nil for top-level issues
+	Parent *IssueRef `json:"parent"`
+	// Children are the sub-issues of the issue, when fetched
+	Children IssueRefsConnection `json:"children"`
+	// Relations link the issue to others, e.g. ones it blocks, when fetched
+	Relations RelationsConnection `json:"relations"`
+	// Assignee is the user the issue is assigned to, when fetched; nil if unassigned
+	Assignee *User `json:"assignee"`
+}
+
+// IssueRef identifies another issue, such as the parent or a sub-issue of an issue.
+type IssueRef struct {
+	Identifier string `json:"identifier"`
+	Title      string `json:"title"`
+	State      State  `json:"state"`
+}
+
+// IssueRefsConnection is a collection of related issues.
+type IssueRefsConnection struct {
+	Nodes []IssueRef `json:"nodes"`
+}
+
+// Label is a label of an issue.
+type Label struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// LabelsConnection is a collection of labels.
+type LabelsConnection struct {
+	Nodes []Label `json:"nodes"`
+}
+
+// Relation links an issue to another one. Type is "blocks", "duplicate", "related", or
+// "similar".
+type Relation struct {
+	Type         string   `json:"type"`
+	RelatedIssue IssueRef `json:"relatedIssue"`
+}
+
+// RelationsConnection is a collection of issue relations.
+type RelationsConnection struct {
+	Nodes []Relation `json:"nodes"`
+}
+
+// CommentsConnection is a collection of comments on an issue.
+type CommentsConnection struct {
+	Nodes []Comment `json:"nodes"`
+}
+
+// Comment is a comment on an issue.
+type Comment struct {
+	// Body is the Markdown text of the comment
+	Body string `json:"body"`
+	// User is the author of the comment; nil for comments posted by integrations
+	User *User `json:"user"`
+}
+
+// User is a user of the tracker.
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// State is a state of an issue's workflow, such as In Progress. Type is the tracker's category
+// of the state, e.g. "started".
+type State struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Provider is an issue tracker runs fetch their issue from and report progress to.
+type Provider interface {
+	// FetchIssueDetails returns the issue with the given key, e.g. "DEL-163", with its current
+	// state; ErrNotFound if there is none
+	FetchIssueDetails(ctx context.Context, issueID string) (*Issue, error)
+	// MarkIssueInProgress moves the issue to In Progress
+	MarkIssueInProgress(ctx context.Context, issue *Issue) error
+	// SetIssueState moves the issue to the state with the given ID, e.g. the one it had before
+	// MarkIssueInProgress
+	SetIssueState(ctx context.Context, issue *Issue, stateID string) error
+	// CreateComment posts a Markdown comment on the issue
+	CreateComment(ctx context.Context, issue *Issue, body string) error
+}
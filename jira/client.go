@@ -0,0 +1,204 @@
+// Package jira provides a client for the REST API of Jira Cloud and Jira Data Center, for runs
+// that work on Jira issues instead of Linear ones.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"monday/issues"
+)
+
+// inProgress is the name of the status MarkIssueInProgress moves issues to.
+const inProgress = "In Progress"
+
+// Client is a client of the Jira REST API, version 2, whose descriptions and comments are
+// plain text rather than Atlassian Document Format.
+type Client struct {
+	// baseURL is the URL of the Jira site, e.g. https://acme.atlassian.net
+	baseURL string
+	// email is the account of token on Jira Cloud; empty for a Data Center personal access token
+	email string
+	// token is the API token or personal access token
+	token string
+	// client is the HTTP client with configured timeouts
+	client *http.Client
+}
+
+var _ issues.Provider = (*Client)(nil)
+
+// NewClient returns a client of the Jira site at baseURL. On Jira Cloud, email and the API
+// token of that account authenticate with basic auth; with an empty email, token is sent as a
+// bearer personal access token, as Jira Data Center expects.
+func NewClient(baseURL, email, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		email:   email,
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// issueResponse is the part of a Jira issue the client reads.
+type issueResponse struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Status      struct {
+			ID             string `json:"id"`
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// transition is a move of an issue from its status to the status To.
+type transition struct {
+	ID string `json:"id"`
+	// Name is the name of the transition, e.g. "Start progress"
+	Name string `json:"name"`
+	To   struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"to"`
+}
+
+// FetchIssueDetails returns the issue with the given key, e.g. "PROJ-42". Jira suggests no
+// branch names, so BranchName is empty.
+func (c *Client) FetchIssueDetails(ctx context.Context, issueID string) (*issues.Issue, error) {
+	var resp issueResponse
+	endpoint := "/rest/api/2/issue/" + url.PathEscape(issueID) + "?fields=summary,description,status"
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &issues.Issue{
+		ID:          resp.ID,
+		Identifier:  resp.Key,
+		Title:       resp.Fields.Summary,
+		Description: resp.Fields.Description,
+		URL:         c.baseURL + "/browse/" + resp.Key,
+		State: issues.State{
+			ID:   resp.Fields.Status.ID,
+			Name: resp.Fields.Status.Name,
+			Type: resp.Fields.Status.StatusCategory.Key,
+		},
+	}, nil
+}
+
+// MarkIssueInProgress moves the issue to the In Progress status through the transition of its
+// workflow that leads there.
+func (c *Client) MarkIssueInProgress(ctx context.Context, issue *issues.Issue) error {
+	transitions, err := c.transitions(ctx, issue)
+	if err != nil {
+		return err
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, inProgress) || strings.EqualFold(t.Name, inProgress) {
+			return c.transition(ctx, issue, t.ID)
+		}
+	}
+	return fmt.Errorf("no transition to %s from %s", inProgress, issue.State.Name)
+}
+
+// SetIssueState moves the issue to the status with the given ID, e.g. the one it had before
+// MarkIssueInProgress, if its workflow has a transition there.
+func (c *Client) SetIssueState(ctx context.Context, issue *issues.Issue, stateID string) error {
+	transitions, err := c.transitions(ctx, issue)
+	if err != nil {
+		return err
+	}
+	for _, t := range transitions {
+		if t.To.ID == stateID {
+			return c.transition(ctx, issue, t.ID)
+		}
+	}
+	return fmt.Errorf("no transition to status %s", stateID)
+}
+
+// CreateComment posts a comment on the issue. Jira shows Markdown as plain text.
+func (c *Client) CreateComment(ctx context.Context, issue *issues.Issue, body string) error {
+	endpoint := "/rest/api/2/issue/" + url.PathEscape(issue.Identifier) + "/comment"
+	if err := c.do(ctx, http.MethodPost, endpoint, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+// transitions returns the transitions available from the current status of issue.
+func (c *Client) transitions(ctx context.Context, issue *issues.Issue) ([]transition, error) {
+	var resp struct {
+		Transitions []transition `json:"transitions"`
+	}
+	endpoint := "/rest/api/2/issue/" + url.PathEscape(issue.Identifier) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list transitions: %w", err)
+	}
+	return resp.Transitions, nil
+}
+
+// transition moves issue through the transition with the given ID.
+func (c *Client) transition(ctx context.Context, issue *issues.Issue, id string) error {
+	body := map[string]any{"transition": map[string]string{"id": id}}
+	endpoint := "/rest/api/2/issue/" + url.PathEscape(issue.Identifier) + "/transitions"
+	if err := c.do(ctx, http.MethodPost, endpoint, body, nil); err != nil {
+		return fmt.Errorf("failed to transition issue: %w", err)
+	}
+	return nil
+}
+
+// do sends a request with the JSON encoding of body, if any, to endpoint and decodes the JSON
+// response into out, if not nil. A 404 is issues.ErrNotFound, which runs report the same way
+// whatever the tracker.
+func (c *Client) do(ctx context.Context, method, endpoint string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound && method == http.MethodGet:
+		return fmt.Errorf("%w: %s", issues.ErrNotFound, strings.TrimSpace(string(data)))
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("Jira API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	case out == nil:
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
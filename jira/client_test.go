@@ -0,0 +1,121 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/issues"
+)
+
+func TestFetchIssueDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/PROJ-42", r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "dev@acme.io", user)
+		assert.Equal(t, "api-token", pass)
+		w.Write([]byte(`{"id":"10042","key":"PROJ-42","fields":{"summary":"Fix login","description":"Users cannot log in",
+			"status":{"id":"1","name":"To Do","statusCategory":{"key":"new"}}}}`))
+	}))
+	defer server.Close()
+
+	issue, err := NewClient(server.URL+"/", "dev@acme.io", "api-token").FetchIssueDetails(context.Background(), "PROJ-42")
+	require.NoError(t, err)
+	assert.Equal(t, issues.Issue{
+		ID:          "10042",
+		Identifier:  "PROJ-42",
+		Title:       "Fix login",
+		Description: "Users cannot log in",
+		URL:         server.URL + "/browse/PROJ-42",
+		State:       issues.State{ID: "1", Name: "To Do", Type: "new"},
+	}, *issue)
+}
+
+func TestFetchIssueDetails_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer pat", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "", "pat").FetchIssueDetails(context.Background(), "PROJ-404")
+	assert.ErrorIs(t, err, issues.ErrNotFound)
+}
+
+func TestTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		move    func(c *Client, issue *issues.Issue) error
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "in progress",
+			move: func(c *Client, issue *issues.Issue) error { return c.MarkIssueInProgress(context.Background(), issue) },
+			want: "21",
+		},
+		{
+			name: "restore",
+			move: func(c *Client, issue *issues.Issue) error { return c.SetIssueState(context.Background(), issue, "1") },
+			want: "11",
+		},
+		{
+			name:    "unknown state",
+			move:    func(c *Client, issue *issues.Issue) error { return c.SetIssueState(context.Background(), issue, "99") },
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rest/api/2/issue/PROJ-42/transitions", r.URL.Path)
+				if r.Method == http.MethodGet {
+					w.Write([]byte(`{"transitions":[{"id":"11","name":"Back to do","to":{"id":"1","name":"To Do"}},
+						{"id":"21","name":"Start progress","to":{"id":"3","name":"In Progress"}}]}`))
+					return
+				}
+				var body struct {
+					Transition struct {
+						ID string `json:"id"`
+					} `json:"transition"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				got = body.Transition.ID
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			err := test.move(NewClient(server.URL, "dev@acme.io", "api-token"), &issues.Issue{Identifier: "PROJ-42"})
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issue/PROJ-42/comment", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"100"}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "dev@acme.io", "api-token").CreateComment(context.Background(), &issues.Issue{Identifier: "PROJ-42"}, "Pull request opened")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"body": "Pull request opened"}, got)
+}
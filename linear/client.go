@@ -5,6 +5,7 @@ package linear
 
 import (
         "bytes"
+        "context"
         "encoding/json"
         "fmt"
         "io"
@@ -13,25 +14,45 @@ import (
         "strconv"
         "strings"
         "time"
+
+        "monday/issues"
+        "monday/retry"
 )
 
 // DefaultLinearEndpoint is the standard Linear API GraphQL endpoint
 const DefaultLinearEndpoint = "https://api.linear.app/graphql"
 
-// IssueDetails represents the essential information about a Linear issue
-// that is needed for creating development environments and tracking progress.
-type IssueDetails struct {
-        // ID is the internal UUID used by Linear for API operations
-        ID          string `json:"id"`
-        // Title is the human-readable issue title
-        Title       string `json:"title"`
-        // Description contains the detailed issue description/requirements
-        Description string `json:"description"`
-        // BranchName is the suggested git branch name for this issue
-        BranchName  string `json:"branchName"`
-        // URL is the direct link to view the issue in Linear's web interface
-        URL         string `json:"url"`
-}
+// The issue model is shared with the other trackers through package issues; these names are
+// kept for the Linear API's clients.
+type (
+        // IssueDetails is a Linear issue
+        IssueDetails = issues.Issue
+        // IssueRef identifies another issue, such as the parent or a sub-issue of an issue
+        IssueRef = issues.IssueRef
+        // IssueRefsConnection is a collection of related issues
+        IssueRefsConnection = issues.IssueRefsConnection
+        // Label is a Linear issue label
+        Label = issues.Label
+        // LabelsConnection is a collection of labels
+        LabelsConnection = issues.LabelsConnection
+        // Relation links an issue to another one
+        Relation = issues.Relation
+        // RelationsConnection is a collection of issue relations
+        RelationsConnection = issues.RelationsConnection
+        // CommentsConnection is a collection of comments on an issue
+        CommentsConnection = issues.CommentsConnection
+        // Comment is a comment on a Linear issue
+        Comment = issues.Comment
+        // User is a Linear user
+        User = issues.User
+        // WorkflowState is a state of a Linear team's workflow, such as "In Progress"
+        WorkflowState = issues.State
+)
+
+// ErrIssueNotFound is returned when no issue matches the requested identifier.
+var ErrIssueNotFound = issues.ErrNotFound
+
+var _ issues.Provider = (*Client)(nil)
 
 // GraphQLRequest represents a standard GraphQL request structure
 // with query string and variables for parameterized queries.
@@ -88,7 +109,13 @@ type Project struct {
 // GraphQLError represents an error returned by the Linear GraphQL API
 // with a human-readable error message.
 type GraphQLError struct {
-        Message string `json:"message"`
+        Message    string                 `json:"message"`
+        Extensions GraphQLErrorExtensions `json:"extensions"`
+}
+
+// GraphQLErrorExtensions classifies a GraphQL error, e.g. with code "RATELIMITED".
+type GraphQLErrorExtensions struct {
+        Code string `json:"code"`
 }
 
 // IssueUpdateResponse represents the response from issue mutation operations
@@ -108,30 +135,70 @@ type IssueUpdateResult struct {
         Success bool `json:"success"`
 }
 
+// AttachmentCreateResponse represents the response from an attachment creation mutation.
+type AttachmentCreateResponse struct {
+        Data struct {
+                AttachmentCreate IssueUpdateResult `json:"attachmentCreate"`
+        } `json:"data"`
+        Errors []GraphQLError `json:"errors"`
+}
+
+// CommentCreateResponse represents the response from the commentCreate mutation.
+type CommentCreateResponse struct {
+        Data   CommentCreateData `json:"data"`
+        Errors []GraphQLError    `json:"errors"`
+}
+
+// CommentCreateData contains the result of a comment creation mutation.
+type CommentCreateData struct {
+        CommentCreate IssueUpdateResult `json:"commentCreate"`
+}
+
 // Client provides authenticated access to the Linear API with configurable endpoints
 // and timeout settings for reliable API communication.
 type Client struct {
         // apiKey is the Linear API authentication token
         apiKey   string
+        // tokens supplies OAuth access tokens sent as Bearer tokens instead of apiKey; nil for
+        // personal API keys
+        tokens   TokenSource
         // endpoint is the GraphQL API URL (configurable for testing)
         endpoint string
         // client is the HTTP client with configured timeouts
         client   *http.Client
+        // limiter throttles requests to stay within the API key's rate limits
+        limiter  *RateLimiter
 }
 
-// NewClient creates a new Linear API client with the provided API key.
+// NewClient creates a new Linear API client with the provided API key. An OAuth access token,
+// starting with "lin_oauth_", is sent as a Bearer token instead.
 // It initializes the client with the default Linear endpoint and a 30-second timeout
-// for reliable API communication even under network latency.
+// for reliable API communication even under network latency. Transient failures are
+// retried with retry.DefaultPolicy, and requests are throttled by a rate limiter shared by
+// all clients of the process.
 func NewClient(apiKey string) *Client {
+        var tokens TokenSource
+        if strings.HasPrefix(apiKey, "lin_oauth_") {
+                tokens = StaticToken(apiKey)
+        }
         return &Client{
                 apiKey:   apiKey,
+                tokens:   tokens,
                 endpoint: DefaultLinearEndpoint,
                 client: &http.Client{
-                        Timeout: 30 * time.Second,
+                        Timeout:   30 * time.Second,
+                        Transport: &retry.Transport{Policy: retry.DefaultPolicy},
                 },
+                limiter: sharedLimiter,
         }
 }
 
+// SetRetryPolicy replaces the policy transient failures of API calls are retried with.
+// The 30-second timeout covers each call with all of its retries.
+func (c *Client) SetRetryPolicy(policy retry.Policy) {
+        c.client.Transport = &retry.Transport{Policy: policy}
+}
+
 // SetEndpoint allows overriding the Linear API endpoint URL.
 // This is primarily used for testing with mock servers or custom Linear instances.
 func (c *Client) SetEndpoint(endpoint string) {
@@ -141,7 +208,7 @@ func (c *Client) SetEndpoint(endpoint string) {
 // FetchIssueDetails retrieves comprehensive information about a Linear issue by its identifier.
 // It accepts issue identifiers in the format "TEAM-123" (e.g., "DEL-163") and returns
 // all necessary details for creating development environments and tracking progress.
-func (c *Client) FetchIssueDetails(issueID string) (*IssueDetails, error) {
+func (c *Client) FetchIssueDetails(ctx context.Context, issueID string) (*IssueDetails, error) {
         // Parse the issue identifier into team key and issue number
         teamKey, number, err := parseIssueIdentifier(issueID)
         if err != nil {
@@ -158,10 +225,68 @@ func (c *Client) FetchIssueDetails(issueID string) (*IssueDetails, error) {
                         }, first: 1) {
                                 nodes {
                                         id
+                                        identifier
                                         title
                                         description
                                         branchName
                                         url
+                                        state {
+                                                id
+                                                name
+                                                type
+                                        }
+                                        comments(first: 50) {
+                                                nodes {
+                                                        body
+                                                        user {
+                                                                id
+                                                                name
+                                                        }
+                                                }
+                                        }
+                                        priority
+                                        priorityLabel
+                                        estimate
+                                        labels {
+                                                nodes {
+                                                        id
+                                                        name
+                                                }
+                                        }
+                                        parent {
+                                                identifier
+                                                title
+                                                state {
+                                                        id
+                                                        name
+                                                        type
+                                                }
+                                        }
+                                        children(first: 50) {
+                                                nodes {
+                                                        identifier
+                                                        title
+                                                        state {
+                                                                id
+                                                                name
+                                                                type
+                                                        }
+                                                }
+                                        }
+                                        relations(first: 50) {
+                                                nodes {
+                                                        type
+                                                        relatedIssue {
+                                                                identifier
+                                                                title
+                                                                state {
+                                                                        id
+                                                                        name
+                                                                        type
+                                                                }
+                                                        }
+                                                }
+                                        }
                                 }
                         }
                 }
@@ -183,17 +308,16 @@ func (c *Client) FetchIssueDetails(issueID string) (*IssueDetails, error) {
         }
 
         // Create HTTP POST request to Linear's GraphQL endpoint
-        req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(jsonData))
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
         if err != nil {
                 return nil, fmt.Errorf("failed to create HTTP request: %w", err)
         }
 
-        // Set required headers for Linear API authentication and content type
+        // Set the content type; do adds the Authorization header
         req.Header.Set("Content-Type", "application/json")
-        req.Header.Set("Authorization", c.apiKey) // Linear expects API key directly, not Bearer token
 
         // Execute the HTTP request
-        resp, err := c.client.Do(req)
+        resp, err := c.do(req)
         if err != nil {
                 return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
         }
@@ -218,7 +342,7 @@ func (c *Client) FetchIssueDetails(issueID string) (*IssueDetails, error) {
 
         // Verify that the issue was found
         if len(response.Data.Issues.Nodes) == 0 {
-                return nil, fmt.Errorf("issue not found: %s", issueID)
+                return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, issueID)
         }
 
         // Return the first (and only) issue from the results
@@ -228,13 +352,143 @@ func (c *Client) FetchIssueDetails(issueID string) (*IssueDetails, error) {
 // MarkIssueInProgress updates the status of a Linear issue to "In Progress".
 // This automatically moves the issue through the workflow to indicate active development.
 // It first looks up the "In Progress" state ID for the issue's team, then updates the issue.
-func (c *Client) MarkIssueInProgress(issue *IssueDetails) error {
+func (c *Client) MarkIssueInProgress(ctx context.Context, issue *IssueDetails) error {
         // First, find the "In Progress" state ID for this team's workflow
-        stateID, err := c.getInProgressStateID()
+        stateID, err := c.getStateID(ctx, "In Progress")
         if err != nil {
                 return fmt.Errorf("failed to get In Progress state ID: %w", err)
         }
 
+        return c.SetIssueState(ctx, issue, stateID)
+}
+
+// MarkIssueInReview updates the status of a Linear issue to "In Review", e.g. once a pull
+// request for it is open.
+func (c *Client) MarkIssueInReview(ctx context.Context, issue *IssueDetails) error {
+        stateID, err := c.getStateID(ctx, "In Review")
+        if err != nil {
+                return fmt.Errorf("failed to get In Review state ID: %w", err)
+        }
+
+        return c.SetIssueState(ctx, issue, stateID)
+}
+
+// AssignIssue assigns the issue with the given internal ID to the Linear user with the given ID.
+func (c *Client) AssignIssue(ctx context.Context, issueID, userID string) error {
+        // GraphQL mutation to update the issue's assignee
+        mutation := `
+                mutation AssignIssue($id: String!, $assigneeId: String!) {
+                        issueUpdate(id: $id, input: { assigneeId: $assigneeId }) {
+                                success
+                        }
+                }
+        `
+
+        request := GraphQLRequest{
+                Query: mutation,
+                Variables: map[string]interface{}{
+                        "id":         issueID,
+                        "assigneeId": userID,
+                },
+        }
+
+        jsonData, err := json.Marshal(request)
+        if err != nil {
+                return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+        }
+
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
+        if err != nil {
+                return fmt.Errorf("failed to create HTTP request: %w", err)
+        }
+        req.Header.Set("Content-Type", "application/json")
+
+        resp, err := c.do(req)
+        if err != nil {
+                return fmt.Errorf("failed to execute HTTP request: %w", err)
+        }
+        defer resp.Body.Close()
+
+        if resp.StatusCode != http.StatusOK {
+                body, _ := io.ReadAll(resp.Body)
+                return fmt.Errorf("Linear API returned status %d: %s", resp.StatusCode, string(body))
+        }
+
+        var response IssueUpdateResponse
+        if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+                return fmt.Errorf("failed to decode GraphQL response: %w", err)
+        }
+        if len(response.Errors) > 0 {
+                return fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+        }
+        if !response.Data.IssueUpdate.Success {
+                return fmt.Errorf("failed to assign issue")
+        }
+
+        return nil
+}
+
+// AttachURL attaches the URL, e.g. of a pull request, to the issue with the given internal ID.
+// Linear shows it in the issue's attachments panel and, for GitHub pull requests, keeps the
+// issue in sync with the pull request's status.
+func (c *Client) AttachURL(ctx context.Context, issueID, title, url string) error {
+        // GraphQL mutation to create the attachment
+        mutation := `
+                mutation AttachURL($issueId: String!, $title: String!, $url: String!) {
+                        attachmentCreate(input: { issueId: $issueId, title: $title, url: $url }) {
+                                success
+                        }
+                }
+        `
+
+        request := GraphQLRequest{
+                Query: mutation,
+                Variables: map[string]interface{}{
+                        "issueId": issueID,
+                        "title":   title,
+                        "url":     url,
+                },
+        }
+
+        jsonData, err := json.Marshal(request)
+        if err != nil {
+                return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+        }
+
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
+        if err != nil {
+                return fmt.Errorf("failed to create HTTP request: %w", err)
+        }
+        req.Header.Set("Content-Type", "application/json")
+
+        resp, err := c.do(req)
+        if err != nil {
+                return fmt.Errorf("failed to execute HTTP request: %w", err)
+        }
+        defer resp.Body.Close()
+
+        if resp.StatusCode != http.StatusOK {
+                body, _ := io.ReadAll(resp.Body)
+                return fmt.Errorf("Linear API returned status %d: %s", resp.StatusCode, string(body))
+        }
+
+        var response AttachmentCreateResponse
+        if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+                return fmt.Errorf("failed to decode GraphQL response: %w", err)
+        }
+        if len(response.Errors) > 0 {
+                return fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+        }
+        if !response.Data.AttachmentCreate.Success {
+                return fmt.Errorf("failed to create attachment")
+        }
+
+        return nil
+}
+
+// SetIssueState moves the issue to the workflow state with the given ID,
+// e.g. to restore the state it had before MarkIssueInProgress.
+func (c *Client) SetIssueState(ctx context.Context, issue *IssueDetails, stateID string) error {
         // GraphQL mutation to update the issue's state
         mutation := `
                 mutation UpdateIssue($id: String!, $stateId: String!) {
@@ -249,7 +503,7 @@ func (c *Client) MarkIssueInProgress(issue *IssueDetails) error {
                 Query: mutation,
                 Variables: map[string]interface{}{
                         "id":      issue.ID,      // Internal UUID of the issue
-                        "stateId": stateID,       // UUID of the "In Progress" state
+                        "stateId": stateID,       // UUID of the target state
                 },
         }
 
@@ -260,17 +514,16 @@ func (c *Client) MarkIssueInProgress(issue *IssueDetails) error {
         }
 
         // Create HTTP POST request
-        req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(jsonData))
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
         if err != nil {
                 return fmt.Errorf("failed to create HTTP request: %w", err)
         }
 
         // Set authentication and content type headers
         req.Header.Set("Content-Type", "application/json")
-        req.Header.Set("Authorization", c.apiKey)
 
         // Execute the mutation
-        resp, err := c.client.Do(req)
+        resp, err := c.do(req)
         if err != nil {
                 return fmt.Errorf("failed to execute HTTP request: %w", err)
         }
@@ -301,10 +554,11 @@ func (c *Client) MarkIssueInProgress(issue *IssueDetails) error {
         return nil
 }
 
-// getInProgressStateID dynamically looks up the "In Progress" workflow state ID.
+// getStateID dynamically looks up the ID of the workflow state called name, such as
+// "In Progress" or "In Review".
 // Different Linear workspaces may have different state configurations, so we query
-// all available workflow states and find the one that matches "In Progress" criteria.
-func (c *Client) getInProgressStateID() (string, error) {
+// all available workflow states and find the started one with that name.
+func (c *Client) getStateID(ctx context.Context, name string) (string, error) {
         // GraphQL query to fetch all workflow states across the workspace
         query := `
                 query GetWorkflowStates {
@@ -331,17 +585,16 @@ func (c *Client) getInProgressStateID() (string, error) {
         }
 
         // Create HTTP POST request
-        req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(jsonData))
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
         if err != nil {
                 return "", fmt.Errorf("failed to create HTTP request: %w", err)
         }
 
         // Set authentication headers
         req.Header.Set("Content-Type", "application/json")
-        req.Header.Set("Authorization", c.apiKey)
 
         // Execute the request
-        resp, err := c.client.Do(req)
+        resp, err := c.do(req)
         if err != nil {
                 return "", fmt.Errorf("failed to execute HTTP request: %w", err)
         }
@@ -377,15 +630,15 @@ func (c *Client) getInProgressStateID() (string, error) {
                 return "", fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
         }
 
-        // Search for the "In Progress" state with type "started"
-        // Linear uses "started" type for active development states
+        // Search for the state with type "started"
+        // Linear uses "started" type for active development and review states
         for _, state := range response.Data.WorkflowStates.Nodes {
-                if state.Name == "In Progress" && state.Type == "started" {
+                if state.Name == name && state.Type == "started" {
                         return state.ID, nil
                 }
         }
 
-        return "", fmt.Errorf("In Progress state not found")
+        return "", fmt.Errorf("%s state not found", name)
 }
 
 // parseIssueIdentifier extracts team key and issue number from Linear issue identifiers.
@@ -414,24 +667,56 @@ func parseIssueIdentifier(identifier string) (string, int, error) {
         return teamKey, number, nil
 }
 
-// FetchIssuesByFilters retrieves issues based on team, project, and tag filters
-func (c *Client) FetchIssuesByFilters(teamKey, projectKey, tag string) ([]IssueDetails, error) {
+// IssueFilter selects the issues returned by FetchIssuesByFilters. Empty fields match every
+// issue.
+type IssueFilter struct {
+        // Team is the key of the issues' team, e.g. "DEL"
+        Team     string
+        // Project is the key of the issues' project
+        Project  string
+        // Label is the name of a label of the issues
+        Label    string
+        // State is the name of the issues' workflow state, e.g. "Todo", in any case
+        State    string
+        // Assignee is the email or name of the issues' assignee, "me" for the authenticated
+        // user, or "none" for unassigned issues
+        Assignee string
+}
+
+// FetchIssuesByFilters retrieves up to 50 issues matching filter, newest first
+func (c *Client) FetchIssuesByFilters(ctx context.Context, filter IssueFilter) ([]IssueDetails, error) {
         var filters []string
         var variables = make(map[string]interface{})
         
-        if teamKey != "" {
+        if filter.Team != "" {
                 filters = append(filters, "team: { key: { eq: $teamKey } }")
-                variables["teamKey"] = teamKey
+                variables["teamKey"] = filter.Team
         }
         
-        if projectKey != "" {
+        if filter.Project != "" {
                 filters = append(filters, "project: { key: { eq: $projectKey } }")
-                variables["projectKey"] = projectKey
+                variables["projectKey"] = filter.Project
         }
         
-        if tag != "" {
+        if filter.Label != "" {
                 filters = append(filters, "labels: { name: { eq: $tag } }")
-                variables["tag"] = tag
+                variables["tag"] = filter.Label
+        }
+        
+        if filter.State != "" {
+                filters = append(filters, "state: { name: { eqIgnoreCase: $state } }")
+                variables["state"] = filter.State
+        }
+        
+        switch strings.ToLower(filter.Assignee) {
+        case "":
+        case "me":
+                filters = append(filters, "assignee: { isMe: { eq: true } }")
+        case "none":
+                filters = append(filters, "assignee: { null: true }")
+        default:
+                filters = append(filters, "assignee: { or: [{ email: { eqIgnoreCase: $assignee } }, { name: { eqIgnoreCase: $assignee } }, { displayName: { eqIgnoreCase: $assignee } }] }")
+                variables["assignee"] = filter.Assignee
         }
         
         filterStr := ""
@@ -440,14 +725,26 @@ func (c *Client) FetchIssuesByFilters(teamKey, projectKey, tag string) ([]IssueD
         }
         
         query := fmt.Sprintf(`
-                query GetIssues($teamKey: String, $projectKey: String, $tag: String) {
+                query GetIssues($teamKey: String, $projectKey: String, $tag: String, $state: String, $assignee: String) {
                         issues(%s, first: 50, orderBy: createdAt) {
                                 nodes {
                                         id
+                                        identifier
                                         title
                                         description
                                         branchName
                                         url
+                                        priority
+                                        priorityLabel
+                                        state {
+                                                id
+                                                name
+                                                type
+                                        }
+                                        assignee {
+                                                id
+                                                name
+                                        }
                                 }
                         }
                 }
@@ -463,15 +760,14 @@ func (c *Client) FetchIssuesByFilters(teamKey, projectKey, tag string) ([]IssueD
                 return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
         }
         
-        req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(jsonData))
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
         if err != nil {
                 return nil, fmt.Errorf("failed to create HTTP request: %w", err)
         }
         
         req.Header.Set("Content-Type", "application/json")
-        req.Header.Set("Authorization", c.apiKey)
         
-        resp, err := c.client.Do(req)
+        resp, err := c.do(req)
         if err != nil {
                 return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
         }
@@ -495,7 +791,7 @@ func (c *Client) FetchIssuesByFilters(teamKey, projectKey, tag string) ([]IssueD
 }
 
 // FetchTeams retrieves all teams available to the authenticated user
-func (c *Client) FetchTeams() ([]Team, error) {
+func (c *Client) FetchTeams(ctx context.Context) ([]Team, error) {
         query := `
                 query GetTeams {
                         teams {
@@ -525,15 +821,14 @@ func (c *Client) FetchTeams() ([]Team, error) {
                 return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
         }
         
-        req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(jsonData))
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
         if err != nil {
                 return nil, fmt.Errorf("failed to create HTTP request: %w", err)
         }
         
         req.Header.Set("Content-Type", "application/json")
-        req.Header.Set("Authorization", c.apiKey)
         
-        resp, err := c.client.Do(req)
+        resp, err := c.do(req)
         if err != nil {
                 return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
         }
@@ -555,3 +850,70 @@ func (c *Client) FetchTeams() ([]Team, error) {
         
         return response.Data.Teams.Nodes, nil
 }
+
+// CreateComment posts a comment on the given issue. The body is rendered by Linear as Markdown.
+func (c *Client) CreateComment(ctx context.Context, issue *IssueDetails, body string) error {
+        // GraphQL mutation to add a comment to the issue
+        mutation := `
+                mutation CreateComment($issueId: String!, $body: String!) {
+                        commentCreate(input: { issueId: $issueId, body: $body }) {
+                                success
+                        }
+                }
+        `
+
+        // Prepare the mutation request with the issue ID and comment body
+        request := GraphQLRequest{
+                Query: mutation,
+                Variables: map[string]interface{}{
+                        "issueId": issue.ID, // Internal UUID of the issue
+                        "body":    body,
+                },
+        }
+
+        // Marshal the request to JSON
+        jsonData, err := json.Marshal(request)
+        if err != nil {
+                return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+        }
+
+        // Create HTTP POST request
+        req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
+        if err != nil {
+                return fmt.Errorf("failed to create HTTP request: %w", err)
+        }
+
+        // Set authentication and content type headers
+        req.Header.Set("Content-Type", "application/json")
+
+        // Execute the mutation
+        resp, err := c.do(req)
+        if err != nil {
+                return fmt.Errorf("failed to execute HTTP request: %w", err)
+        }
+        defer resp.Body.Close()
+
+        // Check for HTTP-level errors
+        if resp.StatusCode != http.StatusOK {
+                body, _ := io.ReadAll(resp.Body)
+                return fmt.Errorf("Linear API returned status %d: %s", resp.StatusCode, string(body))
+        }
+
+        // Parse the mutation response
+        var response CommentCreateResponse
+        if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+                return fmt.Errorf("failed to decode GraphQL response: %w", err)
+        }
+
+        // Check for GraphQL-level errors
+        if len(response.Errors) > 0 {
+                return fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+        }
+
+        // Verify that the comment was created
+        if !response.Data.CommentCreate.Success {
+                return fmt.Errorf("failed to create comment")
+        }
+
+        return nil
+}
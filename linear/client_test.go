@@ -1,22 +1,38 @@
 package linear
 
 import (
+        "context"
         "encoding/json"
         "net/http"
         "net/http/httptest"
         "testing"
+        "time"
 
         "github.com/stretchr/testify/assert"
         "github.com/stretchr/testify/require"
+
+        "monday/retry"
 )
 
 func TestFetchIssueDetails_Success(t *testing.T) {
+        estimate := 3.0
         expectedIssue := IssueDetails{
                 ID:          "ISSUE-123",
                 Title:       "Fix authentication bug",
                 Description: "This is a detailed description of the authentication bug that needs to be fixed.",
                 BranchName:  "issue-123-fix-authentication-bug",
                 URL:         "https://linear.app/team/issue/ISSUE-123",
+                Comments: CommentsConnection{Nodes: []Comment{
+                        {Body: "Happens only with SSO", User: &User{ID: "user-1", Name: "Ada"}},
+                        {Body: "Linked from Sentry"},
+                }},
+                Priority:      2,
+                PriorityLabel: "High",
+                Estimate:      &estimate,
+                Labels:        LabelsConnection{Nodes: []Label{{ID: "label-1", Name: "bug"}}},
+                Parent:        &IssueRef{Identifier: "DEL-100", Title: "Auth overhaul"},
+                Children:      IssueRefsConnection{Nodes: []IssueRef{{Identifier: "DEL-124", Title: "Add SSO test", State: WorkflowState{Name: "Todo"}}}},
+                Relations:     RelationsConnection{Nodes: []Relation{{Type: "blocks", RelatedIssue: IssueRef{Identifier: "DEL-130", Title: "Ship SSO"}}}},
         }
 
         server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -28,6 +44,7 @@ func TestFetchIssueDetails_Success(t *testing.T) {
                 json.NewDecoder(r.Body).Decode(&req)
                 assert.Contains(t, req.Query, "$teamKey")
                 assert.Contains(t, req.Query, "$number")
+                assert.Contains(t, req.Query, "relatedIssue")
                 assert.Equal(t, "DEL", req.Variables["teamKey"])
                 assert.Equal(t, float64(123), req.Variables["number"])
 
@@ -45,7 +62,7 @@ func TestFetchIssueDetails_Success(t *testing.T) {
         client := NewClient("test-api-key")
         client.endpoint = server.URL
 
-        issue, err := client.FetchIssueDetails("DEL-123")
+        issue, err := client.FetchIssueDetails(context.Background(), "DEL-123")
         require.NoError(t, err)
         assert.Equal(t, expectedIssue, *issue)
 }
@@ -60,7 +77,7 @@ func TestFetchIssueDetails_HTTPError(t *testing.T) {
         client := NewClient("test-api-key")
         client.endpoint = server.URL
 
-        issue, err := client.FetchIssueDetails("DEL-999")
+        issue, err := client.FetchIssueDetails(context.Background(), "DEL-999")
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "404")
         assert.Nil(t, issue)
@@ -80,7 +97,7 @@ func TestFetchIssueDetails_GraphQLError(t *testing.T) {
         client := NewClient("test-api-key")
         client.endpoint = server.URL
 
-        issue, err := client.FetchIssueDetails("DEL-999")
+        issue, err := client.FetchIssueDetails(context.Background(), "DEL-999")
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "Issue not found")
         assert.Nil(t, issue)
@@ -95,7 +112,7 @@ func TestFetchIssueDetails_MalformedJSON(t *testing.T) {
         client := NewClient("test-api-key")
         client.endpoint = server.URL
 
-        issue, err := client.FetchIssueDetails("DEL-123")
+        issue, err := client.FetchIssueDetails(context.Background(), "DEL-123")
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "decode")
         assert.Nil(t, issue)
@@ -103,13 +120,57 @@ func TestFetchIssueDetails_MalformedJSON(t *testing.T) {
 
 func TestFetchIssueDetails_NetworkError(t *testing.T) {
         client := NewClient("test-api-key")
+        client.SetRetryPolicy(retry.Policy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
         client.endpoint = "http://nonexistent-server:12345"
 
-        issue, err := client.FetchIssueDetails("DEL-123")
+        issue, err := client.FetchIssueDetails(context.Background(), "DEL-123")
         assert.Error(t, err)
         assert.Nil(t, issue)
 }
 
+func TestFetchIssueDetails_RetriesBadGateway(t *testing.T) {
+        calls := 0
+        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                calls++
+                if calls == 1 {
+                        w.WriteHeader(http.StatusBadGateway)
+                        return
+                }
+                var req GraphQLRequest
+                require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+                assert.Equal(t, "DEL", req.Variables["teamKey"])
+                json.NewEncoder(w).Encode(GraphQLResponse{
+                        Data: GraphQLData{Issues: IssuesConnection{Nodes: []IssueDetails{{ID: "ISSUE-123"}}}},
+                })
+        }))
+        defer server.Close()
+
+        client := NewClient("test-api-key")
+        client.SetRetryPolicy(retry.Policy{MaxAttempts: 2, InitialBackoff: time.Millisecond, RetryOn: []int{http.StatusBadGateway}})
+        client.endpoint = server.URL
+
+        issue, err := client.FetchIssueDetails(context.Background(), "DEL-123")
+        require.NoError(t, err)
+        assert.Equal(t, "ISSUE-123", issue.ID)
+        assert.Equal(t, 2, calls)
+}
+
+func TestFetchIssueDetails_Canceled(t *testing.T) {
+        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                t.Error("request sent despite the canceled context")
+        }))
+        defer server.Close()
+
+        client := NewClient("test-api-key")
+        client.endpoint = server.URL
+
+        ctx, cancel := context.WithCancel(context.Background())
+        cancel()
+        issue, err := client.FetchIssueDetails(ctx, "DEL-123")
+        assert.ErrorIs(t, err, context.Canceled)
+        assert.Nil(t, issue)
+}
+
 func TestGraphQLQuery_Structure(t *testing.T) {
         var receivedQuery GraphQLRequest
 
@@ -135,7 +196,7 @@ func TestGraphQLQuery_Structure(t *testing.T) {
         client := NewClient("test-api-key")
         client.endpoint = server.URL
 
-        _, err := client.FetchIssueDetails("DEL-123")
+        _, err := client.FetchIssueDetails(context.Background(), "DEL-123")
         require.NoError(t, err)
 
         assert.Contains(t, receivedQuery.Query, "query")
@@ -157,7 +218,7 @@ func TestMarkIssueInProgress_Success(t *testing.T) {
 
                 callCount++
                 if callCount == 1 {
-                        // First call: getInProgressStateID
+                        // First call: getStateID
                         response := map[string]interface{}{
                                 "data": map[string]interface{}{
                                         "workflowStates": map[string]interface{}{
@@ -195,7 +256,7 @@ func TestMarkIssueInProgress_Success(t *testing.T) {
                 BranchName: "test-branch",
                 URL:        "https://linear.app/test",
         }
-        err := client.MarkIssueInProgress(issue)
+        err := client.MarkIssueInProgress(context.Background(), issue)
         require.NoError(t, err)
         assert.Equal(t, 2, callCount)
 }
@@ -211,7 +272,7 @@ func TestMarkIssueInProgress_HTTPError(t *testing.T) {
         client.endpoint = server.URL
 
         issue := &IssueDetails{ID: "uuid-123"}
-        err := client.MarkIssueInProgress(issue)
+        err := client.MarkIssueInProgress(context.Background(), issue)
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "401")
 }
@@ -231,7 +292,7 @@ func TestMarkIssueInProgress_GraphQLError(t *testing.T) {
         client.endpoint = server.URL
 
         issue := &IssueDetails{ID: "uuid-123"}
-        err := client.MarkIssueInProgress(issue)
+        err := client.MarkIssueInProgress(context.Background(), issue)
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "Issue not found or access denied")
 }
@@ -247,7 +308,7 @@ func TestMarkIssueInProgress_MutationStructure(t *testing.T) {
                 
                 callCount++
                 if callCount == 1 {
-                        // First call: getInProgressStateID
+                        // First call: getStateID
                         response := map[string]interface{}{
                                 "data": map[string]interface{}{
                                         "workflowStates": map[string]interface{}{
@@ -285,7 +346,7 @@ func TestMarkIssueInProgress_MutationStructure(t *testing.T) {
                 BranchName: "test-branch",
                 URL:        "https://linear.app/test",
         }
-        err := client.MarkIssueInProgress(issue)
+        err := client.MarkIssueInProgress(context.Background(), issue)
         require.NoError(t, err)
 
         require.Len(t, receivedQueries, 2)
@@ -330,11 +391,107 @@ func TestMarkIssueInProgress_StateNotFound(t *testing.T) {
         client.endpoint = server.URL
 
         issue := &IssueDetails{ID: "uuid-123"}
-        err := client.MarkIssueInProgress(issue)
+        err := client.MarkIssueInProgress(context.Background(), issue)
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "In Progress state not found")
 }
 
+func TestMarkIssueInReview(t *testing.T) {
+        var update GraphQLRequest
+        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                var query GraphQLRequest
+                require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+                if query.Variables["stateId"] == nil {
+                        w.Write([]byte(`{"data":{"workflowStates":{"nodes":[
+                                {"id":"state-1","name":"In Progress","type":"started"},
+                                {"id":"state-2","name":"In Review","type":"started"}]}}}`))
+                        return
+                }
+                update = query
+                w.Write([]byte(`{"data":{"issueUpdate":{"success":true}}}`))
+        }))
+        defer server.Close()
+
+        client := NewClient("test-api-key")
+        client.endpoint = server.URL
+
+        err := client.MarkIssueInReview(context.Background(), &IssueDetails{ID: "uuid-123"})
+        require.NoError(t, err)
+        assert.Equal(t, "uuid-123", update.Variables["id"])
+        assert.Equal(t, "state-2", update.Variables["stateId"])
+}
+
+func TestAssignIssue(t *testing.T) {
+        tests := []struct {
+                name     string
+                response string
+                wantErr  string
+        }{
+                {name: "success", response: `{"data":{"issueUpdate":{"success":true}}}`},
+                {name: "unknown user", response: `{"errors":[{"message":"Entity not found: User"}]}`, wantErr: "Entity not found: User"},
+                {name: "not updated", response: `{"data":{"issueUpdate":{"success":false}}}`, wantErr: "failed to assign issue"},
+        }
+        for _, test := range tests {
+                t.Run(test.name, func(t *testing.T) {
+                        var got GraphQLRequest
+                        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                                require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+                                w.Write([]byte(test.response))
+                        }))
+                        defer server.Close()
+
+                        client := NewClient("test-api-key")
+                        client.endpoint = server.URL
+
+                        err := client.AssignIssue(context.Background(), "uuid-123", "user-42")
+                        assert.Contains(t, got.Query, "assigneeId")
+                        assert.Equal(t, "uuid-123", got.Variables["id"])
+                        assert.Equal(t, "user-42", got.Variables["assigneeId"])
+                        if test.wantErr != "" {
+                                assert.ErrorContains(t, err, test.wantErr)
+                                return
+                        }
+                        assert.NoError(t, err)
+                })
+        }
+}
+
+func TestAttachURL(t *testing.T) {
+        tests := []struct {
+                name     string
+                response string
+                wantErr  string
+        }{
+                {name: "success", response: `{"data":{"attachmentCreate":{"success":true}}}`},
+                {name: "invalid url", response: `{"errors":[{"message":"Argument Validation Error"}]}`, wantErr: "Argument Validation Error"},
+                {name: "not created", response: `{"data":{"attachmentCreate":{"success":false}}}`, wantErr: "failed to create attachment"},
+        }
+        for _, test := range tests {
+                t.Run(test.name, func(t *testing.T) {
+                        var got GraphQLRequest
+                        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                                require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+                                w.Write([]byte(test.response))
+                        }))
+                        defer server.Close()
+
+                        client := NewClient("test-api-key")
+                        client.endpoint = server.URL
+
+                        err := client.AttachURL(context.Background(), "uuid-123", "Pull request", "https://github.com/o/r/pull/7")
+                        assert.Contains(t, got.Query, "attachmentCreate")
+                        assert.Equal(t, "uuid-123", got.Variables["issueId"])
+                        assert.Equal(t, "Pull request", got.Variables["title"])
+                        assert.Equal(t, "https://github.com/o/r/pull/7", got.Variables["url"])
+                        if test.wantErr != "" {
+                                assert.ErrorContains(t, err, test.wantErr)
+                                return
+                        }
+                        assert.NoError(t, err)
+                })
+        }
+}
+
 func TestFetchIssueDetails_NotFound(t *testing.T) {
         server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
                 response := GraphQLResponse{
@@ -351,7 +508,7 @@ func TestFetchIssueDetails_NotFound(t *testing.T) {
         client := NewClient("test-api-key")
         client.endpoint = server.URL
 
-        _, err := client.FetchIssueDetails("DEL-999")
+        _, err := client.FetchIssueDetails(context.Background(), "DEL-999")
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "issue not found: DEL-999")
 }
@@ -400,3 +557,166 @@ func TestParseIssueIdentifier_Error(t *testing.T) {
                 })
         }
 }
+
+func TestCreateComment_Success(t *testing.T) {
+        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                assert.Equal(t, "POST", r.Method)
+                assert.Equal(t, "test-api-key", r.Header.Get("Authorization"))
+
+                var req GraphQLRequest
+                json.NewDecoder(r.Body).Decode(&req)
+                assert.Contains(t, req.Query, "commentCreate")
+                assert.Equal(t, "uuid-123", req.Variables["issueId"])
+                assert.Equal(t, "PR opened", req.Variables["body"])
+
+                response := map[string]interface{}{
+                        "data": map[string]interface{}{
+                                "commentCreate": map[string]interface{}{
+                                        "success": true,
+                                },
+                        },
+                }
+                json.NewEncoder(w).Encode(response)
+        }))
+        defer server.Close()
+
+        client := NewClient("test-api-key")
+        client.endpoint = server.URL
+
+        err := client.CreateComment(context.Background(), &IssueDetails{ID: "uuid-123"}, "PR opened")
+        require.NoError(t, err)
+}
+
+func TestCreateComment_Failures(t *testing.T) {
+        tests := []struct {
+                name     string
+                status   int
+                response string
+                errorMsg string
+        }{
+                {
+                        name:     "http error",
+                        status:   http.StatusUnauthorized,
+                        response: `{"error": "Unauthorized"}`,
+                        errorMsg: "401",
+                },
+                {
+                        name:     "graphql error",
+                        status:   http.StatusOK,
+                        response: `{"errors": [{"message": "Entity not found"}]}`,
+                        errorMsg: "Entity not found",
+                },
+                {
+                        name:     "unsuccessful",
+                        status:   http.StatusOK,
+                        response: `{"data": {"commentCreate": {"success": false}}}`,
+                        errorMsg: "failed to create comment",
+                },
+        }
+
+        for _, test := range tests {
+                t.Run(test.name, func(t *testing.T) {
+                        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                                w.WriteHeader(test.status)
+                                w.Write([]byte(test.response))
+                        }))
+                        defer server.Close()
+
+                        client := NewClient("test-api-key")
+                        client.endpoint = server.URL
+
+                        err := client.CreateComment(context.Background(), &IssueDetails{ID: "uuid-123"}, "PR opened")
+                        require.Error(t, err)
+                        assert.Contains(t, err.Error(), test.errorMsg)
+                })
+        }
+}
+
+func TestSetIssueState_Success(t *testing.T) {
+        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                var req GraphQLRequest
+                json.NewDecoder(r.Body).Decode(&req)
+                assert.Contains(t, req.Query, "issueUpdate")
+                assert.Equal(t, "uuid-123", req.Variables["id"])
+                assert.Equal(t, "state-todo", req.Variables["stateId"])
+
+                w.Write([]byte(`{"data": {"issueUpdate": {"success": true}}}`))
+        }))
+        defer server.Close()
+
+        client := NewClient("test-api-key")
+        client.endpoint = server.URL
+
+        err := client.SetIssueState(context.Background(), &IssueDetails{ID: "uuid-123"}, "state-todo")
+        require.NoError(t, err)
+}
+
+func TestFetchIssuesByFilters_Success(t *testing.T) {
+        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                var req GraphQLRequest
+                json.NewDecoder(r.Body).Decode(&req)
+                assert.Contains(t, req.Query, "identifier")
+                assert.Contains(t, req.Query, "labels: { name: { eq: $tag } }")
+                assert.Equal(t, "DEL", req.Variables["teamKey"])
+                assert.Equal(t, "monday", req.Variables["tag"])
+                assert.NotContains(t, req.Variables, "projectKey")
+
+                w.Write([]byte(`{"data": {"issues": {"nodes": [
+                        {"id": "uuid-1", "identifier": "DEL-1", "title": "First", "state": {"id": "s-1", "name": "Todo", "type": "unstarted"}},
+                        {"id": "uuid-2", "identifier": "DEL-2", "title": "Second"}
+                ]}}}`))
+        }))
+        defer server.Close()
+
+        client := NewClient("test-api-key")
+        client.endpoint = server.URL
+
+        issues, err := client.FetchIssuesByFilters(context.Background(), IssueFilter{Team: "DEL", Label: "monday"})
+        require.NoError(t, err)
+        require.Len(t, issues, 2)
+        assert.Equal(t, "DEL-1", issues[0].Identifier)
+        assert.Equal(t, "Todo", issues[0].State.Name)
+        assert.Equal(t, "DEL-2", issues[1].Identifier)
+}
+
+func TestFetchIssuesByFilters_StateAndAssignee(t *testing.T) {
+        tests := []struct {
+                name         string
+                filter       IssueFilter
+                wantFilter   string
+                wantAssignee any
+        }{
+                {name: "state", filter: IssueFilter{State: "todo"}, wantFilter: "state: { name: { eqIgnoreCase: $state } }"},
+                {name: "me", filter: IssueFilter{Assignee: "me"}, wantFilter: "assignee: { isMe: { eq: true } }"},
+                {name: "unassigned", filter: IssueFilter{Assignee: "None"}, wantFilter: "assignee: { null: true }"},
+                {name: "email", filter: IssueFilter{Assignee: "ada@example.com"}, wantFilter: "email: { eqIgnoreCase: $assignee }", wantAssignee: "ada@example.com"},
+        }
+
+        for _, tt := range tests {
+                t.Run(tt.name, func(t *testing.T) {
+                        server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                                var req GraphQLRequest
+                                json.NewDecoder(r.Body).Decode(&req)
+                                assert.Contains(t, req.Query, tt.wantFilter)
+                                if tt.filter.State != "" {
+                                        assert.Equal(t, tt.filter.State, req.Variables["state"])
+                                }
+                                assert.Equal(t, tt.wantAssignee, req.Variables["assignee"])
+
+                                w.Write([]byte(`{"data": {"issues": {"nodes": [
+                                        {"id": "uuid-1", "identifier": "DEL-1", "title": "First", "assignee": {"id": "u-1", "name": "Ada"}}
+                                ]}}}`))
+                        }))
+                        defer server.Close()
+
+                        client := NewClient("test-api-key")
+                        client.endpoint = server.URL
+
+                        issues, err := client.FetchIssuesByFilters(context.Background(), tt.filter)
+                        require.NoError(t, err)
+                        require.Len(t, issues, 1)
+                        require.NotNil(t, issues[0].Assignee)
+                        assert.Equal(t, "Ada", issues[0].Assignee.Name)
+                })
+        }
+}
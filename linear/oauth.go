@@ -0,0 +1,304 @@
+package linear
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAuthorizeURL is the page where users authorize an OAuth app in Linear.
+	DefaultAuthorizeURL = "https://linear.app/oauth/authorize"
+	// DefaultTokenURL exchanges authorization codes and refresh tokens for access tokens.
+	DefaultTokenURL = "https://api.linear.app/oauth/token"
+)
+
+// expiryMargin is how long before it expires an access token is refreshed.
+const expiryMargin = time.Minute
+
+// TokenSource supplies the OAuth access tokens a client is authenticated with.
+type TokenSource interface {
+	// AccessToken returns a valid access token.
+	AccessToken(ctx context.Context) (string, error)
+}
+
+// StaticToken is an access token that is used as is, such as one of LINEAR_ACCESS_TOKEN.
+type StaticToken string
+
+// AccessToken returns the token.
+func (t StaticToken) AccessToken(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// NewOAuthClient creates a Linear API client authenticated with the OAuth access tokens of
+// tokens, with the same timeout, retries, and rate limiter as NewClient.
+func NewOAuthClient(tokens TokenSource) *Client {
+	c := NewClient("")
+	c.tokens = tokens
+	return c
+}
+
+// authorize sets the Authorization header of req: the API key as is, which is what Linear
+// expects of personal API keys, or an OAuth access token as a Bearer token.
+func (c *Client) authorize(req *http.Request) error {
+	if c.tokens == nil {
+		req.Header.Set("Authorization", c.apiKey)
+		return nil
+	}
+	token, err := c.tokens.AccessToken(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get Linear access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token is an OAuth access token of Linear with what is needed to refresh it.
+type Token struct {
+	// AccessToken authenticates API calls
+	AccessToken string `json:"access_token"`
+	// RefreshToken obtains a new access token once this one expires; empty if it cannot be
+	// refreshed
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Scope lists the granted scopes, e.g. "read,write"
+	Scope string `json:"scope,omitempty"`
+	// ExpiresAt is when the access token expires; zero if it does not
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// ClientID is the OAuth app the token was issued to
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// Expired reports whether the access token expires within a minute of now.
+func (t *Token) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Add(expiryMargin).Before(t.ExpiresAt)
+}
+
+// LoadToken reads the token stored at path.
+func LoadToken(path string) (*Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid Linear token in %s: %w", path, err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("invalid Linear token in %s: no access token", path)
+	}
+	return &token, nil
+}
+
+// SaveToken stores token at path, readable only by the user, replacing the file atomically.
+func SaveToken(path string, token *Token) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, ".linear-token-*")
+	if err != nil {
+		return fmt.Errorf("failed to store Linear token: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to store Linear token: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to store Linear token: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store Linear token: %w", err)
+	}
+	return nil
+}
+
+// OAuthConfig is an OAuth app of Linear.
+type OAuthConfig struct {
+	// ClientID identifies the app
+	ClientID string
+	// ClientSecret authenticates the app; it may be empty for authorizations with PKCE
+	ClientSecret string
+	// RedirectURL is where Linear sends the user back to with the authorization code
+	RedirectURL string
+	// Scopes are the requested scopes, e.g. read and write
+	Scopes []string
+	// Actor is "app" to act as the app instead of the authorizing user, or empty
+	Actor string
+	// AuthorizeURL is DefaultAuthorizeURL if empty
+	AuthorizeURL string
+	// TokenURL is DefaultTokenURL if empty
+	TokenURL string
+	// Client sends the token requests; nil for a client with a 30s timeout
+	Client *http.Client
+}
+
+// NewVerifier returns a random string to use as the PKCE code verifier or the state of an
+// authorization.
+func NewVerifier() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// AuthCodeURL returns the page where the user authorizes the app, sending them back to the
+// redirect URL with state and a code redeemable with verifier.
+func (c *OAuthConfig) AuthCodeURL(state, verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+	params := url.Values{
+		"client_id":             {c.ClientID},
+		"redirect_uri":          {c.RedirectURL},
+		"response_type":         {"code"},
+		"scope":                 {strings.Join(c.Scopes, ",")},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+		"prompt":                {"consent"},
+	}
+	if c.Actor != "" {
+		params.Set("actor", c.Actor)
+	}
+	return firstNonEmpty(c.AuthorizeURL, DefaultAuthorizeURL) + "?" + params.Encode()
+}
+
+// Exchange redeems the authorization code for a token.
+func (c *OAuthConfig) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.RedirectURL},
+		"code_verifier": {verifier},
+	})
+}
+
+// Refresh obtains a new access token with refreshToken.
+func (c *OAuthConfig) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// requestToken sends a token request with form and returns the token of the response.
+func (c *OAuthConfig) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, firstNonEmpty(c.TokenURL, DefaultTokenURL), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request Linear token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Linear token endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode Linear token: %w", err)
+	}
+	if out.AccessToken == "" {
+		return nil, errors.New("Linear token endpoint returned no access token")
+	}
+	token := &Token{AccessToken: out.AccessToken, RefreshToken: out.RefreshToken, Scope: out.Scope, ClientID: c.ClientID}
+	if out.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// StoredTokenSource supplies the access token stored in a file by monday auth linear,
+// refreshing it, and storing the refreshed one, when it expires.
+type StoredTokenSource struct {
+	// Path is the file the token is stored in
+	Path string
+	// Config refreshes the token; its ClientID defaults to that of the token
+	Config OAuthConfig
+	// Token is the token last loaded; nil to load it from Path
+	Token *Token
+
+	mu sync.Mutex
+}
+
+// AccessToken returns the stored access token, refreshed if it expired.
+func (s *StoredTokenSource) AccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.Token == nil || s.Token.Expired(now) {
+		// Another process may have refreshed the token already.
+		token, err := LoadToken(s.Path)
+		if err != nil {
+			return "", err
+		}
+		s.Token = token
+	}
+	if !s.Token.Expired(now) {
+		return s.Token.AccessToken, nil
+	}
+	if s.Token.RefreshToken == "" {
+		return "", fmt.Errorf("the Linear token in %s expired; run monday auth linear again", s.Path)
+	}
+
+	config := s.Config
+	if config.ClientID == "" {
+		config.ClientID = s.Token.ClientID
+	}
+	token, err := config.Refresh(ctx, s.Token.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh the Linear token: %w", err)
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = s.Token.RefreshToken
+	}
+	if err := SaveToken(s.Path, token); err != nil {
+		return "", err
+	}
+	s.Token = token
+	return token.AccessToken, nil
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package linear
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthCodeURL(t *testing.T) {
+	config := OAuthConfig{ClientID: "client-1", RedirectURL: "http://localhost:8976/callback", Scopes: []string{"read", "write"}, Actor: "app"}
+	u, err := url.Parse(config.AuthCodeURL("state-1", "verifier-1"))
+	require.NoError(t, err)
+
+	challenge := sha256.Sum256([]byte("verifier-1"))
+	assert.Equal(t, "https://linear.app/oauth/authorize", u.Scheme+"://"+u.Host+u.Path)
+	q := u.Query()
+	assert.Equal(t, "client-1", q.Get("client_id"))
+	assert.Equal(t, "http://localhost:8976/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "read,write", q.Get("scope"))
+	assert.Equal(t, "state-1", q.Get("state"))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, "app", q.Get("actor"))
+}
+
+func TestExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "code-1", r.PostForm.Get("code"))
+		assert.Equal(t, "verifier-1", r.PostForm.Get("code_verifier"))
+		assert.Equal(t, "client-1", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret-1", r.PostForm.Get("client_secret"))
+		w.Write([]byte(`{"access_token": "lin_oauth_access", "token_type": "Bearer", "expires_in": 86399, "scope": "read,write", "refresh_token": "refresh-1"}`))
+	}))
+	defer server.Close()
+
+	config := OAuthConfig{ClientID: "client-1", ClientSecret: "secret-1", TokenURL: server.URL}
+	token, err := config.Exchange(context.Background(), "code-1", "verifier-1")
+	require.NoError(t, err)
+	assert.Equal(t, "lin_oauth_access", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+	assert.Equal(t, "read,write", token.Scope)
+	assert.Equal(t, "client-1", token.ClientID)
+	assert.WithinDuration(t, time.Now().Add(86399*time.Second), token.ExpiresAt, time.Minute)
+}
+
+func TestStoredTokenSourceRefreshesExpiredToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "refresh-1", r.PostForm.Get("refresh_token"))
+		assert.Equal(t, "client-1", r.PostForm.Get("client_id"))
+		w.Write([]byte(`{"access_token": "fresh", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "linear-token.json")
+	require.NoError(t, SaveToken(path, &Token{AccessToken: "stale", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(-time.Hour), ClientID: "client-1"}))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	source := &StoredTokenSource{Path: path, Config: OAuthConfig{TokenURL: server.URL}}
+	token, err := source.AccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", token)
+
+	stored, err := LoadToken(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", stored.AccessToken)
+	assert.Equal(t, "refresh-1", stored.RefreshToken, "the refresh token is kept when none is returned")
+}
+
+func TestStoredTokenSourceWithoutRefreshToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "linear-token.json")
+	require.NoError(t, SaveToken(path, &Token{AccessToken: "stale", ExpiresAt: time.Now().Add(-time.Hour)}))
+
+	_, err := (&StoredTokenSource{Path: path}).AccessToken(context.Background())
+	assert.ErrorContains(t, err, "monday auth linear")
+}
+
+func TestClientSendsOAuthTokensAsBearer(t *testing.T) {
+	tests := []struct {
+		name   string
+		client func() *Client
+		want   string
+	}{
+		{name: "API key", client: func() *Client { return NewClient("lin_api_key") }, want: "lin_api_key"},
+		{name: "OAuth token as API key", client: func() *Client { return NewClient("lin_oauth_token") }, want: "Bearer lin_oauth_token"},
+		{name: "token source", client: func() *Client { return NewOAuthClient(StaticToken("access")) }, want: "Bearer access"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.want, r.Header.Get("Authorization"))
+				w.Write([]byte(`{"data": {"teams": {"nodes": []}}}`))
+			}))
+			defer server.Close()
+
+			client := tt.client()
+			client.endpoint = server.URL
+			_, err := client.FetchTeams(context.Background())
+			require.NoError(t, err)
+		})
+	}
+}
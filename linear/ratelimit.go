@@ -0,0 +1,304 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned for requests rejected by Linear for exceeding the rate limits of
+// the API key when they do not reset within the limiter's MaxWait.
+var ErrRateLimited = errors.New("Linear API rate limit exceeded")
+
+// DefaultMaxWait is how long a request waits at most for the rate limits to reset.
+const DefaultMaxWait = 2 * time.Minute
+
+// maxRateLimitRetries is how many times a request rejected for exceeding the rate limits is
+// sent again.
+const maxRateLimitRetries = 3
+
+// throttleFraction is the share of a rate limit below which requests are spread out over the
+// rest of its window instead of being sent right away.
+const throttleFraction = 10
+
+// RateLimits is the state of the request and complexity rate limits of a Linear API key, as
+// reported by the X-RateLimit headers of its last response.
+type RateLimits struct {
+	// RequestsLimit is how many requests the key may make per window; 0 if unknown
+	RequestsLimit int `json:"requests_limit"`
+	// RequestsRemaining is how many requests are left in the window
+	RequestsRemaining int `json:"requests_remaining"`
+	// RequestsReset is when the request limit is replenished
+	RequestsReset time.Time `json:"requests_reset"`
+	// ComplexityLimit is the total query complexity the key may use per window; 0 if unknown
+	ComplexityLimit int `json:"complexity_limit"`
+	// ComplexityRemaining is how much complexity is left in the window
+	ComplexityRemaining int `json:"complexity_remaining"`
+	// ComplexityReset is when the complexity limit is replenished
+	ComplexityReset time.Time `json:"complexity_reset"`
+	// UpdatedAt is when the limits were reported
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// parseRateLimits returns the rate limits reported by the headers h of a response received at
+// now, and false if h has none.
+func parseRateLimits(h http.Header, now time.Time) (RateLimits, bool) {
+	limits := RateLimits{UpdatedAt: now}
+	found := false
+	intHeader := func(name string, v *int) {
+		if n, err := strconv.Atoi(h.Get(name)); err == nil {
+			*v = n
+			found = true
+		}
+	}
+	resetHeader := func(name string, v *time.Time) {
+		if ms, err := strconv.ParseInt(h.Get(name), 10, 64); err == nil {
+			*v = time.UnixMilli(ms)
+		}
+	}
+	intHeader("X-RateLimit-Requests-Limit", &limits.RequestsLimit)
+	intHeader("X-RateLimit-Requests-Remaining", &limits.RequestsRemaining)
+	resetHeader("X-RateLimit-Requests-Reset", &limits.RequestsReset)
+	intHeader("X-RateLimit-Complexity-Limit", &limits.ComplexityLimit)
+	intHeader("X-RateLimit-Complexity-Remaining", &limits.ComplexityRemaining)
+	resetHeader("X-RateLimit-Complexity-Reset", &limits.ComplexityReset)
+	return limits, found
+}
+
+// delay returns how long the next request waits at now: not at all while more than a tenth of
+// both limits remains, an even share of the rest of the window when less does, and until the
+// window resets when a limit is used up.
+func (r RateLimits) delay(now time.Time) time.Duration {
+	return max(
+		limitDelay(r.RequestsLimit, r.RequestsRemaining, r.RequestsReset, now),
+		limitDelay(r.ComplexityLimit, r.ComplexityRemaining, r.ComplexityReset, now),
+	)
+}
+
+// limitDelay returns the delay of one rate limit for RateLimits.delay.
+func limitDelay(limit, remaining int, reset, now time.Time) time.Duration {
+	if limit <= 0 || !reset.After(now) || remaining > limit/throttleFraction {
+		return 0
+	}
+	untilReset := reset.Sub(now)
+	if remaining <= 0 {
+		return untilReset
+	}
+	return untilReset / time.Duration(remaining+1)
+}
+
+// RateLimiter throttles the requests of the clients sharing it to stay within the rate limits
+// Linear reports, and holds them back until the limits reset once they are exceeded. With a
+// state file, the limits are shared with the limiters of other processes, such as the runs of
+// a batch.
+type RateLimiter struct {
+	// MaxWait caps how long a request waits for the rate limits; DefaultMaxWait if zero
+	MaxWait time.Duration
+
+	mu        sync.Mutex
+	limits    RateLimits
+	stateFile string
+}
+
+// NewRateLimiter returns a limiter sharing the limits it learns through stateFile, or only
+// with the clients of this process if stateFile is empty.
+func NewRateLimiter(stateFile string) *RateLimiter {
+	return &RateLimiter{stateFile: stateFile}
+}
+
+// sharedLimiter is the limiter of clients created by NewClient.
+var sharedLimiter = NewRateLimiter("")
+
+// maxWait returns the limiter's MaxWait or its default.
+func (l *RateLimiter) maxWait() time.Duration {
+	if l.MaxWait > 0 {
+		return l.MaxWait
+	}
+	return DefaultMaxWait
+}
+
+// Limits returns the last rate limits the limiter learned.
+func (l *RateLimiter) Limits() RateLimits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limits
+}
+
+// Wait blocks until the next request may be sent, at most MaxWait, or until ctx is done. The
+// request is counted against the remaining limit so that concurrent ones are spread out too.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.loadLocked()
+	delay := min(l.limits.delay(time.Now()), l.maxWait())
+	if l.limits.RequestsLimit > 0 {
+		l.limits.RequestsRemaining--
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Update records the rate limits reported by the headers h of a response.
+func (l *RateLimiter) Update(h http.Header) {
+	limits, ok := parseRateLimits(h, time.Now())
+	if !ok {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+	l.saveLocked()
+}
+
+// exceeded records that a request was rejected for exceeding the rate limits and returns how
+// long until they reset, or false if that is longer than MaxWait. retryAfter is the
+// Retry-After header of the response.
+func (l *RateLimiter) exceeded(retryAfter string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	wait := l.limits.delay(now)
+	if wait == 0 {
+		// The headers do not say which limit ran out or when: use Retry-After or a minute.
+		wait = time.Minute
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		}
+		l.limits.RequestsLimit = max(l.limits.RequestsLimit, 1)
+		l.limits.RequestsRemaining = 0
+		l.limits.RequestsReset = now.Add(wait)
+		l.limits.UpdatedAt = now
+		l.saveLocked()
+	}
+	return wait, wait <= l.maxWait()
+}
+
+// loadLocked replaces the limits with those in the state file when they are newer; the caller
+// holds l.mu.
+func (l *RateLimiter) loadLocked() {
+	if l.stateFile == "" {
+		return
+	}
+	data, err := os.ReadFile(l.stateFile)
+	if err != nil {
+		return
+	}
+	var limits RateLimits
+	if json.Unmarshal(data, &limits) == nil && limits.UpdatedAt.After(l.limits.UpdatedAt) {
+		l.limits = limits
+	}
+}
+
+// saveLocked writes the limits to the state file, if there is one; the caller holds l.mu.
+// Failures only cost the sharing of the limits and are ignored.
+func (l *RateLimiter) saveLocked() {
+	if l.stateFile == "" {
+		return
+	}
+	data, err := json.Marshal(l.limits)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(l.stateFile), 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.stateFile), ".ratelimit-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), l.stateFile) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// rateLimited reports whether resp rejects its request for exceeding the rate limits: with
+// status 429, or with a RATELIMITED GraphQL error, which Linear answers with status 400. The
+// body of a 400 response is read and replaced so the caller can still read it.
+func rateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadRequest:
+	default:
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var response struct {
+		Errors []GraphQLError `json:"errors"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return false
+	}
+	for _, e := range response.Errors {
+		if e.Extensions.Code == "RATELIMITED" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRateLimiter replaces the limiter the client's requests are throttled with, by default
+// one shared by all clients of the process.
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.limiter = limiter
+}
+
+// do sends req, authorized with the client's credentials, once the rate limiter allows it and
+// records the rate limits of the response. A request rejected for exceeding the rate limits is
+// sent again once they reset, unless that takes longer than the limiter's MaxWait.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		if err := c.authorize(req); err != nil {
+			return nil, err
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		c.limiter.Update(resp.Header)
+		if !rateLimited(resp) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		wait, ok := c.limiter.exceeded(resp.Header.Get("Retry-After"))
+		if !ok || attempt >= maxRateLimitRetries || req.GetBody == nil {
+			return nil, fmt.Errorf("%w: the limits reset in %s", ErrRateLimited, wait.Round(time.Second))
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+}
@@ -0,0 +1,148 @@
+package linear
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	now := time.Now()
+	reset := now.Add(time.Hour).Truncate(time.Millisecond)
+	h := http.Header{}
+	h.Set("X-RateLimit-Requests-Limit", "1500")
+	h.Set("X-RateLimit-Requests-Remaining", "1499")
+	h.Set("X-RateLimit-Requests-Reset", strconv.FormatInt(reset.UnixMilli(), 10))
+	h.Set("X-RateLimit-Complexity-Limit", "250000")
+	h.Set("X-RateLimit-Complexity-Remaining", "249000")
+
+	limits, ok := parseRateLimits(h, now)
+	require.True(t, ok)
+	assert.Equal(t, 1500, limits.RequestsLimit)
+	assert.Equal(t, 1499, limits.RequestsRemaining)
+	assert.True(t, limits.RequestsReset.Equal(reset))
+	assert.Equal(t, 250000, limits.ComplexityLimit)
+	assert.Equal(t, 249000, limits.ComplexityRemaining)
+
+	_, ok = parseRateLimits(http.Header{}, now)
+	assert.False(t, ok)
+}
+
+func TestRateLimitsDelay(t *testing.T) {
+	now := time.Now()
+	reset := now.Add(100 * time.Second)
+	tests := []struct {
+		name   string
+		limits RateLimits
+		want   time.Duration
+	}{
+		{name: "unknown", want: 0},
+		{name: "plenty left", limits: RateLimits{RequestsLimit: 1500, RequestsRemaining: 1000, RequestsReset: reset}, want: 0},
+		{name: "few requests left", limits: RateLimits{RequestsLimit: 1500, RequestsRemaining: 99, RequestsReset: reset}, want: time.Second},
+		{name: "requests used up", limits: RateLimits{RequestsLimit: 1500, RequestsRemaining: 0, RequestsReset: reset}, want: 100 * time.Second},
+		{name: "complexity used up", limits: RateLimits{RequestsLimit: 1500, RequestsRemaining: 1000, RequestsReset: reset, ComplexityLimit: 250000, ComplexityRemaining: 0, ComplexityReset: reset}, want: 100 * time.Second},
+		{name: "window over", limits: RateLimits{RequestsLimit: 1500, RequestsRemaining: 0, RequestsReset: now.Add(-time.Second)}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.limits.delay(now))
+		})
+	}
+}
+
+func TestRateLimiterSharesLimitsThroughStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "ratelimit.json")
+	first := NewRateLimiter(stateFile)
+	h := http.Header{}
+	h.Set("X-RateLimit-Requests-Limit", "1500")
+	h.Set("X-RateLimit-Requests-Remaining", "0")
+	h.Set("X-RateLimit-Requests-Reset", strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10))
+	first.Update(h)
+
+	second := NewRateLimiter(stateFile)
+	second.MaxWait = 20 * time.Millisecond
+	start := time.Now()
+	require.NoError(t, second.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, 1500, second.Limits().RequestsLimit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	second.MaxWait = time.Hour
+	assert.ErrorIs(t, second.Wait(ctx), context.Canceled)
+}
+
+func TestClientRetriesWhenRateLimited(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Requests-Limit", "1500")
+			w.Header().Set("X-RateLimit-Requests-Remaining", "0")
+			w.Header().Set("X-RateLimit-Requests-Reset", strconv.FormatInt(time.Now().Add(50*time.Millisecond).UnixMilli(), 10))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"message": "Rate limit exceeded", "extensions": {"code": "RATELIMITED"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"teams": {"nodes": [{"id": "team-1", "key": "DEL", "name": "Delivery"}]}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key")
+	client.endpoint = server.URL
+	client.SetRateLimiter(NewRateLimiter(""))
+
+	teams, err := client.FetchTeams(context.Background())
+	require.NoError(t, err)
+	require.Len(t, teams, 1)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClientFailsWhenRateLimitResetsTooLate(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RateLimit-Complexity-Limit", "250000")
+		w.Header().Set("X-RateLimit-Complexity-Remaining", "0")
+		w.Header().Set("X-RateLimit-Complexity-Reset", strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"message": "Rate limit exceeded", "extensions": {"code": "RATELIMITED"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key")
+	client.endpoint = server.URL
+	limiter := NewRateLimiter("")
+	limiter.MaxWait = time.Minute
+	client.SetRateLimiter(limiter)
+
+	_, err := client.FetchTeams(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrRateLimited), "error = %v", err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClientKeepsOtherBadRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"message": "Query too complex"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key")
+	client.endpoint = server.URL
+	client.SetRateLimiter(NewRateLimiter(""))
+
+	_, err := client.FetchTeams(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Query too complex")
+	assert.False(t, errors.Is(err, ErrRateLimited))
+}
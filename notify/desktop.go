@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"monday/command"
+	"monday/osascript"
+)
+
+// Desktop raises macOS Notification Center alerts through osascript when a run finishes.
+type Desktop struct {
+	minDuration time.Duration
+	// run executes a command; it is replaced in tests
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// NewDesktop returns a desktop notifier for runs that took at least minDuration; shorter runs
+// finish while the user is still watching and are not announced.
+func NewDesktop(minDuration time.Duration) *Desktop {
+	return &Desktop{minDuration: minDuration, run: command.Run}
+}
+
+// Notify raises an alert for a succeeded or failed run. Start and approval events are ignored,
+// as the run asks for approval in its terminal.
+func (d *Desktop) Notify(ctx context.Context, event Event) error {
+	if event.Kind == RunStarted || event.Kind == RunAwaitingApproval || event.Duration < d.minDuration {
+		return nil
+	}
+
+	message := event.Issue()
+	if event.Kind == RunFailed && event.Stage != "" {
+		message = fmt.Sprintf("Stopped in %s: %s", event.Stage, event.Issue())
+	}
+	script := fmt.Sprintf("display notification %s with title %s subtitle %s",
+		osascript.Quote(message),
+		osascript.Quote("monday"),
+		osascript.Quote(fmt.Sprintf("Run %s after %s", event.Kind, event.Duration.Round(time.Second))))
+	if err := d.run(ctx, "osascript", "-e", script); err != nil {
+		return fmt.Errorf("failed to raise desktop notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordCommands replaces the command runner of d and returns the arguments of every command run.
+func recordCommands(d *Desktop, err error) *[][]string {
+	var commands [][]string
+	d.run = func(ctx context.Context, name string, args ...string) error {
+		commands = append(commands, append([]string{name}, args...))
+		return err
+	}
+	return &commands
+}
+
+func TestDesktopNotify(t *testing.T) {
+	desktop := NewDesktop(time.Minute)
+	commands := recordCommands(desktop, nil)
+
+	err := desktop.Notify(context.Background(), Event{
+		Kind:       RunFailed,
+		IssueID:    "DEL-163",
+		IssueTitle: `Fix "login"`,
+		Stage:      "agent",
+		Duration:   2 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *commands, 1)
+	assert.Equal(t, []string{
+		"osascript", "-e",
+		`display notification "Stopped in agent: DEL-163: Fix \"login\"" with title "monday" subtitle "Run failed after 2m0s"`,
+	}, (*commands)[0])
+}
+
+func TestDesktopSkipsStartsAndShortRuns(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+	}{
+		{name: "started", event: Event{Kind: RunStarted, IssueID: "DEL-1", Duration: time.Hour}},
+		{name: "awaiting approval", event: Event{Kind: RunAwaitingApproval, IssueID: "DEL-1", Duration: time.Hour}},
+		{name: "short run", event: Event{Kind: RunSucceeded, IssueID: "DEL-1", Duration: 30 * time.Second}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			desktop := NewDesktop(time.Minute)
+			commands := recordCommands(desktop, nil)
+			require.NoError(t, desktop.Notify(context.Background(), test.event))
+			assert.Empty(t, *commands)
+		})
+	}
+}
+
+func TestDesktopReportsCommandErrors(t *testing.T) {
+	desktop := NewDesktop(0)
+	recordCommands(desktop, errors.New("exit status 1"))
+
+	err := desktop.Notify(context.Background(), Event{Kind: RunSucceeded, IssueID: "DEL-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit status 1")
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Discord embed colors.
+const (
+	discordBlue  = 0x3498db
+	discordGreen = 0x2ecc71
+	discordRed   = 0xe74c3c
+)
+
+// Discord posts run events to a Discord channel webhook.
+type Discord struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewDiscord returns a Discord notifier posting to webhookURL.
+func NewDiscord(webhookURL string) (*Discord, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("discord requires a webhook URL")
+	}
+	return &Discord{webhookURL: webhookURL, httpClient: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Notify posts event as an embed.
+func (d *Discord) Notify(ctx context.Context, event Event) error {
+	type field struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline"`
+	}
+	var fields []field
+	for _, detail := range event.Details() {
+		// Discord rejects field values longer than 1024 characters.
+		value := detail[1]
+		if len(value) > 1024 {
+			value = value[:1021] + "..."
+		}
+		fields = append(fields, field{Name: detail[0], Value: value, Inline: detail[0] != "Error" && detail[0] != "Repository"})
+	}
+
+	color := discordBlue
+	switch event.Kind {
+	case RunSucceeded:
+		color = discordGreen
+	case RunFailed:
+		color = discordRed
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"username": "monday",
+		"embeds": []map[string]any{{
+			"title":  event.Headline(),
+			"url":    event.IssueURL,
+			"color":  color,
+			"fields": fields,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode discord message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post discord message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("discord returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
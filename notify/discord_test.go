@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordNotify(t *testing.T) {
+	server, bodies, _ := recordRequests(t, "")
+	discord, err := NewDiscord(server.URL)
+	require.NoError(t, err)
+
+	err = discord.Notify(context.Background(), Event{
+		Kind:     RunFailed,
+		IssueID:  "DEL-163",
+		IssueURL: "https://linear.app/acme/issue/DEL-163",
+		Repo:     "https://github.com/acme/app",
+		Stage:    "agent",
+		Error:    strings.Repeat("x", 2000),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *bodies, 1)
+	embed := (*bodies)[0]["embeds"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Failed DEL-163", embed["title"])
+	assert.Equal(t, "https://linear.app/acme/issue/DEL-163", embed["url"])
+	assert.Equal(t, float64(discordRed), embed["color"])
+
+	fields := embed["fields"].([]any)
+	last := fields[len(fields)-1].(map[string]any)
+	assert.Equal(t, "Error", last["name"])
+	assert.Len(t, last["value"], 1024)
+}
+
+func TestDiscordReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Unknown Webhook"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	discord, err := NewDiscord(server.URL)
+	require.NoError(t, err)
+	err = discord.Notify(context.Background(), Event{Kind: RunStarted, IssueID: "DEL-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
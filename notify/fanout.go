@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Notifier delivers run events to one channel.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+var (
+	_ Notifier = (*Slack)(nil)
+	_ Notifier = (*Discord)(nil)
+	_ Notifier = (*Teams)(nil)
+	_ Notifier = (*Desktop)(nil)
+	_ Notifier = (*Fanout)(nil)
+)
+
+// Severity ranks events so channels can be limited to the ones that matter to them.
+type Severity int
+
+// Event severities, from least to most severe.
+const (
+	SeverityInfo Severity = iota
+	SeverityNotice
+	SeverityError
+)
+
+// severityNames maps severities to their configuration names.
+var severityNames = map[Severity]string{
+	SeverityInfo:   "info",
+	SeverityNotice: "notice",
+	SeverityError:  "error",
+}
+
+// String returns the configuration name of s.
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// ParseSeverity parses a severity name: info, notice, or error.
+func ParseSeverity(name string) (Severity, error) {
+	for severity, candidate := range severityNames {
+		if strings.EqualFold(strings.TrimSpace(name), candidate) {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q (want info, notice, or error)", name)
+}
+
+// Severity returns how severe the event is: starts are info, approval requests and successes
+// notice, failures error.
+func (k EventKind) Severity() Severity {
+	switch k {
+	case RunStarted:
+		return SeverityInfo
+	case RunAwaitingApproval, RunSucceeded:
+		return SeverityNotice
+	default:
+		return SeverityError
+	}
+}
+
+// Channel is a named notifier that receives events of at least MinSeverity.
+type Channel struct {
+	Name        string
+	Notifier    Notifier
+	MinSeverity Severity
+}
+
+// Fanout delivers each event to every channel that accepts it.
+type Fanout struct {
+	channels []Channel
+}
+
+// NewFanout returns a notifier delivering to channels.
+func NewFanout(channels ...Channel) *Fanout {
+	return &Fanout{channels: channels}
+}
+
+// Len returns the number of channels.
+func (f *Fanout) Len() int {
+	return len(f.channels)
+}
+
+// With returns a fanout that also delivers to channel.
+func (f *Fanout) With(channel Channel) *Fanout {
+	channels := append(f.channels[:len(f.channels):len(f.channels)], channel)
+	return &Fanout{channels: channels}
+}
+
+// Notify delivers event to the accepting channels concurrently. The returned error joins the
+// errors of the channels that failed, each prefixed with the channel name.
+func (f *Fanout) Notify(ctx context.Context, event Event) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, channel := range f.channels {
+		if event.Kind.Severity() < channel.MinSeverity {
+			continue
+		}
+		wg.Add(1)
+		go func(channel Channel) {
+			defer wg.Done()
+			if err := channel.Notifier.Notify(ctx, event); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", channel.Name, err))
+				mu.Unlock()
+			}
+		}(channel)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a Notifier that records the kinds of the events it receives.
+type recorder struct {
+	mu    sync.Mutex
+	kinds []EventKind
+	err   error
+}
+
+func (r *recorder) Notify(ctx context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.kinds = append(r.kinds, event.Kind)
+	return r.err
+}
+
+func TestFanoutFiltersBySeverity(t *testing.T) {
+	everything := &recorder{}
+	failures := &recorder{}
+	fanout := NewFanout(
+		Channel{Name: "everything", Notifier: everything},
+		Channel{Name: "failures", Notifier: failures, MinSeverity: SeverityError},
+	)
+
+	for _, kind := range []EventKind{RunStarted, RunAwaitingApproval, RunSucceeded, RunFailed} {
+		require.NoError(t, fanout.Notify(context.Background(), Event{Kind: kind}))
+	}
+
+	assert.Equal(t, []EventKind{RunStarted, RunAwaitingApproval, RunSucceeded, RunFailed}, everything.kinds)
+	assert.Equal(t, []EventKind{RunFailed}, failures.kinds)
+}
+
+func TestFanoutJoinsChannelErrors(t *testing.T) {
+	working := &recorder{}
+	fanout := NewFanout(
+		Channel{Name: "slack", Notifier: &recorder{err: errors.New("timeout")}},
+		Channel{Name: "discord", Notifier: working},
+	).With(Channel{Name: "teams", Notifier: &recorder{err: errors.New("status 400")}})
+	assert.Equal(t, 3, fanout.Len())
+
+	err := fanout.Notify(context.Background(), Event{Kind: RunFailed})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slack: timeout")
+	assert.Contains(t, err.Error(), "teams: status 400")
+	assert.Equal(t, []EventKind{RunFailed}, working.kinds)
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Severity
+		errorMsg string
+	}{
+		{name: "info", input: "info", expected: SeverityInfo},
+		{name: "case and space", input: " Error ", expected: SeverityError},
+		{name: "unknown", input: "loud", errorMsg: "unknown severity"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			severity, err := ParseSeverity(test.input)
+			if test.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, severity)
+		})
+	}
+}
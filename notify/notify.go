@@ -0,0 +1,100 @@
+// Package notify tells people about workflow runs through chat and desktop channels.
+package notify
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind identifies a point in a run's lifecycle.
+type EventKind string
+
+// Run lifecycle events.
+const (
+	RunStarted          EventKind = "started"
+	RunAwaitingApproval EventKind = "awaiting_approval"
+	RunSucceeded        EventKind = "succeeded"
+	RunFailed           EventKind = "failed"
+)
+
+// Event describes a run at one point of its lifecycle.
+type Event struct {
+	// Kind is the lifecycle event
+	Kind EventKind
+	// RunID identifies the run
+	RunID string
+	// IssueID, IssueTitle, and IssueURL describe the Linear issue
+	IssueID    string
+	IssueTitle string
+	IssueURL   string
+	// Repo is the repository URL or local path
+	Repo string
+	// Branch is the issue branch
+	Branch string
+	// PRURL is the pull request, once created
+	PRURL string
+	// Duration is how long the run took, for finished runs
+	Duration time.Duration
+	// CostUSD is the agent cost, when known
+	CostUSD *float64
+	// Stage is the stage a failed run stopped in
+	Stage string
+	// Error is the redacted error of a failed run
+	Error string
+	// Changes summarizes the changes awaiting approval, e.g. "3 files changed, 10 insertions(+)"
+	Changes string
+}
+
+// Issue returns the issue identifier followed by its title, if known.
+func (e Event) Issue() string {
+	if e.IssueTitle == "" {
+		return e.IssueID
+	}
+	return fmt.Sprintf("%s: %s", e.IssueID, e.IssueTitle)
+}
+
+// Headline returns a one-line description of the event.
+func (e Event) Headline() string {
+	return fmt.Sprintf("%s %s", e.verb(), e.Issue())
+}
+
+// verb describes the event kind.
+func (e Event) verb() string {
+	switch e.Kind {
+	case RunStarted:
+		return "Started"
+	case RunAwaitingApproval:
+		return "Waiting for approval of"
+	case RunSucceeded:
+		return "Finished"
+	default:
+		return "Failed"
+	}
+}
+
+// Details returns the label/value pairs worth showing for the event, in display order.
+func (e Event) Details() [][2]string {
+	details := [][2]string{{"Repository", e.Repo}}
+	if e.Branch != "" {
+		details = append(details, [2]string{"Branch", e.Branch})
+	}
+	if e.PRURL != "" {
+		details = append(details, [2]string{"Pull request", e.PRURL})
+	}
+	if e.Kind != RunStarted && e.Kind != RunAwaitingApproval {
+		details = append(details, [2]string{"Duration", e.Duration.Round(time.Second).String()})
+	}
+	if e.Changes != "" {
+		details = append(details, [2]string{"Changes", e.Changes})
+	}
+	if e.CostUSD != nil {
+		details = append(details, [2]string{"Agent cost", fmt.Sprintf("$%.2f", *e.CostUSD)})
+	}
+	if e.Stage != "" {
+		details = append(details, [2]string{"Failed stage", e.Stage})
+	}
+	if e.Error != "" {
+		details = append(details, [2]string{"Error", e.Error})
+	}
+	return details
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// slackAPIURL is the chat.postMessage endpoint used with bot tokens.
+const slackAPIURL = "https://slack.com/api/chat.postMessage"
+
+// SlackConfig configures a Slack notifier. Either WebhookURL or Token and Channel must be set.
+type SlackConfig struct {
+	// WebhookURL is an incoming webhook URL, which posts to the channel it was created for
+	WebhookURL string
+	// Token is a bot token used with chat.postMessage
+	Token string
+	// Channel is the default channel for bot token messages
+	Channel string
+	// RepoChannels maps a repository URL substring to the channel for runs of matching
+	// repositories; it requires a bot token
+	RepoChannels map[string]string
+}
+
+// Slack posts run events to Slack.
+type Slack struct {
+	config     SlackConfig
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewSlack returns a Slack notifier for config.
+func NewSlack(config SlackConfig) (*Slack, error) {
+	if config.WebhookURL == "" && config.Token == "" {
+		return nil, fmt.Errorf("slack requires a webhook URL or a bot token")
+	}
+	if config.Token != "" && config.Channel == "" && len(config.RepoChannels) == 0 {
+		return nil, fmt.Errorf("slack bot token requires a channel")
+	}
+	return &Slack{
+		config:     config,
+		apiURL:     slackAPIURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Notify posts event to the event's channel.
+func (s *Slack) Notify(ctx context.Context, event Event) error {
+	message := map[string]any{
+		"text": fmt.Sprintf("%s %s", slackEmoji(event.Kind), event.Headline()),
+		"blocks": []map[string]any{
+			{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": slackText(event)}},
+		},
+	}
+
+	if s.config.Token == "" {
+		return s.post(ctx, s.config.WebhookURL, "", message)
+	}
+	channel := s.channel(event.Repo)
+	if channel == "" {
+		return s.post(ctx, s.config.WebhookURL, "", message)
+	}
+	message["channel"] = channel
+	return s.post(ctx, s.apiURL, s.config.Token, message)
+}
+
+// channel returns the channel for runs of repo: the longest matching override, or the default.
+func (s *Slack) channel(repo string) string {
+	channel, longest := s.config.Channel, 0
+	for pattern, override := range s.config.RepoChannels {
+		if strings.Contains(repo, pattern) && len(pattern) > longest {
+			channel, longest = override, len(pattern)
+		}
+	}
+	return channel
+}
+
+// post sends message to url, authenticating with token when set.
+func (s *Slack) post(ctx context.Context, url, token string, message map[string]any) error {
+	if url == "" {
+		return fmt.Errorf("no slack channel configured")
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	// chat.postMessage reports failures in the body with status 200.
+	if token != "" {
+		var result struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(respBody, &result); err == nil && !result.OK {
+			return fmt.Errorf("slack returned error: %s", result.Error)
+		}
+	}
+	return nil
+}
+
+// slackText renders event as Slack mrkdwn.
+func slackText(event Event) string {
+	var b strings.Builder
+	headline := event.Headline()
+	if event.IssueURL != "" {
+		headline = fmt.Sprintf("%s <%s|%s>", event.verb(), event.IssueURL, event.Issue())
+	}
+	fmt.Fprintf(&b, "%s *%s*", slackEmoji(event.Kind), headline)
+	for _, detail := range event.Details() {
+		value := detail[1]
+		if detail[0] == "Pull request" {
+			value = fmt.Sprintf("<%s>", value)
+		}
+		fmt.Fprintf(&b, "\n*%s:* %s", detail[0], value)
+	}
+	return b.String()
+}
+
+// slackEmoji returns the emoji shortcode for kind.
+func slackEmoji(kind EventKind) string {
+	switch kind {
+	case RunStarted:
+		return ":rocket:"
+	case RunAwaitingApproval:
+		return ":eyes:"
+	case RunSucceeded:
+		return ":white_check_mark:"
+	default:
+		return ":x:"
+	}
+}
+
+// ParseRepoChannels parses per-repository channel overrides written as
+// "owner/repo=#channel,other/repo=#other".
+func ParseRepoChannels(s string) (map[string]string, error) {
+	channels := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		repo, channel, ok := strings.Cut(entry, "=")
+		repo, channel = strings.TrimSpace(repo), strings.TrimSpace(channel)
+		if !ok || repo == "" || channel == "" {
+			return nil, fmt.Errorf("invalid repository channel %q: expected repo=channel", entry)
+		}
+		channels[repo] = channel
+	}
+	return channels, nil
+}
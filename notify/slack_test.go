@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordRequests returns a server that records the JSON bodies and authorization headers it receives.
+func recordRequests(t *testing.T, response string) (*httptest.Server, *[]map[string]any, *[]string) {
+	t.Helper()
+	var bodies []map[string]any
+	var auths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		auths = append(auths, r.Header.Get("Authorization"))
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies, &auths
+}
+
+func TestSlackWebhook(t *testing.T) {
+	server, bodies, auths := recordRequests(t, "ok")
+	slack, err := NewSlack(SlackConfig{WebhookURL: server.URL})
+	require.NoError(t, err)
+
+	cost := 1.5
+	err = slack.Notify(context.Background(), Event{
+		Kind:       RunSucceeded,
+		IssueID:    "DEL-163",
+		IssueTitle: "Fix login",
+		IssueURL:   "https://linear.app/acme/issue/DEL-163",
+		Repo:       "https://github.com/acme/app",
+		PRURL:      "https://github.com/acme/app/pull/7",
+		Duration:   3 * time.Minute,
+		CostUSD:    &cost,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *bodies, 1)
+	assert.Empty(t, (*auths)[0])
+	body := (*bodies)[0]
+	assert.Equal(t, ":white_check_mark: Finished DEL-163: Fix login", body["text"])
+	text := body["blocks"].([]any)[0].(map[string]any)["text"].(map[string]any)["text"].(string)
+	assert.Contains(t, text, "<https://linear.app/acme/issue/DEL-163|DEL-163: Fix login>")
+	assert.Contains(t, text, "*Pull request:* <https://github.com/acme/app/pull/7>")
+	assert.Contains(t, text, "*Duration:* 3m0s")
+	assert.Contains(t, text, "*Agent cost:* $1.50")
+	assert.NotContains(t, body, "channel")
+}
+
+func TestSlackApprovalRequest(t *testing.T) {
+	server, bodies, _ := recordRequests(t, "ok")
+	slack, err := NewSlack(SlackConfig{WebhookURL: server.URL})
+	require.NoError(t, err)
+
+	err = slack.Notify(context.Background(), Event{
+		Kind:       RunAwaitingApproval,
+		IssueID:    "DEL-163",
+		IssueTitle: "Fix login",
+		Repo:       "https://github.com/acme/app",
+		Branch:     "feature/del-163",
+		Changes:    "2 files changed, 10 insertions(+)",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *bodies, 1)
+	body := (*bodies)[0]
+	assert.Equal(t, ":eyes: Waiting for approval of DEL-163: Fix login", body["text"])
+	text := body["blocks"].([]any)[0].(map[string]any)["text"].(map[string]any)["text"].(string)
+	assert.Contains(t, text, "*Changes:* 2 files changed, 10 insertions(+)")
+	assert.NotContains(t, text, "Duration")
+}
+
+func TestSlackBotTokenUsesRepoChannel(t *testing.T) {
+	server, bodies, auths := recordRequests(t, `{"ok":true}`)
+	slack, err := NewSlack(SlackConfig{
+		Token:        "xoxb-token",
+		Channel:      "#monday",
+		RepoChannels: map[string]string{"acme/": "#acme", "acme/app": "#acme-app"},
+	})
+	require.NoError(t, err)
+	slack.apiURL = server.URL
+
+	require.NoError(t, slack.Notify(context.Background(), Event{Kind: RunStarted, IssueID: "DEL-1", Repo: "https://github.com/acme/app"}))
+	require.NoError(t, slack.Notify(context.Background(), Event{Kind: RunFailed, IssueID: "DEL-2", Repo: "https://github.com/other/repo"}))
+
+	require.Len(t, *bodies, 2)
+	assert.Equal(t, "Bearer xoxb-token", (*auths)[0])
+	assert.Equal(t, "#acme-app", (*bodies)[0]["channel"])
+	assert.Equal(t, "#monday", (*bodies)[1]["channel"])
+}
+
+func TestSlackReportsAPIErrors(t *testing.T) {
+	server, _, _ := recordRequests(t, `{"ok":false,"error":"channel_not_found"}`)
+	slack, err := NewSlack(SlackConfig{Token: "xoxb-token", Channel: "#missing"})
+	require.NoError(t, err)
+	slack.apiURL = server.URL
+
+	err = slack.Notify(context.Background(), Event{Kind: RunFailed, IssueID: "DEL-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "channel_not_found")
+}
+
+func TestNewSlackValidatesConfig(t *testing.T) {
+	_, err := NewSlack(SlackConfig{})
+	assert.Error(t, err)
+	_, err = NewSlack(SlackConfig{Token: "xoxb-token"})
+	assert.Error(t, err)
+}
+
+func TestParseRepoChannels(t *testing.T) {
+	channels, err := ParseRepoChannels("acme/app=#app, acme/api = #api,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"acme/app": "#app", "acme/api": "#api"}, channels)
+
+	_, err = ParseRepoChannels("acme/app")
+	assert.Error(t, err)
+}
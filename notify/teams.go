@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Teams posts run events to a Microsoft Teams incoming webhook as adaptive cards.
+type Teams struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewTeams returns a Teams notifier posting to webhookURL.
+func NewTeams(webhookURL string) (*Teams, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("teams requires a webhook URL")
+	}
+	return &Teams{webhookURL: webhookURL, httpClient: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Notify posts event as an adaptive card with the run details as facts and links to the
+// issue and pull request.
+func (t *Teams) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     teamsCard(event),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode teams message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create teams request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post teams message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("teams returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// teamsCard renders event as an adaptive card.
+func teamsCard(event Event) map[string]any {
+	color := "accent"
+	switch event.Kind {
+	case RunSucceeded:
+		color = "good"
+	case RunFailed:
+		color = "attention"
+	}
+
+	var facts []map[string]string
+	for _, detail := range event.Details() {
+		facts = append(facts, map[string]string{"title": detail[0], "value": detail[1]})
+	}
+
+	var actions []map[string]string
+	if event.PRURL != "" {
+		actions = append(actions, map[string]string{"type": "Action.OpenUrl", "title": "View pull request", "url": event.PRURL})
+	}
+	if event.IssueURL != "" {
+		actions = append(actions, map[string]string{"type": "Action.OpenUrl", "title": "View issue", "url": event.IssueURL})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"msteams": map[string]string{"width": "Full"},
+		"body": []map[string]any{
+			{"type": "TextBlock", "text": event.Headline(), "weight": "Bolder", "size": "Medium", "color": color, "wrap": true},
+			{"type": "FactSet", "facts": facts},
+		},
+	}
+	if len(actions) > 0 {
+		card["actions"] = actions
+	}
+	return card
+}
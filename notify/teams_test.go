@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamsNotify(t *testing.T) {
+	server, bodies, _ := recordRequests(t, "1")
+	teams, err := NewTeams(server.URL)
+	require.NoError(t, err)
+
+	err = teams.Notify(context.Background(), Event{
+		Kind:     RunSucceeded,
+		IssueID:  "DEL-163",
+		IssueURL: "https://linear.app/acme/issue/DEL-163",
+		Repo:     "https://github.com/acme/app",
+		PRURL:    "https://github.com/acme/app/pull/7",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *bodies, 1)
+	assert.Equal(t, "message", (*bodies)[0]["type"])
+	attachment := (*bodies)[0]["attachments"].([]any)[0].(map[string]any)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+
+	card := attachment["content"].(map[string]any)
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	body := card["body"].([]any)
+	headline := body[0].(map[string]any)
+	assert.Equal(t, "good", headline["color"])
+	assert.Contains(t, headline["text"], "DEL-163")
+	assert.Equal(t, "FactSet", body[1].(map[string]any)["type"])
+
+	actions := card["actions"].([]any)
+	require.Len(t, actions, 2)
+	assert.Equal(t, "https://github.com/acme/app/pull/7", actions[0].(map[string]any)["url"])
+	assert.Equal(t, "https://linear.app/acme/issue/DEL-163", actions[1].(map[string]any)["url"])
+}
+
+func TestTeamsReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Webhook Bad Request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	teams, err := NewTeams(server.URL)
+	require.NoError(t, err)
+	err = teams.Notify(context.Background(), Event{Kind: RunStarted, IssueID: "DEL-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+}
@@ -0,0 +1,10 @@
+// Package osascript holds what the terminal launchers and desktop notifications share to drive
+// macOS applications through AppleScript.
+package osascript
+
+import "strings"
+
+// Quote quotes s as an AppleScript string literal. Newlines become spaces.
+func Quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
@@ -0,0 +1,11 @@
+package osascript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"DEL-1: Fix \"login\" in C:\\app "`, Quote("DEL-1: Fix \"login\" in C:\\app\n"))
+}
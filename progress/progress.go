@@ -0,0 +1,283 @@
+// Package progress turns the JSON event stream of a coding agent into short, human-readable
+// progress lines, so non-verbose runs show what the agent is doing without its full output.
+// It understands Codex response items and Claude Code stream-json messages; anything else is
+// ignored.
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Event kinds.
+const (
+	KindCommand = "command"
+	KindTest    = "test"
+	KindEdit    = "edit"
+)
+
+// maxDetailLength truncates long commands in progress lines.
+const maxDetailLength = 80
+
+// testCommandPattern matches commands that run a test suite.
+var testCommandPattern = regexp.MustCompile(`\b(go test|npm (run )?test|yarn test|pnpm test|pytest|cargo test|make test|jest|vitest|rspec|mvn test|gradle test|bundle exec rake)\b`)
+
+// patchFilePattern matches the file headers of an apply_patch payload.
+var patchFilePattern = regexp.MustCompile(`(?m)^\*\*\* (?:Add|Update|Delete) File: (.+)$`)
+
+// Usage is the token usage and cost an agent reported.
+type Usage struct {
+	// InputTokens and OutputTokens count the tokens consumed
+	InputTokens  int64
+	OutputTokens int64
+	// CostUSD is the reported cost; only meaningful when HasCost is set
+	CostUSD float64
+	// HasCost is set when the agent reported a cost
+	HasCost bool
+}
+
+// add accumulates other into u.
+func (u *Usage) add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	if other.HasCost {
+		u.CostUSD += other.CostUSD
+		u.HasCost = true
+	}
+}
+
+// ParseUsage extracts the usage reported by one line of agent output: a Claude Code "result"
+// message or a Codex "turn.completed" event.
+func ParseUsage(line []byte) (Usage, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return Usage{}, false
+	}
+
+	var item struct {
+		Type         string   `json:"type"`
+		TotalCostUSD *float64 `json:"total_cost_usd"`
+		Usage        *struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(line, &item); err != nil {
+		return Usage{}, false
+	}
+	if item.Type != "result" && item.Type != "turn.completed" {
+		return Usage{}, false
+	}
+
+	var usage Usage
+	if item.Usage != nil {
+		usage.InputTokens = item.Usage.InputTokens
+		usage.OutputTokens = item.Usage.OutputTokens
+	}
+	if item.TotalCostUSD != nil {
+		usage.CostUSD = *item.TotalCostUSD
+		usage.HasCost = true
+	}
+	return usage, item.Usage != nil || usage.HasCost
+}
+
+// Event is a notable agent action.
+type Event struct {
+	// Kind is one of KindCommand, KindTest, or KindEdit
+	Kind string
+	// Detail is the command line or the edited file
+	Detail string
+}
+
+// String renders the event as a progress line.
+func (e Event) String() string {
+	switch e.Kind {
+	case KindTest:
+		return fmt.Sprintf("🧪 Running tests: %s", truncate(e.Detail))
+	case KindEdit:
+		return fmt.Sprintf("✏️  Edited %s", e.Detail)
+	default:
+		return fmt.Sprintf("⚙️  Running: %s", truncate(e.Detail))
+	}
+}
+
+// Parse extracts the events from one line of agent output.
+func Parse(line []byte) []Event {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil
+	}
+
+	var item struct {
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+		Message   struct {
+			Content []struct {
+				Type  string          `json:"type"`
+				Name  string          `json:"name"`
+				Input json.RawMessage `json:"input"`
+			} `json:"content"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(line, &item); err != nil {
+		return nil
+	}
+
+	switch item.Type {
+	case "function_call":
+		return codexEvents(item.Name, item.Arguments)
+	case "assistant":
+		var events []Event
+		for _, content := range item.Message.Content {
+			if content.Type == "tool_use" {
+				events = append(events, claudeEvents(content.Name, content.Input)...)
+			}
+		}
+		return events
+	}
+	return nil
+}
+
+// codexEvents interprets a Codex function call.
+func codexEvents(name, arguments string) []Event {
+	if name != "shell" && name != "container.exec" {
+		return nil
+	}
+	var args struct {
+		Command []string `json:"command"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil || len(args.Command) == 0 {
+		return nil
+	}
+
+	if args.Command[0] == "apply_patch" && len(args.Command) > 1 {
+		return patchEvents(args.Command[1])
+	}
+	command := args.Command
+	// Unwrap `bash -lc "<script>"`.
+	if len(command) == 3 && (command[1] == "-lc" || command[1] == "-c") {
+		command = command[2:]
+	}
+	return []Event{commandEvent(strings.Join(command, " "))}
+}
+
+// claudeEvents interprets a Claude Code tool use.
+func claudeEvents(name string, input json.RawMessage) []Event {
+	var args struct {
+		Command  string `json:"command"`
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil {
+		return nil
+	}
+
+	switch name {
+	case "Bash":
+		if args.Command != "" {
+			return []Event{commandEvent(args.Command)}
+		}
+	case "Edit", "MultiEdit", "Write", "NotebookEdit":
+		if args.FilePath != "" {
+			return []Event{{Kind: KindEdit, Detail: args.FilePath}}
+		}
+	}
+	return nil
+}
+
+// patchEvents returns an edit event for every file touched by an apply_patch payload.
+func patchEvents(patch string) []Event {
+	var events []Event
+	for _, match := range patchFilePattern.FindAllStringSubmatch(patch, -1) {
+		events = append(events, Event{Kind: KindEdit, Detail: strings.TrimSpace(match[1])})
+	}
+	return events
+}
+
+// commandEvent classifies a shell command.
+func commandEvent(command string) Event {
+	command = strings.TrimSpace(command)
+	if testCommandPattern.MatchString(command) {
+		return Event{Kind: KindTest, Detail: command}
+	}
+	return Event{Kind: KindCommand, Detail: command}
+}
+
+// truncate shortens s to a single line of at most maxDetailLength characters.
+func truncate(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " …"
+	}
+	if len([]rune(s)) > maxDetailLength {
+		s = string([]rune(s)[:maxDetailLength-1]) + "…"
+	}
+	return s
+}
+
+// Writer consumes agent output and writes a progress line for every event, indented under the
+// current stage. Each edited file is reported once. Reported usage is accumulated.
+type Writer struct {
+	out    io.Writer
+	buf    []byte
+	edited map[string]bool
+	usage  Usage
+}
+
+// NewWriter returns a Writer printing progress lines to out.
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{out: out, edited: make(map[string]bool)}
+}
+
+// Write buffers p and reports the events of every complete line.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.report(w.buf[:i]); err != nil {
+			return len(p), err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush reports the events of any buffered partial line.
+func (w *Writer) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.report(w.buf)
+	w.buf = nil
+	return err
+}
+
+// Usage returns the usage reported so far.
+func (w *Writer) Usage() Usage {
+	return w.usage
+}
+
+// report writes the progress lines for one line of agent output.
+func (w *Writer) report(line []byte) error {
+	if usage, ok := ParseUsage(line); ok {
+		w.usage.add(usage)
+	}
+	for _, event := range Parse(line) {
+		if event.Kind == KindEdit {
+			if w.edited[event.Detail] {
+				continue
+			}
+			w.edited[event.Detail] = true
+		}
+		if _, err := fmt.Fprintf(w.out, "   %s\n", event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
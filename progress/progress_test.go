@@ -0,0 +1,99 @@
+package progress
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []Event
+	}{
+		{
+			name:     "codex shell command",
+			line:     `{"type":"function_call","name":"shell","arguments":"{\"command\":[\"bash\",\"-lc\",\"ls -la\"]}"}`,
+			expected: []Event{{Kind: KindCommand, Detail: "ls -la"}},
+		},
+		{
+			name:     "codex test run",
+			line:     `{"type":"function_call","name":"shell","arguments":"{\"command\":[\"go\",\"test\",\"./...\"]}"}`,
+			expected: []Event{{Kind: KindTest, Detail: "go test ./..."}},
+		},
+		{
+			name: "codex apply_patch",
+			line: `{"type":"function_call","name":"shell","arguments":"{\"command\":[\"apply_patch\",\"*** Begin Patch\\n*** Update File: cmd/root.go\\n@@\\n*** Add File: cmd/new.go\\n*** End Patch\"]}"}`,
+			expected: []Event{
+				{Kind: KindEdit, Detail: "cmd/root.go"},
+				{Kind: KindEdit, Detail: "cmd/new.go"},
+			},
+		},
+		{
+			name: "claude tool uses",
+			line: `{"type":"assistant","message":{"content":[{"type":"text","text":"Fixing"},{"type":"tool_use","name":"Edit","input":{"file_path":"main.go"}},{"type":"tool_use","name":"Bash","input":{"command":"npm test"}}]}}`,
+			expected: []Event{
+				{Kind: KindEdit, Detail: "main.go"},
+				{Kind: KindTest, Detail: "npm test"},
+			},
+		},
+		{
+			name: "message item",
+			line: `{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Done"}]}`,
+		},
+		{
+			name: "plain text",
+			line: "Thinking about the problem...",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Parse([]byte(test.line)))
+		})
+	}
+}
+
+func TestWriterReportsEachEditOnce(t *testing.T) {
+	var out strings.Builder
+	w := NewWriter(&out)
+
+	edit := `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Write","input":{"file_path":"a.go"}}]}}`
+	w.Write([]byte(edit + "\n" + edit[:20]))
+	w.Write([]byte(edit[20:] + "\n"))
+	w.Write([]byte(`{"type":"function_call","name":"shell","arguments":"{\"command\":[\"make\",\"build\"]}"}`))
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, "   ✏️  Edited a.go\n   ⚙️  Running: make build\n", out.String())
+}
+
+func TestEventStringTruncatesLongCommands(t *testing.T) {
+	event := Event{Kind: KindCommand, Detail: strings.Repeat("x", 200)}
+	assert.LessOrEqual(t, len([]rune(event.String())), maxDetailLength+len([]rune("⚙️  Running: ")))
+
+	event = Event{Kind: KindCommand, Detail: "cat <<EOF\nline"}
+	assert.Equal(t, "⚙️  Running: cat <<EOF …", event.String())
+}
+
+func TestParseUsage(t *testing.T) {
+	usage, ok := ParseUsage([]byte(`{"type":"result","total_cost_usd":0.42,"usage":{"input_tokens":1000,"output_tokens":200}}`))
+	assert.True(t, ok)
+	assert.Equal(t, Usage{InputTokens: 1000, OutputTokens: 200, CostUSD: 0.42, HasCost: true}, usage)
+
+	usage, ok = ParseUsage([]byte(`{"type":"turn.completed","usage":{"input_tokens":50,"cached_input_tokens":10,"output_tokens":5}}`))
+	assert.True(t, ok)
+	assert.Equal(t, Usage{InputTokens: 50, OutputTokens: 5}, usage)
+
+	_, ok = ParseUsage([]byte(`{"type":"function_call","name":"shell"}`))
+	assert.False(t, ok)
+}
+
+func TestWriterAccumulatesUsage(t *testing.T) {
+	w := NewWriter(io.Discard)
+	w.Write([]byte(`{"type":"turn.completed","usage":{"input_tokens":50,"output_tokens":5}}` + "\n"))
+	w.Write([]byte(`{"type":"turn.completed","usage":{"input_tokens":20,"output_tokens":2}}` + "\n"))
+	assert.Equal(t, Usage{InputTokens: 70, OutputTokens: 7}, w.Usage())
+}
@@ -0,0 +1,284 @@
+// Package prompt builds the prompt of the agent working on an issue from a Go template, with
+// the issue, its acceptance criteria and discussion, the repository's conventions, and any
+// additional context.
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"monday/issues"
+)
+
+// DefaultTemplate is the template prompts are built from unless another one is given. Without
+// details, acceptance criteria, comments, conventions, or context it renders the issue's
+// title and description only.
+const DefaultTemplate = `{{.Issue.Title}}
+
+{{.Issue.Description}}
+{{- if .Details}}
+
+## Issue details
+{{range .Details}}
+- {{.}}
+{{- end}}
+{{- end}}
+{{- if .AcceptanceCriteria}}
+
+## Acceptance criteria
+
+The change is done when:
+{{range .AcceptanceCriteria}}
+- {{.}}
+{{- end}}
+{{- end}}
+{{- if .Comments}}
+
+## Discussion on the issue
+{{- range .Comments}}
+
+**{{.Author}}:** {{.Body}}
+{{- end}}
+{{- end}}
+{{- if .Conventions}}
+
+## Repository conventions
+
+Follow these contribution guidelines of the repository:
+
+{{.Conventions}}
+{{- end}}
+{{- range .Contexts}}
+
+## Additional context: {{.Source}}
+
+{{.Content}}
+{{- end}}`
+
+// ConventionFiles are the files, relative to the root of a repository, its conventions are read
+// from; the first one that exists is used.
+var ConventionFiles = []string{"CONTRIBUTING.md", ".github/CONTRIBUTING.md", "docs/CONTRIBUTING.md"}
+
+// maxConventionBytes is the size the conventions of a repository are truncated to.
+const maxConventionBytes = 32 << 10
+
+// Context is additional material for the prompt.
+type Context struct {
+	// Source names where the material came from: a path or URL
+	Source string
+	// Content is the material itself
+	Content string
+}
+
+// Comment is a comment on the issue.
+type Comment struct {
+	// Author is the name of whoever posted the comment
+	Author string
+	// Body is the Markdown text of the comment
+	Body string
+}
+
+// Data is what prompt templates are executed with.
+type Data struct {
+	// Issue is the issue the agent works on
+	Issue *issues.Issue
+	// Details describe the issue beyond its description, one line each: its priority,
+	// estimate, labels, parent issue, sub-issues, and linked issues
+	Details []string
+	// AcceptanceCriteria are the criteria listed in the issue's description
+	AcceptanceCriteria []string
+	// Comments are the comments on the issue, with empty ones left out
+	Comments []Comment
+	// Conventions are the repository's contribution guidelines; empty before the repository
+	// is cloned or if it has none
+	Conventions string
+	// Contexts are additional material, such as design docs or error logs
+	Contexts []Context
+}
+
+// NewData returns the data of the prompt for issue with the conventions of the repository and
+// the additional contexts.
+func NewData(issue *issues.Issue, conventions string, contexts []Context) Data {
+	data := Data{
+		Issue:              issue,
+		Details:            Details(issue),
+		AcceptanceCriteria: AcceptanceCriteria(issue.Description),
+		Conventions:        strings.TrimSpace(conventions),
+	}
+	for _, c := range issue.Comments.Nodes {
+		body := strings.TrimSpace(c.Body)
+		if body == "" {
+			continue
+		}
+		author := "Integration"
+		if c.User != nil && c.User.Name != "" {
+			author = c.User.Name
+		}
+		data.Comments = append(data.Comments, Comment{Author: author, Body: body})
+	}
+	for _, c := range contexts {
+		data.Contexts = append(data.Contexts, Context{Source: c.Source, Content: strings.TrimSpace(c.Content)})
+	}
+	return data
+}
+
+// Template builds prompts.
+type Template struct {
+	tmpl *template.Template
+}
+
+// Default returns the template of DefaultTemplate.
+func Default() *Template {
+	return &Template{tmpl: template.Must(template.New("prompt").Parse(DefaultTemplate))}
+}
+
+// Load parses the template file at path. Templates that fail on an issue without a title and
+// description are rejected up front instead of when the prompt is built.
+func Load(path string) (*Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	t := &Template{tmpl: tmpl}
+	if _, err := t.Build(NewData(&issues.Issue{}, "", nil)); err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return t, nil
+}
+
+// Build renders the prompt for data.
+func (t *Template) Build(data Data) (string, error) {
+	var out bytes.Buffer
+	if err := t.tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// relationPhrases describe how an issue relates to the related issue of each relation type.
+var relationPhrases = map[string]string{
+	"blocks":    "Blocks",
+	"duplicate": "Duplicate of",
+	"related":   "Related to",
+	"similar":   "Similar to",
+}
+
+// Details returns the lines describing issue beyond its description: its priority, estimate,
+// labels, parent issue, sub-issues, and linked issues, as far as it has them.
+func Details(issue *issues.Issue) []string {
+	var details []string
+	if issue.Priority != 0 && issue.PriorityLabel != "" {
+		details = append(details, "Priority: "+issue.PriorityLabel)
+	}
+	if issue.Estimate != nil {
+		details = append(details, fmt.Sprintf("Estimate: %s points", strconv.FormatFloat(*issue.Estimate, 'f', -1, 64)))
+	}
+	if len(issue.Labels.Nodes) > 0 {
+		names := make([]string, len(issue.Labels.Nodes))
+		for i, label := range issue.Labels.Nodes {
+			names[i] = label.Name
+		}
+		details = append(details, "Labels: "+strings.Join(names, ", "))
+	}
+	if issue.Parent != nil {
+		details = append(details, "Sub-issue of "+issueRef(*issue.Parent))
+	}
+	for _, child := range issue.Children.Nodes {
+		details = append(details, "Has sub-issue "+issueRef(child))
+	}
+	for _, relation := range issue.Relations.Nodes {
+		phrase, ok := relationPhrases[relation.Type]
+		if !ok {
+			phrase = "Linked to"
+		}
+		details = append(details, phrase+" "+issueRef(relation.RelatedIssue))
+	}
+	return details
+}
+
+// issueRef renders a reference to another issue: its identifier, title, and state if known.
+func issueRef(ref issues.IssueRef) string {
+	s := fmt.Sprintf("%s: %s", ref.Identifier, ref.Title)
+	if ref.State.Name != "" {
+		s += fmt.Sprintf(" (%s)", ref.State.Name)
+	}
+	return s
+}
+
+// criteriaHeading matches a line introducing acceptance criteria: a Markdown heading, a bold
+// line, or a line ending with a colon.
+var criteriaHeading = regexp.MustCompile(`(?i)^(#+\s*|\*\*|__)?\s*acceptance criteria\s*(:|\*\*|__)*\s*:?\s*$`)
+
+// listItem matches a Markdown list item, with an optional task checkbox, capturing its text.
+var listItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.+)$`)
+
+// AcceptanceCriteria returns the items of the list following an "Acceptance criteria" heading
+// in description, or, without such a heading, the task list items of description.
+func AcceptanceCriteria(description string) []string {
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		if criteriaHeading.MatchString(strings.TrimSpace(line)) {
+			return listAfter(lines[i+1:])
+		}
+	}
+	var tasks []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "- [ ]") || strings.HasPrefix(trimmed, "- [x]") || strings.HasPrefix(trimmed, "- [X]") {
+			if m := listItem.FindStringSubmatch(trimmed); m != nil {
+				tasks = append(tasks, strings.TrimSpace(m[1]))
+			}
+		}
+	}
+	return tasks
+}
+
+// listAfter returns the items of the list lines start with, skipping blank lines before it.
+// The list ends at the first line that is neither an item nor blank, or at a blank line after
+// the items.
+func listAfter(lines []string) []string {
+	var items []string
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if len(items) > 0 {
+				break
+			}
+			continue
+		}
+		m := listItem.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		items = append(items, strings.TrimSpace(m[1]))
+	}
+	return items
+}
+
+// LoadConventions returns the contents of the first of ConventionFiles in the repository at
+// dir, truncated to a size that leaves room for the rest of the prompt, or "" if there is none.
+func LoadConventions(dir string) (string, error) {
+	for _, name := range ConventionFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(data) > maxConventionBytes {
+			return string(data[:maxConventionBytes]) + "\n[truncated]", nil
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,139 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/linear"
+)
+
+func TestAcceptanceCriteria(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		expected    []string
+	}{
+		{
+			name:        "none",
+			description: "Users cannot log in.",
+		},
+		{
+			name:        "heading",
+			description: "Users cannot log in.\n\n## Acceptance Criteria\n\n- SSO users can log in\n- [ ] Errors are shown\n\nSee the logs.",
+			expected:    []string{"SSO users can log in", "Errors are shown"},
+		},
+		{
+			name:        "bold line with numbered list",
+			description: "**Acceptance criteria:**\n1. Login works\n2) Logout works\nNotes follow",
+			expected:    []string{"Login works", "Logout works"},
+		},
+		{
+			name:        "task list without heading",
+			description: "Steps:\n- [ ] Add the endpoint\n- [x] Write the migration\n- plain item",
+			expected:    []string{"Add the endpoint", "Write the migration"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, AcceptanceCriteria(test.description))
+		})
+	}
+}
+
+func TestDetails(t *testing.T) {
+	estimate := 2.5
+	issue := &linear.IssueDetails{
+		Priority:      1,
+		PriorityLabel: "Urgent",
+		Estimate:      &estimate,
+		Labels:        linear.LabelsConnection{Nodes: []linear.Label{{Name: "bug"}, {Name: "auth"}}},
+		Parent:        &linear.IssueRef{Identifier: "DEL-100", Title: "Auth overhaul", State: linear.WorkflowState{Name: "In Progress"}},
+		Children:      linear.IssueRefsConnection{Nodes: []linear.IssueRef{{Identifier: "DEL-124", Title: "Add SSO test"}}},
+		Relations: linear.RelationsConnection{Nodes: []linear.Relation{
+			{Type: "blocks", RelatedIssue: linear.IssueRef{Identifier: "DEL-130", Title: "Ship SSO"}},
+			{Type: "unknown", RelatedIssue: linear.IssueRef{Identifier: "DEL-131", Title: "Docs"}},
+		}},
+	}
+
+	assert.Equal(t, []string{
+		"Priority: Urgent",
+		"Estimate: 2.5 points",
+		"Labels: bug, auth",
+		"Sub-issue of DEL-100: Auth overhaul (In Progress)",
+		"Has sub-issue DEL-124: Add SSO test",
+		"Blocks DEL-130: Ship SSO",
+		"Linked to DEL-131: Docs",
+	}, Details(issue))
+	assert.Empty(t, Details(&linear.IssueDetails{PriorityLabel: "No priority"}))
+}
+
+func TestDefaultTemplate(t *testing.T) {
+	issue := &linear.IssueDetails{Title: "Fix login", Description: "Users cannot log in"}
+
+	got, err := Default().Build(NewData(issue, "", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "Fix login\n\nUsers cannot log in", got)
+
+	issue = &linear.IssueDetails{
+		Title:       "Fix login",
+		Description: "Users cannot log in\n\nAcceptance criteria:\n- SSO works",
+		Labels:      linear.LabelsConnection{Nodes: []linear.Label{{Name: "bug"}}},
+		Comments: linear.CommentsConnection{Nodes: []linear.Comment{
+			{Body: "Only on Safari", User: &linear.User{Name: "Ada"}},
+			{Body: "  "},
+			{Body: "Sentry: 40 events"},
+		}},
+	}
+	got, err = Default().Build(NewData(issue, "Run make lint.\n", []Context{{Source: "errors.log", Content: "panic: nil map\n"}}))
+	require.NoError(t, err)
+	expected := "Fix login\n\nUsers cannot log in\n\nAcceptance criteria:\n- SSO works" +
+		"\n\n## Issue details\n\n- Labels: bug" +
+		"\n\n## Acceptance criteria\n\nThe change is done when:\n\n- SSO works" +
+		"\n\n## Discussion on the issue\n\n**Ada:** Only on Safari\n\n**Integration:** Sentry: 40 events" +
+		"\n\n## Repository conventions\n\nFollow these contribution guidelines of the repository:\n\nRun make lint." +
+		"\n\n## Additional context: errors.log\n\npanic: nil map"
+	assert.Equal(t, expected, got)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prompt.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("Work on {{.Issue.Identifier}}: {{.Issue.Title}}\n{{range .AcceptanceCriteria}}* {{.}}\n{{end}}"), 0o644))
+
+	tmpl, err := Load(path)
+	require.NoError(t, err)
+	got, err := tmpl.Build(NewData(&linear.IssueDetails{Identifier: "DEL-1", Title: "Fix login", Description: "- [ ] SSO works"}, "", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "Work on DEL-1: Fix login\n* SSO works", got)
+
+	require.NoError(t, os.WriteFile(path, []byte("{{.Issue.Nope}}"), 0o644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "invalid prompt template")
+
+	_, err = Load(filepath.Join(dir, "missing.tmpl"))
+	assert.ErrorContains(t, err, "failed to read prompt template")
+}
+
+func TestLoadConventions(t *testing.T) {
+	dir := t.TempDir()
+	conventions, err := LoadConventions(dir)
+	require.NoError(t, err)
+	assert.Empty(t, conventions)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "CONTRIBUTING.md"), []byte("Use gofmt."), 0o644))
+	conventions, err = LoadConventions(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "Use gofmt.", conventions)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CONTRIBUTING.md"), []byte(strings.Repeat("x", maxConventionBytes+1)), 0o644))
+	conventions, err = LoadConventions(dir)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(conventions, "\n[truncated]"))
+}
@@ -0,0 +1,53 @@
+package redact
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writer redacts output line by line before forwarding it to an underlying writer, so a
+// secret split across two writes is still masked. Call Flush after the producer is done
+// to emit a trailing partial line.
+type Writer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+// NewWriter returns a Writer forwarding redacted lines to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write buffers p and forwards every completed line, redacted.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf.Next(i + 1)
+		if _, err := io.WriteString(w.w, String(string(line))); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush forwards any buffered partial line.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w.w, String(w.buf.String()))
+	w.buf.Reset()
+	return err
+}
@@ -0,0 +1,89 @@
+package redact
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Core wraps a zapcore.Core so that messages, string fields, and error fields are redacted
+// before they are encoded. Use it with zap.WrapCore.
+func Core(core zapcore.Core) zapcore.Core {
+	return &redactingCore{Core: core}
+}
+
+// redactingCore redacts entries before passing them to the wrapped core.
+type redactingCore struct {
+	zapcore.Core
+}
+
+// With redacts fields attached to a child logger.
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+// Check routes entries through this core so Write can redact them.
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write redacts the entry message and fields and writes them to the wrapped core.
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = String(ent.Message)
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+// redactFields returns a copy of fields with string, stringer, and error values redacted.
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = String(field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Error(err)}
+			}
+		case zapcore.StringerType:
+			if s, ok := field.Interface.(interface{ String() string }); ok {
+				field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: String(s.String())}
+			}
+		case zapcore.ArrayMarshalerType, zapcore.ReflectType:
+			field = redactComplex(field)
+		}
+		redacted[i] = field
+	}
+	return redacted
+}
+
+// redactComplex redacts string slices, the common complex field type in monday's logs
+// (e.g. zap.Strings("args", ...)). Other complex values are passed through unchanged.
+func redactComplex(field zapcore.Field) zapcore.Field {
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	values, ok := enc.Fields[field.Key].([]interface{})
+	if !ok {
+		return field
+	}
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return field
+		}
+		strs = append(strs, String(s))
+	}
+	return zapcore.Field{Key: field.Key, Type: zapcore.ArrayMarshalerType, Interface: stringArray(strs)}
+}
+
+// stringArray marshals a slice of strings as a zap array.
+type stringArray []string
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (a stringArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, s := range a {
+		enc.AppendString(s)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package retry retries operations that fail transiently, such as API calls answered with a
+// 502 or a git push cut off by the network, with exponential backoff.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Policy says how often and how patiently an operation is retried.
+type Policy struct {
+	// MaxAttempts is how many times the operation runs at most, the first included; values
+	// below 2 turn retries off
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled before each further one
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+	// RetryOn lists the HTTP statuses that are retried
+	RetryOn []int
+}
+
+// DefaultPolicy retries three times, waiting 1s, 2s, and 4s, on rate limiting and on the
+// statuses of overloaded or restarting servers and proxies.
+var DefaultPolicy = Policy{
+	MaxAttempts:    4,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+	RetryOn: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// Backoff returns the wait before the retry following attempt, counted from 1, with up to 20%
+// of jitter so that clients failing together do not retry together.
+func (p Policy) Backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait - time.Duration(rand.Int63n(int64(wait)/5+1))
+}
+
+// permanentError marks an error that is not worth retrying.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do returns it right away instead of retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do runs op until it succeeds, returns a Permanent error, ctx is done, or the policy's
+// attempts are used up, and returns its last error. onRetry, if not nil, is called with each
+// failure that is retried and the wait before the retry.
+func (p Policy) Do(ctx context.Context, op func() error, onRetry func(err error, wait time.Duration)) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		var permanent *permanentError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &permanent):
+			return permanent.err
+		case attempt >= p.MaxAttempts || ctx.Err() != nil:
+			return err
+		}
+
+		wait := p.Backoff(attempt)
+		if onRetry != nil {
+			onRetry(err, wait)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Transport is an http.RoundTripper that retries requests failing with a network error or a
+// status of its policy. Requests with a body are only retried when it can be read again,
+// as for those created by http.NewRequest from a bytes.Buffer, bytes.Reader, or strings.Reader.
+type Transport struct {
+	// Base sends the requests; nil is http.DefaultTransport
+	Base http.RoundTripper
+	// Policy is the retry policy of the requests
+	Policy Policy
+}
+
+// RoundTrip sends req, retrying as the policy says. A Retry-After header given in seconds
+// replaces the backoff when it is longer, up to the policy's MaxBackoff.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		retryable := err != nil || slices.Contains(t.Policy.RetryOn, resp.StatusCode)
+		if !retryable || attempt >= t.Policy.MaxAttempts || req.Context().Err() != nil {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := t.Policy.Backoff(attempt)
+		if resp != nil {
+			if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && time.Duration(seconds)*time.Second > wait {
+				wait = time.Duration(seconds) * time.Second
+				if t.Policy.MaxBackoff > 0 {
+					wait = min(wait, t.Policy.MaxBackoff)
+				}
+			}
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var quick = Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryOn: []int{http.StatusBadGateway}}
+
+func TestDo(t *testing.T) {
+	failure := errors.New("connection reset")
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "success", errs: []error{nil}, wantCalls: 1},
+		{name: "transient failure", errs: []error{failure, nil}, wantCalls: 2},
+		{name: "attempts used up", errs: []error{failure, failure, failure, nil}, wantCalls: 3, wantErr: failure},
+		{name: "permanent failure", errs: []error{Permanent(failure), nil}, wantCalls: 1, wantErr: failure},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls, retries := 0, 0
+			err := quick.Do(context.Background(), func() error {
+				calls++
+				return test.errs[calls-1]
+			}, func(error, time.Duration) { retries++ })
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantCalls, calls)
+			assert.Equal(t, test.wantCalls-1, retries)
+		})
+	}
+}
+
+func TestDo_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := quick.Do(ctx, func() error {
+		calls++
+		return errors.New("connection reset")
+	}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 5 * time.Second},
+		{attempt: 60, want: 5 * time.Second},
+	}
+	for _, test := range tests {
+		wait := policy.Backoff(test.attempt)
+		assert.LessOrEqual(t, wait, test.want)
+		assert.GreaterOrEqual(t, wait, test.want*4/5)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Policy: quick}}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query":"issue"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{`{"query":"issue"}`, `{"query":"issue"}`}, bodies)
+}
+
+func TestTransport_NotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Policy: quick}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,105 @@
+// Package sandbox runs commands inside a Docker container that only sees one directory of the
+// host, so code monday does not trust, such as an agent, cannot touch the rest of the host.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Options configures a container.
+type Options struct {
+	// Image is the image the container runs; it needs the tools the commands run with
+	Image string
+	// Memory is the memory limit, e.g. "4g"; empty for none
+	Memory string
+	// CPUs is the number of CPUs the container may use, e.g. "2"; empty for no limit
+	CPUs string
+	// User is the "uid:gid" commands run as, so files they create belong to the host user;
+	// empty for the image's user
+	User string
+	// Env lists KEY=value variables set for every command
+	Env []string
+}
+
+// Container is a running container with a host directory mounted at the same path, so paths
+// mean the same inside and outside of it. Its root filesystem is read-only; only the mounted
+// directory and /tmp are writable.
+type Container struct {
+	// name is the name of the container
+	name string
+}
+
+// Start starts the container name with dir mounted read-write. It keeps running until Stop.
+func Start(ctx context.Context, name, dir string, opts Options) (*Container, error) {
+	cmd := exec.CommandContext(ctx, "docker", runArgs(name, dir, opts)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to start container: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return &Container{name: name}, nil
+}
+
+// runArgs returns the docker arguments starting the container name with dir mounted.
+func runArgs(name, dir string, opts Options) []string {
+	args := []string{"run", "--detach", "--rm", "--name", name,
+		"--read-only", "--tmpfs", "/tmp",
+		"--cap-drop", "ALL", "--security-opt", "no-new-privileges",
+		"--volume", dir + ":" + dir, "--workdir", dir,
+		"--env", "HOME=/tmp"}
+	if opts.Memory != "" {
+		args = append(args, "--memory", opts.Memory)
+	}
+	if opts.CPUs != "" {
+		args = append(args, "--cpus", opts.CPUs)
+	}
+	if opts.User != "" {
+		args = append(args, "--user", opts.User)
+	}
+	for _, kv := range opts.Env {
+		args = append(args, "--env", kv)
+	}
+	return append(args, "--entrypoint", "sleep", opts.Image, "infinity")
+}
+
+// Name returns the name of the container.
+func (c *Container) Name() string {
+	return c.name
+}
+
+// Command returns the command running name with args in the directory dir of the container,
+// with env, a list of KEY=value variables, added to its environment. The values are passed
+// through the environment of the docker client rather than its arguments, so they do not show
+// up in process listings.
+func (c *Container) Command(ctx context.Context, dir string, env []string, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "docker", c.execArgs(dir, env, name, args)...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// execArgs returns the docker arguments of Command.
+func (c *Container) execArgs(dir string, env []string, name string, args []string) []string {
+	execArgs := []string{"exec", "--workdir", dir}
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		execArgs = append(execArgs, "--env", key)
+	}
+	execArgs = append(execArgs, c.name, name)
+	return append(execArgs, args...)
+}
+
+// Stop removes the container and everything it wrote outside of the mounted directory.
+func (c *Container) Stop(ctx context.Context) error {
+	out, err := exec.CommandContext(ctx, "docker", "rm", "--force", c.name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove container %s: %w: %s", c.name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
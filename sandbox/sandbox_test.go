@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "no limits",
+			opts:     Options{Image: "monday-agent"},
+			expected: "run --detach --rm --name monday-run --read-only --tmpfs /tmp --cap-drop ALL --security-opt no-new-privileges --volume /work:/work --workdir /work --env HOME=/tmp --entrypoint sleep monday-agent infinity",
+		},
+		{
+			name:     "limits, user, and environment",
+			opts:     Options{Image: "monday-agent", Memory: "4g", CPUs: "2", User: "1000:1000", Env: []string{"GIT_AUTHOR_NAME=Monday"}},
+			expected: "run --detach --rm --name monday-run --read-only --tmpfs /tmp --cap-drop ALL --security-opt no-new-privileges --volume /work:/work --workdir /work --env HOME=/tmp --memory 4g --cpus 2 --user 1000:1000 --env GIT_AUTHOR_NAME=Monday --entrypoint sleep monday-agent infinity",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, strings.Join(runArgs("monday-run", "/work", test.opts), " "))
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	c := &Container{name: "monday-run"}
+
+	cmd := c.Command(context.Background(), "/work", []string{"OPENAI_API_KEY=sk-secret"}, "codex", "exec", "fix it")
+
+	assert.Equal(t, []string{"docker", "exec", "--workdir", "/work", "--env", "OPENAI_API_KEY", "monday-run", "codex", "exec", "fix it"}, cmd.Args)
+	assert.NotContains(t, strings.Join(cmd.Args, " "), "sk-secret")
+	assert.Contains(t, cmd.Env, "OPENAI_API_KEY=sk-secret")
+}
+
+func TestCommand_NoEnv(t *testing.T) {
+	c := &Container{name: "monday-run"}
+
+	cmd := c.Command(context.Background(), "/work", nil, "git", "status")
+
+	assert.Equal(t, []string{"docker", "exec", "--workdir", "/work", "monday-run", "git", "status"}, cmd.Args)
+	assert.Nil(t, cmd.Env)
+}
@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// gcpSecretManagerEndpoint is the Google Cloud Secret Manager API.
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com/v1/"
+	// gcpMetadataTokenURL hands out access tokens of the service account of a Google Cloud
+	// workload, such as a Cloud Run service.
+	gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCPResolver resolves gcp-sm://projects/PROJECT/secrets/SECRET[/versions/VERSION] to a
+// secret version in Google Cloud Secret Manager; the latest version unless one is given.
+type GCPResolver struct {
+	// Endpoint is the Secret Manager API; empty for the Google Cloud one
+	Endpoint string
+	// AccessToken authenticates with the API; empty for $GOOGLE_OAUTH_ACCESS_TOKEN, or else a
+	// token of the workload's service account from the metadata server
+	AccessToken string
+	// TokenURL is the metadata server endpoint of access tokens; empty for the Google Cloud one
+	TokenURL string
+	// Client sends the requests; nil for a client with a 10s timeout
+	Client *http.Client
+}
+
+// Resolve reads the secret version ref names.
+func (g *GCPResolver) Resolve(ctx context.Context, ref *url.URL) (string, error) {
+	name := strings.Trim(ref.Host+ref.Path, "/")
+	parts := strings.Split(name, "/")
+	if (len(parts) != 4 && len(parts) != 6) || parts[0] != "projects" || parts[2] != "secrets" || (len(parts) == 6 && parts[4] != "versions") {
+		return "", fmt.Errorf("invalid reference %q: want gcp-sm://projects/PROJECT/secrets/SECRET[/versions/VERSION]", ref.Redacted())
+	}
+	if len(parts) == 4 {
+		name += "/versions/latest"
+	}
+
+	c := client(g.Client)
+	token, err := g.accessToken(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, firstNonEmpty(g.Endpoint, gcpSecretManagerEndpoint)+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := getJSON(c, req, &out); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid payload of %s: %w", name, err)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("%s is empty", name)
+	}
+	return string(data), nil
+}
+
+// accessToken returns the token to call the API with.
+func (g *GCPResolver) accessToken(ctx context.Context, c *http.Client) (string, error) {
+	if token := firstNonEmpty(g.AccessToken, os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")); token != "" {
+		return token, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, firstNonEmpty(g.TokenURL, gcpMetadataTokenURL), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := getJSON(c, req, &out); err != nil {
+		return "", fmt.Errorf("failed to get an access token from the metadata server (set GOOGLE_OAUTH_ACCESS_TOKEN outside of Google Cloud): %w", err)
+	}
+	return out.AccessToken, nil
+}
@@ -0,0 +1,113 @@
+// Package secrets resolves references to secrets kept outside of monday's configuration, so
+// settings such as API keys can name where the secret lives instead of holding it. A reference
+// is a URL whose scheme selects the Resolver:
+//
+//	env://CI_LINEAR_API_KEY
+//	file:///run/secrets/linear_api_key
+//	vault://secret/monday#linear
+//	gcp-sm://projects/acme/secrets/linear-api-key/versions/latest
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Resolver looks up the secrets of one reference scheme.
+type Resolver interface {
+	// Resolve returns the secret ref refers to
+	Resolve(ctx context.Context, ref *url.URL) (string, error)
+}
+
+var (
+	mu        sync.RWMutex
+	resolvers = map[string]Resolver{
+		"env":    EnvResolver{},
+		"file":   FileResolver{},
+		"vault":  &VaultResolver{},
+		"gcp-sm": &GCPResolver{},
+	}
+)
+
+// Register makes r resolve the references of scheme, replacing any resolver registered for it.
+func Register(scheme string, r Resolver) {
+	mu.Lock()
+	defer mu.Unlock()
+	resolvers[scheme] = r
+}
+
+// resolverFor returns the resolver of the reference value, or nil if value is not one.
+func resolverFor(value string) (Resolver, *url.URL) {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return nil, nil
+	}
+	mu.RLock()
+	r := resolvers[scheme]
+	mu.RUnlock()
+	if r == nil {
+		return nil, nil
+	}
+	ref, err := url.Parse(value)
+	if err != nil {
+		return nil, nil
+	}
+	return r, ref
+}
+
+// IsReference reports whether value is a reference of a registered scheme.
+func IsReference(value string) bool {
+	r, _ := resolverFor(value)
+	return r != nil
+}
+
+// Resolve returns the secret value refers to, or value itself if it is not a reference.
+func Resolve(ctx context.Context, value string) (string, error) {
+	r, ref := resolverFor(value)
+	if r == nil {
+		return value, nil
+	}
+	secret, err := r.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s secret: %w", ref.Scheme, err)
+	}
+	return secret, nil
+}
+
+// EnvResolver resolves env://NAME to the value of the environment variable NAME.
+type EnvResolver struct{}
+
+// Resolve returns the value of the variable ref names; it must be set.
+func (EnvResolver) Resolve(_ context.Context, ref *url.URL) (string, error) {
+	name := ref.Host
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+// FileResolver resolves file:///path to the contents of the file, without surrounding
+// whitespace, as written by Docker and Kubernetes secret mounts.
+type FileResolver struct{}
+
+// Resolve reads the file ref names.
+func (FileResolver) Resolve(_ context.Context, ref *url.URL) (string, error) {
+	path := ref.Path
+	if ref.Host != "" {
+		path = ref.Host + path
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return value, nil
+}
@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "linear_api_key")
+	require.NoError(t, os.WriteFile(path, []byte("lin_api_file\n"), 0o600))
+	t.Setenv("CI_LINEAR_API_KEY", "lin_api_env")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "plain value", value: "lin_api_plain", want: "lin_api_plain"},
+		{name: "URL of an unknown scheme", value: "https://github.com/acme/app", want: "https://github.com/acme/app"},
+		{name: "environment", value: "env://CI_LINEAR_API_KEY", want: "lin_api_env"},
+		{name: "unset environment", value: "env://MONDAY_TEST_UNSET", wantErr: "is not set"},
+		{name: "file", value: "file://" + path, want: "lin_api_file"},
+		{name: "missing file", value: "file:///nonexistent/secret", wantErr: "failed to resolve file secret"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Resolve(context.Background(), test.value)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestIsReference(t *testing.T) {
+	assert.True(t, IsReference("vault://secret/monday#linear"))
+	assert.True(t, IsReference("gcp-sm://projects/acme/secrets/linear"))
+	assert.False(t, IsReference("lin_api_123"))
+	assert.False(t, IsReference("https://example.com"))
+}
+
+func TestVaultResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/monday", r.URL.Path)
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		w.Write([]byte(`{"data": {"data": {"linear": "lin_api_vault"}, "metadata": {"version": 3}}}`))
+	}))
+	defer server.Close()
+	r := &VaultResolver{Addr: server.URL, Token: "s.token", Client: server.Client()}
+
+	got, err := r.Resolve(context.Background(), mustParse(t, "vault://secret/monday#linear"))
+	require.NoError(t, err)
+	assert.Equal(t, "lin_api_vault", got)
+
+	_, err = r.Resolve(context.Background(), mustParse(t, "vault://secret/monday#github"))
+	assert.ErrorContains(t, err, `has no key "github"`)
+	_, err = r.Resolve(context.Background(), mustParse(t, "vault://secret/monday"))
+	assert.ErrorContains(t, err, "want vault://mount/path#key")
+}
+
+func TestGCPResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			w.Write([]byte(`{"access_token": "ya29.token", "expires_in": 3599}`))
+		case "/v1/projects/acme/secrets/linear/versions/latest:access":
+			assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"payload": {"data": "` + base64.StdEncoding.EncodeToString([]byte("lin_api_gcp")) + `"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	r := &GCPResolver{Endpoint: server.URL + "/v1/", TokenURL: server.URL + "/token", Client: server.Client()}
+
+	got, err := r.Resolve(context.Background(), mustParse(t, "gcp-sm://projects/acme/secrets/linear"))
+	require.NoError(t, err)
+	assert.Equal(t, "lin_api_gcp", got)
+
+	_, err = r.Resolve(context.Background(), mustParse(t, "gcp-sm://projects/acme/secrets/linear/versions/7"))
+	assert.ErrorContains(t, err, "answered 404")
+	_, err = r.Resolve(context.Background(), mustParse(t, "gcp-sm://acme/linear"))
+	assert.ErrorContains(t, err, "want gcp-sm://projects/PROJECT/secrets/SECRET")
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultResolver resolves vault://mount/path#key to the field key of the secret at path in the
+// KV version 2 secrets engine mounted at mount of a HashiCorp Vault server.
+type VaultResolver struct {
+	// Addr is the address of the server; empty for $VAULT_ADDR
+	Addr string
+	// Token authenticates with the server; empty for $VAULT_TOKEN
+	Token string
+	// Namespace is the Vault Enterprise namespace; empty for $VAULT_NAMESPACE
+	Namespace string
+	// Client sends the requests; nil for a client with a 10s timeout
+	Client *http.Client
+}
+
+// Resolve reads the secret ref names.
+func (v *VaultResolver) Resolve(ctx context.Context, ref *url.URL) (string, error) {
+	addr := firstNonEmpty(v.Addr, os.Getenv("VAULT_ADDR"))
+	token := firstNonEmpty(v.Token, os.Getenv("VAULT_TOKEN"))
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required")
+	}
+	path := strings.Trim(ref.Path, "/")
+	if ref.Host == "" || path == "" || ref.Fragment == "" {
+		return "", fmt.Errorf("invalid reference %q: want vault://mount/path#key", ref.Redacted())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+ref.Host+"/data/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := firstNonEmpty(v.Namespace, os.Getenv("VAULT_NAMESPACE")); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	var out struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := getJSON(client(v.Client), req, &out); err != nil {
+		return "", err
+	}
+	value, ok := out.Data.Data[ref.Fragment].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("secret %s/%s has no key %q", ref.Host, path, ref.Fragment)
+	}
+	return value, nil
+}
+
+// client returns c, or a client with a 10s timeout if c is nil.
+func client(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// getJSON sends req with c and decodes the JSON response into out.
+func getJSON(c *http.Client, req *http.Request, out any) error {
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,248 @@
+// Package store keeps a SQLite database of monday runs, written by the CLI and the server as
+// runs are queued, start, move through their stages, and end. It records where each run is,
+// such as its status, branch, and pull request, with the run's summary, and points at the
+// run's log. Runs are queried by repository, issue, status, and start time.
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// StatusQueued is the status of a run the server accepted but has not started yet. Other runs
+// have the status of their summary.
+const StatusQueued = "queued"
+
+// ErrNotFound is returned for runs the store has no record of.
+var ErrNotFound = errors.New("run not found")
+
+// schema creates the runs table.
+const schema = `CREATE TABLE IF NOT EXISTS runs (
+	run_id      TEXT PRIMARY KEY,
+	issue_id    TEXT NOT NULL,
+	repo        TEXT NOT NULL,
+	branch      TEXT NOT NULL DEFAULT '',
+	pr_url      TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL,
+	pid         INTEGER NOT NULL DEFAULT 0,
+	started_at  TIMESTAMP NOT NULL,
+	finished_at TIMESTAMP,
+	log_path    TEXT NOT NULL DEFAULT '',
+	summary     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS runs_issue_id ON runs (issue_id);
+CREATE INDEX IF NOT EXISTS runs_started_at ON runs (started_at);`
+
+// Run is the record of one run.
+type Run struct {
+	// RunID uniquely identifies the run
+	RunID string
+	// IssueID is the issue identifier, e.g. "DEL-163"
+	IssueID string
+	// Repo is the repository URL or local repository path the run works on
+	Repo string
+	// Branch is the issue branch, once known
+	Branch string
+	// PRURL is the pull request the run created or followed up on
+	PRURL string
+	// Status is queued, running, succeeded, failed, or canceled
+	Status string
+	// PID is the process executing the run
+	PID int
+	// StartedAt is when the run began, or was queued
+	StartedAt time.Time
+	// FinishedAt is when the run ended; zero while it is queued or running
+	FinishedAt time.Time
+	// LogPath is the run's log file, next to which its summary and checkpoint are kept
+	LogPath string
+	// Summary is the run's summary.json as of its last record; empty for queued runs
+	Summary []byte
+}
+
+// Filter selects runs. Zero fields match everything.
+type Filter struct {
+	// Repo matches runs whose repository URL or path contains it
+	Repo string
+	// Issue matches the issue identifier, case-insensitively
+	Issue string
+	// Status matches the run status exactly
+	Status string
+	// Since excludes runs started before it
+	Since time.Time
+	// Until excludes runs started after it
+	Until time.Time
+}
+
+// Store is a database of runs. It is safe for concurrent use, also by several processes.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database at path, creating it if needed.
+func Open(path string) (*Store, error) {
+	// Batches and the server write from several goroutines and processes at once; writers
+	// wait for each other rather than failing.
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run database: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set up run database %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// columns are the columns of the runs table, in the order of Run.args and scan.
+const columns = "run_id, issue_id, repo, branch, pr_url, status, pid, started_at, finished_at, log_path, summary"
+
+// insert adds a run given the values of columns.
+const insert = "INSERT INTO runs (" + columns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+// Save records run, replacing the record of the run with the same ID.
+func (s *Store) Save(run Run) error {
+	_, err := s.db.Exec(insert+` ON CONFLICT (run_id) DO UPDATE SET
+		issue_id = excluded.issue_id, repo = excluded.repo, branch = excluded.branch, pr_url = excluded.pr_url,
+		status = excluded.status, pid = excluded.pid, started_at = excluded.started_at,
+		finished_at = excluded.finished_at, log_path = excluded.log_path, summary = excluded.summary`,
+		run.args()...)
+	if err != nil {
+		return fmt.Errorf("failed to record run %s: %w", run.RunID, err)
+	}
+	return nil
+}
+
+// Add records run unless the store already has a record of it, such as one saved by the run
+// itself once it started.
+func (s *Store) Add(run Run) error {
+	_, err := s.db.Exec(insert+" ON CONFLICT (run_id) DO NOTHING", run.args()...)
+	if err != nil {
+		return fmt.Errorf("failed to record run %s: %w", run.RunID, err)
+	}
+	return nil
+}
+
+// args returns the values of columns for run.
+func (run Run) args() []any {
+	finished := sql.NullTime{Time: run.FinishedAt.UTC(), Valid: !run.FinishedAt.IsZero()}
+	return []any{run.RunID, run.IssueID, run.Repo, run.Branch, run.PRURL, run.Status, run.PID,
+		run.StartedAt.UTC(), finished, run.LogPath, string(run.Summary)}
+}
+
+// scan reads a row of columns.
+func scan(row interface{ Scan(...any) error }) (*Run, error) {
+	var run Run
+	var finished sql.NullTime
+	var sum string
+	if err := row.Scan(&run.RunID, &run.IssueID, &run.Repo, &run.Branch, &run.PRURL, &run.Status, &run.PID, &run.StartedAt, &finished, &run.LogPath, &sum); err != nil {
+		return nil, err
+	}
+	run.FinishedAt = finished.Time
+	if sum != "" {
+		run.Summary = []byte(sum)
+	}
+	return &run, nil
+}
+
+// Get returns the record of the run with the given ID, or ErrNotFound.
+func (s *Store) Get(runID string) (*Run, error) {
+	run, err := scan(s.db.QueryRow("SELECT "+columns+" FROM runs WHERE run_id = ?", runID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run %s: %w", runID, err)
+	}
+	return run, nil
+}
+
+// Query returns the runs that pass filter, oldest first.
+func (s *Store) Query(filter Filter) ([]*Run, error) {
+	var where []string
+	var args []any
+	if filter.Repo != "" {
+		where = append(where, "instr(repo, ?) > 0")
+		args = append(args, filter.Repo)
+	}
+	if filter.Issue != "" {
+		where = append(where, "issue_id = ? COLLATE NOCASE")
+		args = append(args, filter.Issue)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "started_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "started_at <= ?")
+		args = append(args, filter.Until.UTC())
+	}
+	query := "SELECT " + columns + " FROM runs"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	rows, err := s.db.Query(query+" ORDER BY started_at, run_id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+	var runs []*Run
+	for rows.Next() {
+		run, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read runs: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Import adds the runs returned by load unless the database has imported runs before. It
+// brings in runs recorded before the database existed; records the database already has are
+// kept.
+func (s *Store) Import(load func() ([]Run, error)) error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read run database version: %w", err)
+	}
+	if version > 0 {
+		return nil
+	}
+	runs, err := load()
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to import runs: %w", err)
+	}
+	defer tx.Rollback()
+	for _, run := range runs {
+		if _, err := tx.Exec(insert+" ON CONFLICT (run_id) DO NOTHING", run.args()...); err != nil {
+			return fmt.Errorf("failed to import run %s: %w", run.RunID, err)
+		}
+	}
+	if _, err := tx.Exec("PRAGMA user_version = 1"); err != nil {
+		return fmt.Errorf("failed to import runs: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to import runs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "monday.db")
+	s, err := Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s, path
+}
+
+func TestSaveAndGet(t *testing.T) {
+	s, _ := openStore(t)
+	started := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	run := Run{RunID: "run-1", IssueID: "DEL-1", Repo: "https://github.com/acme/app", Status: "running", PID: 42, StartedAt: started, LogPath: "/runs/run-1/run.log"}
+	require.NoError(t, s.Save(run))
+
+	got, err := s.Get("run-1")
+	require.NoError(t, err)
+	assert.True(t, got.StartedAt.Equal(started))
+	assert.True(t, got.FinishedAt.IsZero())
+	got.StartedAt = started
+	assert.Equal(t, run, *got)
+
+	run.Branch = "feature/del-1"
+	run.PRURL = "https://github.com/acme/app/pull/7"
+	run.Status = "succeeded"
+	run.FinishedAt = started.Add(time.Minute)
+	require.NoError(t, s.Save(run))
+	got, err = s.Get("run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "succeeded", got.Status)
+	assert.Equal(t, "https://github.com/acme/app/pull/7", got.PRURL)
+	assert.True(t, got.FinishedAt.Equal(run.FinishedAt))
+}
+
+func TestAddKeepsExistingRecord(t *testing.T) {
+	s, _ := openStore(t)
+	require.NoError(t, s.Save(Run{RunID: "run-1", IssueID: "DEL-1", Repo: "repo", Status: "running", StartedAt: time.Now()}))
+	require.NoError(t, s.Add(Run{RunID: "run-1", IssueID: "DEL-1", Repo: "repo", Status: StatusQueued, StartedAt: time.Now()}))
+	require.NoError(t, s.Add(Run{RunID: "run-2", IssueID: "DEL-2", Repo: "repo", Status: StatusQueued, StartedAt: time.Now()}))
+
+	got, err := s.Get("run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "running", got.Status)
+	got, err = s.Get("run-2")
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, got.Status)
+}
+
+func TestGetUnknownRun(t *testing.T) {
+	s, _ := openStore(t)
+	_, err := s.Get("run-9")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	_, path := openStore(t)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s, err := Open(path)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer s.Close()
+			errs <- s.Save(Run{RunID: "run-" + string(rune('a'+i)), IssueID: "DEL-1", Repo: "repo", Status: "running", StartedAt: time.Now()})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	s, _ := openStore(t)
+	now := time.Now()
+	for _, run := range []Run{
+		{RunID: "new", IssueID: "DEL-1", Repo: "https://github.com/acme/app", Status: "succeeded", StartedAt: now},
+		{RunID: "old", IssueID: "DEL-1", Repo: "https://github.com/acme/app", Status: "succeeded", StartedAt: now.Add(-48 * time.Hour)},
+		{RunID: "failed", IssueID: "DEL-2", Repo: "https://github.com/acme/api", Status: "failed", StartedAt: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, s.Save(run))
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{name: "no filter", filter: Filter{}, expected: []string{"old", "failed", "new"}},
+		{name: "status", filter: Filter{Status: "succeeded"}, expected: []string{"old", "new"}},
+		{name: "issue is case-insensitive", filter: Filter{Issue: "del-1"}, expected: []string{"old", "new"}},
+		{name: "repo", filter: Filter{Repo: "acme/api"}, expected: []string{"failed"}},
+		{name: "since", filter: Filter{Since: now.Add(-24 * time.Hour)}, expected: []string{"failed", "new"}},
+		{name: "until", filter: Filter{Until: now.Add(-24 * time.Hour)}, expected: []string{"old"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runs, err := s.Query(test.filter)
+			require.NoError(t, err)
+			var ids []string
+			for _, run := range runs {
+				ids = append(ids, run.RunID)
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
+
+func TestImportOnce(t *testing.T) {
+	s, _ := openStore(t)
+	require.NoError(t, s.Save(Run{RunID: "run-1", IssueID: "DEL-1", Repo: "repo", Status: "running", StartedAt: time.Now()}))
+	loads := 0
+	load := func() ([]Run, error) {
+		loads++
+		return []Run{
+			{RunID: "run-1", IssueID: "DEL-1", Repo: "repo", Status: "failed", StartedAt: time.Now()},
+			{RunID: "run-0", IssueID: "DEL-0", Repo: "repo", Status: "succeeded", StartedAt: time.Now().Add(-time.Hour), Summary: []byte(`{"run_id":"run-0"}`)},
+		}, nil
+	}
+	require.NoError(t, s.Import(load))
+	require.NoError(t, s.Import(load))
+	assert.Equal(t, 1, loads)
+
+	runs, err := s.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "run-0", runs[0].RunID)
+	assert.JSONEq(t, `{"run_id":"run-0"}`, string(runs[0].Summary))
+	assert.Equal(t, "running", runs[1].Status)
+}
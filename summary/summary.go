@@ -0,0 +1,332 @@
+// Package summary defines the machine-readable record of a single monday run. A Summary is
+// written as summary.json (the contract for dashboards, notifications, and other tooling)
+// and as a rendered summary.md next to the run's logs.
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Run and stage outcomes.
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// Summary describes one workflow run.
+type Summary struct {
+	// RunID uniquely identifies the run
+	RunID string `json:"run_id"`
+	// IssueID is the Linear issue identifier, e.g. "DEL-163"
+	IssueID string `json:"issue_id"`
+	// IssueTitle is the title of the Linear issue
+	IssueTitle string `json:"issue_title,omitempty"`
+	// IssueURL links to the issue in Linear
+	IssueURL string `json:"issue_url,omitempty"`
+	// Repo is the repository URL or local repository path the run worked on
+	Repo string `json:"repo"`
+	// Branch is the issue branch
+	Branch string `json:"branch,omitempty"`
+	// Workspace is the clone or worktree the run works in, once prepared
+	Workspace string `json:"workspace,omitempty"`
+	// PRURL is the pull request created by the run
+	PRURL string `json:"pr_url,omitempty"`
+	// Phase is how far the run got, from fetched through pr_created
+	Phase string `json:"phase,omitempty"`
+	// ResumedFrom is the run this run continued from the last phase that run reached
+	ResumedFrom string `json:"resumed_from,omitempty"`
+	// ReplayOf is the run whose recorded inputs this run re-executed
+	ReplayOf string `json:"replay_of,omitempty"`
+	// DryRun marks runs that only prepared the workspace and printed what they would do
+	DryRun bool `json:"dry_run,omitempty"`
+	// Status is the overall outcome: running, succeeded, failed, or canceled
+	Status string `json:"status"`
+	// PID is the process executing the run, used to tell live runs from interrupted ones
+	PID int `json:"pid,omitempty"`
+	// StartedAt is when the run began
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is when the run ended; zero while running
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	// DurationSeconds is the wall-clock duration of the run
+	DurationSeconds float64 `json:"duration_seconds"`
+	// Stages lists every stage in execution order
+	Stages []Stage `json:"stages"`
+	// Gates records the last run of each gate command, such as the tests, in the workspace
+	Gates []Gate `json:"gates,omitempty"`
+	// FilesChanged lists the files committed by the run
+	FilesChanged []string `json:"files_changed,omitempty"`
+	// AgentCostUSD is the estimated cost of the agent run, when known
+	AgentCostUSD *float64 `json:"agent_cost_usd,omitempty"`
+	// AgentInputTokens and AgentOutputTokens count the tokens the agent reported using
+	AgentInputTokens  int64 `json:"agent_input_tokens,omitempty"`
+	AgentOutputTokens int64 `json:"agent_output_tokens,omitempty"`
+	// Errors collects the errors of failed stages and the run itself
+	Errors []string `json:"errors,omitempty"`
+
+	mu sync.Mutex
+}
+
+// Stage records the outcome of one step of a run.
+type Stage struct {
+	// Name identifies the stage, e.g. "clone" or "agent"
+	Name string `json:"name"`
+	// Status is running, succeeded, or failed
+	Status string `json:"status"`
+	// StartedAt is when the stage began
+	StartedAt time.Time `json:"started_at"`
+	// DurationSeconds is how long the stage took, across all attempts
+	DurationSeconds float64 `json:"duration_seconds"`
+	// Retries counts the attempts after the first
+	Retries int `json:"retries"`
+	// Error is the failure message of a failed stage
+	Error string `json:"error,omitempty"`
+}
+
+// Gate records a check the agent's changes must pass before they are committed.
+type Gate struct {
+	// Name identifies the gate, e.g. "tests" or "lint"
+	Name string `json:"name"`
+	// Command is the shell command the gate runs
+	Command string `json:"command"`
+	// Passed reports whether the command succeeded
+	Passed bool `json:"passed"`
+	// Output is the end of the output of a failed command
+	Output string `json:"output,omitempty"`
+}
+
+// New starts the summary of a run.
+func New(runID, issueID, repo string) *Summary {
+	return &Summary{
+		RunID:     runID,
+		IssueID:   issueID,
+		Repo:      repo,
+		Status:    StatusRunning,
+		StartedAt: time.Now(),
+	}
+}
+
+// StartStage records the start of a stage and returns a function that ends it with the
+// outcome of err. Starting a stage that was already started counts as a retry of it.
+func (s *Summary) StartStage(name string) func(err error) {
+	s.mu.Lock()
+	index := s.stageIndex(name)
+	if index >= 0 {
+		s.Stages[index].Retries++
+		s.Stages[index].Status = StatusRunning
+		s.Stages[index].Error = ""
+	} else {
+		s.Stages = append(s.Stages, Stage{Name: name, Status: StatusRunning, StartedAt: time.Now()})
+		index = len(s.Stages) - 1
+	}
+	s.mu.Unlock()
+
+	return func(err error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		stage := &s.Stages[index]
+		stage.DurationSeconds = time.Since(stage.StartedAt).Seconds()
+		stage.Status = StatusSucceeded
+		if err != nil {
+			stage.Status = StatusFailed
+			stage.Error = err.Error()
+			s.Errors = append(s.Errors, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+}
+
+// stageIndex returns the index of the stage called name, or -1; the caller holds s.mu.
+func (s *Summary) stageIndex(name string) int {
+	for i, stage := range s.Stages {
+		if stage.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FailedStage returns the name of the stage the run stopped in: the last stage that failed or
+// is still running, or the empty string.
+func (s *Summary) FailedStage() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.Stages) - 1; i >= 0; i-- {
+		if s.Stages[i].Status != StatusSucceeded {
+			return s.Stages[i].Name
+		}
+	}
+	return ""
+}
+
+// RecordGate records the outcome of a run of a gate, replacing that of an earlier run of it.
+func (s *Summary) RecordGate(gate Gate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.Gates {
+		if s.Gates[i].Name == gate.Name {
+			s.Gates[i] = gate
+			return
+		}
+	}
+	s.Gates = append(s.Gates, gate)
+}
+
+// Finish records the overall outcome of the run.
+func (s *Summary) Finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.FinishedAt = time.Now()
+	s.DurationSeconds = s.FinishedAt.Sub(s.StartedAt).Seconds()
+	s.Status = StatusSucceeded
+	if err != nil {
+		s.Status = StatusFailed
+		if errors.Is(err, context.Canceled) {
+			s.Status = StatusCanceled
+		}
+		// A failed stage already recorded the error the run stopped on.
+		if len(s.Errors) == 0 {
+			s.Errors = append(s.Errors, err.Error())
+		}
+	}
+}
+
+// WriteFiles writes summary.json and summary.md into dir and returns the JSON path.
+func (s *Summary) WriteFiles(dir string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create summary directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode summary: %w", err)
+	}
+	jsonPath := filepath.Join(dir, "summary.json")
+	if err := os.WriteFile(jsonPath, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write summary: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "summary.md"), []byte(s.markdown()), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write summary: %w", err)
+	}
+	return jsonPath, nil
+}
+
+// JSON returns the summary as it is written to summary.json.
+func (s *Summary) JSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(s)
+}
+
+// Load reads a summary.json file.
+func Load(path string) (*Summary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary: %w", err)
+	}
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode summary: %w", err)
+	}
+	return &s, nil
+}
+
+// Markdown renders the summary for humans.
+func (s *Summary) Markdown() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.markdown()
+}
+
+// markdown renders the summary; the caller holds s.mu.
+func (s *Summary) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Monday run %s\n\n", s.RunID)
+
+	issue := s.IssueID
+	if s.IssueTitle != "" {
+		issue = fmt.Sprintf("%s: %s", s.IssueID, s.IssueTitle)
+	}
+	if s.IssueURL != "" {
+		issue = fmt.Sprintf("[%s](%s)", issue, s.IssueURL)
+	}
+	fmt.Fprintf(&b, "- **Issue:** %s\n", issue)
+	fmt.Fprintf(&b, "- **Repository:** %s\n", s.Repo)
+	if s.Branch != "" {
+		fmt.Fprintf(&b, "- **Branch:** `%s`\n", s.Branch)
+	}
+	if s.PRURL != "" {
+		fmt.Fprintf(&b, "- **Pull request:** %s\n", s.PRURL)
+	}
+	if s.ResumedFrom != "" {
+		fmt.Fprintf(&b, "- **Resumed from:** run %s\n", s.ResumedFrom)
+	}
+	if s.ReplayOf != "" {
+		fmt.Fprintf(&b, "- **Replay of:** run %s\n", s.ReplayOf)
+	}
+	fmt.Fprintf(&b, "- **Status:** %s\n", s.Status)
+	if s.DryRun {
+		b.WriteString("- **Dry run:** yes\n")
+	}
+	fmt.Fprintf(&b, "- **Duration:** %s\n", FormatSeconds(s.DurationSeconds))
+	if s.AgentCostUSD != nil {
+		fmt.Fprintf(&b, "- **Agent cost:** $%.2f\n", *s.AgentCostUSD)
+	}
+	if s.AgentInputTokens > 0 || s.AgentOutputTokens > 0 {
+		fmt.Fprintf(&b, "- **Agent tokens:** %d in, %d out\n", s.AgentInputTokens, s.AgentOutputTokens)
+	}
+
+	if len(s.Stages) > 0 {
+		b.WriteString("\n## Stages\n\n| Stage | Status | Duration | Retries |\n|-------|--------|----------|---------|\n")
+		for _, stage := range s.Stages {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d |\n", stage.Name, stage.Status, FormatSeconds(stage.DurationSeconds), stage.Retries)
+		}
+	}
+
+	if len(s.Gates) > 0 {
+		b.WriteString("\n## Gates\n\n| Gate | Command | Result |\n|------|---------|--------|\n")
+		for _, gate := range s.Gates {
+			result := "passed"
+			if !gate.Passed {
+				result = "failed"
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", gate.Name, gate.Command, result)
+		}
+		for _, gate := range s.Gates {
+			if gate.Output != "" {
+				fmt.Fprintf(&b, "\n### %s output\n\n```\n%s\n```\n", gate.Name, gate.Output)
+			}
+		}
+	}
+
+	if len(s.FilesChanged) > 0 {
+		b.WriteString("\n## Files changed\n\n")
+		for _, file := range s.FilesChanged {
+			fmt.Fprintf(&b, "- `%s`\n", file)
+		}
+	}
+
+	if len(s.Errors) > 0 {
+		b.WriteString("\n## Errors\n\n")
+		for _, e := range s.Errors {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+	}
+	return b.String()
+}
+
+// FormatSeconds renders a duration in seconds rounded to a readable precision.
+func FormatSeconds(seconds float64) string {
+	return (time.Duration(seconds * float64(time.Second))).Round(100 * time.Millisecond).String()
+}
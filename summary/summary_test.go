@@ -0,0 +1,111 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagesRecordOutcomes(t *testing.T) {
+	s := New("run-1", "DEL-163", "https://github.com/owner/repo.git")
+
+	s.StartStage("fetch_issue")(nil)
+	s.StartStage("agent")(errors.New("codex exited with status 1"))
+	s.Finish(errors.New("failed to run Codex: codex exited with status 1"))
+
+	require.Len(t, s.Stages, 2)
+	assert.Equal(t, StatusSucceeded, s.Stages[0].Status)
+	assert.Equal(t, StatusFailed, s.Stages[1].Status)
+	assert.Equal(t, "codex exited with status 1", s.Stages[1].Error)
+	assert.Equal(t, StatusFailed, s.Status)
+	assert.Equal(t, []string{"agent: codex exited with status 1"}, s.Errors)
+	assert.False(t, s.FinishedAt.IsZero())
+	assert.Equal(t, "agent", s.FailedStage())
+}
+
+func TestStartStageAgainCountsRetry(t *testing.T) {
+	s := New("run-1", "DEL-163", "repo")
+
+	s.StartStage("push")(errors.New("connection reset"))
+	s.StartStage("push")(nil)
+
+	require.Len(t, s.Stages, 1)
+	assert.Equal(t, 1, s.Stages[0].Retries)
+	assert.Equal(t, StatusSucceeded, s.Stages[0].Status)
+	assert.Empty(t, s.Stages[0].Error)
+	assert.Equal(t, []string{"push: connection reset"}, s.Errors)
+}
+
+func TestRecordGateReplacesEarlierRun(t *testing.T) {
+	s := New("run-1", "DEL-163", "repo")
+
+	s.RecordGate(Gate{Name: "lint", Command: "golangci-lint run", Passed: true})
+	s.RecordGate(Gate{Name: "tests", Command: "go test ./...", Output: "FAIL: TestLogin"})
+	s.RecordGate(Gate{Name: "tests", Command: "go test ./...", Passed: true})
+
+	require.Len(t, s.Gates, 2)
+	assert.Equal(t, Gate{Name: "tests", Command: "go test ./...", Passed: true}, s.Gates[1])
+	assert.Contains(t, s.Markdown(), "| tests | `go test ./...` | passed |\n")
+}
+
+func TestFinishRecordsCancellation(t *testing.T) {
+	s := New("run-1", "DEL-163", "repo")
+	s.StartStage("agent")(context.Canceled)
+	s.Finish(fmt.Errorf("run canceled: %w", context.Canceled))
+
+	assert.Equal(t, StatusCanceled, s.Status)
+	assert.Equal(t, "agent", s.FailedStage())
+}
+
+func TestFinishRecordsErrorsOutsideStages(t *testing.T) {
+	s := New("run-1", "DEL-163", "repo")
+	s.Finish(errors.New("LINEAR_API_KEY environment variable is required"))
+
+	assert.Equal(t, StatusFailed, s.Status)
+	assert.Equal(t, []string{"LINEAR_API_KEY environment variable is required"}, s.Errors)
+}
+
+func TestWriteFilesRoundTrip(t *testing.T) {
+	s := New("run-1", "DEL-163", "repo")
+	s.IssueTitle = "Fix login"
+	s.Branch = "feature/del_163"
+	s.PRURL = "https://github.com/owner/repo/pull/1"
+	s.FilesChanged = []string{"main.go"}
+	s.ResumedFrom = "run-0"
+	s.ReplayOf = "run-00"
+	s.DryRun = true
+	s.Phase = "committed"
+	s.StartStage("commit")(nil)
+	s.Finish(nil)
+
+	dir := t.TempDir()
+	path, err := s.WriteFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "summary.json"), path)
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, loaded.Status)
+	assert.Equal(t, s.PRURL, loaded.PRURL)
+	assert.Equal(t, []string{"main.go"}, loaded.FilesChanged)
+	assert.Equal(t, "run-0", loaded.ResumedFrom)
+	assert.True(t, loaded.DryRun)
+	assert.Equal(t, "committed", loaded.Phase)
+	require.Len(t, loaded.Stages, 1)
+	assert.Equal(t, "commit", loaded.Stages[0].Name)
+
+	markdown, err := os.ReadFile(filepath.Join(dir, "summary.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(markdown), "DEL-163: Fix login")
+	assert.Contains(t, string(markdown), "| commit | succeeded |")
+	assert.Contains(t, string(markdown), "`main.go`")
+	assert.Contains(t, string(markdown), "**Resumed from:** run run-0")
+	assert.Contains(t, string(markdown), "**Replay of:** run run-00")
+	assert.Contains(t, string(markdown), "**Dry run:** yes")
+}
@@ -0,0 +1,264 @@
+// Package terminal opens a terminal window or tab in a directory, so work on an issue can be
+// picked up interactively in its worktree. A Launcher exists for each supported terminal.
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"monday/command"
+	"monday/osascript"
+)
+
+// Launcher opens terminals.
+type Launcher interface {
+	// Name is the name the launcher is selected by, e.g. "iterm"
+	Name() string
+	// Open opens a terminal in dir whose window or tab is titled title, and runs command in it
+	// when it is not empty
+	Open(ctx context.Context, dir, title, command string) error
+}
+
+// Names lists the names of the launchers New accepts.
+var Names = []string{"terminal", "iterm", "tmux", "gnome-terminal", "konsole", "kitty", "alacritty", "windows-terminal"}
+
+// linuxTerminals are the Linux terminals Default looks for, in order.
+var linuxTerminals = []string{"gnome-terminal", "konsole", "kitty", "alacritty"}
+
+// New returns the launcher called name, or, for "", the default: tmux inside a tmux session,
+// Terminal.app or iTerm2 on macOS, the first installed terminal of linuxTerminals on Linux, and
+// Windows Terminal on Windows.
+func New(name string) (Launcher, error) {
+	switch name {
+	case "":
+		return Default()
+	case "terminal":
+		return &AppleTerminal{run: command.Run}, nil
+	case "iterm":
+		return &ITerm{run: command.Run}, nil
+	case "tmux":
+		return &Tmux{run: command.Run}, nil
+	case "gnome-terminal", "konsole", "kitty", "alacritty":
+		return &Linux{program: name, run: startCommand}, nil
+	case "windows-terminal":
+		return &WindowsTerminal{run: startCommand}, nil
+	default:
+		return nil, fmt.Errorf("unknown terminal %q: must be one of %s", name, strings.Join(Names, ", "))
+	}
+}
+
+// Default returns the launcher for the terminal monday runs in.
+func Default() (Launcher, error) {
+	return defaultFor(runtime.GOOS)
+}
+
+// defaultFor returns the launcher for the terminal monday runs in on the operating system goos.
+func defaultFor(goos string) (Launcher, error) {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return New("tmux")
+	case goos == "darwin" && os.Getenv("TERM_PROGRAM") == "iTerm.app":
+		return New("iterm")
+	case goos == "darwin":
+		return New("terminal")
+	case goos == "linux":
+		for _, name := range linuxTerminals {
+			if _, err := exec.LookPath(name); err == nil {
+				return New(name)
+			}
+		}
+		return nil, fmt.Errorf("none of %s is installed: install one or choose tmux", strings.Join(linuxTerminals, ", "))
+	case goos == "windows":
+		return New("windows-terminal")
+	default:
+		return nil, fmt.Errorf("no terminal to open on %s: choose one of %s", goos, strings.Join(Names, ", "))
+	}
+}
+
+// AppleTerminal opens windows of macOS Terminal.app.
+type AppleTerminal struct {
+	// run executes a command; it is replaced in tests
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// Name returns "terminal".
+func (*AppleTerminal) Name() string { return "terminal" }
+
+// Open opens a Terminal.app window in dir and runs command in its shell.
+func (t *AppleTerminal) Open(ctx context.Context, dir, title, command string) error {
+	script := fmt.Sprintf(`tell application "Terminal"
+	activate
+	set newTab to do script %s
+	set custom title of newTab to %s
+end tell`, osascript.Quote(cdCommand(dir, command)), osascript.Quote(title))
+	if err := t.run(ctx, "osascript", "-e", script); err != nil {
+		return fmt.Errorf("failed to open Terminal.app: %w", err)
+	}
+	return nil
+}
+
+// SplitDirections lists the directions ITerm.Split accepts.
+var SplitDirections = []string{"horizontal", "vertical"}
+
+// ITerm opens windows of iTerm2.
+type ITerm struct {
+	// Split, if set, splits the current session of the frontmost window "horizontal"ly or
+	// "vertical"ly instead of opening a window
+	Split string
+	// run executes a command; it is replaced in tests
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// Name returns "iterm".
+func (*ITerm) Name() string { return "iterm" }
+
+// Open opens an iTerm2 window, or pane with Split, in dir and runs command in its shell.
+func (t *ITerm) Open(ctx context.Context, dir, title, command string) error {
+	session := "set newSession to current session of (create window with default profile)"
+	if t.Split != "" {
+		session = fmt.Sprintf("tell current session of current window to set newSession to (split %sly with default profile)", t.Split)
+	}
+	script := fmt.Sprintf(`tell application "iTerm"
+	activate
+	%s
+	tell newSession
+		set name to %s
+		write text %s
+	end tell
+end tell`, session, osascript.Quote(title), osascript.Quote(cdCommand(dir, command)))
+	if err := t.run(ctx, "osascript", "-e", script); err != nil {
+		return fmt.Errorf("failed to open iTerm2: %w", err)
+	}
+	return nil
+}
+
+// Tmux opens windows of the tmux session monday runs in, or of the most recently used session
+// when it runs outside of tmux.
+type Tmux struct {
+	// run executes a command; it is replaced in tests
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// Name returns "tmux".
+func (*Tmux) Name() string { return "tmux" }
+
+// Open opens a tmux window in dir running command, followed by a shell.
+func (t *Tmux) Open(ctx context.Context, dir, title, command string) error {
+	args := []string{"new-window", "-c", dir, "-n", title}
+	if command != "" {
+		args = append(args, keepOpen(command))
+	}
+	if err := t.run(ctx, "tmux", args...); err != nil {
+		return fmt.Errorf("failed to open a tmux window: %w", err)
+	}
+	return nil
+}
+
+// Linux opens windows of a Linux terminal emulator: gnome-terminal, konsole, kitty,
+// or alacritty.
+type Linux struct {
+	// program is the terminal emulator
+	program string
+	// run starts a command without waiting for it; it is replaced in tests
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// Name returns the name of the terminal emulator.
+func (t *Linux) Name() string { return t.program }
+
+// Open opens a window of the terminal emulator in dir running command, followed by a shell. The
+// emulator keeps running after monday exits.
+func (t *Linux) Open(ctx context.Context, dir, title, command string) error {
+	var args []string
+	switch t.program {
+	case "gnome-terminal":
+		args = []string{"--window", "--working-directory=" + dir, "--title=" + title}
+		if command != "" {
+			args = append(args, "--")
+		}
+	case "konsole":
+		args = []string{"--new-tab", "--workdir", dir, "-p", "tabtitle=" + title}
+		if command != "" {
+			args = append(args, "-e")
+		}
+	case "kitty":
+		args = []string{"--directory", dir, "--title", title}
+	case "alacritty":
+		args = []string{"--working-directory", dir, "--title", title}
+		if command != "" {
+			args = append(args, "-e")
+		}
+	}
+	if command != "" {
+		args = append(args, "sh", "-c", keepOpen(command))
+	}
+	if err := t.run(ctx, t.program, args...); err != nil {
+		return fmt.Errorf("failed to open %s: %w", t.program, err)
+	}
+	return nil
+}
+
+// WindowsTerminal opens tabs of Windows Terminal.
+type WindowsTerminal struct {
+	// run starts a command without waiting for it; it is replaced in tests
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// Name returns "windows-terminal".
+func (*WindowsTerminal) Name() string { return "windows-terminal" }
+
+// Open opens a tab in dir in the most recently used Windows Terminal window, or in a new window
+// when none is open, and runs command in PowerShell there.
+func (t *WindowsTerminal) Open(ctx context.Context, dir, title, command string) error {
+	args := []string{"-w", "0", "new-tab", "--title", wtArgument(title), "-d", wtArgument(filepath.FromSlash(dir))}
+	if command != "" {
+		args = append(args, "powershell.exe", "-NoExit", "-Command", wtArgument(command))
+	}
+	if err := t.run(ctx, "wt.exe", args...); err != nil {
+		return fmt.Errorf("failed to open Windows Terminal: %w", err)
+	}
+	return nil
+}
+
+// wtArgument escapes the semicolons of s, which wt.exe otherwise takes as the start of another
+// command.
+func wtArgument(s string) string {
+	return strings.ReplaceAll(s, ";", `\;`)
+}
+
+// cdCommand is the shell command changing to dir and running command, if it is not empty.
+func cdCommand(dir, command string) string {
+	if command == "" {
+		return "cd " + shellQuote(dir)
+	}
+	return "cd " + shellQuote(dir) + " && " + command
+}
+
+// keepOpen is the shell command running command and then the user's shell, so the terminal stays
+// open after command exits.
+func keepOpen(command string) string {
+	return command + `; exec "${SHELL:-/bin/sh}"`
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// startCommand starts a command detached from monday, so it outlives it; ctx only bounds
+// starting it.
+func startCommand(ctx context.Context, name string, args ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
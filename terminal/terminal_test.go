@@ -0,0 +1,240 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the commands a launcher runs.
+type recorder struct {
+	commands [][]string
+	err      error
+}
+
+func (r *recorder) run(_ context.Context, name string, args ...string) error {
+	r.commands = append(r.commands, append([]string{name}, args...))
+	return r.err
+}
+
+func TestLaunchers(t *testing.T) {
+	dir := "/home/ada/.monday/worktrees/app/DEL-1 (o'brien)"
+	tests := []struct {
+		name     string
+		launcher func(*recorder) Launcher
+		check    func(t *testing.T, command []string)
+	}{
+		{
+			name:     "terminal",
+			launcher: func(r *recorder) Launcher { return &AppleTerminal{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				require.Equal(t, []string{"osascript", "-e"}, command[:2])
+				assert.Contains(t, command[2], `tell application "Terminal"`)
+				assert.Contains(t, command[2], `do script "cd '/home/ada/.monday/worktrees/app/DEL-1 (o'\\''brien)'"`)
+				assert.Contains(t, command[2], `set custom title of newTab to "DEL-1: Fix \"login\""`)
+			},
+		},
+		{
+			name:     "iterm",
+			launcher: func(r *recorder) Launcher { return &ITerm{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				require.Equal(t, []string{"osascript", "-e"}, command[:2])
+				assert.Contains(t, command[2], `tell application "iTerm"`)
+				assert.Contains(t, command[2], `write text "cd '/home/ada/.monday/worktrees/app/DEL-1 (o'\\''brien)'"`)
+				assert.Contains(t, command[2], `set name to "DEL-1: Fix \"login\""`)
+			},
+		},
+		{
+			name:     "tmux",
+			launcher: func(r *recorder) Launcher { return &Tmux{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"tmux", "new-window", "-c", dir, "-n", `DEL-1: Fix "login"`}, command)
+			},
+		},
+		{
+			name:     "gnome-terminal",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "gnome-terminal", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"gnome-terminal", "--window", "--working-directory=" + dir, `--title=DEL-1: Fix "login"`}, command)
+			},
+		},
+		{
+			name:     "konsole",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "konsole", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"konsole", "--new-tab", "--workdir", dir, "-p", `tabtitle=DEL-1: Fix "login"`}, command)
+			},
+		},
+		{
+			name:     "kitty",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "kitty", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"kitty", "--directory", dir, "--title", `DEL-1: Fix "login"`}, command)
+			},
+		},
+		{
+			name:     "alacritty",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "alacritty", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"alacritty", "--working-directory", dir, "--title", `DEL-1: Fix "login"`}, command)
+			},
+		},
+		{
+			name:     "windows-terminal",
+			launcher: func(r *recorder) Launcher { return &WindowsTerminal{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"wt.exe", "-w", "0", "new-tab", "--title", `DEL-1: Fix "login"`, "-d", filepath.FromSlash(dir)}, command)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &recorder{}
+			launcher := test.launcher(r)
+			assert.Equal(t, test.name, launcher.Name())
+
+			require.NoError(t, launcher.Open(context.Background(), dir, `DEL-1: Fix "login"`, ""))
+			require.Len(t, r.commands, 1)
+			test.check(t, r.commands[0])
+
+			r.err = errors.New("exit status 1")
+			assert.Error(t, launcher.Open(context.Background(), dir, "DEL-1", ""))
+		})
+	}
+}
+
+func TestITermSplit(t *testing.T) {
+	r := &recorder{}
+	require.NoError(t, (&ITerm{Split: "vertical", run: r.run}).Open(context.Background(), "/home/ada/app", "DEL-1", ""))
+	require.Len(t, r.commands, 1)
+	assert.Contains(t, r.commands[0][2], "tell current session of current window to set newSession to (split vertically with default profile)")
+	assert.NotContains(t, r.commands[0][2], "create window")
+	assert.Contains(t, r.commands[0][2], `write text "cd '/home/ada/app'"`)
+}
+
+func TestLaunchersRunCommand(t *testing.T) {
+	dir := "/home/ada/.monday/worktrees/app/DEL-1"
+	shell := []string{"sh", "-c", `claude --continue; exec "${SHELL:-/bin/sh}"`}
+	tests := []struct {
+		name     string
+		launcher func(*recorder) Launcher
+		check    func(t *testing.T, command []string)
+	}{
+		{
+			name:     "terminal",
+			launcher: func(r *recorder) Launcher { return &AppleTerminal{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Contains(t, command[2], `do script "cd '/home/ada/.monday/worktrees/app/DEL-1' && claude --continue"`)
+			},
+		},
+		{
+			name:     "iterm",
+			launcher: func(r *recorder) Launcher { return &ITerm{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Contains(t, command[2], `write text "cd '/home/ada/.monday/worktrees/app/DEL-1' && claude --continue"`)
+			},
+		},
+		{
+			name:     "tmux",
+			launcher: func(r *recorder) Launcher { return &Tmux{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"tmux", "new-window", "-c", dir, "-n", "DEL-1", shell[2]}, command)
+			},
+		},
+		{
+			name:     "gnome-terminal",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "gnome-terminal", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, append([]string{"gnome-terminal", "--window", "--working-directory=" + dir, "--title=DEL-1", "--"}, shell...), command)
+			},
+		},
+		{
+			name:     "konsole",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "konsole", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, append([]string{"konsole", "--new-tab", "--workdir", dir, "-p", "tabtitle=DEL-1", "-e"}, shell...), command)
+			},
+		},
+		{
+			name:     "kitty",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "kitty", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, append([]string{"kitty", "--directory", dir, "--title", "DEL-1"}, shell...), command)
+			},
+		},
+		{
+			name:     "alacritty",
+			launcher: func(r *recorder) Launcher { return &Linux{program: "alacritty", run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, append([]string{"alacritty", "--working-directory", dir, "--title", "DEL-1", "-e"}, shell...), command)
+			},
+		},
+		{
+			name:     "windows-terminal",
+			launcher: func(r *recorder) Launcher { return &WindowsTerminal{run: r.run} },
+			check: func(t *testing.T, command []string) {
+				assert.Equal(t, []string{"wt.exe", "-w", "0", "new-tab", "--title", "DEL-1", "-d", filepath.FromSlash(dir), "powershell.exe", "-NoExit", "-Command", "claude --continue"}, command)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &recorder{}
+			require.NoError(t, test.launcher(r).Open(context.Background(), dir, "DEL-1", "claude --continue"))
+			require.Len(t, r.commands, 1)
+			test.check(t, r.commands[0])
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range Names {
+		launcher, err := New(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, launcher.Name())
+	}
+
+	_, err := New("hyper")
+	assert.ErrorContains(t, err, "must be one of terminal, iterm, tmux, gnome-terminal, konsole, kitty, alacritty, windows-terminal")
+}
+
+func TestDefaultFor(t *testing.T) {
+	t.Setenv("TMUX", "")
+
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	launcher, err := defaultFor("darwin")
+	require.NoError(t, err)
+	assert.Equal(t, "iterm", launcher.Name())
+
+	t.Setenv("TERM_PROGRAM", "Apple_Terminal")
+	launcher, err = defaultFor("darwin")
+	require.NoError(t, err)
+	assert.Equal(t, "terminal", launcher.Name())
+
+	launcher, err = defaultFor("windows")
+	require.NoError(t, err)
+	assert.Equal(t, "windows-terminal", launcher.Name())
+
+	_, err = defaultFor("plan9")
+	assert.ErrorContains(t, err, "no terminal to open on plan9")
+}
+
+func TestDefaultInTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+
+	launcher, err := Default()
+	require.NoError(t, err)
+	assert.Equal(t, "tmux", launcher.Name())
+}
+
+func TestWindowsTerminalEscapesSemicolons(t *testing.T) {
+	r := &recorder{}
+	require.NoError(t, (&WindowsTerminal{run: r.run}).Open(context.Background(), "C:/worktrees/a;b", "DEL-1; DEL-2", ""))
+	assert.Equal(t, []string{"wt.exe", "-w", "0", "new-tab", "--title", `DEL-1\; DEL-2`, "-d", filepath.FromSlash(`C:/worktrees/a\;b`)}, r.commands[0])
+}
@@ -0,0 +1,15 @@
+package vcs
+
+import "strings"
+
+// GitHubRepo returns the repository at repoURL, given as an HTTPS or SSH
+// (git@github.com:owner/repo.git) URL, in the HOST/OWNER/REPO form the gh CLI takes, or "" if
+// repoURL is not a repository URL.
+func GitHubRepo(repoURL string) string {
+	host, repoPath := splitRepoURL(repoURL)
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || strings.Count(repoPath, "/") != 1 {
+		return ""
+	}
+	return host + "/" + repoPath
+}
@@ -0,0 +1,202 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin is how long before it expires an installation token is replaced.
+const tokenRefreshMargin = 5 * time.Minute
+
+// GitHubApp mints installation access tokens of a GitHub App, with which pushes and pull
+// requests appear under the app's bot identity instead of that of a person.
+type GitHubApp struct {
+	appID          string
+	key            *rsa.PrivateKey
+	installationID int64
+	apiURL         string
+	httpClient     *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]installationToken
+}
+
+// installationToken is an access token of an installation of the app and when it expires.
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewGitHubApp returns the app with the given ID, authenticating with privateKey, the PEM
+// encoded RSA key generated for it. installationID selects the installation to mint tokens
+// of; if it is 0, the installation on each repository is looked up.
+func NewGitHubApp(appID string, privateKey []byte, installationID int64) (*GitHubApp, error) {
+	if _, err := strconv.ParseInt(appID, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid GitHub App ID %q", appID)
+	}
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: %w", err)
+	}
+	return &GitHubApp{
+		appID:          appID,
+		key:            key,
+		installationID: installationID,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		tokens:         map[string]installationToken{},
+	}, nil
+}
+
+// parsePrivateKey decodes a PEM encoded RSA private key in PKCS #1 form, as GitHub generates
+// them, or in PKCS #8 form.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// GitHubAPIURL returns the base URL of the REST API of the GitHub instance at host: that of
+// GitHub.com, or the /api/v3 path of a GitHub Enterprise Server.
+func GitHubAPIURL(host string) string {
+	if host == "" || strings.EqualFold(host, "github.com") {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// Token returns an installation token that may push to and open pull requests on repo, in
+// the HOST/OWNER/REPO form of GitHubRepo. Tokens are scoped to the repository and reused until
+// shortly before they expire, an hour after they are minted.
+func (a *GitHubApp) Token(ctx context.Context, repo string) (string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid GitHub repository %q", repo)
+	}
+	host, owner, name := parts[0], parts[1], parts[2]
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if cached, ok := a.tokens[repo]; ok && time.Now().Add(tokenRefreshMargin).Before(cached.ExpiresAt) {
+		return cached.Token, nil
+	}
+
+	apiURL := a.apiURL
+	if apiURL == "" {
+		apiURL = GitHubAPIURL(host)
+	}
+	id := a.installationID
+	if id == 0 {
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		if err := a.do(ctx, http.MethodGet, apiURL+"/repos/"+owner+"/"+name+"/installation", nil, &installation); err != nil {
+			return "", fmt.Errorf("failed to find the installation of the GitHub App on %s/%s: %w", owner, name, err)
+		}
+		id = installation.ID
+	}
+
+	var token installationToken
+	body := map[string][]string{"repositories": {name}}
+	if err := a.do(ctx, http.MethodPost, fmt.Sprintf("%s/app/installations/%d/access_tokens", apiURL, id), body, &token); err != nil {
+		return "", fmt.Errorf("failed to create an installation token of the GitHub App: %w", err)
+	}
+	if token.Token == "" {
+		return "", errors.New("GitHub returned no installation token")
+	}
+	a.tokens[repo] = token
+	return token.Token, nil
+}
+
+// jwt returns the JSON Web Token that authenticates the app itself at now, valid for nine
+// minutes; GitHub accepts at most ten, and the issue time is backdated for clock drift.
+func (a *GitHubApp) jwt(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// do sends a request authenticated as the app to url with body, if not nil, encoded as JSON,
+// and decodes the JSON response into out.
+func (a *GitHubApp) do(ctx context.Context, method, url string, body, out any) error {
+	jwt, err := a.jwt(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign GitHub App token: %w", err)
+	}
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(data, out)
+}
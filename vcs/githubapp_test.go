@@ -0,0 +1,104 @@
+package vcs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAppKey returns a new RSA key and its PEM encoding in PKCS #1 form.
+func testAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestGitHubAppToken(t *testing.T) {
+	key, keyPEM := testAppKey(t)
+	var minted atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(claims), `"iss":"12345"`)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/app/installation":
+			w.Write([]byte(`{"id": 42}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/42/access_tokens":
+			var body map[string][]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []string{"app"}, body["repositories"])
+			minted.Add(1)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token": "ghs_installation", "expires_at": "` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	app, err := NewGitHubApp("12345", keyPEM, 0)
+	require.NoError(t, err)
+	app.apiURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		token, err := app.Token(context.Background(), "github.com/acme/app")
+		require.NoError(t, err)
+		assert.Equal(t, "ghs_installation", token)
+	}
+	assert.Equal(t, int32(1), minted.Load(), "the token is reused until it is about to expire")
+}
+
+func TestGitHubAppTokenError(t *testing.T) {
+	_, keyPEM := testAppKey(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	app, err := NewGitHubApp("12345", keyPEM, 7)
+	require.NoError(t, err)
+	app.apiURL = server.URL
+
+	_, err = app.Token(context.Background(), "github.com/acme/app")
+	assert.ErrorContains(t, err, "status 404: Not Found")
+}
+
+func TestNewGitHubAppRejectsInvalidConfig(t *testing.T) {
+	_, keyPEM := testAppKey(t)
+	_, err := NewGitHubApp("my-app", keyPEM, 0)
+	assert.ErrorContains(t, err, "invalid GitHub App ID")
+
+	_, err = NewGitHubApp("12345", []byte("not a key"), 0)
+	assert.ErrorContains(t, err, "invalid GitHub App private key")
+}
+
+func TestGitHubAPIURL(t *testing.T) {
+	assert.Equal(t, "https://api.github.com", GitHubAPIURL("github.com"))
+	assert.Equal(t, "https://github.acme.dev/api/v3", GitHubAPIURL("github.acme.dev"))
+}
@@ -0,0 +1,323 @@
+// Package vcs talks to the services hosting the repositories runs work on, beyond what git
+// itself does: opening, finding, and closing merge requests on GitLab, minting the installation
+// tokens of GitHub Apps, and authenticating git over HTTPS with an access token.
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultGitLabURL is the base URL of GitLab.com.
+const DefaultGitLabURL = "https://gitlab.com"
+
+// ChangeRequest describes a pull or merge request to open.
+type ChangeRequest struct {
+	// Title is the title of the request
+	Title string
+	// Body is the description of the request
+	Body string
+	// SourceBranch is the branch with the changes
+	SourceBranch string
+	// TargetBranch is the branch to merge into; empty for the repository's default branch
+	TargetBranch string
+	// Draft opens the request as a draft
+	Draft bool
+	// Labels are added to the request
+	Labels []string
+	// Reviewers are the usernames of the people asked to review the request
+	Reviewers []string
+	// Assignees are the usernames of the people the request is assigned to
+	Assignees []string
+	// Milestone is the title of the milestone the request is added to
+	Milestone string
+}
+
+// GitLab is a client of the REST API of a GitLab instance for one project.
+type GitLab struct {
+	baseURL    string
+	project    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitLab returns a client for the project with the given path, e.g. "group/app", on the
+// GitLab instance at baseURL, authenticating with token.
+func NewGitLab(baseURL, project, token string) *GitLab {
+	return &GitLab{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		project:    project,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// IsGitLab reports whether repoURL is hosted on GitLab.com or on the GitLab instance at
+// instanceURL, which may be empty.
+func IsGitLab(repoURL, instanceURL string) bool {
+	host := repoHost(repoURL)
+	if host == "" {
+		return false
+	}
+	if host == "gitlab.com" {
+		return true
+	}
+	instance, err := url.Parse(instanceURL)
+	return err == nil && instance.Host != "" && strings.EqualFold(host, instance.Hostname())
+}
+
+// ParseGitLabRepo returns the base URL of the instance and the project path of the GitLab
+// repository at repoURL, given as an HTTPS or SSH (git@host:group/app.git) URL.
+func ParseGitLabRepo(repoURL string) (baseURL, project string, err error) {
+	host, repoPath := splitRepoURL(repoURL)
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || !strings.Contains(repoPath, "/") {
+		return "", "", fmt.Errorf("invalid GitLab repository URL %q", repoURL)
+	}
+	return "https://" + host, repoPath, nil
+}
+
+// repoHost returns the host of repoURL, or "" if it is not a URL.
+func repoHost(repoURL string) string {
+	host, _ := splitRepoURL(repoURL)
+	return strings.ToLower(host)
+}
+
+// splitRepoURL splits an HTTPS or SSH repository URL into its host and path.
+func splitRepoURL(repoURL string) (host, repoPath string) {
+	if u, err := url.Parse(repoURL); err == nil && u.Host != "" {
+		return u.Hostname(), u.Path
+	}
+	// scp-like SSH syntax: [user@]host:path
+	if at := strings.Index(repoURL, "@"); at >= 0 {
+		repoURL = repoURL[at+1:]
+	}
+	host, repoPath, ok := strings.Cut(repoURL, ":")
+	if !ok || strings.Contains(host, "/") {
+		return "", ""
+	}
+	return host, repoPath
+}
+
+// GitAuthConfig returns the git configuration key and value that make git authenticate to the
+// GitLab instance at baseURL over HTTPS with token.
+func GitAuthConfig(baseURL, token string) (key, value string) {
+	credentials := base64.StdEncoding.EncodeToString([]byte("oauth2:" + token))
+	return "http." + strings.TrimSuffix(baseURL, "/") + "/.extraHeader", "Authorization: Basic " + credentials
+}
+
+// CreateMergeRequest opens a merge request for req and returns its URL.
+func (g *GitLab) CreateMergeRequest(ctx context.Context, req ChangeRequest) (string, error) {
+	target := req.TargetBranch
+	if target == "" {
+		var project struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do(ctx, http.MethodGet, "", nil, &project); err != nil {
+			return "", fmt.Errorf("failed to look up default branch: %w", err)
+		}
+		target = project.DefaultBranch
+	}
+	title := req.Title
+	if req.Draft {
+		title = "Draft: " + title
+	}
+
+	body := map[string]any{
+		"source_branch": req.SourceBranch,
+		"target_branch": target,
+		"title":         title,
+		"description":   req.Body,
+	}
+	if len(req.Labels) > 0 {
+		body["labels"] = strings.Join(req.Labels, ",")
+	}
+	if len(req.Reviewers) > 0 {
+		ids, err := g.memberIDs(ctx, req.Reviewers)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up reviewers: %w", err)
+		}
+		body["reviewer_ids"] = ids
+	}
+	if len(req.Assignees) > 0 {
+		ids, err := g.memberIDs(ctx, req.Assignees)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up assignees: %w", err)
+		}
+		body["assignee_ids"] = ids
+	}
+	if req.Milestone != "" {
+		id, err := g.milestoneID(ctx, req.Milestone)
+		if err != nil {
+			return "", err
+		}
+		body["milestone_id"] = id
+	}
+	var mr struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/merge_requests", body, &mr); err != nil {
+		return "", fmt.Errorf("failed to create merge request: %w", err)
+	}
+	return mr.WebURL, nil
+}
+
+// memberIDs returns the user IDs of the members of the project with the given usernames.
+func (g *GitLab) memberIDs(ctx context.Context, usernames []string) ([]int, error) {
+	ids := make([]int, 0, len(usernames))
+	for _, username := range usernames {
+		username = strings.TrimPrefix(username, "@")
+		var members []struct {
+			ID       int    `json:"id"`
+			Username string `json:"username"`
+		}
+		if err := g.do(ctx, http.MethodGet, "/members/all?"+url.Values{"query": {username}}.Encode(), nil, &members); err != nil {
+			return nil, err
+		}
+		found := false
+		for _, m := range members {
+			if strings.EqualFold(m.Username, username) {
+				ids = append(ids, m.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s is not a member of %s", username, g.project)
+		}
+	}
+	return ids, nil
+}
+
+// milestoneID returns the ID of the active milestone of the project with the given title.
+func (g *GitLab) milestoneID(ctx context.Context, title string) (int, error) {
+	var milestones []struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	query := url.Values{"title": {title}, "state": {"active"}}
+	if err := g.do(ctx, http.MethodGet, "/milestones?"+query.Encode(), nil, &milestones); err != nil {
+		return 0, fmt.Errorf("failed to look up milestone: %w", err)
+	}
+	if len(milestones) == 0 {
+		return 0, fmt.Errorf("no active milestone %q in %s", title, g.project)
+	}
+	return milestones[0].ID, nil
+}
+
+// OpenMergeRequest returns the URL of the open merge request from branch, or "" if there is none.
+func (g *GitLab) OpenMergeRequest(ctx context.Context, branch string) (string, error) {
+	query := url.Values{"state": {"opened"}, "source_branch": {branch}}
+	var mrs []struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := g.do(ctx, http.MethodGet, "/merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+		return "", fmt.Errorf("failed to list merge requests: %w", err)
+	}
+	if len(mrs) == 0 {
+		return "", nil
+	}
+	return mrs[0].WebURL, nil
+}
+
+// CloseMergeRequest closes the merge request at mrURL after commenting on it.
+func (g *GitLab) CloseMergeRequest(ctx context.Context, mrURL, comment string) error {
+	endpoint, err := mergeRequestEndpoint(mrURL)
+	if err != nil {
+		return err
+	}
+	if comment != "" {
+		if err := g.do(ctx, http.MethodPost, endpoint+"/notes", map[string]string{"body": comment}, nil); err != nil {
+			return fmt.Errorf("failed to comment on merge request: %w", err)
+		}
+	}
+	if err := g.do(ctx, http.MethodPut, endpoint, map[string]string{"state_event": "close"}, nil); err != nil {
+		return fmt.Errorf("failed to close merge request: %w", err)
+	}
+	return nil
+}
+
+// AppendToMergeRequest appends text to the description of the merge request at mrURL.
+func (g *GitLab) AppendToMergeRequest(ctx context.Context, mrURL, text string) error {
+	endpoint, err := mergeRequestEndpoint(mrURL)
+	if err != nil {
+		return err
+	}
+	var mr struct {
+		Description string `json:"description"`
+	}
+	if err := g.do(ctx, http.MethodGet, endpoint, nil, &mr); err != nil {
+		return fmt.Errorf("failed to get merge request: %w", err)
+	}
+	if err := g.do(ctx, http.MethodPut, endpoint, map[string]string{"description": mr.Description + text}, nil); err != nil {
+		return fmt.Errorf("failed to update merge request: %w", err)
+	}
+	return nil
+}
+
+// mergeRequestEndpoint returns the API endpoint, relative to the project, of the merge request
+// at mrURL.
+func mergeRequestEndpoint(mrURL string) (string, error) {
+	iid, err := strconv.Atoi(path.Base(mrURL))
+	if err != nil || !strings.Contains(mrURL, "/merge_requests/") {
+		return "", fmt.Errorf("invalid merge request URL %q", mrURL)
+	}
+	return fmt.Sprintf("/merge_requests/%d", iid), nil
+}
+
+// MergeRequestsURL returns the API endpoint merge requests are created at.
+func (g *GitLab) MergeRequestsURL() string {
+	return g.projectURL() + "/merge_requests"
+}
+
+func (g *GitLab) projectURL() string {
+	return g.baseURL + "/api/v4/projects/" + url.PathEscape(g.project)
+}
+
+// do sends a request with the JSON encoding of body, if any, to endpoint of the project and
+// decodes the JSON response into out, if not nil.
+func (g *GitLab) do(ctx context.Context, method, endpoint string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.projectURL()+endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GitLab API returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,186 @@
+package vcs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGitLab(t *testing.T) {
+	tests := []struct {
+		repoURL  string
+		instance string
+		want     bool
+	}{
+		{repoURL: "https://gitlab.com/group/app.git", want: true},
+		{repoURL: "git@gitlab.com:group/app.git", want: true},
+		{repoURL: "https://git.acme.io/group/app", instance: "https://git.acme.io", want: true},
+		{repoURL: "https://github.com/owner/repo"},
+		{repoURL: "https://git.acme.io/group/app"},
+		{repoURL: "/home/dev/app"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, IsGitLab(test.repoURL, test.instance), test.repoURL)
+	}
+}
+
+func TestParseGitLabRepo(t *testing.T) {
+	tests := []struct {
+		repoURL     string
+		wantBase    string
+		wantProject string
+		wantErr     bool
+	}{
+		{repoURL: "https://gitlab.com/group/sub/app.git", wantBase: "https://gitlab.com", wantProject: "group/sub/app"},
+		{repoURL: "git@git.acme.io:group/app.git", wantBase: "https://git.acme.io", wantProject: "group/app"},
+		{repoURL: "https://gitlab.com/app", wantErr: true},
+	}
+	for _, test := range tests {
+		base, project, err := ParseGitLabRepo(test.repoURL)
+		if test.wantErr {
+			assert.Error(t, err, test.repoURL)
+			continue
+		}
+		require.NoError(t, err, test.repoURL)
+		assert.Equal(t, test.wantBase, base)
+		assert.Equal(t, test.wantProject, project)
+	}
+}
+
+func TestGitAuthConfig(t *testing.T) {
+	key, value := GitAuthConfig("https://gitlab.com/", "glpat-123")
+
+	assert.Equal(t, "http.https://gitlab.com/.extraHeader", key)
+	assert.Equal(t, "Authorization: Basic "+base64.StdEncoding.EncodeToString([]byte("oauth2:glpat-123")), value)
+}
+
+func TestCreateMergeRequest(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "glpat-123", r.Header.Get("PRIVATE-TOKEN"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/api/v4/projects/group%2Fapp":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v4/projects/group%2Fapp/merge_requests":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"iid": 7, "web_url": "https://gitlab.com/group/app/-/merge_requests/7"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGitLab(server.URL, "group/app", "glpat-123")
+	mrURL, err := client.CreateMergeRequest(context.Background(), ChangeRequest{Title: "feat: Fix login", Body: "Details", SourceBranch: "del-1", Draft: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/group/app/-/merge_requests/7", mrURL)
+	assert.Equal(t, map[string]string{"source_branch": "del-1", "target_branch": "main", "title": "Draft: feat: Fix login", "description": "Details"}, got)
+}
+
+func TestCreateMergeRequestWithMetadata(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/group%2Fapp") {
+		case "/members/all":
+			switch r.URL.Query().Get("query") {
+			case "alice":
+				w.Write([]byte(`[{"id": 11, "username": "alice"}, {"id": 12, "username": "alice2"}]`))
+			case "bob":
+				w.Write([]byte(`[{"id": 21, "username": "bob"}]`))
+			default:
+				w.Write([]byte(`[]`))
+			}
+		case "/milestones":
+			assert.Equal(t, "v2.4", r.URL.Query().Get("title"))
+			w.Write([]byte(`[{"id": 5, "title": "v2.4"}]`))
+		case "/merge_requests":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"web_url": "https://gitlab.com/group/app/-/merge_requests/7"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := NewGitLab(server.URL, "group/app", "token")
+
+	cr := ChangeRequest{Title: "feat: Fix login", SourceBranch: "del-1", TargetBranch: "main", Labels: []string{"bug", "monday"}, Reviewers: []string{"@alice"}, Assignees: []string{"bob"}, Milestone: "v2.4"}
+	_, err := client.CreateMergeRequest(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, "bug,monday", got["labels"])
+	assert.Equal(t, []any{float64(11)}, got["reviewer_ids"])
+	assert.Equal(t, []any{float64(21)}, got["assignee_ids"])
+	assert.Equal(t, float64(5), got["milestone_id"])
+
+	cr.Reviewers = []string{"carol"}
+	_, err = client.CreateMergeRequest(context.Background(), cr)
+	assert.ErrorContains(t, err, "carol is not a member of group/app")
+}
+
+func TestOpenAndCloseMergeRequest(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/group%2Fapp"))
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "del-1", r.URL.Query().Get("source_branch"))
+			w.Write([]byte(`[{"web_url": "https://gitlab.com/group/app/-/merge_requests/7"}]`))
+		case http.MethodPut:
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := NewGitLab(server.URL, "group/app", "token")
+
+	mrURL, err := client.OpenMergeRequest(context.Background(), "del-1")
+	require.NoError(t, err)
+	require.NoError(t, client.CloseMergeRequest(context.Background(), mrURL, "Closed by monday"))
+
+	assert.Equal(t, []string{"GET /merge_requests", "POST /merge_requests/7/notes", "PUT /merge_requests/7"}, requests)
+	assert.Error(t, client.CloseMergeRequest(context.Background(), "https://gitlab.com/group/app", ""))
+}
+
+func TestAppendToMergeRequest(t *testing.T) {
+	var updated map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fapp/merge_requests/7", r.URL.EscapedPath())
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"description": "Fixes DEL-1"}`))
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := NewGitLab(server.URL, "group/app", "token")
+
+	require.NoError(t, client.AppendToMergeRequest(context.Background(), "https://gitlab.com/group/app/-/merge_requests/7", "\n\nRelated: x"))
+	assert.Equal(t, "Fixes DEL-1\n\nRelated: x", updated["description"])
+	assert.Error(t, client.AppendToMergeRequest(context.Background(), "https://gitlab.com/group/app", "x"))
+}
+
+func TestGitLabAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "401 Unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewGitLab(server.URL, "group/app", "bad").OpenMergeRequest(context.Background(), "del-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401 Unauthorized")
+}
@@ -0,0 +1,49 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"monday/issues"
+)
+
+// ProviderFunc returns a client of an issue tracker, typically authenticated from the
+// environment.
+type ProviderFunc func() (issues.Provider, error)
+
+var providers = map[string]ProviderFunc{}
+
+// RegisterProvider makes the issue tracker called name available to runs, which select it
+// with Options.Provider or MONDAY_ISSUE_PROVIDER. It panics if name is already registered.
+func RegisterProvider(name string, fn ProviderFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := providers[name]; ok {
+		panic(fmt.Sprintf("workflow: issue provider %q registered twice", name))
+	}
+	providers[name] = fn
+}
+
+// Providers returns the names of the registered issue trackers, sorted.
+func Providers() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProvider returns a client of the registered issue tracker called name.
+func NewProvider(name string) (issues.Provider, error) {
+	mu.Lock()
+	fn, ok := providers[name]
+	mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("invalid issue provider %q: must be %s", name, strings.Join(Providers(), " or "))
+	}
+	return fn()
+}
@@ -0,0 +1,37 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/issues"
+)
+
+type fakeTracker struct{ issues.Provider }
+
+func (fakeTracker) FetchIssueDetails(context.Context, string) (*issues.Issue, error) {
+	return &issues.Issue{Identifier: "GH-1"}, nil
+}
+
+func TestProviders(t *testing.T) {
+	saved := providers
+	t.Cleanup(func() { providers = saved })
+	providers = map[string]ProviderFunc{}
+
+	RegisterProvider("github", func() (issues.Provider, error) { return fakeTracker{}, nil })
+	RegisterProvider("linear", func() (issues.Provider, error) { return fakeTracker{}, nil })
+	assert.Panics(t, func() { RegisterProvider("github", nil) })
+	assert.Equal(t, []string{"github", "linear"}, Providers())
+
+	provider, err := NewProvider("github")
+	require.NoError(t, err)
+	issue, err := provider.FetchIssueDetails(context.Background(), "GH-1")
+	require.NoError(t, err)
+	assert.Equal(t, "GH-1", issue.Identifier)
+
+	_, err = NewProvider("trello")
+	assert.EqualError(t, err, `invalid issue provider "trello": must be github or linear`)
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+
+	"monday/summary"
+)
+
+// Options configure a run. IssueID and one of RepoURL and LocalRepo are required.
+type Options struct {
+	// RunID identifies the run; empty gets a new one
+	RunID string
+	// IssueID is the identifier or URL of the issue the run works on
+	IssueID string
+	// RepoURL is the repository the run clones and opens the pull request in
+	RepoURL string
+	// LocalRepo is a local clone to work in a worktree of instead of cloning RepoURL
+	LocalRepo string
+	// Provider names the issue tracker the issue comes from, one registered with
+	// RegisterProvider; empty selects $MONDAY_ISSUE_PROVIDER or linear
+	Provider string
+	// DryRun stops the run once the issue is fetched, printing what it would do
+	DryRun bool
+	// Rollback removes the worktree or clone and the branches the run created when it fails
+	Rollback bool
+	// Containerized runs the clone, agent, gates, commit, and push in a container
+	Containerized bool
+	// WorktreeMode works in a worktree of a bare clone cached per repository
+	WorktreeMode bool
+	// Continue is the ID of a failed run to pick up after the last phase it completed
+	Continue string
+	// Replay is the ID of a run to replay with the issue and prompt it recorded
+	Replay string
+	// Publish pushes the branch of a replay and opens its pull request
+	Publish bool
+	// Log receives the run's log entries; nil discards them
+	Log *zap.Logger
+}
+
+// SkipRest is returned by a stage to end the run successfully without the stages after it,
+// as dry runs do once they printed their plan.
+var SkipRest = errors.New("skip the rest of the run")
+
+// Ender is implemented by stages that have to do something once the run they ran in ends,
+// such as stop what they started or undo what they changed when the run failed.
+type Ender interface {
+	// End is called with the error the run ends with, or nil, after every stage
+	End(ctx context.Context, run *Run, err error)
+}
+
+// Observer sets up what runs have around their stages, such as their log and timeouts, and
+// learns how they end.
+type Observer interface {
+	// Start is called before the first stage, which runs with the returned context. A run
+	// whose Start fails ends without running its stages.
+	Start(ctx context.Context, run *Run) (context.Context, error)
+	// End is called once the run ended with err, also when Start failed, and returns the
+	// error the run ends with.
+	End(run *Run, err error) error
+}
+
+// PanicError is the error of a run whose stage panicked.
+type PanicError struct {
+	// Value is the value the stage panicked with
+	Value any
+	// Stack is the stack trace of the panic
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("workflow panicked: %v", e.Value)
+}
+
+var observers []Observer
+
+// RegisterObserver adds o to the observers of every run. Runs start observers in the order
+// they were registered and end them in the reverse order.
+func RegisterObserver(o Observer) {
+	mu.Lock()
+	defer mu.Unlock()
+	observers = append(observers, o)
+}
+
+// Workflow is a run of the stages of Stages with Options.
+type Workflow struct {
+	Options   Options
+	Stages    []Stage
+	Observers []Observer
+}
+
+// New returns the workflow of a run with opts through the registered stages, followed by the
+// registered observers.
+func New(opts Options) *Workflow {
+	mu.Lock()
+	defer mu.Unlock()
+	return &Workflow{
+		Options:   opts,
+		Stages:    append([]Stage(nil), stages...),
+		Observers: append([]Observer(nil), observers...),
+	}
+}
+
+// Run runs the workflow's stages in order until one fails, returns SkipRest, or ctx is done,
+// and then ends the stages that ran. It returns the run's summary, which is nil if no observer
+// set one up, along with the error the run failed with. A panic is returned as a *PanicError.
+func (w *Workflow) Run(ctx context.Context) (*summary.Summary, error) {
+	run := &Run{Options: w.Options, ID: w.Options.RunID, IssueID: w.Options.IssueID, Repo: w.Options.RepoURL, Log: w.Options.Log}
+	if w.Options.LocalRepo != "" {
+		run.Repo = w.Options.LocalRepo
+	}
+	if run.Log == nil {
+		run.Log = zap.NewNop()
+	}
+	err := w.run(ctx, run)
+	return run.Summary, err
+}
+
+func (w *Workflow) run(ctx context.Context, run *Run) (err error) {
+	var started int
+	var ran []Stage
+	defer func() {
+		for i := started - 1; i >= 0; i-- {
+			err = w.Observers[i].End(run, err)
+		}
+	}()
+	defer func() {
+		for i := len(ran) - 1; i >= 0; i-- {
+			if ender, ok := ran[i].(Ender); ok {
+				ender.End(ctx, run, err)
+			}
+		}
+	}()
+	defer func() {
+		if p := recover(); p != nil {
+			err = &PanicError{Value: p, Stack: string(debug.Stack())}
+		}
+	}()
+
+	for _, o := range w.Observers {
+		started++
+		if ctx, err = o.Start(ctx, run); err != nil {
+			return err
+		}
+	}
+	if len(w.Stages) == 0 {
+		return errors.New("workflow: no stages registered; import monday/cmd for monday's")
+	}
+	for _, stage := range w.Stages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ran = append(ran, stage)
+		if err := stage.Run(ctx, run); errors.Is(err, SkipRest) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"monday/summary"
+)
+
+// recorder records the stages it runs and ends, and observes runs.
+type recorder struct {
+	calls    []string
+	startErr error
+}
+
+func (r *recorder) stage(name string, err error) Stage {
+	return endingStage{Stage: StepFunc(name, func(context.Context, *Run) error {
+		r.calls = append(r.calls, "run "+name)
+		return err
+	}), r: r}
+}
+
+func (r *recorder) Start(ctx context.Context, run *Run) (context.Context, error) {
+	r.calls = append(r.calls, "start")
+	run.Summary = summary.New("run-1", run.IssueID, run.Repo)
+	return ctx, r.startErr
+}
+
+func (r *recorder) End(run *Run, err error) error {
+	r.calls = append(r.calls, "end")
+	if err != nil {
+		return errors.New("observed: " + err.Error())
+	}
+	return nil
+}
+
+type endingStage struct {
+	Stage
+	r *recorder
+}
+
+func (s endingStage) End(_ context.Context, _ *Run, err error) {
+	s.r.calls = append(s.r.calls, "end "+s.Name())
+}
+
+func TestRunRunsStagesInOrder(t *testing.T) {
+	r := &recorder{}
+	w := &Workflow{
+		Options:   Options{IssueID: "DEL-1", LocalRepo: "/src/app"},
+		Stages:    []Stage{r.stage("fetch", nil), r.stage("plan", SkipRest), r.stage("push", nil)},
+		Observers: []Observer{r},
+	}
+
+	sum, err := w.Run(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, sum)
+	assert.Equal(t, "/src/app", sum.Repo)
+	assert.Equal(t, []string{"start", "run fetch", "run plan", "end plan", "end fetch", "end"}, r.calls)
+}
+
+func TestRunStopsAtFailure(t *testing.T) {
+	r := &recorder{}
+	var ended error
+	undo := StepFunc("undo", noop)
+	w := &Workflow{
+		Stages: []Stage{
+			undoStage{Stage: undo, end: func(err error) { ended = err }},
+			r.stage("agent", errors.New("agent failed")),
+			r.stage("push", nil),
+		},
+		Observers: []Observer{r},
+	}
+
+	_, err := w.Run(context.Background())
+	assert.EqualError(t, err, "observed: agent failed")
+	assert.EqualError(t, ended, "agent failed")
+	assert.Equal(t, []string{"start", "run agent", "end agent", "end"}, r.calls)
+}
+
+type undoStage struct {
+	Stage
+	end func(err error)
+}
+
+func (s undoStage) End(_ context.Context, _ *Run, err error) { s.end(err) }
+
+func TestRunReturnsPanics(t *testing.T) {
+	w := &Workflow{Stages: []Stage{StepFunc("agent", func(context.Context, *Run) error { panic("boom") })}}
+
+	_, err := w.Run(context.Background())
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.EqualError(t, err, "workflow panicked: boom")
+}
+
+func TestRunEndsObserversWhoseStartFailed(t *testing.T) {
+	r := &recorder{startErr: errors.New("no API key")}
+	w := &Workflow{Stages: []Stage{r.stage("fetch", nil)}, Observers: []Observer{r}}
+
+	_, err := w.Run(context.Background())
+	assert.EqualError(t, err, "observed: no API key")
+	assert.Equal(t, []string{"start", "end"}, r.calls)
+}
+
+func TestRunStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &recorder{}
+	cancelling := StepFunc("fetch", func(context.Context, *Run) error {
+		cancel()
+		return nil
+	})
+	w := &Workflow{Stages: []Stage{cancelling, r.stage("push", nil)}}
+
+	_, err := w.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, r.calls)
+}
+
+func TestRunWithoutStages(t *testing.T) {
+	_, err := (&Workflow{}).Run(context.Background())
+	assert.ErrorContains(t, err, "no stages registered")
+}
@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+)
+
+// Stage is a stage of a run, such as fetching the issue or pushing the branch. A stage that
+// fails fails the run, and the stages after it do not run.
+type Stage interface {
+	// Name identifies the stage in logs and in the run summary
+	Name() string
+	// Run executes the stage
+	Run(ctx context.Context, run *Run) error
+}
+
+var stages []Stage
+
+// RegisterStage adds stage to the stages of every run, after those registered before it. It
+// panics if another stage has the same name.
+func RegisterStage(stage Stage) {
+	mu.Lock()
+	defer mu.Unlock()
+	checkStage(stage)
+	stages = append(stages, stage)
+}
+
+// InsertStage adds stage to the stages of every run, right after the stage called after, as
+// in InsertStage("agent", StepFunc("lint", lint)). It panics if there is no such stage or
+// another stage has the same name.
+func InsertStage(after string, stage Stage) {
+	mu.Lock()
+	defer mu.Unlock()
+	checkStage(stage)
+	for i, s := range stages {
+		if s.Name() == after {
+			stages = append(stages[:i+1], append([]Stage{stage}, stages[i+1:]...)...)
+			return
+		}
+	}
+	panic(fmt.Sprintf("workflow: no stage %q to insert %q after", after, stage.Name()))
+}
+
+// Stages returns the stages of runs, in order.
+func Stages() []Stage {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Stage(nil), stages...)
+}
+
+func checkStage(stage Stage) {
+	for _, s := range stages {
+		if s.Name() == stage.Name() {
+			panic(fmt.Sprintf("workflow: stage %q registered twice", stage.Name()))
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertStage(t *testing.T) {
+	saved := stages
+	t.Cleanup(func() { stages = saved })
+	stages = nil
+
+	RegisterStage(StepFunc("agent", noop))
+	RegisterStage(StepFunc("push", noop))
+	InsertStage("agent", StepFunc("lint", noop))
+	InsertStage("push", StepFunc("announce", noop))
+
+	var names []string
+	for _, stage := range Stages() {
+		names = append(names, stage.Name())
+	}
+	assert.Equal(t, []string{"agent", "lint", "push", "announce"}, names)
+	assert.Panics(t, func() { RegisterStage(StepFunc("lint", noop)) })
+	assert.Panics(t, func() { InsertStage("deploy", StepFunc("notify", noop)) })
+}
+
+func TestNewUsesRegisteredStages(t *testing.T) {
+	saved := stages
+	t.Cleanup(func() { stages = saved })
+	stages = nil
+
+	RegisterStage(StepFunc("agent", noop))
+	w := New(Options{IssueID: "DEL-1"})
+	RegisterStage(StepFunc("push", noop))
+
+	assert.Len(t, w.Stages, 1)
+	assert.Equal(t, "DEL-1", w.Options.IssueID)
+}
@@ -0,0 +1,163 @@
+// Package workflow runs monday: it takes an issue through the stages of a run, from fetching
+// it to opening the pull request, and lets Go programs embed monday and extend its runs
+// without forking it.
+//
+// A run is configured with Options and started with New and Workflow.Run:
+//
+//	import (
+//		_ "monday/cmd" // registers monday's stages
+//		"monday/workflow"
+//	)
+//
+//	sum, err := workflow.New(workflow.Options{IssueID: "DEL-123", RepoURL: "https://github.com/acme/app"}).Run(ctx)
+//
+// Runs go through the stages registered with RegisterStage and InsertStage, in order; package
+// cmd registers monday's own, and programs can add theirs between them. Steps are lighter:
+// they are registered for a point of a run's lifecycle and run in the workspace after the
+// repository's hooks of that point:
+//
+//	func main() {
+//		workflow.Register(workflow.PreCommit, workflow.StepFunc("license-headers", addHeaders))
+//		cmd.Execute()
+//	}
+//
+// Issues come from the issue tracker named in Options.Provider, one of those registered with
+// RegisterProvider, and observers registered with RegisterObserver set up what runs have
+// around their stages, such as their logs, and learn how they end.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"monday/issues"
+	"monday/summary"
+)
+
+// Point is a point of a run's lifecycle at which steps run.
+type Point string
+
+// Lifecycle points, in the order a run reaches them.
+const (
+	// PreAgent is before the agent runs
+	PreAgent Point = "pre_agent"
+	// PostAgent is after the agent, before the gates
+	PostAgent Point = "post_agent"
+	// PreCommit is after the gates, before the changes are committed
+	PreCommit Point = "pre_commit"
+	// PostPR is after the pull request is created
+	PostPR Point = "post_pr"
+)
+
+// Points lists the lifecycle points in the order a run reaches them.
+var Points = []Point{PreAgent, PostAgent, PreCommit, PostPR}
+
+// Run describes a run to its stages and steps.
+type Run struct {
+	// Options are the options the run was started with
+	Options Options
+	// ID uniquely identifies the run
+	ID string
+	// Dir is the directory holding the run's log, summary, and other files
+	Dir string
+	// Workspace is the worktree or clone the run works in, and the step's working directory;
+	// empty before it is prepared
+	Workspace string
+	// IssueID, IssueTitle, and IssueURL describe the Linear issue
+	IssueID    string
+	IssueTitle string
+	IssueURL   string
+	// Repo is the repository URL or local repository path
+	Repo string
+	// Branch is the issue branch
+	Branch string
+	// PRURL is the pull request of the run; empty before PostPR
+	PRURL string
+	// Log receives the log entries of the stage or step, which end up in the run log
+	Log *zap.Logger
+	// Issue is the issue, once fetched; nil for steps
+	Issue *issues.Issue
+	// Summary records the run's stages and outcome; nil for steps
+	Summary *summary.Summary
+
+	values map[any]any
+}
+
+// Set stores value under key for the stages after the current one, which get it with Value.
+// As with context values, key should be of an unexported type of the package that sets it.
+func (r *Run) Set(key, value any) {
+	if r.values == nil {
+		r.values = map[any]any{}
+	}
+	r.values[key] = value
+}
+
+// Value returns the value stored under key with Set, or nil.
+func (r *Run) Value(key any) any {
+	return r.values[key]
+}
+
+// Step is a custom step of a run. A step that fails fails the run, except at PostPR. Steps
+// and stages have the same interface.
+type Step = Stage
+
+// StepFunc returns the step or stage called name that runs fn.
+func StepFunc(name string, fn func(ctx context.Context, run *Run) error) Step {
+	return funcStep{name: name, fn: fn}
+}
+
+type funcStep struct {
+	name string
+	fn   func(ctx context.Context, run *Run) error
+}
+
+func (s funcStep) Name() string { return s.name }
+
+func (s funcStep) Run(ctx context.Context, run *Run) error { return s.fn(ctx, run) }
+
+var (
+	mu    sync.Mutex
+	steps = map[Point][]Step{}
+)
+
+// Register adds step to the steps run at point, after those registered before it. It panics
+// if point is not a lifecycle point or another step of point has the same name.
+func Register(point Point, step Step) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !known(point) {
+		panic(fmt.Sprintf("workflow: unknown lifecycle point %q", point))
+	}
+	for _, s := range steps[point] {
+		if s.Name() == step.Name() {
+			panic(fmt.Sprintf("workflow: step %q registered twice for %s", step.Name(), point))
+		}
+	}
+	steps[point] = append(steps[point], step)
+}
+
+// Steps returns the steps registered for point, in registration order.
+func Steps(point Point) []Step {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Step(nil), steps[point]...)
+}
+
+// Reset removes every registered step; it is meant for tests.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	steps = map[Point][]Step{}
+}
+
+func known(point Point) bool {
+	for _, p := range Points {
+		if p == point {
+			return true
+		}
+	}
+	return false
+}
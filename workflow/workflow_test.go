@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noop(context.Context, *Run) error { return nil }
+
+func TestRegisterKeepsOrder(t *testing.T) {
+	t.Cleanup(Reset)
+
+	Register(PreCommit, StepFunc("format", noop))
+	Register(PreCommit, StepFunc("license-headers", noop))
+	Register(PostPR, StepFunc("announce", noop))
+
+	got := Steps(PreCommit)
+	require.Len(t, got, 2)
+	assert.Equal(t, "format", got[0].Name())
+	assert.Equal(t, "license-headers", got[1].Name())
+	assert.Len(t, Steps(PostPR), 1)
+	assert.Empty(t, Steps(PreAgent))
+}
+
+func TestRegisterRejectsInvalidSteps(t *testing.T) {
+	t.Cleanup(Reset)
+
+	assert.Panics(t, func() { Register(Point("post_merge"), StepFunc("x", noop)) })
+	Register(PreAgent, StepFunc("codegen", noop))
+	assert.Panics(t, func() { Register(PreAgent, StepFunc("codegen", noop)) })
+}
+
+func TestStepFuncRuns(t *testing.T) {
+	var got string
+	step := StepFunc("record", func(_ context.Context, run *Run) error {
+		got = run.IssueID
+		return nil
+	})
+
+	require.NoError(t, step.Run(context.Background(), &Run{IssueID: "DEL-1"}))
+	assert.Equal(t, "DEL-1", got)
+}